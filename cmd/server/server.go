@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/server"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"net/http"
 	"os"
@@ -27,6 +30,20 @@ func startAppServer(conf *config.Config) {
 		logging.DefaultLogger().Fatalw("failed to initialize server", "err", err)
 	}
 
+	// setup outbox relay.
+	relay, err := outbox.NewRelay(serverEnv, conf)
+	if err != nil {
+		logging.DefaultLogger().Fatalw("failed to initialize outbox relay", "err", err)
+	}
+
+	// start background jobs.
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	go article.NewTrashPurger(serverEnv, conf).Start(jobCtx)
+	go article.NewScheduledPublisher(serverEnv, conf).Start(jobCtx)
+	go webhook.NewDispatcher(serverEnv, conf).Start(jobCtx)
+	go relay.Start(jobCtx)
+
 	// start server.
 	appsrv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", conf.ServerConfig.Port),
@@ -48,10 +65,14 @@ func startAppServer(conf *config.Config) {
 	<-quit
 
 	logging.DefaultLogger().Info("Shutting down app server")
+	cancelJobs()
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		logging.DefaultLogger().Fatal(err)
 	}
+	if err := serverEnv.Close(); err != nil {
+		logging.DefaultLogger().Errorw("failed to close server environments", "err", err)
+	}
 	logging.DefaultLogger().Info("Terminate application")
 }
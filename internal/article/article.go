@@ -6,15 +6,20 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	types2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
 	"net/http"
+	"strings"
 )
 
-// handleGetArticles handles "GET /api/articles?tag=&author=&favorited=&limit=&size=" to get articles.
+// searchSnippetLength is the maximum number of characters of article's text in a search snippet.
+const searchSnippetLength = 160
+
+// handleGetArticles handles "GET /api/articles?tag=&author=&favorited=&limit=&offset=&cursor=" to get articles.
 func (h *Handler) handleGetArticles(c echo.Context) error {
 	var (
 		ctx         = c.Request().Context()
@@ -30,11 +35,20 @@ func (h *Handler) handleGetArticles(c echo.Context) error {
 	}
 
 	// Query articles
-	articles, err := h.articleDB.FindArticlesByQuery(ctx, currentUser, model.ArticleQuery{
-		Tag:         query.Tag,
-		Author:      query.Author,
-		FavoritedBy: query.Favorited,
-	}, query.PageableQuery.Offset, query.PageableQuery.Limit)
+	var (
+		articles     *model.Articles
+		err          error
+		articleQuery = model.ArticleQuery{
+			Tag:         query.Tag,
+			Author:      query.Author,
+			FavoritedBy: query.Favorited,
+		}
+	)
+	if query.IsCursorMode() {
+		articles, err = h.articleDB.FindArticlesByQueryWithCursor(ctx, currentUser, articleQuery, query.decodedCursor, query.Limit)
+	} else {
+		articles, err = h.articleDB.FindArticlesByQuery(ctx, currentUser, articleQuery, query.Offset, query.Limit)
+	}
 	if err != nil {
 		return httputils.NewInternalServerError(err)
 	}
@@ -48,7 +62,7 @@ func (h *Handler) handleGetArticles(c echo.Context) error {
 	return c.JSON(http.StatusOK, types2.ToArticlesResponse(articles))
 }
 
-// handleGetFeeds handles "GET /api/articles/feed" to get feeds.
+// handleGetFeeds handles "GET /api/articles/feed?limit=&offset=&cursor=" to get feeds.
 func (h *Handler) handleGetFeeds(c echo.Context) error {
 	var (
 		ctx         = c.Request().Context()
@@ -73,7 +87,12 @@ func (h *Handler) handleGetFeeds(c echo.Context) error {
 	}
 
 	// Find feeds
-	feeds, err := h.articleDB.FindArticlesByAuthors(ctx, currentUser, followers, query.Offset, query.Limit)
+	var feeds *model.Articles
+	if query.IsCursorMode() {
+		feeds, err = h.articleDB.FindArticlesByAuthorsWithCursor(ctx, currentUser, followers, query.decodedCursor, query.Limit)
+	} else {
+		feeds, err = h.articleDB.FindArticlesByAuthors(ctx, currentUser, followers, query.Offset, query.Limit)
+	}
 	if err != nil {
 		return httputils.NewInternalServerError(err)
 	}
@@ -83,6 +102,42 @@ func (h *Handler) handleGetFeeds(c echo.Context) error {
 	return c.JSON(http.StatusOK, types2.ToArticlesResponse(feeds))
 }
 
+// handleSearchArticles handles "GET /api/articles/search?q=&limit=&offset=" to search articles.
+func (h *Handler) handleSearchArticles(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		query       = SearchArticleQuery{PageableQuery: &PageableQuery{}}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("ArticleHandler_handleSearchArticles failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Search articles
+	articles, err := h.articleDB.SearchArticles(ctx, currentUser, query.Keyword, query.Offset, query.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Check follow or not given article's authors.
+	if currentUser != nil {
+		if err := h.checkFollowAuthorsArticles(ctx, currentUser, articles.Articles...); err != nil {
+			return httputils.NewInternalServerError(err)
+		}
+	}
+
+	// Highlight matched words
+	terms := model.ParseSearchTerms(query.Keyword)
+	for _, a := range articles.Articles {
+		a.SetSnippet(terms, searchSnippetLength)
+	}
+	return c.JSON(http.StatusOK, types2.ToArticlesResponse(articles))
+}
+
 // handleGetArticle handles "GET /api/articles/:slug" to get an article.
 func (h *Handler) handleGetArticle(c echo.Context) error {
 	var (
@@ -92,9 +147,12 @@ func (h *Handler) handleGetArticle(c echo.Context) error {
 	)
 
 	// Query article
-	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	article, err := h.articleDB.FindBySlug(ctx, currentUser, slug)
 	if err != nil {
-		return err
+		if err == database.ErrRecordNotFound {
+			return h.redirectToCurrentSlug(c, currentUser, slug)
+		}
+		return httputils.NewInternalServerError(err)
 	}
 
 	// Check follow or not given article's authors.
@@ -106,6 +164,23 @@ func (h *Handler) handleGetArticle(c echo.Context) error {
 	return c.JSON(http.StatusOK, types2.ToArticleResponse(article))
 }
 
+// redirectToCurrentSlug responds "301 Moved Permanently" to the current slug of an article
+// if given slug was used by the article previously, otherwise not found error.
+func (h *Handler) redirectToCurrentSlug(c echo.Context, currentUser *userModel.User, slug string) error {
+	current, err := h.articleDB.FindCurrentSlug(c.Request().Context(), currentUser, slug)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("article(%s) not found", slug))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	location := strings.TrimSuffix(c.Request().URL.Path, slug) + current
+	if c.Request().URL.RawQuery != "" {
+		location += "?" + c.Request().URL.RawQuery
+	}
+	return c.Redirect(http.StatusMovedPermanently, location)
+}
+
 // handleCreateArticle handles "POST /api/articles" to post an article.
 func (h *Handler) handleCreateArticle(c echo.Context) error {
 	var (
@@ -129,6 +204,11 @@ func (h *Handler) handleCreateArticle(c echo.Context) error {
 		}
 		return httputils.NewInternalServerError(err)
 	}
+	eventTypes := []string{event.TypeArticleCreated}
+	if a.IsPublished() {
+		eventTypes = append(eventTypes, event.TypeArticlePublished)
+	}
+	h.publishArticle(ctx, &a, eventTypes...)
 	return c.JSON(http.StatusOK, types2.ToArticleResponse(&a))
 }
 
@@ -148,6 +228,7 @@ func (h *Handler) handleUpdateArticle(c echo.Context) error {
 	}
 
 	// Bind request
+	wasPublished := a.IsPublished()
 	req := UpdateArticleRequest{}
 	if err := req.Bind(c, a, currentUser); err != nil {
 		logger.Errorw("ArticleHandler_handleUpdateArticle failed to bind updating an article", "err", err)
@@ -159,8 +240,16 @@ func (h *Handler) handleUpdateArticle(c echo.Context) error {
 		if err == database.ErrKeyConflict {
 			return httputils.NewStatusUnprocessableEntity("duplicate title")
 		}
+		if err == database.ErrConcurrentUpdate {
+			return httputils.NewError(http.StatusConflict, "article was updated concurrently")
+		}
 		return httputils.NewInternalServerError(err)
 	}
+	eventTypes := []string{event.TypeArticleUpdated}
+	if !wasPublished && a.IsPublished() {
+		eventTypes = append(eventTypes, event.TypeArticlePublished)
+	}
+	h.publishArticle(ctx, a, eventTypes...)
 	return c.JSON(http.StatusOK, types2.ToArticleResponse(a))
 }
 
@@ -171,6 +260,11 @@ func (h *Handler) handleDeleteArticle(c echo.Context) error {
 		currentUser = h.currentUser(c)
 		slug        = c.Param("slug")
 	)
+	// Load current role
+	if err := h.loadRole(ctx, currentUser); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
 	// Delete article
 	if err := h.articleDB.DeleteBySlug(ctx, currentUser, slug); err != nil {
 		if err == database.ErrRecordNotFound {
@@ -181,7 +275,81 @@ func (h *Handler) handleDeleteArticle(c echo.Context) error {
 	return c.JSON(http.StatusOK, types2.ToStatusResponse(types2.StatusDeleted, nil))
 }
 
+// handleGetTrash handles "GET /api/user/trash?limit=&offset=" to get deleted articles of current user.
+func (h *Handler) handleGetTrash(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		query       = TrashQuery{PageableQuery: &PageableQuery{}}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("ArticleHandler_handleGetTrash failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query deleted articles
+	articles, err := h.articleDB.FindDeletedArticles(ctx, currentUser, query.Offset, query.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToArticlesResponse(articles))
+}
+
+// handleGetDrafts handles "GET /api/user/drafts?limit=&offset=" to get draft and scheduled articles of current user.
+func (h *Handler) handleGetDrafts(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		query       = DraftQuery{PageableQuery: &PageableQuery{}}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("ArticleHandler_handleGetDrafts failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query draft articles
+	articles, err := h.articleDB.FindDraftArticles(ctx, currentUser, query.Offset, query.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToArticlesResponse(articles))
+}
+
+// handleRestoreArticle handles "POST /api/articles/:slug/restore" to restore a deleted article.
+func (h *Handler) handleRestoreArticle(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Restore article
+	if err := h.articleDB.RestoreBySlug(ctx, currentUser, slug); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("deleted article(%s) not found", slug))
+		}
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity("duplicate slug")
+		}
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Query article again
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, types2.ToArticleResponse(article))
+}
+
 // handleFavorite handles "POST /api/articles/:slug/favorite" to update favorite status.
+// users blocked by the author can not favorite the article.
 func (h *Handler) handleFavorite(c echo.Context) error {
 	slug := c.Param("slug")
 	return h.favoriteOrUnFavoriteArticle(c, slug, true)
@@ -216,6 +384,11 @@ func (h *Handler) favoriteOrUnFavoriteArticle(c echo.Context, slug string, isFav
 	if err != nil {
 		return err
 	}
+	if isFavorite {
+		if err := h.checkNotBlockedByAuthor(ctx, currentUser, article); err != nil {
+			return err
+		}
+	}
 
 	// Update favorite or unfavorite
 	if isFavorite {
@@ -226,6 +399,14 @@ func (h *Handler) favoriteOrUnFavoriteArticle(c echo.Context, slug string, isFav
 	if err != nil {
 		return httputils.NewInternalServerError(err)
 	}
+	if isFavorite {
+		h.eventBus.Publish(ctx, &event.Event{
+			Type:      event.TypeArticleFavorited,
+			ActorID:   currentUser.ID,
+			UserID:    article.AuthorID,
+			ArticleID: article.ID,
+		})
+	}
 
 	// Query article again
 	article, err = h.getArticleBySlug(ctx, currentUser, slug)
@@ -247,6 +428,18 @@ func (h *Handler) getArticleBySlug(ctx context.Context, currentUser *userModel.U
 	return article, nil
 }
 
+// checkNotBlockedByAuthor returns a forbidden error if given user is blocked by the author of given article.
+func (h *Handler) checkNotBlockedByAuthor(ctx context.Context, u *userModel.User, article *model.Article) error {
+	blocked, err := h.userDB.IsBlocked(ctx, article.AuthorID, u.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	if blocked {
+		return httputils.NewError(http.StatusForbidden, "blocked by the author")
+	}
+	return nil
+}
+
 func (h *Handler) checkFollowAuthorsArticles(ctx context.Context, u *userModel.User, articles ...*model.Article) error {
 	if len(articles) == 0 {
 		return nil
@@ -270,11 +463,25 @@ func (h *Handler) checkFollowAuthorsArticles(ctx context.Context, u *userModel.U
 }
 
 func (h *Handler) currentUser(c echo.Context) *userModel.User {
-	uid := authutils.CurrentUser(c)
-	if uid == 0 {
+	claims := authutils.CurrentClaims(c)
+	if claims == nil || claims.UserID == 0 {
 		return nil
 	}
 	return &userModel.User{
-		ID: uid,
+		ID: claims.UserID,
 	}
 }
+
+// loadRole sets the current role of given user stored in the database instead of the role in the token,
+// so a changed role is applied before the token expires.
+func (h *Handler) loadRole(ctx context.Context, u *userModel.User) error {
+	if u == nil {
+		return nil
+	}
+	role, err := h.findRole(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	u.Role = role
+	return nil
+}
@@ -0,0 +1,61 @@
+package article
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	userMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleDelete_CurrentRole(t *testing.T) {
+	cfg, err := config.Load("")
+	assert.NoError(t, err)
+
+	var (
+		// demoted from a moderator after the token was issued
+		user      = &userModel.User{ID: 1, Role: authutils.RoleUser}
+		article   = &model.Article{ID: 1, Slug: "article1", AuthorID: 2}
+		articleDB = &mocks.ArticleDB{}
+		userDB    = &userMocks.UserDB{}
+		h         = &Handler{cfg: cfg, articleDB: articleDB, userDB: userDB}
+	)
+	userDB.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	articleDB.On("FindBySlug", mock.Anything, mock.Anything, article.Slug).Return(article, nil)
+	isUser := mock.MatchedBy(func(u *userModel.User) bool {
+		return u.ID == user.ID && u.Role == authutils.RoleUser
+	})
+	articleDB.On("DeleteBySlug", mock.Anything, isUser, article.Slug).Return(nil)
+	articleDB.On("DeleteCommentByID", mock.Anything, isUser, article.ID, uint(1)).Return(nil)
+
+	e := echo.New()
+	h.Route(e.Group("/api"), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", &jwt.Token{Claims: &authutils.JWTClaims{UserID: user.ID, Role: authutils.RoleModerator}})
+			return next(c)
+		}
+	})
+
+	for _, path := range []string{"/api/articles/article1", "/api/articles/article1/comments/1"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+	articleDB.AssertExpectations(t)
+
+	// failed to load the role
+	userDB.ExpectedCalls = nil
+	userDB.On("FindByID", mock.Anything, user.ID).Return(nil, errors.New("force error"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/articles/article1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -12,16 +12,27 @@ import (
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
 	"net/http"
 	"strconv"
+	"time"
 )
 
-// handleGetComments handles "GET /articles/:slug/comments" to find comments.
+// handleGetComments handles "GET /articles/:slug/comments?limit=&cursor=&order=" to find comments.
+// root comments are paginated and replies are nested in their parent comments.
+// comments of users muted by current user are excluded with their replies.
 func (h *Handler) handleGetComments(c echo.Context) error {
 	var (
 		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
 		currentUser = h.currentUser(c)
 		slug        = c.Param("slug")
+		query       = &CommentQuery{}
 	)
 
+	// Bind query
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("ArticleHandler_handleGetComments failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
 	// Query article
 	article, err := h.getArticleBySlug(ctx, currentUser, slug)
 	if err != nil {
@@ -29,14 +40,22 @@ func (h *Handler) handleGetComments(c echo.Context) error {
 	}
 
 	// Query comments
-	comments, err := h.articleDB.FindCommentsByArticleID(ctx, article.ID)
+	var userID uint
+	if currentUser != nil {
+		userID = currentUser.ID
+	}
+	comments, err := h.articleDB.FindCommentsByArticleIDWithCursor(ctx, article.ID, userID, query.Order, query.decodedCursor, query.Limit)
 	if err != nil {
 		return httputils.NewInternalServerError(err)
 	}
 
-	// Check follow or not given comment's authors.
+	// Check follow or not given comment's authors and reactions of current user.
 	if currentUser != nil {
-		if err := h.checkFollowAuthorsFromComments(ctx, currentUser, comments...); err != nil {
+		flatten := articlemodel.FlattenComments(comments.Comments)
+		if err := h.checkFollowAuthorsFromComments(ctx, currentUser, flatten...); err != nil {
+			return httputils.NewInternalServerError(err)
+		}
+		if err := h.setUserReactionsToComments(ctx, currentUser, flatten...); err != nil {
 			return httputils.NewInternalServerError(err)
 		}
 	}
@@ -44,6 +63,7 @@ func (h *Handler) handleGetComments(c echo.Context) error {
 }
 
 // handleCreateComment handles "POST /api/articles/:slug/comments" to create a new comment.
+// the comment is a reply if a parent comment id is given and users blocked by the author can not comment.
 func (h *Handler) handleCreateComment(c echo.Context) error {
 	var (
 		ctx         = c.Request().Context()
@@ -59,6 +79,9 @@ func (h *Handler) handleCreateComment(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := h.checkNotBlockedByAuthor(ctx, currentUser, article); err != nil {
+		return err
+	}
 
 	// Bind request
 	if err := req.Bind(c, article, &comment, currentUser); err != nil {
@@ -68,11 +91,71 @@ func (h *Handler) handleCreateComment(c echo.Context) error {
 
 	// Save a comment
 	if err := h.articleDB.SaveComment(ctx, &comment); err != nil {
+		if err == database.ErrRecordNotFound && comment.ParentID != nil {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("parent comment(%d) not found", *comment.ParentID))
+		}
+		if err == articlemodel.ErrCommentTooDeep {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("replies can be nested up to %d levels", h.cfg.ArticleConfig.Comment.MaxDepth))
+		}
 		return httputils.NewInternalServerError(err)
 	}
+	h.publishComment(ctx, article, &comment)
 	return c.JSON(http.StatusOK, types2.ToCommentResponse(&comment))
 }
 
+// handleUpdateComment handles "PUT /api/articles/:slug/comments/:id" to update a comment.
+// only the author can update the comment within the edit window.
+func (h *Handler) handleUpdateComment(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+		commentID   = c.Param("id")
+		req         = &UpdateCommentRequest{}
+	)
+
+	// Bind request
+	cid, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		logger.Errorw("ArticleHandler_handleUpdateComment invalid comment id", "commentID", commentID, "err", err)
+		return httputils.NewBindError("id", "uint")
+	}
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+
+	// Query comment
+	comment, err := h.getComment(ctx, article.ID, uint(cid))
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != currentUser.ID {
+		return httputils.NewNotFoundError(fmt.Sprintf("comment(%d) not found", cid))
+	}
+	if !comment.IsEditable(time.Now(), h.cfg.ArticleConfig.Comment.EditWindow) {
+		return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("comment(%d) can't be edited after %s", cid, h.cfg.ArticleConfig.Comment.EditWindow))
+	}
+
+	// Bind request
+	if err := req.Bind(c, comment); err != nil {
+		logger.Errorw("ArticleHandler_handleUpdateComment failed to bind updating a comment", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Update a comment
+	if err := h.articleDB.UpdateComment(ctx, currentUser, comment); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("comment(%d) not found", cid))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToCommentResponse(comment))
+}
+
 // handleDeleteComment handles "DELETE /articles/:slug/comments/:id" to delete a comment.
 func (h *Handler) handleDeleteComment(c echo.Context) error {
 	var (
@@ -96,6 +179,11 @@ func (h *Handler) handleDeleteComment(c echo.Context) error {
 		return err
 	}
 
+	// Load current role
+	if err := h.loadRole(ctx, currentUser); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
 	// Delete a comment
 	if err := h.articleDB.DeleteCommentByID(ctx, currentUser, article.ID, uint(cid)); err != nil {
 		if err == database.ErrRecordNotFound {
@@ -106,6 +194,37 @@ func (h *Handler) handleDeleteComment(c echo.Context) error {
 	return c.JSON(http.StatusOK, types2.ToStatusResponse(types2.StatusDeleted, nil))
 }
 
+// getComment returns a comment if exists, otherwise wrapped http error
+func (h *Handler) getComment(ctx context.Context, articleID, commentID uint) (*articlemodel.Comment, error) {
+	comment, err := h.articleDB.FindCommentByID(ctx, articleID, commentID)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, httputils.NewNotFoundError(fmt.Sprintf("comment(%d) not found", commentID))
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	return comment, nil
+}
+
+// setUserReactionsToComments sets reactions of given user to each comment.
+func (h *Handler) setUserReactionsToComments(ctx context.Context, u *userModel.User, comments ...*articlemodel.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	reactions, err := h.articleDB.FindUserReactions(ctx, u, articlemodel.ReactionTargetComment, ids)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		c.UserReactions = reactions[c.ID]
+	}
+	return nil
+}
+
 // TODO: change author in article and comment to pointer because of duplicate codes.
 func (h *Handler) checkFollowAuthorsFromComments(ctx context.Context, u *userModel.User, comments ...*articlemodel.Comment) error {
 	if len(comments) == 0 {
@@ -113,7 +232,13 @@ func (h *Handler) checkFollowAuthorsFromComments(ctx context.Context, u *userMod
 	}
 	var authors []uint
 	for _, c := range comments {
-		authors = append(authors, c.AuthorID)
+		// placeholders of deleted comments have no author.
+		if c.AuthorID != 0 {
+			authors = append(authors, c.AuthorID)
+		}
+	}
+	if len(authors) == 0 {
+		return nil
 	}
 
 	fm, err := h.userDB.IsFollows(ctx, u.ID, authors)
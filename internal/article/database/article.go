@@ -6,27 +6,40 @@ import (
 	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"gorm.io/gorm"
+	"time"
 )
 
 //go:generate mockery --name ArticleDB --filename article_mock.go
 type ArticleDB interface {
 	ArticleQueryDB
 	CommentDB
+	ArticleRevisionDB
+	ReactionDB
+	ReportDB
 
 	// Save saves a given article a and saves tags in article a.
-	// database.ErrKeyConflict will return if duplicate emails.
+	// The slug is made from the title with a numeric suffix if the slug is used by another article.
+	// The first revision of the article and an "article.created" outbox event are saved together.
 	Save(ctx context.Context, a *model2.Article) error
 
 	// Update updates a given model.Article from articleID and authorID.
-	// title, description, body will be updated.
+	// title, description, body, status and publish time will be updated and a new revision and
+	// an "article.updated" outbox event are saved.
+	// The slug is changed only if the slug of the title is changed, and the previous slug is kept in slug history.
 	// database.ErrRecordNotFound will be returned if not exists.
-	// database.ErrKeyConflict will be returned if duplicate slug
+	// database.ErrKeyConflict will be returned if duplicate slug and
+	// database.ErrConcurrentUpdate will be returned if failed to number the revision by concurrent updates
+	// or the article was hidden or unhidden after given article a was read.
 	Update(ctx context.Context, user *userModel.User, a *model2.Article) error
 
 	// DeleteBySlug deletes an article matched by user's id and slug.
+	// any article matched by slug is deleted if user's role has authutils.PermissionDeleteAnyArticle.
+	// the user who deleted is recorded, so articles deleted by other users cannot be restored by the author.
 	// database.ErrRecordNotFound will be returned if zero row affected.
 	DeleteBySlug(ctx context.Context, user *userModel.User, slug string) error
 
@@ -38,47 +51,128 @@ type ArticleDB interface {
 	// database.ErrRecordNotFound will be returned if zero row affected.
 	UnFavoriteArticle(ctx context.Context, user *userModel.User, articleID uint) error
 
+	// IsFavorited returns a true if given user favorited the article, otherwise false.
+	IsFavorited(ctx context.Context, user *userModel.User, articleID uint) (bool, error)
+
 	// FindTags returns tags all
 	FindTags(ctx context.Context) ([]*model2.Tag, error)
+
+	// FindDeletedArticles returns ([]*model.Articles, total count, error) which are written and deleted by given user.
+	// articles are ordered by deleted time descending and each articles contains Author, Tags, FavoritesCount and Favorited.
+	FindDeletedArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error)
+
+	// RestoreBySlug restores the most recently deleted article matched by user's id and slug.
+	// articles deleted by other users than the author (e.g. moderators) are not restored.
+	// database.ErrRecordNotFound will be returned if not exists.
+	// database.ErrKeyConflict will be returned if the slug is used by another article.
+	RestoreBySlug(ctx context.Context, user *userModel.User, slug string) error
+
+	// PurgeDeleted permanently deletes at most limit articles and limit comments deleted before given time.
+	// comments, tags, favorites, revisions, slug history and reactions of purged articles are deleted together.
+	// deleted comments with replies are not purged until the replies are purged.
+	// returns the number of purged articles and comments.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error)
+
+	// FindDraftArticles returns ([]*model.Articles, total count, error) which are drafts or scheduled by given user.
+	// articles are ordered by updated time descending and each articles contains Author, Tags, FavoritesCount and Favorited.
+	FindDraftArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error)
+
+	// PublishScheduled publishes at most limit scheduled articles which publish time is before or equal to given time
+	// with "article.published" outbox events. returns published articles which contain Author and Tags.
+	PublishScheduled(ctx context.Context, now time.Time, limit int) ([]*model2.Article, error)
 }
 
 type ArticleQueryDB interface {
-	// FindBySlug returns a model.Article with Author, Tags, FavoritesCount if exists.
-	// Favorited field will be setted if provide user.
-	// database.ErrRecordNotFound will be returned if not exists.
+	// FindBySlug returns a model.Article with Author, Tags, FavoritesCount, CommentsCount, Reactions if exists.
+	// Favorited and UserReactions fields will be setted if provide user.
+	// database.ErrRecordNotFound will be returned if not exists or not published and given user is not the author.
 	FindBySlug(ctx context.Context, user *userModel.User, slug string) (*model2.Article, error)
 
+	// FindCurrentSlug returns the current slug of an article which previously used given slug.
+	// database.ErrRecordNotFound will be returned if not exists or not published and given user is not the author.
+	FindCurrentSlug(ctx context.Context, user *userModel.User, slug string) (string, error)
+
 	// FindArticlesByQuery returns ([]*model.Articles, total count, error) from given queries.
+	// articles which are not published are only included if given user is the author and
+	// articles of authors muted by given user are excluded unless the author is given in the query.
 	// each articles contains Author, Tags, FavoritesCount and Favorited(if provide user).
 	FindArticlesByQuery(ctx context.Context, user *userModel.User, query model2.ArticleQuery, offset, limit int) (*model2.Articles, error)
 
-	// FindArticlesByAuthors returns ([]*model.Articles, total count, error) from given author ids.
+	// FindArticlesByQueryWithCursor returns ([]*model.Articles, total count, error) from given queries
+	// which are positioned after given cursor. The first page will be returned if cursor is nil.
+	// articles are filtered as same as FindArticlesByQuery.
+	// each articles contains Author, Tags, FavoritesCount and Favorited(if provide user).
+	FindArticlesByQueryWithCursor(ctx context.Context, user *userModel.User, query model2.ArticleQuery, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error)
+
+	// FindArticlesByAuthors returns ([]*model.Articles, total count, error) which are published by given author ids.
 	// each articles contains Author, Tags, FavoritesCount and Favorited.
 	FindArticlesByAuthors(ctx context.Context, user *userModel.User, authors []uint, offset, limit int) (*model2.Articles, error)
+
+	// FindArticlesByAuthorsWithCursor returns ([]*model.Articles, total count, error) from given author ids
+	// which are positioned after given cursor. The first page will be returned if cursor is nil.
+	// each articles contains Author, Tags, FavoritesCount and Favorited.
+	FindArticlesByAuthorsWithCursor(ctx context.Context, user *userModel.User, authors []uint, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error)
+
+	// SearchArticles returns ([]*model.Articles, total count, error) which are published and contain all words of
+	// given keyword in title, description or body. Articles are ordered by relevance.
+	// each articles contains Author, Tags, FavoritesCount and Favorited(if provide user).
+	SearchArticles(ctx context.Context, user *userModel.User, keyword string, offset, limit int) (*model2.Articles, error)
+
+	// CountContentsByAuthor returns model.ContentCounts of articles and comments written by given user and
+	// articles favorited by given user. deleted comments are not counted.
+	CountContentsByAuthor(ctx context.Context, userID uint) (*model2.ContentCounts, error)
 }
 
 type CommentDB interface {
-	// SaveComment saves a given comment c.
+	// SaveComment saves a given comment c with a "comment.created" outbox event.
 	// database.ErrFKConstraint will be returned if not exist article id or author id.
+	// database.ErrRecordNotFound will be returned if the parent comment not exists in the article and
+	// model.ErrCommentTooDeep will be returned if the reply is nested deeper than article.comment.maxDepth.
 	SaveComment(ctx context.Context, c *model2.Comment) error
 
 	// FindCommentsByArticleID returns ([]*model.Comments, error) from given article id.
+	// deleted and hidden comments are included, so use model.BuildCommentTree to hide them.
 	FindCommentsByArticleID(ctx context.Context, articleID uint) ([]*model2.Comment, error)
 
+	// FindCommentsByArticleIDWithCursor returns at most limit root comments of given article in given order
+	// (model.CommentOrderNewest or model.CommentOrderOldest) which are positioned after given cursor.
+	// The first page will be returned if cursor is nil. Each root comment contains all of its replies and
+	// deleted or hidden comments are hidden as same as model.BuildCommentTree. CommentsCount is the number of
+	// comments not deleted or hidden including replies.
+	// Comments written by users muted by given user are excluded with their replies and from CommentsCount.
+	// Nothing is excluded if userID is 0.
+	FindCommentsByArticleIDWithCursor(ctx context.Context, articleID, userID uint, order string, cursor *model2.CommentCursor, limit int) (*model2.Comments, error)
+
+	// FindCommentByID returns a model.Comment with Author matched by article id and comment id.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindCommentByID(ctx context.Context, articleID, commentID uint) (*model2.Comment, error)
+
+	// UpdateComment updates a body of given comment c matched by user's id and article id and marks it edited.
+	// database.ErrRecordNotFound will be returned if zero row affected.
+	UpdateComment(ctx context.Context, user *userModel.User, c *model2.Comment) error
+
 	// DeleteCommentByID deletes a comment matched by user'id and comment id.
+	// any comment matched by comment id is deleted if user's role has authutils.PermissionDeleteAnyComment.
 	// database.ErrRecordNotFound will be returned if zero row affected.
 	DeleteCommentByID(ctx context.Context, user *userModel.User, articleID, commentID uint) error
 }
 
 // NewArticleDB creates a new ArticleDB with given gorm.DB
-func NewArticleDB(_ *config.Config, db *gorm.DB) ArticleDB {
+func NewArticleDB(conf *config.Config, db *gorm.DB) ArticleDB {
+	maxDepth := conf.ArticleConfig.Comment.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
 	return &articleDB{
-		db: db,
+		db:              db,
+		maxCommentDepth: maxDepth,
 	}
 }
 
 type articleDB struct {
 	db *gorm.DB
+	// maxCommentDepth is the max number of nested levels of replies under a root comment.
+	maxCommentDepth int
 }
 
 func (adb *articleDB) Save(ctx context.Context, a *model2.Article) error {
@@ -98,7 +192,18 @@ func (adb *articleDB) Save(ctx context.Context, a *model2.Article) error {
 				return err
 			}
 		}
-		return txDb.WithContext(ctx).Create(a).Error
+		slug, err := uniqueSlug(ctx, txDb, 0, a.Title)
+		if err != nil {
+			return err
+		}
+		a.Slug = slug
+		if err := txDb.WithContext(ctx).Create(a).Error; err != nil {
+			return err
+		}
+		if err := txDb.WithContext(ctx).Create(model2.NewArticleRevision(a, 1, a.AuthorID)).Error; err != nil {
+			return err
+		}
+		return appendArticleEvent(ctx, txDb, event.TypeArticleCreated, a, a.AuthorID)
 	}); err != nil {
 		logger.Errorw("ArticleDB_Save failed to save an article", "err", err)
 		return database.WrapError(err)
@@ -110,18 +215,50 @@ func (adb *articleDB) Update(ctx context.Context, user *userModel.User, a *model
 	logger := logging.FromContext(ctx)
 	logger.Debugw("ArticleDB_Update try to update an article", "article", a)
 
-	result := adb.db.WithContext(ctx).
-		Model(a).
-		Select("Slug", "Title", "Description", "Body").
-		Where("article_id = ? AND author_id = ?", a.ID, user.ID).
-		Updates(a)
-	if result.Error != nil {
-		logger.Errorw("ArticleDB_Update failed to update an article", "err", result.Error)
-		return database.WrapError(result.Error)
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
 	}
-	if result.RowsAffected != 1 {
-		logger.Error("ArticleDB_Update failed to update an article. zero rows affected")
-		return database.WrapError(gorm.ErrRecordNotFound)
+	if err := database.RunInTx(ctx, adb.db, opts, func(txDb *gorm.DB) error {
+		if err := updateSlug(ctx, txDb, user, a); err != nil {
+			return err
+		}
+		db := txDb.WithContext(ctx).
+			Model(a).
+			Select("Slug", "Title", "Description", "Body", "Status", "PublishAt", "PublishedAt").
+			Where("article_id = ? AND author_id = ?", a.ID, user.ID)
+		// the article must be hidden only if it was hidden when read, so hiding by reports or moderators
+		// between reading and updating is not undone.
+		if a.IsHidden() {
+			db = db.Where("status = ?", model2.ArticleStatusHidden)
+		} else {
+			db = db.Where("status <> ?", model2.ArticleStatusHidden)
+		}
+		result := db.Updates(a)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			var count int64
+			if err := txDb.WithContext(ctx).Model(new(model2.Article)).
+				Where("article_id = ? AND author_id = ?", a.ID, user.ID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count != 0 {
+				logger.Errorw("ArticleDB_Update failed to update an article. status was changed concurrently", "articleID", a.ID)
+				return database.ErrConcurrentUpdate
+			}
+			logger.Error("ArticleDB_Update failed to update an article. zero rows affected")
+			return gorm.ErrRecordNotFound
+		}
+		if err := saveNextRevision(ctx, txDb, a, user.ID); err != nil {
+			return err
+		}
+		return appendArticleEvent(ctx, txDb, event.TypeArticleUpdated, a, user.ID)
+	}); err != nil {
+		logger.Errorw("ArticleDB_Update failed to update an article", "err", err)
+		return database.WrapError(err)
 	}
 	return nil
 }
@@ -132,9 +269,17 @@ func (adb *articleDB) DeleteBySlug(ctx context.Context, user *userModel.User, sl
 		logger.Error("ArticleDB_DeleteBySlug no user")
 		return database.WrapError(gorm.ErrRecordNotFound)
 	}
-	logger.Debugw("ArticleDB_DeleteBySlug try to delete an article", "userID", user.ID, "slug", slug)
+	logger.Debugw("ArticleDB_DeleteBySlug try to delete an article", "userID", user.ID, "role", user.Role, "slug", slug)
 
-	result := adb.db.WithContext(ctx).Where("slug = ? AND author_id = ?", slug, user.ID).Delete(&model2.Article{})
+	db := adb.db.WithContext(ctx).Model(new(model2.Article)).Where("slug = ?", slug)
+	if !authutils.HasPermission(user.Role, authutils.PermissionDeleteAnyArticle) {
+		db = db.Where("author_id = ?", user.ID)
+	}
+	// soft delete with the user who deleted, so articles deleted by moderators are not restored by authors.
+	result := db.UpdateColumns(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": user.ID,
+	})
 	if result.Error != nil {
 		logger.Errorw("ArticleDB_DeleteBySlug failed to delete", "err", result.Error)
 		return database.WrapError(result.Error)
@@ -186,6 +331,21 @@ func (adb *articleDB) UnFavoriteArticle(ctx context.Context, user *userModel.Use
 	return nil
 }
 
+func (adb *articleDB) IsFavorited(ctx context.Context, user *userModel.User, articleID uint) (bool, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		return false, nil
+	}
+	logger.Debugw("ArticleDB_IsFavorited try to check favorited", "userID", user.ID, "articleID", articleID)
+
+	a := model2.Article{ID: articleID}
+	if err := setFavorited(adb.db.WithContext(ctx), user, &a); err != nil {
+		logger.Errorw("ArticleDB_IsFavorited failed to check favorited", "userID", user.ID, "articleID", articleID, "err", err)
+		return false, database.WrapError(err)
+	}
+	return a.Favorited, nil
+}
+
 func (adb *articleDB) FindTags(ctx context.Context) ([]*model2.Tag, error) {
 	logger := logging.FromContext(ctx)
 	logger.Debug("ArticleDB_FindTags try to find tags all")
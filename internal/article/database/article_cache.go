@@ -0,0 +1,318 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
+)
+
+// NewArticleCacheDB creates a new ArticleDB which caches articles found by slug and tags with delegate ArticleDB.
+// Cached articles are shared by all users, so Favorited field is always fetched from delegate.
+func NewArticleCacheDB(conf *config.Config, c cache.Cache, delegate ArticleDB) ArticleDB {
+	return &articleCache{
+		conf:     conf,
+		prefix:   conf.CacheConfig.Prefix,
+		ttl:      conf.CacheConfig.TTL,
+		cache:    c,
+		delegate: delegate,
+	}
+}
+
+type articleCache struct {
+	conf     *config.Config
+	prefix   string
+	ttl      time.Duration
+	cache    cache.Cache
+	delegate ArticleDB
+}
+
+func (ac *articleCache) Save(ctx context.Context, a *model2.Article) error {
+	if err := ac.delegate.Save(ctx, a); err != nil {
+		return err
+	}
+	// new tags may be created.
+	ac.deleteKeys(ctx, ac.getTagsCacheKey())
+	return nil
+}
+
+func (ac *articleCache) Update(ctx context.Context, user *userModel.User, a *model2.Article) error {
+	// slug can be changed while updating, so keep the previous one.
+	prevSlug := a.Slug
+	if err := ac.delegate.Update(ctx, user, a); err != nil {
+		return err
+	}
+	ac.deleteKeys(ctx, ac.getArticleCacheKey(prevSlug), ac.getArticleCacheKey(a.Slug), ac.getArticleSlugCacheKey(a.ID))
+	return nil
+}
+
+func (ac *articleCache) DeleteBySlug(ctx context.Context, user *userModel.User, slug string) error {
+	if err := ac.delegate.DeleteBySlug(ctx, user, slug); err != nil {
+		return err
+	}
+	ac.deleteKeys(ctx, ac.getArticleCacheKey(slug))
+	return nil
+}
+
+func (ac *articleCache) FavoriteArticle(ctx context.Context, user *userModel.User, articleID uint) error {
+	if err := ac.delegate.FavoriteArticle(ctx, user, articleID); err != nil {
+		return err
+	}
+	ac.evictArticleByID(ctx, articleID)
+	return nil
+}
+
+func (ac *articleCache) UnFavoriteArticle(ctx context.Context, user *userModel.User, articleID uint) error {
+	if err := ac.delegate.UnFavoriteArticle(ctx, user, articleID); err != nil {
+		return err
+	}
+	ac.evictArticleByID(ctx, articleID)
+	return nil
+}
+
+func (ac *articleCache) IsFavorited(ctx context.Context, user *userModel.User, articleID uint) (bool, error) {
+	return ac.delegate.IsFavorited(ctx, user, articleID)
+}
+
+func (ac *articleCache) FindTags(ctx context.Context) ([]*model2.Tag, error) {
+	var tags []*model2.Tag
+	err := ac.cache.Once(ctx, ac.getTagsCacheKey(), &tags, ac.ttl, func() (interface{}, error) {
+		return ac.delegate.FindTags(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (ac *articleCache) FindDeletedArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindDeletedArticles(ctx, user, offset, limit)
+}
+
+func (ac *articleCache) RestoreBySlug(ctx context.Context, user *userModel.User, slug string) error {
+	if err := ac.delegate.RestoreBySlug(ctx, user, slug); err != nil {
+		return err
+	}
+	ac.deleteKeys(ctx, ac.getArticleCacheKey(slug))
+	return nil
+}
+
+func (ac *articleCache) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	// purged articles are already evicted when deleted.
+	return ac.delegate.PurgeDeleted(ctx, deletedBefore, limit)
+}
+
+func (ac *articleCache) FindDraftArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindDraftArticles(ctx, user, offset, limit)
+}
+
+func (ac *articleCache) PublishScheduled(ctx context.Context, now time.Time, limit int) ([]*model2.Article, error) {
+	// scheduled articles are not cached because only published articles are cached.
+	return ac.delegate.PublishScheduled(ctx, now, limit)
+}
+
+func (ac *articleCache) FindBySlug(ctx context.Context, user *userModel.User, slug string) (*model2.Article, error) {
+	var find model2.Article
+	err := ac.cache.Once(ctx, ac.getArticleCacheKey(slug), &find, ac.ttl, func() (interface{}, error) {
+		// find an article without user to share the cached value.
+		a, err := ac.delegate.FindBySlug(ctx, nil, slug)
+		if err != nil {
+			return nil, err
+		}
+		if err := ac.cache.Set(ctx, ac.getArticleSlugCacheKey(a.ID), a.Slug, ac.ttl); err != nil {
+			logging.FromContext(ctx).Errorw("ArticleCache_FindBySlug failed to set a slug", "articleID", a.ID, "err", err)
+		}
+		return a, nil
+	})
+	if err != nil {
+		// articles which are not published are not cached and only found by the author.
+		if err == database.ErrRecordNotFound && user != nil {
+			return ac.delegate.FindBySlug(ctx, user, slug)
+		}
+		return nil, err
+	}
+	if user != nil {
+		favorited, err := ac.delegate.IsFavorited(ctx, user, find.ID)
+		if err != nil {
+			return nil, err
+		}
+		find.Favorited = favorited
+		reactions, err := ac.delegate.FindUserReactions(ctx, user, model2.ReactionTargetArticle, []uint{find.ID})
+		if err != nil {
+			return nil, err
+		}
+		find.UserReactions = reactions[find.ID]
+	}
+	return &find, nil
+}
+
+func (ac *articleCache) FindCurrentSlug(ctx context.Context, user *userModel.User, slug string) (string, error) {
+	return ac.delegate.FindCurrentSlug(ctx, user, slug)
+}
+
+func (ac *articleCache) FindArticlesByQuery(ctx context.Context, user *userModel.User, query model2.ArticleQuery, offset, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindArticlesByQuery(ctx, user, query, offset, limit)
+}
+
+func (ac *articleCache) FindArticlesByQueryWithCursor(ctx context.Context, user *userModel.User, query model2.ArticleQuery, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindArticlesByQueryWithCursor(ctx, user, query, cursor, limit)
+}
+
+func (ac *articleCache) FindArticlesByAuthors(ctx context.Context, user *userModel.User, authors []uint, offset, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindArticlesByAuthors(ctx, user, authors, offset, limit)
+}
+
+func (ac *articleCache) FindArticlesByAuthorsWithCursor(ctx context.Context, user *userModel.User, authors []uint, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error) {
+	return ac.delegate.FindArticlesByAuthorsWithCursor(ctx, user, authors, cursor, limit)
+}
+
+func (ac *articleCache) SearchArticles(ctx context.Context, user *userModel.User, keyword string, offset, limit int) (*model2.Articles, error) {
+	return ac.delegate.SearchArticles(ctx, user, keyword, offset, limit)
+}
+
+func (ac *articleCache) CountContentsByAuthor(ctx context.Context, userID uint) (*model2.ContentCounts, error) {
+	return ac.delegate.CountContentsByAuthor(ctx, userID)
+}
+
+func (ac *articleCache) SaveComment(ctx context.Context, c *model2.Comment) error {
+	if err := ac.delegate.SaveComment(ctx, c); err != nil {
+		return err
+	}
+	ac.evictArticleByID(ctx, c.ArticleID)
+	return nil
+}
+
+func (ac *articleCache) FindCommentsByArticleID(ctx context.Context, articleID uint) ([]*model2.Comment, error) {
+	return ac.delegate.FindCommentsByArticleID(ctx, articleID)
+}
+
+func (ac *articleCache) FindCommentsByArticleIDWithCursor(ctx context.Context, articleID, userID uint, order string, cursor *model2.CommentCursor, limit int) (*model2.Comments, error) {
+	return ac.delegate.FindCommentsByArticleIDWithCursor(ctx, articleID, userID, order, cursor, limit)
+}
+
+func (ac *articleCache) FindCommentByID(ctx context.Context, articleID, commentID uint) (*model2.Comment, error) {
+	return ac.delegate.FindCommentByID(ctx, articleID, commentID)
+}
+
+func (ac *articleCache) UpdateComment(ctx context.Context, user *userModel.User, c *model2.Comment) error {
+	if err := ac.delegate.UpdateComment(ctx, user, c); err != nil {
+		return err
+	}
+	ac.evictArticleByID(ctx, c.ArticleID)
+	return nil
+}
+
+func (ac *articleCache) DeleteCommentByID(ctx context.Context, user *userModel.User, articleID, commentID uint) error {
+	if err := ac.delegate.DeleteCommentByID(ctx, user, articleID, commentID); err != nil {
+		return err
+	}
+	ac.evictArticleByID(ctx, articleID)
+	return nil
+}
+
+func (ac *articleCache) FindRevisions(ctx context.Context, articleID uint) ([]*model2.ArticleRevision, error) {
+	return ac.delegate.FindRevisions(ctx, articleID)
+}
+
+func (ac *articleCache) FindRevision(ctx context.Context, articleID, number uint) (*model2.ArticleRevision, error) {
+	return ac.delegate.FindRevision(ctx, articleID, number)
+}
+
+func (ac *articleCache) AddReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error {
+	if err := ac.delegate.AddReaction(ctx, user, targetType, targetID, kind); err != nil {
+		return err
+	}
+	if targetType == model2.ReactionTargetArticle {
+		ac.evictArticleByID(ctx, targetID)
+	}
+	return nil
+}
+
+func (ac *articleCache) RemoveReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error {
+	if err := ac.delegate.RemoveReaction(ctx, user, targetType, targetID, kind); err != nil {
+		return err
+	}
+	if targetType == model2.ReactionTargetArticle {
+		ac.evictArticleByID(ctx, targetID)
+	}
+	return nil
+}
+
+func (ac *articleCache) FindUserReactions(ctx context.Context, user *userModel.User, targetType string, targetIDs []uint) (map[uint][]string, error) {
+	return ac.delegate.FindUserReactions(ctx, user, targetType, targetIDs)
+}
+
+func (ac *articleCache) SaveReport(ctx context.Context, r *model2.Report, threshold int) (bool, error) {
+	hidden, err := ac.delegate.SaveReport(ctx, r, threshold)
+	if err != nil {
+		return false, err
+	}
+	if hidden {
+		ac.evictArticleByID(ctx, r.ArticleID)
+	}
+	return hidden, nil
+}
+
+func (ac *articleCache) FindReports(ctx context.Context, query model2.ReportQuery, offset, limit int) (*model2.Reports, error) {
+	return ac.delegate.FindReports(ctx, query, offset, limit)
+}
+
+func (ac *articleCache) FindReportByID(ctx context.Context, reportID uint) (*model2.Report, error) {
+	return ac.delegate.FindReportByID(ctx, reportID)
+}
+
+func (ac *articleCache) ResolveReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error) {
+	resolved, err := ac.delegate.ResolveReports(ctx, user, r)
+	if err != nil {
+		return 0, err
+	}
+	ac.evictArticleByID(ctx, r.ArticleID)
+	return resolved, nil
+}
+
+func (ac *articleCache) DismissReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error) {
+	dismissed, err := ac.delegate.DismissReports(ctx, user, r)
+	if err != nil {
+		return 0, err
+	}
+	ac.evictArticleByID(ctx, r.ArticleID)
+	return dismissed, nil
+}
+
+// evictArticleByID deletes a cached article matched by given article id if exists.
+func (ac *articleCache) evictArticleByID(ctx context.Context, articleID uint) {
+	var (
+		slugKey = ac.getArticleSlugCacheKey(articleID)
+		slug    string
+	)
+	if err := ac.cache.Get(ctx, slugKey, &slug); err != nil {
+		if err != cache.ErrCacheMiss {
+			logging.FromContext(ctx).Errorw("ArticleCache_evictArticleByID failed to get a slug", "articleID", articleID, "err", err)
+		}
+		return
+	}
+	ac.deleteKeys(ctx, ac.getArticleCacheKey(slug), slugKey)
+}
+
+func (ac *articleCache) deleteKeys(ctx context.Context, keys ...string) {
+	if err := ac.cache.Delete(ctx, keys...); err != nil {
+		logging.FromContext(ctx).Errorw("ArticleCache failed to delete caches", "keys", keys, "err", err)
+	}
+}
+
+func (ac *articleCache) getArticleCacheKey(slug string) string {
+	return fmt.Sprintf("%sarticles.%s", ac.prefix, slug)
+}
+
+func (ac *articleCache) getArticleSlugCacheKey(articleID uint) string {
+	return fmt.Sprintf("%sarticle_slugs.%d", ac.prefix, articleID)
+}
+
+func (ac *articleCache) getTagsCacheKey() string {
+	return fmt.Sprintf("%stags", ac.prefix)
+}
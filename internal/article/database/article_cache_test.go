@@ -0,0 +1,275 @@
+package database
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/database/mocks"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+type CacheSuite struct {
+	suite.Suite
+	cacheDB    ArticleDB
+	cacheClose cache.CloseFunc
+	cli        redis.UniversalClient
+	dbMock     *mocks.ArticleDB
+	user       *userModel.User
+	article    *model2.Article
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) SetupTest() {
+	conf, _ := config.Load("")
+	logging.SetConfig(&logging.Config{
+		Encoding:    "console",
+		Level:       zapcore.FatalLevel,
+		Development: false,
+	})
+
+	// setup database mock
+	s.dbMock = &mocks.ArticleDB{}
+
+	cli, c, closeFn := cache.NewTestCache(s.T())
+	s.cacheDB = NewArticleCacheDB(conf, c, s.dbMock)
+	s.cacheClose = closeFn
+	s.cli = cli
+
+	s.user = &userModel.User{ID: 1, Name: "user1"}
+	s.article = &model2.Article{
+		ID:             1,
+		Slug:           "title",
+		Title:          "title",
+		Description:    "description",
+		Body:           "body",
+		Author:         userModel.User{ID: 2, Name: "user2"},
+		AuthorID:       2,
+		Tags:           []*model2.Tag{{ID: 1, Name: "tag1"}},
+		CreatedAt:      time.Now().Truncate(time.Millisecond),
+		UpdatedAt:      time.Now().Truncate(time.Millisecond),
+		FavoritesCount: 3,
+	}
+}
+
+func (s *CacheSuite) TearDownTest() {
+	if s.cacheClose != nil {
+		s.cacheClose()
+	}
+}
+
+func (s *CacheSuite) TestFindBySlug() {
+	s.dbMock.On("FindBySlug", mock.Anything, (*userModel.User)(nil), s.article.Slug).Return(s.article, nil).Once()
+	s.dbMock.On("IsFavorited", mock.Anything, s.user, s.article.ID).Return(true, nil)
+	s.dbMock.On("FindUserReactions", mock.Anything, s.user, model2.ReactionTargetArticle, []uint{s.article.ID}).
+		Return(map[uint][]string{s.article.ID: {"heart"}}, nil)
+
+	// first call from database
+	find, err := s.cacheDB.FindBySlug(context.TODO(), s.user, s.article.Slug)
+	s.NoError(err)
+	s.assertArticle(find)
+	s.True(find.Favorited)
+	s.Equal([]string{"heart"}, find.UserReactions)
+
+	// second call from cache
+	find, err = s.cacheDB.FindBySlug(context.TODO(), nil, s.article.Slug)
+	s.NoError(err)
+	s.assertArticle(find)
+	s.False(find.Favorited)
+	s.Empty(find.UserReactions)
+
+	s.dbMock.AssertNumberOfCalls(s.T(), "FindBySlug", 1)
+	s.dbMock.AssertNumberOfCalls(s.T(), "IsFavorited", 1)
+	s.Len(s.getCacheKeys(), 2)
+}
+
+func (s *CacheSuite) TestFindBySlugNotFound() {
+	s.dbMock.On("FindBySlug", mock.Anything, mock.Anything, "not-exist").Return(nil, database.ErrRecordNotFound)
+
+	find, err := s.cacheDB.FindBySlug(context.TODO(), s.user, "not-exist")
+
+	s.Nil(find)
+	s.Equal(database.ErrRecordNotFound, err)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestFindBySlugDraft() {
+	draft := *s.article
+	draft.Status = model2.ArticleStatusDraft
+	s.dbMock.On("FindBySlug", mock.Anything, (*userModel.User)(nil), draft.Slug).Return(nil, database.ErrRecordNotFound)
+	s.dbMock.On("FindBySlug", mock.Anything, s.user, draft.Slug).Return(&draft, nil)
+
+	find, err := s.cacheDB.FindBySlug(context.TODO(), s.user, draft.Slug)
+
+	s.NoError(err)
+	s.Equal(model2.ArticleStatusDraft, find.Status)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestUpdate() {
+	s.cacheArticle()
+	s.dbMock.On("Update", mock.Anything, s.user, mock.Anything).Run(func(args mock.Arguments) {
+		a := args.Get(2).(*model2.Article)
+		a.Slug = "updated-title"
+	}).Return(nil)
+
+	updated := *s.article
+	err := s.cacheDB.Update(context.TODO(), s.user, &updated)
+
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestDeleteBySlug() {
+	s.cacheArticle()
+	s.dbMock.On("DeleteBySlug", mock.Anything, s.user, s.article.Slug).Return(nil)
+
+	err := s.cacheDB.DeleteBySlug(context.TODO(), s.user, s.article.Slug)
+
+	s.NoError(err)
+	s.NotContains(s.getCacheKeys(), s.cacheDB.(*articleCache).getArticleCacheKey(s.article.Slug))
+}
+
+func (s *CacheSuite) TestRestoreBySlug() {
+	s.cacheArticle()
+	s.dbMock.On("RestoreBySlug", mock.Anything, s.user, s.article.Slug).Return(nil)
+
+	err := s.cacheDB.RestoreBySlug(context.TODO(), s.user, s.article.Slug)
+
+	s.NoError(err)
+	s.NotContains(s.getCacheKeys(), s.cacheDB.(*articleCache).getArticleCacheKey(s.article.Slug))
+}
+
+func (s *CacheSuite) TestFavoriteAndUnFavorite() {
+	s.cacheArticle()
+	s.dbMock.On("FavoriteArticle", mock.Anything, s.user, s.article.ID).Return(nil)
+	s.NoError(s.cacheDB.FavoriteArticle(context.TODO(), s.user, s.article.ID))
+	s.Empty(s.getCacheKeys())
+
+	s.cacheArticle()
+	s.dbMock.On("UnFavoriteArticle", mock.Anything, s.user, s.article.ID).Return(nil)
+	s.NoError(s.cacheDB.UnFavoriteArticle(context.TODO(), s.user, s.article.ID))
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestCommentWrites() {
+	s.cacheArticle()
+	s.dbMock.On("SaveComment", mock.Anything, mock.Anything).Return(nil)
+	s.NoError(s.cacheDB.SaveComment(context.TODO(), &model2.Comment{ArticleID: s.article.ID, Body: "comment"}))
+	s.Empty(s.getCacheKeys())
+
+	s.cacheArticle()
+	s.dbMock.On("DeleteCommentByID", mock.Anything, s.user, s.article.ID, uint(1)).Return(nil)
+	s.NoError(s.cacheDB.DeleteCommentByID(context.TODO(), s.user, s.article.ID, 1))
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestReactionWrites() {
+	s.cacheArticle()
+	s.dbMock.On("AddReaction", mock.Anything, s.user, model2.ReactionTargetArticle, s.article.ID, "heart").Return(nil)
+	s.NoError(s.cacheDB.AddReaction(context.TODO(), s.user, model2.ReactionTargetArticle, s.article.ID, "heart"))
+	s.Empty(s.getCacheKeys())
+
+	s.cacheArticle()
+	s.dbMock.On("RemoveReaction", mock.Anything, s.user, model2.ReactionTargetArticle, s.article.ID, "heart").Return(nil)
+	s.NoError(s.cacheDB.RemoveReaction(context.TODO(), s.user, model2.ReactionTargetArticle, s.article.ID, "heart"))
+	s.Empty(s.getCacheKeys())
+
+	// reactions to comments are not related to cached articles.
+	s.cacheArticle()
+	s.dbMock.On("AddReaction", mock.Anything, s.user, model2.ReactionTargetComment, uint(1), "heart").Return(nil)
+	s.NoError(s.cacheDB.AddReaction(context.TODO(), s.user, model2.ReactionTargetComment, 1, "heart"))
+	s.Len(s.getCacheKeys(), 2)
+}
+
+func (s *CacheSuite) TestReportWrites() {
+	report := &model2.Report{ID: 1, TargetType: model2.ReportTargetComment, TargetID: 3, ArticleID: s.article.ID}
+
+	// not hidden
+	s.cacheArticle()
+	s.dbMock.On("SaveReport", mock.Anything, report, 5).Return(false, nil).Once()
+	_, err := s.cacheDB.SaveReport(context.TODO(), report, 5)
+	s.NoError(err)
+	s.Len(s.getCacheKeys(), 2)
+
+	// hidden
+	s.dbMock.On("SaveReport", mock.Anything, report, 5).Return(true, nil).Once()
+	_, err = s.cacheDB.SaveReport(context.TODO(), report, 5)
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+
+	s.cacheArticle()
+	s.dbMock.On("ResolveReports", mock.Anything, s.user, report).Return(int64(1), nil)
+	_, err = s.cacheDB.ResolveReports(context.TODO(), s.user, report)
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+
+	s.cacheArticle()
+	s.dbMock.On("DismissReports", mock.Anything, s.user, report).Return(int64(1), nil)
+	_, err = s.cacheDB.DismissReports(context.TODO(), s.user, report)
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) TestWriteFailNoEviction() {
+	s.cacheArticle()
+	s.dbMock.On("FavoriteArticle", mock.Anything, s.user, s.article.ID).Return(database.ErrKeyConflict)
+
+	err := s.cacheDB.FavoriteArticle(context.TODO(), s.user, s.article.ID)
+
+	s.Equal(database.ErrKeyConflict, err)
+	s.Len(s.getCacheKeys(), 2)
+}
+
+func (s *CacheSuite) TestFindTags() {
+	tags := []*model2.Tag{{ID: 1, Name: "tag1"}, {ID: 2, Name: "tag2"}}
+	s.dbMock.On("FindTags", mock.Anything).Return(tags, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		find, err := s.cacheDB.FindTags(context.TODO())
+		s.NoError(err)
+		s.Len(find, 2)
+		s.Equal("tag1", find[0].Name)
+		s.Equal("tag2", find[1].Name)
+	}
+	s.dbMock.AssertNumberOfCalls(s.T(), "FindTags", 1)
+
+	// evict tags after saving an article
+	s.dbMock.On("Save", mock.Anything, mock.Anything).Return(nil)
+	s.NoError(s.cacheDB.Save(context.TODO(), &model2.Article{Title: "new"}))
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *CacheSuite) cacheArticle() {
+	s.dbMock.On("FindBySlug", mock.Anything, (*userModel.User)(nil), s.article.Slug).Return(s.article, nil)
+	_, err := s.cacheDB.FindBySlug(context.TODO(), nil, s.article.Slug)
+	s.NoError(err)
+	s.Len(s.getCacheKeys(), 2)
+}
+
+func (s *CacheSuite) assertArticle(find *model2.Article) {
+	s.Equal(s.article.ID, find.ID)
+	s.Equal(s.article.Slug, find.Slug)
+	s.Equal(s.article.Title, find.Title)
+	s.Equal(s.article.Body, find.Body)
+	s.Equal(s.article.Author.Name, find.Author.Name)
+	s.Equal(s.article.FavoritesCount, find.FavoritesCount)
+	s.Len(find.Tags, 1)
+	s.Equal("tag1", find.Tags[0].Name)
+	s.True(s.article.CreatedAt.Equal(find.CreatedAt))
+}
+
+func (s *CacheSuite) getCacheKeys() []string {
+	return s.cli.Keys(context.Background(), "*").Val()
+}
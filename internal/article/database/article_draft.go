@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (adb *articleDB) FindDraftArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_FindDraftArticles no user")
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("ArticleDB_FindDraftArticles try to find draft articles", "userID", user.ID, "offset", offset, "limit", limit)
+
+	if limit <= 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: 0,
+		}, nil
+	}
+
+	db := adb.db.WithContext(ctx)
+	// find draft article ids ordered by updated time.
+	var ids []uint
+	if err := db.Model(new(model2.Article)).
+		Where("author_id = ? AND status <> ?", user.ID, model2.ArticleStatusPublished).
+		Order("updated_at DESC, article_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Pluck("article_id", &ids).Error; err != nil {
+		logger.Errorw("ArticleDB_FindDraftArticles failed to fetch article ids", "userID", user.ID, "offset", offset, "limit", limit, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	// find total count of draft articles.
+	var total int64
+	if err := db.Model(new(model2.Article)).
+		Where("author_id = ? AND status <> ?", user.ID, model2.ArticleStatusPublished).
+		Count(&total).Error; err != nil {
+		logger.Errorw("ArticleDB_FindDraftArticles failed to fetch total count", "userID", user.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	if len(ids) == 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: total,
+		}, nil
+	}
+
+	articles, err := articlesByIds(db, ids)
+	if err != nil {
+		logger.Errorw("ArticleDB_FindDraftArticles failed to fetch articles with author and tags.", "userID", user.ID, "ids", ids, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := fillArticlesExtraData(db, user, articles); err != nil {
+		logger.Errorw("ArticleDB_FindDraftArticles failed to update extra data", "userID", user.ID, "ids", ids, "err", err)
+	}
+	return &model2.Articles{
+		Articles:      sortArticlesByIds(articles, ids),
+		ArticlesCount: total,
+	}, nil
+}
+
+func (adb *articleDB) PublishScheduled(ctx context.Context, now time.Time, limit int) ([]*model2.Article, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_PublishScheduled try to publish scheduled articles", "now", now, "limit", limit)
+
+	if limit <= 0 {
+		return []*model2.Article{}, nil
+	}
+	db := adb.db.WithContext(ctx)
+
+	var ids []uint
+	if err := db.Model(new(model2.Article)).
+		Where("status = ? AND publish_at <= ?", model2.ArticleStatusScheduled, now).
+		Order("publish_at ASC, article_id ASC").
+		Limit(limit).
+		Pluck("article_id", &ids).Error; err != nil {
+		logger.Errorw("ArticleDB_PublishScheduled failed to fetch article ids", "now", now, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if len(ids) == 0 {
+		return []*model2.Article{}, nil
+	}
+
+	var published []*model2.Article
+	if err := database.RunInTx(ctx, adb.db, nil, func(txDb *gorm.DB) error {
+		// update columns without hooks to keep the slug and updated time.
+		// the status is checked again because the author can change it concurrently.
+		publishedIds := make([]uint, 0, len(ids))
+		for _, id := range ids {
+			result := txDb.Model(new(model2.Article)).
+				Where("article_id = ? AND status = ?", id, model2.ArticleStatusScheduled).
+				UpdateColumns(map[string]interface{}{
+					"status":       model2.ArticleStatusPublished,
+					"published_at": now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 1 {
+				publishedIds = append(publishedIds, id)
+			}
+		}
+
+		articles, err := articlesByIds(txDb, publishedIds)
+		if err != nil {
+			return err
+		}
+		published = sortArticlesByIds(articles, publishedIds)
+		for _, a := range published {
+			if err := appendArticleEvent(ctx, txDb, event.TypeArticlePublished, a, a.AuthorID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Errorw("ArticleDB_PublishScheduled failed to publish articles", "ids", ids, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return published, nil
+}
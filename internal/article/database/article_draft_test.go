@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	outboxModel "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"time"
+)
+
+func (s *Suite) TestFindBySlugWithDraft() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	a.Status = model.ArticleStatusDraft
+	s.NoError(s.db.Save(context.TODO(), a))
+
+	// found by the author
+	find, err := s.db.FindBySlug(context.TODO(), s.u1, a.Slug)
+	s.NoError(err)
+	s.Equal(model.ArticleStatusDraft, find.Status)
+	s.Nil(find.PublishAt)
+
+	// not found by others
+	_, err = s.db.FindBySlug(context.TODO(), s.u2, a.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+	_, err = s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestFindArticlesByQueryWithDraft() {
+	published := newArticle("article1", "description", "body", *s.u1, nil)
+	draft := newArticle("article2", "description", "body", *s.u1, nil)
+	draft.Status = model.ArticleStatusDraft
+	for _, a := range []*model.Article{published, draft} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+
+	// author
+	articles, err := s.db.FindArticlesByQuery(context.TODO(), s.u1, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.Len(articles.Articles, 2)
+
+	// others
+	articles, err = s.db.FindArticlesByQuery(context.TODO(), s.u2, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(1, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(published.ID, articles.Articles[0].ID)
+
+	// feeds
+	articles, err = s.db.FindArticlesByAuthors(context.TODO(), s.u2, []uint{s.u1.ID}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(1, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(published.ID, articles.Articles[0].ID)
+}
+
+func (s *Suite) TestFindDraftArticles() {
+	now := time.Now()
+	publishAt := now.Add(time.Hour)
+	published := newArticle("article1", "description", "body", *s.u1, nil)
+	draft := newArticle("article2", "description", "body", *s.u1, []string{"tag1"})
+	draft.Status = model.ArticleStatusDraft
+	scheduled := newArticle("article3", "description", "body", *s.u1, nil)
+	scheduled.Status = model.ArticleStatusScheduled
+	scheduled.PublishAt = &publishAt
+	other := newArticle("article4", "description", "body", *s.u2, nil)
+	other.Status = model.ArticleStatusDraft
+	for _, a := range []*model.Article{published, draft, scheduled, other} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", draft.ID).UpdateColumn("updated_at", now.Add(-time.Hour)).Error)
+
+	// first iteration
+	articles, err := s.db.FindDraftArticles(context.TODO(), s.u1, 0, 1)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(scheduled.ID, articles.Articles[0].ID)
+	s.Equal(model.ArticleStatusScheduled, articles.Articles[0].Status)
+	s.WithinDuration(publishAt, *articles.Articles[0].PublishAt, time.Second)
+
+	// second iteration
+	articles, err = s.db.FindDraftArticles(context.TODO(), s.u1, 1, 1)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(draft.ID, articles.Articles[0].ID)
+	s.Len(articles.Articles[0].Tags, 1)
+
+	// no drafts
+	articles, err = s.db.FindDraftArticles(context.TODO(), s.u3, 0, 10)
+	s.NoError(err)
+	s.EqualValues(0, articles.ArticlesCount)
+	s.Empty(articles.Articles)
+}
+
+func (s *Suite) TestPublishScheduled() {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	due := newArticle("article1", "description", "body", *s.u1, nil)
+	due.Status = model.ArticleStatusScheduled
+	due.PublishAt = &past
+	notDue := newArticle("article2", "description", "body", *s.u1, nil)
+	notDue.Status = model.ArticleStatusScheduled
+	notDue.PublishAt = &future
+	draft := newArticle("article3", "description", "body", *s.u1, nil)
+	draft.Status = model.ArticleStatusDraft
+	for _, a := range []*model.Article{due, notDue, draft} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+
+	// when
+	published, err := s.db.PublishScheduled(context.TODO(), now, 10)
+
+	// then
+	s.NoError(err)
+	s.Len(published, 1)
+	s.Equal(due.ID, published[0].ID)
+	s.Equal(model.ArticleStatusPublished, published[0].Status)
+	s.Equal(s.u1.Name, published[0].Author.Name)
+	s.NotNil(published[0].PublishedAt)
+	var events []*outboxModel.OutboxEvent
+	s.NoError(s.originDB.Where("event_type = ?", event.TypeArticlePublished).Find(&events).Error)
+	s.Len(events, 1)
+	s.Equal(due.ID, events[0].AggregateID)
+	find, err := s.db.FindBySlug(context.TODO(), nil, due.Slug)
+	s.NoError(err)
+	s.Equal(model.ArticleStatusPublished, find.Status)
+	s.Equal(due.Slug, find.Slug)
+	_, err = s.db.FindBySlug(context.TODO(), nil, notDue.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// nothing to publish
+	published, err = s.db.PublishScheduled(context.TODO(), now, 10)
+	s.NoError(err)
+	s.Empty(published)
+}
+
+func (s *Suite) TestFindArticlesOrderedByPublishedTime() {
+	draft := newArticle("article1", "description", "body", *s.u1, nil)
+	draft.Status = model.ArticleStatusDraft
+	s.NoError(s.db.Save(context.TODO(), draft))
+	published := newArticle("article2", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), published))
+
+	// publish the draft created before
+	now := time.Now()
+	draft.Status = model.ArticleStatusPublished
+	draft.PublishAt = &now
+	draft.PublishedAt = &now
+	s.NoError(s.db.Update(context.TODO(), s.u1, draft))
+
+	// then
+	articles, err := s.db.FindArticlesByQueryWithCursor(context.TODO(), nil, model.ArticleQuery{}, nil, 1)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.Equal(draft.ID, articles.Articles[0].ID)
+	s.NotNil(articles.NextCursor)
+
+	articles, err = s.db.FindArticlesByQueryWithCursor(context.TODO(), nil, model.ArticleQuery{}, articles.NextCursor, 1)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.Equal(published.ID, articles.Articles[0].ID)
+
+	articles, err = s.db.FindArticlesByAuthorsWithCursor(context.TODO(), s.u2, []uint{s.u1.ID}, nil, 10)
+	s.NoError(err)
+	s.Len(articles.Articles, 2)
+	s.Equal(draft.ID, articles.Articles[0].ID)
+	s.Equal(published.ID, articles.Articles[1].ID)
+}
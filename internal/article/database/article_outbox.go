@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	outboxDB "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/database"
+	outboxModel "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+// articleOutboxPayload is a payload of article events in the outbox.
+type articleOutboxPayload struct {
+	ArticleID   uint       `json:"articleId"`
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Body        string     `json:"body"`
+	TagList     []string   `json:"tagList"`
+	AuthorID    uint       `json:"authorId"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+}
+
+// commentOutboxPayload is a payload of comment events in the outbox.
+type commentOutboxPayload struct {
+	CommentID uint      `json:"commentId"`
+	ArticleID uint      `json:"articleId"`
+	ParentID  *uint     `json:"parentId,omitempty"`
+	AuthorID  uint      `json:"authorId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// appendArticleEvent appends an event of given type about the article a written by authorID
+// to the outbox in given transaction.
+func appendArticleEvent(ctx context.Context, txDb *gorm.DB, eventType string, a *model2.Article, authorID uint) error {
+	payload := articleOutboxPayload{
+		ArticleID:   a.ID,
+		Slug:        a.Slug,
+		Title:       a.Title,
+		Description: a.Description,
+		Body:        a.Body,
+		TagList:     make([]string, len(a.Tags)),
+		AuthorID:    authorID,
+		Status:      a.Status,
+		PublishAt:   a.PublishAt,
+	}
+	for i, tag := range a.Tags {
+		payload.TagList[i] = tag.Name
+	}
+	e, err := outboxModel.NewOutboxEvent(outboxModel.AggregateTypeArticle, a.ID, eventType, &payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return outboxDB.Append(ctx, txDb, e)
+}
+
+// appendCommentEvent appends an event of given created comment c to the outbox in given transaction.
+func appendCommentEvent(ctx context.Context, txDb *gorm.DB, c *model2.Comment) error {
+	payload := commentOutboxPayload{
+		CommentID: c.ID,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+	}
+	e, err := outboxModel.NewOutboxEvent(outboxModel.AggregateTypeComment, c.ID, event.TypeCommentCreated, &payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return outboxDB.Append(ctx, txDb, e)
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	outboxModel "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+)
+
+func (s *Suite) TestOutboxEvents() {
+	a := newArticle("article1", "description", "body", *s.u1, []string{"tag1"})
+
+	// when
+	s.NoError(s.db.Save(context.TODO(), a))
+	update := *a
+	update.Title = "updated title"
+	s.NoError(s.db.Update(context.TODO(), s.u1, &update))
+	c := newComment("comment1", *s.u2, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+
+	// then
+	var events []*outboxModel.OutboxEvent
+	s.NoError(s.originDB.Order("outbox_id ASC").Find(&events).Error)
+	s.Len(events, 3)
+
+	s.Equal(event.TypeArticleCreated, events[0].EventType)
+	s.Equal(outboxModel.AggregateTypeArticle, events[0].AggregateType)
+	s.Equal(a.ID, events[0].AggregateID)
+	s.Equal(outboxModel.StatusPending, events[0].Status)
+	var created articleOutboxPayload
+	s.NoError(json.Unmarshal([]byte(events[0].Payload), &created))
+	s.Equal(a.Slug, created.Slug)
+	s.Equal(s.u1.ID, created.AuthorID)
+	s.Equal([]string{"tag1"}, created.TagList)
+	s.Equal(model.ArticleStatusPublished, created.Status)
+
+	s.Equal(event.TypeArticleUpdated, events[1].EventType)
+	var updated articleOutboxPayload
+	s.NoError(json.Unmarshal([]byte(events[1].Payload), &updated))
+	s.Equal("updated title", updated.Title)
+
+	s.Equal(event.TypeCommentCreated, events[2].EventType)
+	s.Equal(outboxModel.AggregateTypeComment, events[2].AggregateType)
+	s.Equal(c.ID, events[2].AggregateID)
+	var comment commentOutboxPayload
+	s.NoError(json.Unmarshal([]byte(events[2].Payload), &comment))
+	s.Equal(a.ID, comment.ArticleID)
+	s.Equal(s.u2.ID, comment.AuthorID)
+
+	for _, e := range events[1:] {
+		s.NotEqual(events[0].IdempotencyKey, e.IdempotencyKey)
+	}
+}
+
+func (s *Suite) TestOutboxEventsRollback() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	parentID := uint(999)
+	c := newComment("comment1", *s.u2, *a)
+	c.ParentID = &parentID
+
+	// when the comment is not saved
+	s.Error(s.db.SaveComment(context.TODO(), c))
+
+	// then
+	var count int64
+	s.NoError(s.originDB.Model(new(outboxModel.OutboxEvent)).Count(&count).Error)
+	s.EqualValues(1, count)
+}
@@ -8,7 +8,6 @@ import (
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"gorm.io/gorm"
-	"time"
 )
 
 func (adb *articleDB) FindBySlug(ctx context.Context, user *userModel.User, slug string) (*model2.Article, error) {
@@ -25,6 +24,11 @@ func (adb *articleDB) FindBySlug(ctx context.Context, user *userModel.User, slug
 		logger.Error("ArticleDB_FindBySlug failed to find an article", "slug", slug, "err", err)
 		return nil, database.WrapError(err)
 	}
+	// only the author can find articles which are not published.
+	if !article.IsPublished() && (user == nil || user.ID != article.AuthorID) {
+		logger.Debugw("ArticleDB_FindBySlug not published article", "slug", slug, "status", article.Status)
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
 	// load tags
 	if err := db.Model(&article).Association("Tags").Find(&article.Tags); err != nil {
 		logger.Error("ArticleDB_FindBySlug failed to fetch tags", "slug", slug, "err", err)
@@ -35,6 +39,11 @@ func (adb *articleDB) FindBySlug(ctx context.Context, user *userModel.User, slug
 		logger.Error("ArticleDB_FindBySlug failed to fetch favorites count", "articleID", article.ID, "err", err)
 		return nil, database.WrapError(err)
 	}
+	// set comments count
+	if err := setCommentsCountBulk(db, []*model2.Article{&article}); err != nil {
+		logger.Error("ArticleDB_FindBySlug failed to fetch comments count", "articleID", article.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
 	if user != nil {
 		// set favorited from current user
 		if err := setFavorited(db, user, &article); err != nil {
@@ -42,10 +51,31 @@ func (adb *articleDB) FindBySlug(ctx context.Context, user *userModel.User, slug
 			return nil, database.WrapError(err)
 		}
 	}
+	// set reactions
+	if err := setArticleReactionsBulk(db, user, []*model2.Article{&article}); err != nil {
+		logger.Error("ArticleDB_FindBySlug failed to fetch reactions", "articleID", article.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
 	return &article, nil
 }
 
 func (adb *articleDB) FindArticlesByQuery(ctx context.Context, user *userModel.User, query model2.ArticleQuery, offset, limit int) (*model2.Articles, error) {
+	return adb.findArticlesByQuery(ctx, user, query, nil, offset, limit)
+}
+
+func (adb *articleDB) FindArticlesByQueryWithCursor(ctx context.Context, user *userModel.User, query model2.ArticleQuery, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error) {
+	return adb.findArticlesByQuery(ctx, user, query, cursor, 0, limit)
+}
+
+func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel.User, authors []uint, offset, limit int) (*model2.Articles, error) {
+	return adb.findArticlesByAuthors(ctx, user, authors, nil, offset, limit)
+}
+
+func (adb *articleDB) FindArticlesByAuthorsWithCursor(ctx context.Context, user *userModel.User, authors []uint, cursor *model2.ArticleCursor, limit int) (*model2.Articles, error) {
+	return adb.findArticlesByAuthors(ctx, user, authors, cursor, 0, limit)
+}
+
+func (adb *articleDB) findArticlesByQuery(ctx context.Context, user *userModel.User, query model2.ArticleQuery, cursor *model2.ArticleCursor, offset, limit int) (*model2.Articles, error) {
 	var (
 		logger = logging.FromContext(ctx)
 		userID = uint(0)
@@ -53,7 +83,7 @@ func (adb *articleDB) FindArticlesByQuery(ctx context.Context, user *userModel.U
 	if user != nil && user.ID != 0 {
 		userID = user.ID
 	}
-	logger.Debugw("ArticleDB_FindArticlesByQuery try to find recent articles", "userID", userID, "query", query, "offset", offset, "limit", limit)
+	logger.Debugw("ArticleDB_FindArticlesByQuery try to find recent articles", "userID", userID, "query", query, "cursor", cursor, "offset", offset, "limit", limit)
 
 	if limit <= 0 {
 		return &model2.Articles{
@@ -62,15 +92,16 @@ func (adb *articleDB) FindArticlesByQuery(ctx context.Context, user *userModel.U
 		}, nil
 	}
 
-	// find article ids from given query and offset, limit.
-	ids, err := articleIdsByQuery(ctx, adb.db, query, offset, limit)
+	// find article ids from given query and cursor or offset, limit.
+	// fetch one more id to check whether the next page exists or not.
+	ids, err := articleIdsByQuery(ctx, adb.db, userID, query, cursor, offset, limit+1)
 	if err != nil {
 		logger.Error("ArticleDB_FindArticlesByQuery failed to fetch article ids", "userID", userID, "query", query, "offset", offset, "limit", limit, "err", err)
 		return nil, database.WrapError(err)
 	}
 
 	// find total count from given query.
-	total, err := countArticleByQuery(ctx, adb.db, query)
+	total, err := countArticleByQuery(ctx, adb.db, userID, query)
 	if err != nil {
 		logger.Error("ArticleDB_FindArticlesByQuery failed to fetch total count", "userID", userID, "query", query, "err", err)
 		return nil, database.WrapError(err)
@@ -83,6 +114,10 @@ func (adb *articleDB) FindArticlesByQuery(ctx context.Context, user *userModel.U
 		}, nil
 	}
 
+	hasNext := len(ids) > limit
+	if hasNext {
+		ids = ids[:limit]
+	}
 	articles, err := articlesByIds(adb.db, ids)
 	if err != nil {
 		logger.Error("ArticleDB_FindArticlesByQuery failed to fetch articles with author and tags.", "userID", userID, "ids", ids, "err", err)
@@ -91,13 +126,10 @@ func (adb *articleDB) FindArticlesByQuery(ctx context.Context, user *userModel.U
 	if err := fillArticlesExtraData(adb.db, user, articles); err != nil {
 		logger.Error("ArticleDB_FindArticlesByQuery failed to update extra data", "userID", userID, "ids", ids, "err", err)
 	}
-	return &model2.Articles{
-		Articles:      articles,
-		ArticlesCount: total,
-	}, nil
+	return newArticles(articles, total, hasNext), nil
 }
 
-func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel.User, authors []uint, offset, limit int) (*model2.Articles, error) {
+func (adb *articleDB) findArticlesByAuthors(ctx context.Context, user *userModel.User, authors []uint, cursor *model2.ArticleCursor, offset, limit int) (*model2.Articles, error) {
 	var (
 		logger = logging.FromContext(ctx)
 		userID = uint(0)
@@ -105,7 +137,7 @@ func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel
 	if user != nil {
 		userID = user.ID
 	}
-	logger.Debugw("ArticleDB_FindArticlesByAuthors try to find feed articles", "userID", userID, "authors", authors, "offset", offset, "limit", limit)
+	logger.Debugw("ArticleDB_FindArticlesByAuthors try to find feed articles", "userID", userID, "authors", authors, "cursor", cursor, "offset", offset, "limit", limit)
 
 	if len(authors) == 0 || limit <= 0 {
 		return &model2.Articles{
@@ -114,8 +146,9 @@ func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel
 		}, nil
 	}
 
-	// find article ids from given author ids and offset, limit.
-	ids, err := articleIdsByAuthors(adb.db, authors, offset, limit)
+	// find article ids from given author ids and cursor or offset, limit.
+	// fetch one more id to check whether the next page exists or not.
+	ids, err := articleIdsByAuthors(adb.db, authors, cursor, offset, limit+1)
 	if err != nil {
 		logger.Error("ArticleDB_FindArticlesByAuthors failed to fetch article ids", "authors", authors, "offset", offset, "limit", limit, "err", err)
 		return nil, database.WrapError(err)
@@ -135,6 +168,10 @@ func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel
 		}, nil
 	}
 
+	hasNext := len(ids) > limit
+	if hasNext {
+		ids = ids[:limit]
+	}
 	articles, err := articlesByIds(adb.db, ids)
 	if err != nil {
 		logger.Error("ArticleDB_FindArticlesByAuthors failed to fetch articles with author and tags.", "userID", userID, "ids", ids, "err", err)
@@ -143,10 +180,19 @@ func (adb *articleDB) FindArticlesByAuthors(ctx context.Context, user *userModel
 	if err := fillArticlesExtraData(adb.db, user, articles); err != nil {
 		logger.Error("ArticleDB_FindArticlesByAuthors failed to update extra data", "userID", userID, "ids", ids, "err", err)
 	}
-	return &model2.Articles{
+	return newArticles(articles, total, hasNext), nil
+}
+
+// newArticles returns a new model.Articles with the next cursor positioned at the last article if hasNext is true.
+func newArticles(articles []*model2.Article, total int64, hasNext bool) *model2.Articles {
+	res := &model2.Articles{
 		Articles:      articles,
 		ArticlesCount: total,
-	}, nil
+	}
+	if hasNext && len(articles) != 0 {
+		res.NextCursor = model2.NewArticleCursor(articles[len(articles)-1])
+	}
+	return res
 }
 
 func fillArticlesExtraData(db *gorm.DB, user *userModel.User, articles []*model2.Article) error {
@@ -154,6 +200,14 @@ func fillArticlesExtraData(db *gorm.DB, user *userModel.User, articles []*model2
 	if err := setFavoriteCountBulk(db, articles); err != nil {
 		return err
 	}
+	// set comments count
+	if err := setCommentsCountBulk(db, articles); err != nil {
+		return err
+	}
+	// set reactions
+	if err := setArticleReactionsBulk(db, user, articles); err != nil {
+		return err
+	}
 	// set is favorited from given user to articles.
 	if user != nil {
 		if err := setFavoritedBulk(db, user, articles); err != nil {
@@ -205,6 +259,37 @@ func setFavoriteCountBulk(db *gorm.DB, articles []*model2.Article) error {
 	return nil
 }
 
+// setCommentsCountBulk sets CommentsCount field on each article. hidden comments are not counted.
+func setCommentsCountBulk(db *gorm.DB, articles []*model2.Article) error {
+	m := make(map[uint]*model2.Article)
+	ids := make([]uint, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+		m[a.ID] = a
+	}
+
+	type CommentsCount struct {
+		ArticleID     uint `gorm:"column:article_id"`
+		CommentsCount int  `gorm:"column:comments_count"`
+	}
+
+	var counts []*CommentsCount
+	if err := db.Model(new(model2.Comment)).
+		Where("article_id IN (?) AND hidden_at IS NULL", ids).
+		Group("article_id").
+		Select("article_id, count(comment_id) as comments_count").
+		Find(&counts).Error; err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		if a, ok := m[c.ArticleID]; ok {
+			a.CommentsCount = c.CommentsCount
+		}
+	}
+	return nil
+}
+
 // setFavorited sets given article's Favorited field
 func setFavorited(db *gorm.DB, user *userModel.User, article *model2.Article) error {
 	if user == nil {
@@ -248,25 +333,35 @@ func setFavoritedBulk(db *gorm.DB, user *userModel.User, articles []*model2.Arti
 	return nil
 }
 
-// articleIdsByQuery returns article ids from given query and offset, limit.
-func articleIdsByQuery(ctx context.Context, db *gorm.DB, query model2.ArticleQuery, offset, limit int) ([]uint, error) {
-	db = buildArticleQuery(ctx, db, query)
-	rows, err := db.Select("DISTINCT a.article_id, a.created_at").
+// articleSortedAt is an expression of the time ordering article listings which is the published time
+// or the created time for articles which are not published.
+const articleSortedAt = "COALESCE(a.published_at, a.created_at)"
+
+// articleIdsByQuery returns article ids from given query and cursor or offset, limit.
+// offset will be ignored if provide cursor.
+func articleIdsByQuery(ctx context.Context, db *gorm.DB, userID uint, query model2.ArticleQuery, cursor *model2.ArticleCursor, offset, limit int) ([]uint, error) {
+	db = buildArticleQuery(ctx, db, userID, query)
+	if cursor != nil {
+		db = db.Where(articleSortedAt+" < ? OR ("+articleSortedAt+" = ? AND a.article_id < ?)", cursor.PublishedAt, cursor.PublishedAt, cursor.ArticleID)
+	} else {
+		db = db.Offset(offset)
+	}
+	rows, err := db.Select("DISTINCT a.article_id, " + articleSortedAt + " AS sorted_at").
 		Where("a.deleted_at IS NULL").
-		Order("a.created_at DESC").
-		Offset(offset).
+		Order("sorted_at DESC, a.article_id DESC").
 		Limit(limit).
 		Rows()
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	var ids []uint
 	for rows.Next() {
 		var (
-			id        uint
-			createdAt time.Time
+			id       uint
+			sortedAt interface{}
 		)
-		if err := rows.Scan(&id, &createdAt); err != nil {
+		if err := rows.Scan(&id, &sortedAt); err != nil {
 			return nil, err
 		}
 		ids = append(ids, id)
@@ -274,14 +369,19 @@ func articleIdsByQuery(ctx context.Context, db *gorm.DB, query model2.ArticleQue
 	return ids, nil
 }
 
-// articleIdsByAuthors returns article ids from given authors and offset, limit.
-func articleIdsByAuthors(db *gorm.DB, authors []uint, offset, limit int) ([]uint, error) {
-	var ids []uint
-	if err := db.Model(new(model2.Article)).
+// articleIdsByAuthors returns article ids from given authors and cursor or offset, limit.
+// offset will be ignored if provide cursor.
+func articleIdsByAuthors(db *gorm.DB, authors []uint, cursor *model2.ArticleCursor, offset, limit int) ([]uint, error) {
+	db = db.Model(new(model2.Article)).
 		Select("article_id").
-		Where("author_id IN (?) AND deleted_at IS NULL", authors).
-		Order("created_at DESC").
-		Offset(offset).
+		Where("author_id IN (?) AND deleted_at IS NULL AND status = ?", authors, model2.ArticleStatusPublished)
+	if cursor != nil {
+		db = db.Where("published_at < ? OR (published_at = ? AND article_id < ?)", cursor.PublishedAt, cursor.PublishedAt, cursor.ArticleID)
+	} else {
+		db = db.Offset(offset)
+	}
+	var ids []uint
+	if err := db.Order("published_at DESC, article_id DESC").
 		Limit(limit).
 		Find(&ids).Error; err != nil {
 		return nil, err
@@ -289,30 +389,38 @@ func articleIdsByAuthors(db *gorm.DB, authors []uint, offset, limit int) ([]uint
 	return ids, nil
 }
 
-func countArticleByQuery(ctx context.Context, db *gorm.DB, query model2.ArticleQuery) (int64, error) {
-	db = buildArticleQuery(ctx, db, query)
+func countArticleByQuery(ctx context.Context, db *gorm.DB, userID uint, query model2.ArticleQuery) (int64, error) {
+	db = buildArticleQuery(ctx, db, userID, query)
 	var count int64
 	return count, db.Distinct("a.article_id").Count(&count).Error
 }
 
 func countArticleByAuthors(db *gorm.DB, authors []uint) (int64, error) {
 	var count int64
-	return count, db.Model(new(model2.Article)).Where("deleted_at IS NULL AND author_id IN (?)", authors).Count(&count).Error
+	return count, db.Model(new(model2.Article)).
+		Where("deleted_at IS NULL AND status = ? AND author_id IN (?)", model2.ArticleStatusPublished, authors).
+		Count(&count).Error
 }
 
-func buildArticleQuery(ctx context.Context, db *gorm.DB, query model2.ArticleQuery) *gorm.DB {
+// buildArticleQuery returns a gorm.DB of articles matched by given query.
+// articles which are not published are only included if the author is given userID and
+// articles of authors muted by given userID are excluded unless the author is given in the query.
+func buildArticleQuery(ctx context.Context, db *gorm.DB, userID uint, query model2.ArticleQuery) *gorm.DB {
 	db = db.WithContext(ctx).Table("articles a").
 		Joins("LEFT JOIN article_tags at ON at.article_id = a.article_id").
 		Joins("LEFT JOIN tags t ON t.tag_id = at.tag_id").
 		Joins("LEFT JOIN article_favorites af ON af.article_id = a.article_id").
 		Joins("LEFT JOIN users u ON u.user_id = a.author_id").
 		Joins("LEFT JOIN users uf ON uf.user_id = af.user_id").
-		Where("a.deleted_at IS NULL")
+		Where("a.deleted_at IS NULL").
+		Where("a.status = ? OR a.author_id = ?", model2.ArticleStatusPublished, userID)
 	if query.Tag != "" {
 		db = db.Where("t.name = ?", query.Tag)
 	}
 	if query.Author != "" {
 		db = db.Where("u.name = ?", query.Author)
+	} else if userID != 0 {
+		db = db.Where("a.author_id NOT IN (SELECT m.mute_id FROM mutes m WHERE m.user_id = ?)", userID)
 	}
 	if query.FavoritedBy != "" {
 		db = db.Where("uf.name = ?", query.FavoritedBy)
@@ -331,7 +439,7 @@ func articlesByIds(db *gorm.DB, ids []uint) ([]*model2.Article, error) {
 	if err := db.Model(new(model2.Article)).
 		Joins("Author").
 		Where("articles.article_id IN (?)", ids).
-		Order("articles.created_at DESC").
+		Order("COALESCE(articles.published_at, articles.created_at) DESC, articles.article_id DESC").
 		Find(&articles).Error; err != nil {
 		return nil, err
 	}
@@ -373,3 +481,47 @@ func articlesByIds(db *gorm.DB, ids []uint) ([]*model2.Article, error) {
 	}
 	return articles, nil
 }
+
+func (adb *articleDB) CountContentsByAuthor(ctx context.Context, userID uint) (*model2.ContentCounts, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_CountContentsByAuthor try to count contents", "userID", userID)
+
+	var (
+		counts model2.ContentCounts
+		db     = adb.db.WithContext(ctx)
+	)
+	queries := []struct {
+		name  string
+		count *int64
+		query *gorm.DB
+	}{
+		{
+			name:  "articles",
+			count: &counts.Articles,
+			query: db.Model(new(model2.Article)).Where("author_id = ? AND status = ?", userID, model2.ArticleStatusPublished),
+		}, {
+			name:  "drafts",
+			count: &counts.Drafts,
+			query: db.Model(new(model2.Article)).Where("author_id = ? AND status <> ?", userID, model2.ArticleStatusPublished),
+		}, {
+			name:  "deleted articles",
+			count: &counts.DeletedArticles,
+			query: db.Unscoped().Model(new(model2.Article)).Where("author_id = ? AND deleted_at IS NOT NULL", userID),
+		}, {
+			name:  "comments",
+			count: &counts.Comments,
+			query: db.Model(new(model2.Comment)).Where("author_id = ?", userID),
+		}, {
+			name:  "favorites",
+			count: &counts.Favorites,
+			query: db.Model(new(model2.ArticleFavorite)).Where("user_id = ?", userID),
+		},
+	}
+	for _, q := range queries {
+		if err := q.query.Count(q.count).Error; err != nil {
+			logger.Errorw("ArticleDB_CountContentsByAuthor failed to count "+q.name, "userID", userID, "err", err)
+			return nil, database.WrapError(err)
+		}
+	}
+	return &counts, nil
+}
@@ -45,7 +45,7 @@ func (s *QuerySuite) SetupSuite() {
 	fixtures, err := testfixtures.New(
 		testfixtures.Database(sqlDB),
 		testfixtures.DangerousSkipTestDatabaseCheck(), // disable to check contains "test" in database
-		testfixtures.Dialect("mysql"),
+		testfixtures.Dialect(s.originDB.Dialector.Name()),
 		testfixtures.Files(
 			"fixtures/users.yaml",
 			"fixtures/tags.yaml",
@@ -180,6 +180,117 @@ func (s *QuerySuite) TestFindArticlesByAuthors() {
 	assertArticleExtraFields(s.T(), articles.Articles[0], "tag1 tag3", true, 2)
 }
 
+func (s *QuerySuite) TestFindArticlesByQueryWithCursor() {
+	var (
+		user  = s.users[2]
+		query = model2.ArticleQuery{
+			Tag:         "tag1",
+			Author:      "user1",
+			FavoritedBy: "user2",
+		}
+		limit = 2
+	)
+	offsetArticles, err := s.db.FindArticlesByQuery(context.TODO(), user, query, 0, 4)
+	s.NoError(err)
+	s.Len(offsetArticles.Articles, 4)
+	s.Nil(offsetArticles.NextCursor)
+
+	// first iteration
+	articles, err := s.db.FindArticlesByQueryWithCursor(context.TODO(), user, query, nil, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 2)
+	s.EqualValues(articles.ArticlesCount, 4)
+	s.Equal(offsetArticles.Articles[0].ID, articles.Articles[0].ID)
+	s.Equal(offsetArticles.Articles[1].ID, articles.Articles[1].ID)
+	s.NotNil(articles.NextCursor)
+	s.Equal(articles.Articles[1].ID, articles.NextCursor.ArticleID)
+
+	// second iteration
+	articles, err = s.db.FindArticlesByQueryWithCursor(context.TODO(), user, query, articles.NextCursor, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 2)
+	s.EqualValues(articles.ArticlesCount, 4)
+	s.Equal(offsetArticles.Articles[2].ID, articles.Articles[0].ID)
+	s.Equal(offsetArticles.Articles[3].ID, articles.Articles[1].ID)
+	assertArticleExtraFields(s.T(), articles.Articles[1], "tag1 tag2", true, 2)
+	s.Nil(articles.NextCursor)
+}
+
+func (s *QuerySuite) TestFindArticlesByAuthorsWithCursor() {
+	var (
+		user    = s.users[0]
+		authors = []uint{s.users[1].ID, s.users[2].ID} // user2, user3
+		limit   = 2
+	)
+
+	// first iteration
+	articles, err := s.db.FindArticlesByAuthorsWithCursor(context.TODO(), user, authors, nil, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 2)
+	s.EqualValues(articles.ArticlesCount, 3)
+	assertArticleExtraFields(s.T(), articles.Articles[0], "tag3", true, 2)
+	assertArticleExtraFields(s.T(), articles.Articles[1], "tag2", false, 1)
+	s.NotNil(articles.NextCursor)
+
+	// second iteration
+	articles, err = s.db.FindArticlesByAuthorsWithCursor(context.TODO(), user, authors, articles.NextCursor, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.EqualValues(articles.ArticlesCount, 3)
+	assertArticleTableValues(s.T(), s.originDB, articles.Articles[0])
+	assertArticleExtraFields(s.T(), articles.Articles[0], "tag1 tag3", true, 2)
+	s.Nil(articles.NextCursor)
+}
+
+func (s *QuerySuite) TestIsFavorited() {
+	// article1 is favorited by user2, user3
+	favorited, err := s.db.(ArticleDB).IsFavorited(context.TODO(), s.users[1], 1)
+	s.NoError(err)
+	s.True(favorited)
+
+	favorited, err = s.db.(ArticleDB).IsFavorited(context.TODO(), s.users[0], 1)
+	s.NoError(err)
+	s.False(favorited)
+
+	favorited, err = s.db.(ArticleDB).IsFavorited(context.TODO(), nil, 1)
+	s.NoError(err)
+	s.False(favorited)
+}
+
+func (s *QuerySuite) TestSearchArticles() {
+	var (
+		user  = s.users[0]
+		limit = 1
+	)
+
+	// exact word
+	articles, err := s.db.SearchArticles(context.TODO(), user, "user2article1", 0, 10)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.EqualValues(1, articles.ArticlesCount)
+	assertArticleTableValues(s.T(), s.originDB, articles.Articles[0])
+	assertArticleExtraFields(s.T(), articles.Articles[0], "tag1 tag3", true, 2)
+
+	// prefix with pagination
+	articles, err = s.db.SearchArticles(context.TODO(), user, "user2article", 0, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.EqualValues(2, articles.ArticlesCount)
+	first := articles.Articles[0].ID
+
+	articles, err = s.db.SearchArticles(context.TODO(), user, "user2article", limit, limit)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.NotEqual(first, articles.Articles[0].ID)
+
+	// no matches
+	articles, err = s.db.SearchArticles(context.TODO(), user, "notexistword", 0, limit)
+	s.NoError(err)
+	s.Empty(articles.Articles)
+	s.EqualValues(0, articles.ArticlesCount)
+}
+
 func assertArticleTableValues(t *testing.T, db *gorm.DB, a *model2.Article) {
 	var find model2.Article
 	assert.NoError(t, db.First(&find, "article_id = ?", a.ID).Error)
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+)
+
+type ReactionDB interface {
+	// AddReaction saves a reaction of given user with given kind to the target.
+	// target type is one of model.ReactionTargetArticle and model.ReactionTargetComment.
+	// database.ErrKeyConflict will be returned if already reacted with the kind.
+	AddReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error
+
+	// RemoveReaction deletes a reaction of given user with given kind to the target.
+	// database.ErrRecordNotFound will be returned if zero row affected.
+	RemoveReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error
+
+	// FindUserReactions returns reaction kinds of given user to each target id.
+	FindUserReactions(ctx context.Context, user *userModel.User, targetType string, targetIDs []uint) (map[uint][]string, error)
+}
+
+func (adb *articleDB) AddReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_AddReaction no user")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger = logger.With("userID", user.ID, "targetType", targetType, "targetID", targetID, "kind", kind)
+	logger.Debug("ArticleDB_AddReaction try to add a reaction")
+
+	r := model2.Reaction{
+		UserID:     user.ID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Kind:       kind,
+	}
+	if err := adb.db.WithContext(ctx).Create(&r).Error; err != nil {
+		logger.Errorw("ArticleDB_AddReaction failed to add a reaction", "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (adb *articleDB) RemoveReaction(ctx context.Context, user *userModel.User, targetType string, targetID uint, kind string) error {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_RemoveReaction no user")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger = logger.With("userID", user.ID, "targetType", targetType, "targetID", targetID, "kind", kind)
+	logger.Debug("ArticleDB_RemoveReaction try to remove a reaction")
+
+	result := adb.db.WithContext(ctx).
+		Where("user_id = ? AND target_type = ? AND target_id = ? AND kind = ?", user.ID, targetType, targetID, kind).
+		Delete(new(model2.Reaction))
+	if result.Error != nil {
+		logger.Errorw("ArticleDB_RemoveReaction failed to remove a reaction", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("ArticleDB_RemoveReaction failed to remove a reaction. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (adb *articleDB) FindUserReactions(ctx context.Context, user *userModel.User, targetType string, targetIDs []uint) (map[uint][]string, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil || len(targetIDs) == 0 {
+		return make(map[uint][]string), nil
+	}
+	logger.Debugw("ArticleDB_FindUserReactions try to find reactions", "userID", user.ID, "targetType", targetType, "targetIDs", targetIDs)
+
+	reactions, err := userReactions(adb.db.WithContext(ctx), user, targetType, targetIDs)
+	if err != nil {
+		logger.Errorw("ArticleDB_FindUserReactions failed to find reactions", "userID", user.ID, "targetType", targetType, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return reactions, nil
+}
+
+// userReactions returns reaction kinds of given user to each target id.
+func userReactions(db *gorm.DB, user *userModel.User, targetType string, targetIDs []uint) (map[uint][]string, error) {
+	var reactions []*model2.Reaction
+	if err := db.Model(new(model2.Reaction)).
+		Where("user_id = ? AND target_type = ? AND target_id IN (?)", user.ID, targetType, targetIDs).
+		Order("created_at ASC").
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+	m := make(map[uint][]string)
+	for _, r := range reactions {
+		m[r.TargetID] = append(m[r.TargetID], r.Kind)
+	}
+	return m, nil
+}
+
+// reactionCounts returns the number of reactions by kind to each target id.
+func reactionCounts(db *gorm.DB, targetType string, targetIDs []uint) (map[uint]map[string]int, error) {
+	type ReactionCount struct {
+		TargetID uint   `gorm:"column:target_id"`
+		Kind     string `gorm:"column:kind"`
+		Count    int    `gorm:"column:reactions_count"`
+	}
+
+	var counts []*ReactionCount
+	if err := db.Model(new(model2.Reaction)).
+		Where("target_type = ? AND target_id IN (?)", targetType, targetIDs).
+		Group("target_id, kind").
+		Select("target_id, kind, count(user_id) as reactions_count").
+		Find(&counts).Error; err != nil {
+		return nil, err
+	}
+	m := make(map[uint]map[string]int)
+	for _, c := range counts {
+		if _, ok := m[c.TargetID]; !ok {
+			m[c.TargetID] = make(map[string]int)
+		}
+		m[c.TargetID][c.Kind] = c.Count
+	}
+	return m, nil
+}
+
+// setArticleReactionsBulk sets Reactions field on each article and UserReactions field if provide user.
+func setArticleReactionsBulk(db *gorm.DB, user *userModel.User, articles []*model2.Article) error {
+	ids := make([]uint, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	counts, err := reactionCounts(db, model2.ReactionTargetArticle, ids)
+	if err != nil {
+		return err
+	}
+	var reactions map[uint][]string
+	if user != nil {
+		if reactions, err = userReactions(db, user, model2.ReactionTargetArticle, ids); err != nil {
+			return err
+		}
+	}
+	for _, a := range articles {
+		a.Reactions = counts[a.ID]
+		a.UserReactions = reactions[a.ID]
+	}
+	return nil
+}
+
+// setCommentReactionCountsBulk sets Reactions field on each comment.
+func setCommentReactionCountsBulk(db *gorm.DB, comments []*model2.Comment) error {
+	counts, err := reactionCounts(db, model2.ReactionTargetComment, commentIds(comments))
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		c.Reactions = counts[c.ID]
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"time"
+)
+
+func (s *Suite) TestArticleReactions() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+
+	// when
+	s.NoError(s.db.AddReaction(context.TODO(), s.u1, model.ReactionTargetArticle, a.ID, "heart"))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u1, model.ReactionTargetArticle, a.ID, "+1"))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u2, model.ReactionTargetArticle, a.ID, "heart"))
+
+	// then
+	find, err := s.db.FindBySlug(context.TODO(), s.u1, a.Slug)
+	s.NoError(err)
+	s.Equal(map[string]int{"heart": 2, "+1": 1}, find.Reactions)
+	s.ElementsMatch([]string{"heart", "+1"}, find.UserReactions)
+
+	articles, err := s.db.FindArticlesByQuery(context.TODO(), s.u2, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+	s.Equal(map[string]int{"heart": 2, "+1": 1}, articles.Articles[0].Reactions)
+	s.Equal([]string{"heart"}, articles.Articles[0].UserReactions)
+
+	// remove a reaction
+	s.NoError(s.db.RemoveReaction(context.TODO(), s.u1, model.ReactionTargetArticle, a.ID, "heart"))
+	find, err = s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.NoError(err)
+	s.Equal(map[string]int{"heart": 1, "+1": 1}, find.Reactions)
+	s.Empty(find.UserReactions)
+}
+
+func (s *Suite) TestReactionsFail() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u1, model.ReactionTargetArticle, a.ID, "heart"))
+
+	// duplicate
+	err := s.db.AddReaction(context.TODO(), s.u1, model.ReactionTargetArticle, a.ID, "heart")
+	s.Equal(database.ErrKeyConflict, err)
+
+	// not found
+	err = s.db.RemoveReaction(context.TODO(), s.u2, model.ReactionTargetArticle, a.ID, "heart")
+	s.Equal(database.ErrRecordNotFound, err)
+	err = s.db.RemoveReaction(context.TODO(), s.u1, model.ReactionTargetComment, a.ID, "heart")
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// no user
+	err = s.db.AddReaction(context.TODO(), nil, model.ReactionTargetArticle, a.ID, "heart")
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestCommentReactions() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c1 := newComment("comment1", *s.u1, *a)
+	c2 := newComment("comment2", *s.u1, *a)
+	for _, c := range []*model.Comment{c1, c2} {
+		s.NoError(s.db.SaveComment(context.TODO(), c))
+	}
+	s.NoError(s.db.AddReaction(context.TODO(), s.u1, model.ReactionTargetComment, c1.ID, "laugh"))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u2, model.ReactionTargetComment, c1.ID, "laugh"))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u2, model.ReactionTargetComment, c2.ID, "eyes"))
+
+	// counts
+	comments, err := s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderOldest, nil, 10)
+	s.NoError(err)
+	s.Len(comments.Comments, 2)
+	s.Equal(map[string]int{"laugh": 2}, comments.Comments[0].Reactions)
+	s.Equal(map[string]int{"eyes": 1}, comments.Comments[1].Reactions)
+	find, err := s.db.FindCommentByID(context.TODO(), a.ID, c1.ID)
+	s.NoError(err)
+	s.Equal(map[string]int{"laugh": 2}, find.Reactions)
+
+	// user reactions
+	reactions, err := s.db.FindUserReactions(context.TODO(), s.u2, model.ReactionTargetComment, []uint{c1.ID, c2.ID})
+	s.NoError(err)
+	s.Equal(map[uint][]string{c1.ID: {"laugh"}, c2.ID: {"eyes"}}, reactions)
+	reactions, err = s.db.FindUserReactions(context.TODO(), nil, model.ReactionTargetComment, []uint{c1.ID, c2.ID})
+	s.NoError(err)
+	s.Empty(reactions)
+}
+
+func (s *Suite) TestPurgeDeletedWithReactions() {
+	now := time.Now()
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u2, model.ReactionTargetArticle, a.ID, "heart"))
+	s.NoError(s.db.AddReaction(context.TODO(), s.u2, model.ReactionTargetComment, c.ID, "heart"))
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", a.ID).Update("deleted_at", now.Add(-48*time.Hour)).Error)
+
+	purged, err := s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+
+	s.NoError(err)
+	s.EqualValues(1, purged)
+	var count int64
+	s.NoError(s.originDB.Model(new(model.Reaction)).Count(&count).Error)
+	s.Zero(count)
+}
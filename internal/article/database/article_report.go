@@ -0,0 +1,247 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ReportDB interface {
+	// SaveReport saves a given pending report r and hides the target if the number of pending reports
+	// to the target reaches given threshold. The target is never hidden if threshold is less than or equal to 0.
+	// returns true if the target is hidden by this report.
+	// database.ErrKeyConflict will be returned if the reporter already reported the target.
+	SaveReport(ctx context.Context, r *model2.Report, threshold int) (bool, error)
+
+	// FindReports returns ([]*model.Report, total count, error) matched by given query.
+	// reports are ordered by created time descending and each reports contains Reporter and ArticleSlug.
+	FindReports(ctx context.Context, query model2.ReportQuery, offset, limit int) (*model2.Reports, error)
+
+	// FindReportByID returns a model.Report with Reporter and ArticleSlug.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindReportByID(ctx context.Context, reportID uint) (*model2.Report, error)
+
+	// ResolveReports marks pending reports to the target of given report r as resolved by given user
+	// and hides the target. returns the number of resolved reports.
+	ResolveReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error)
+
+	// DismissReports marks pending reports to the target of given report r as dismissed by given user
+	// and restores the target if hidden. returns the number of dismissed reports.
+	DismissReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error)
+}
+
+func (adb *articleDB) SaveReport(ctx context.Context, r *model2.Report, threshold int) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger = logger.With("reporterID", r.ReporterID, "targetType", r.TargetType, "targetID", r.TargetID)
+	logger.Debug("ArticleDB_SaveReport try to save a report")
+
+	hidden := false
+	r.Status = model2.ReportStatusPending
+	if err := database.RunInTx(ctx, adb.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		if err := txDb.WithContext(ctx).Create(r).Error; err != nil {
+			return err
+		}
+		if threshold <= 0 {
+			return nil
+		}
+		var count int64
+		if err := txDb.WithContext(ctx).Model(new(model2.Report)).
+			Where("target_type = ? AND target_id = ? AND status = ?", r.TargetType, r.TargetID, model2.ReportStatusPending).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count < int64(threshold) {
+			return nil
+		}
+		affected, err := hideReportTarget(txDb.WithContext(ctx), r.TargetType, r.TargetID, time.Now())
+		if err != nil {
+			return err
+		}
+		hidden = affected != 0
+		return nil
+	}); err != nil {
+		logger.Errorw("ArticleDB_SaveReport failed to save a report", "err", err)
+		return false, database.WrapError(err)
+	}
+	return hidden, nil
+}
+
+func (adb *articleDB) FindReports(ctx context.Context, query model2.ReportQuery, offset, limit int) (*model2.Reports, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_FindReports try to find reports", "query", query, "offset", offset, "limit", limit)
+
+	db := adb.db.WithContext(ctx).Model(new(model2.Report))
+	if query.Status != "" {
+		db = db.Where("reports.status = ?", query.Status)
+	}
+	if query.TargetType != "" {
+		db = db.Where("reports.target_type = ?", query.TargetType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		logger.Errorw("ArticleDB_FindReports failed to count reports", "err", err)
+		return nil, database.WrapError(err)
+	}
+	var reports []*model2.Report
+	if err := db.Joins("Reporter").
+		Order("reports.created_at DESC, reports.report_id DESC").
+		Offset(offset).Limit(limit).
+		Find(&reports).Error; err != nil {
+		logger.Errorw("ArticleDB_FindReports failed to find reports", "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := setReportArticleSlugsBulk(adb.db.WithContext(ctx), reports); err != nil {
+		logger.Errorw("ArticleDB_FindReports failed to fetch article slugs", "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &model2.Reports{
+		Reports:      reports,
+		ReportsCount: total,
+	}, nil
+}
+
+func (adb *articleDB) FindReportByID(ctx context.Context, reportID uint) (*model2.Report, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_FindReportByID try to find a report", "reportID", reportID)
+
+	var report model2.Report
+	if err := adb.db.WithContext(ctx).Model(new(model2.Report)).
+		Joins("Reporter").
+		Where("reports.report_id = ?", reportID).
+		First(&report).Error; err != nil {
+		logger.Errorw("ArticleDB_FindReportByID failed to find a report", "reportID", reportID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := setReportArticleSlugsBulk(adb.db.WithContext(ctx), []*model2.Report{&report}); err != nil {
+		logger.Errorw("ArticleDB_FindReportByID failed to fetch an article slug", "reportID", reportID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &report, nil
+}
+
+func (adb *articleDB) ResolveReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_ResolveReports no user")
+		return 0, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger = logger.With("userID", user.ID, "targetType", r.TargetType, "targetID", r.TargetID)
+	logger.Debug("ArticleDB_ResolveReports try to resolve reports")
+
+	resolved, err := adb.closeReports(ctx, user, r, model2.ReportStatusResolved, func(db *gorm.DB, now time.Time) error {
+		_, err := hideReportTarget(db, r.TargetType, r.TargetID, now)
+		return err
+	})
+	if err != nil {
+		logger.Errorw("ArticleDB_ResolveReports failed to resolve reports", "err", err)
+		return 0, database.WrapError(err)
+	}
+	return resolved, nil
+}
+
+func (adb *articleDB) DismissReports(ctx context.Context, user *userModel.User, r *model2.Report) (int64, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_DismissReports no user")
+		return 0, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger = logger.With("userID", user.ID, "targetType", r.TargetType, "targetID", r.TargetID)
+	logger.Debug("ArticleDB_DismissReports try to dismiss reports")
+
+	dismissed, err := adb.closeReports(ctx, user, r, model2.ReportStatusDismissed, func(db *gorm.DB, _ time.Time) error {
+		return restoreReportTarget(db, r.TargetType, r.TargetID)
+	})
+	if err != nil {
+		logger.Errorw("ArticleDB_DismissReports failed to dismiss reports", "err", err)
+		return 0, database.WrapError(err)
+	}
+	return dismissed, nil
+}
+
+// closeReports changes pending reports to the target of given report r to given status and
+// calls updateTarget in a transaction. returns the number of closed reports.
+func (adb *articleDB) closeReports(ctx context.Context, user *userModel.User, r *model2.Report, status string,
+	updateTarget func(db *gorm.DB, now time.Time) error) (int64, error) {
+	var (
+		closed int64
+		now    = time.Now()
+	)
+	err := database.RunInTx(ctx, adb.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		result := txDb.WithContext(ctx).Model(new(model2.Report)).
+			Where("target_type = ? AND target_id = ? AND status = ?", r.TargetType, r.TargetID, model2.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"resolver_id": user.ID,
+				"resolved_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		closed = result.RowsAffected
+		return updateTarget(txDb.WithContext(ctx), now)
+	})
+	return closed, err
+}
+
+// hideReportTarget hides a published article or a comment not hidden matched by given target.
+// returns the number of hidden rows.
+func hideReportTarget(db *gorm.DB, targetType string, targetID uint, now time.Time) (int64, error) {
+	var result *gorm.DB
+	switch targetType {
+	case model2.ReportTargetArticle:
+		result = db.Model(new(model2.Article)).
+			Where("article_id = ? AND status = ?", targetID, model2.ArticleStatusPublished).
+			UpdateColumn("status", model2.ArticleStatusHidden)
+	default:
+		result = db.Model(new(model2.Comment)).
+			Where("comment_id = ? AND hidden_at IS NULL", targetID).
+			UpdateColumn("hidden_at", now)
+	}
+	return result.RowsAffected, result.Error
+}
+
+// restoreReportTarget restores a hidden article or a hidden comment matched by given target.
+func restoreReportTarget(db *gorm.DB, targetType string, targetID uint) error {
+	switch targetType {
+	case model2.ReportTargetArticle:
+		return db.Model(new(model2.Article)).
+			Where("article_id = ? AND status = ?", targetID, model2.ArticleStatusHidden).
+			UpdateColumn("status", model2.ArticleStatusPublished).Error
+	default:
+		return db.Model(new(model2.Comment)).
+			Where("comment_id = ? AND hidden_at IS NOT NULL", targetID).
+			UpdateColumn("hidden_at", nil).Error
+	}
+}
+
+// setReportArticleSlugsBulk sets ArticleSlug field on each report. deleted articles are included.
+func setReportArticleSlugsBulk(db *gorm.DB, reports []*model2.Report) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(reports))
+	for i, r := range reports {
+		ids[i] = r.ArticleID
+	}
+	var articles []*model2.Article
+	if err := db.Unscoped().Model(new(model2.Article)).
+		Select("article_id, slug").
+		Where("article_id IN (?)", ids).
+		Find(&articles).Error; err != nil {
+		return err
+	}
+	slugs := make(map[uint]string)
+	for _, a := range articles {
+		slugs[a.ID] = a.Slug
+	}
+	for _, r := range reports {
+		r.ArticleSlug = slugs[r.ArticleID]
+	}
+	return nil
+}
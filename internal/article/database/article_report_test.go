@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+)
+
+func (s *Suite) TestSaveReport() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+
+	// when
+	hidden, err := s.db.SaveReport(context.TODO(), newReport(*s.u2, model.ReportTargetArticle, a.ID, a.ID), 2)
+
+	// then
+	s.NoError(err)
+	s.False(hidden)
+	find, err := s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.NoError(err)
+	s.True(find.IsPublished())
+
+	// duplicate
+	_, err = s.db.SaveReport(context.TODO(), newReport(*s.u2, model.ReportTargetArticle, a.ID, a.ID), 2)
+	s.Equal(database.ErrKeyConflict, err)
+
+	// reaches the threshold
+	hidden, err = s.db.SaveReport(context.TODO(), newReport(*s.u3, model.ReportTargetArticle, a.ID, a.ID), 2)
+	s.NoError(err)
+	s.True(hidden)
+	_, err = s.db.FindBySlug(context.TODO(), s.u2, a.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+	find, err = s.db.FindBySlug(context.TODO(), s.u1, a.Slug)
+	s.NoError(err)
+	s.True(find.IsHidden())
+}
+
+func (s *Suite) TestSaveReport_Comment() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c1 := newComment("comment1", *s.u1, *a)
+	c2 := newComment("comment2", *s.u1, *a)
+	for _, c := range []*model.Comment{c1, c2} {
+		s.NoError(s.db.SaveComment(context.TODO(), c))
+	}
+
+	// when
+	hidden, err := s.db.SaveReport(context.TODO(), newReport(*s.u2, model.ReportTargetComment, c1.ID, a.ID), 1)
+
+	// then
+	s.NoError(err)
+	s.True(hidden)
+	comments, err := s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderOldest, nil, 10)
+	s.NoError(err)
+	s.EqualValues(1, comments.CommentsCount)
+	s.Len(comments.Comments, 1)
+	s.Equal(c2.ID, comments.Comments[0].ID)
+	find, err := s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.NoError(err)
+	s.Equal(1, find.CommentsCount)
+
+	// no threshold
+	hidden, err = s.db.SaveReport(context.TODO(), newReport(*s.u2, model.ReportTargetComment, c2.ID, a.ID), 0)
+	s.NoError(err)
+	s.False(hidden)
+}
+
+func (s *Suite) TestFindReports() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+	r1 := newReport(*s.u2, model.ReportTargetArticle, a.ID, a.ID)
+	r2 := newReport(*s.u2, model.ReportTargetComment, c.ID, a.ID)
+	r3 := newReport(*s.u3, model.ReportTargetComment, c.ID, a.ID)
+	for _, r := range []*model.Report{r1, r2, r3} {
+		_, err := s.db.SaveReport(context.TODO(), r, 0)
+		s.NoError(err)
+	}
+	_, err := s.db.DismissReports(context.TODO(), s.u3, r1)
+	s.NoError(err)
+
+	// when
+	all, err1 := s.db.FindReports(context.TODO(), model.ReportQuery{}, 0, 10)
+	pending, err2 := s.db.FindReports(context.TODO(), model.ReportQuery{Status: model.ReportStatusPending}, 0, 1)
+	articles, err3 := s.db.FindReports(context.TODO(), model.ReportQuery{TargetType: model.ReportTargetArticle}, 0, 10)
+
+	// then
+	s.NoError(err1)
+	s.EqualValues(3, all.ReportsCount)
+	s.Len(all.Reports, 3)
+	s.Equal(r3.ID, all.Reports[0].ID)
+	s.Equal(s.u3.Name, all.Reports[0].Reporter.Name)
+	s.Equal(a.Slug, all.Reports[0].ArticleSlug)
+
+	s.NoError(err2)
+	s.EqualValues(2, pending.ReportsCount)
+	s.Len(pending.Reports, 1)
+
+	s.NoError(err3)
+	s.EqualValues(1, articles.ReportsCount)
+	s.Equal(model.ReportStatusDismissed, articles.Reports[0].Status)
+	s.Equal(s.u3.ID, *articles.Reports[0].ResolverID)
+	s.NotNil(articles.Reports[0].ResolvedAt)
+
+	find, err := s.db.FindReportByID(context.TODO(), r2.ID)
+	s.NoError(err)
+	s.Equal(s.u2.Name, find.Reporter.Name)
+	s.Equal(a.Slug, find.ArticleSlug)
+	s.Equal("spam", find.Reason)
+	_, err = s.db.FindReportByID(context.TODO(), r3.ID+100)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestResolveAndDismissReports() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	r1 := newReport(*s.u2, model.ReportTargetArticle, a.ID, a.ID)
+	r2 := newReport(*s.u3, model.ReportTargetArticle, a.ID, a.ID)
+	for _, r := range []*model.Report{r1, r2} {
+		_, err := s.db.SaveReport(context.TODO(), r, 0)
+		s.NoError(err)
+	}
+
+	// resolve
+	resolved, err := s.db.ResolveReports(context.TODO(), s.u3, r1)
+	s.NoError(err)
+	s.EqualValues(2, resolved)
+	find, err := s.db.FindBySlug(context.TODO(), s.u1, a.Slug)
+	s.NoError(err)
+	s.True(find.IsHidden())
+
+	// dismiss restores the article even if no pending reports
+	dismissed, err := s.db.DismissReports(context.TODO(), s.u3, r1)
+	s.NoError(err)
+	s.Zero(dismissed)
+	find, err = s.db.FindBySlug(context.TODO(), s.u2, a.Slug)
+	s.NoError(err)
+	s.True(find.IsPublished())
+
+	// draft articles are not hidden
+	a.Status = model.ArticleStatusDraft
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+	_, err = s.db.ResolveReports(context.TODO(), s.u3, r1)
+	s.NoError(err)
+	find, err = s.db.FindBySlug(context.TODO(), s.u1, a.Slug)
+	s.NoError(err)
+	s.Equal(model.ArticleStatusDraft, find.Status)
+
+	// no user
+	_, err = s.db.ResolveReports(context.TODO(), nil, r1)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestDismissReports_Comment() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+	r := newReport(*s.u2, model.ReportTargetComment, c.ID, a.ID)
+	hidden, err := s.db.SaveReport(context.TODO(), r, 1)
+	s.NoError(err)
+	s.True(hidden)
+
+	// when
+	dismissed, err := s.db.DismissReports(context.TODO(), s.u3, r)
+
+	// then
+	s.NoError(err)
+	s.EqualValues(1, dismissed)
+	find, err := s.db.FindCommentByID(context.TODO(), a.ID, c.ID)
+	s.NoError(err)
+	s.False(find.IsHidden())
+}
+
+func newReport(reporter userModel.User, targetType string, targetID, articleID uint) *model.Report {
+	return &model.Report{
+		Reporter:   reporter,
+		ReporterID: reporter.ID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		ArticleID:  articleID,
+		Reason:     "spam",
+	}
+}
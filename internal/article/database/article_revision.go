@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleRevisionDB interface {
+	// FindRevisions returns ([]*model.ArticleRevision, error) of given article id ordered by number descending.
+	FindRevisions(ctx context.Context, articleID uint) ([]*model2.ArticleRevision, error)
+
+	// FindRevision returns a model.ArticleRevision matched by given article id and revision number.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindRevision(ctx context.Context, articleID, number uint) (*model2.ArticleRevision, error)
+}
+
+func (adb *articleDB) FindRevisions(ctx context.Context, articleID uint) ([]*model2.ArticleRevision, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleRevisionDB_FindRevisions try to find revisions", "articleID", articleID)
+
+	var revisions []*model2.ArticleRevision
+	if err := adb.db.WithContext(ctx).
+		Where("article_id = ?", articleID).
+		Order("number DESC").
+		Find(&revisions).Error; err != nil {
+		logger.Errorw("ArticleRevisionDB_FindRevisions failed to find revisions", "articleID", articleID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return revisions, nil
+}
+
+func (adb *articleDB) FindRevision(ctx context.Context, articleID, number uint) (*model2.ArticleRevision, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleRevisionDB_FindRevision try to find a revision", "articleID", articleID, "number", number)
+
+	var revision model2.ArticleRevision
+	if err := adb.db.WithContext(ctx).
+		First(&revision, "article_id = ? AND number = ?", articleID, number).Error; err != nil {
+		logger.Errorw("ArticleRevisionDB_FindRevision failed to find a revision", "articleID", articleID, "number", number, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &revision, nil
+}
+
+// saveNextRevision saves current contents of given article as the next revision.
+// The article row is locked until given transaction db ends, so concurrent updates are numbered in order.
+// database.ErrConcurrentUpdate is returned if the number is taken by others in spite of the lock.
+func saveNextRevision(ctx context.Context, db *gorm.DB, a *model2.Article, editorID uint) error {
+	var locked []uint
+	if err := db.WithContext(ctx).Model(new(model2.Article)).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("article_id = ?", a.ID).
+		Pluck("article_id", &locked).Error; err != nil {
+		return err
+	}
+
+	var last uint
+	if err := db.WithContext(ctx).Model(new(model2.ArticleRevision)).
+		Where("article_id = ?", a.ID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+	if err := db.WithContext(ctx).Create(model2.NewArticleRevision(a, last+1, editorID)).Error; err != nil {
+		if database.WrapError(err) == database.ErrKeyConflict {
+			return database.ErrConcurrentUpdate
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+)
+
+func (s *Suite) TestUpdateSavesRevision() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	a.Title = "article1 updated"
+	a.Body = "body updated"
+
+	// when
+	err := s.db.Update(context.TODO(), s.u1, a)
+
+	// then
+	s.NoError(err)
+	revisions, err := s.db.FindRevisions(context.TODO(), a.ID)
+	s.NoError(err)
+	s.Len(revisions, 2)
+	s.EqualValues(2, revisions[0].Number)
+	s.Equal("article1 updated", revisions[0].Title)
+	s.Equal("body updated", revisions[0].Body)
+	s.Equal(s.u1.ID, revisions[0].EditorID)
+	s.EqualValues(1, revisions[1].Number)
+	s.Equal("article1", revisions[1].Title)
+	s.Equal("body", revisions[1].Body)
+}
+
+func (s *Suite) TestUpdateFailNoRevision() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	a.Body = "body updated"
+
+	// when
+	err := s.db.Update(context.TODO(), s.u2, a)
+
+	// then
+	s.Equal(database.ErrRecordNotFound, err)
+	revisions, err := s.db.FindRevisions(context.TODO(), a.ID)
+	s.NoError(err)
+	s.Len(revisions, 1)
+}
+
+func (s *Suite) TestFindRevision() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+
+	find, err := s.db.FindRevision(context.TODO(), a.ID, 1)
+	s.NoError(err)
+	s.Equal(a.ID, find.ArticleID)
+	s.Equal(a.Title, find.Title)
+	s.Equal(a.Description, find.Description)
+	s.Equal(a.Body, find.Body)
+
+	_, err = s.db.FindRevision(context.TODO(), a.ID, 2)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestPurgeDeletedWithRevisions() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, a.Slug))
+	var deleted model.Article
+	s.NoError(s.originDB.Unscoped().First(&deleted, "article_id = ?", a.ID).Error)
+
+	purged, err := s.db.PurgeDeleted(context.TODO(), deleted.DeletedAt.Time.Add(1), 10)
+
+	s.NoError(err)
+	s.EqualValues(1, purged)
+	revisions, err := s.db.FindRevisions(context.TODO(), a.ID)
+	s.NoError(err)
+	s.Empty(revisions)
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	matchArticleExpr    = "MATCH(a.title, a.description, a.body) AGAINST (? IN BOOLEAN MODE)"
+	tsvectorArticleExpr = "to_tsvector('simple', coalesce(a.title, '') || ' ' || coalesce(a.description, '') || ' ' || coalesce(a.body, ''))"
+	likeArticleExpr     = "(LOWER(a.title) LIKE ? ESCAPE '\\' OR LOWER(a.description) LIKE ? ESCAPE '\\' OR LOWER(a.body) LIKE ? ESCAPE '\\')"
+)
+
+// articleSearch is a search condition and relevance score of articles depending on a database driver.
+type articleSearch struct {
+	where     string
+	whereArgs []interface{}
+	score     string
+	scoreArgs []interface{}
+}
+
+// newArticleSearch returns a articleSearch from given search terms.
+// mysql uses a full-text index, postgres uses a tsvector index and sqlite matches each term with LIKE.
+func newArticleSearch(driver string, terms []string) *articleSearch {
+	switch driver {
+	case database.DriverPostgres:
+		prefixes := make([]string, len(terms))
+		for i, term := range terms {
+			prefixes[i] = term + ":*"
+		}
+		query := strings.Join(prefixes, " & ")
+		return &articleSearch{
+			where:     tsvectorArticleExpr + " @@ to_tsquery('simple', ?)",
+			whereArgs: []interface{}{query},
+			score:     "ts_rank(" + tsvectorArticleExpr + ", to_tsquery('simple', ?))",
+			scoreArgs: []interface{}{query},
+		}
+	case database.DriverSQLite:
+		var (
+			conditions = make([]string, len(terms))
+			args       []interface{}
+		)
+		for i, term := range terms {
+			conditions[i] = likeArticleExpr
+			pattern := "%" + strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(term) + "%"
+			args = append(args, pattern, pattern, pattern)
+		}
+		return &articleSearch{
+			where:     strings.Join(conditions, " AND "),
+			whereArgs: args,
+			score:     "0",
+		}
+	default:
+		query := model2.ToBooleanModeQuery(terms)
+		return &articleSearch{
+			where:     matchArticleExpr,
+			whereArgs: []interface{}{query},
+			score:     matchArticleExpr,
+			scoreArgs: []interface{}{query},
+		}
+	}
+}
+
+func (adb *articleDB) SearchArticles(ctx context.Context, user *userModel.User, keyword string, offset, limit int) (*model2.Articles, error) {
+	var (
+		logger = logging.FromContext(ctx)
+		userID = uint(0)
+	)
+	if user != nil {
+		userID = user.ID
+	}
+	logger.Debugw("ArticleDB_SearchArticles try to search articles", "userID", userID, "keyword", keyword, "offset", offset, "limit", limit)
+
+	terms := model2.ParseSearchTerms(keyword)
+	if len(terms) == 0 || limit <= 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: 0,
+		}, nil
+	}
+	search := newArticleSearch(adb.db.Dialector.Name(), terms)
+
+	// find article ids ordered by relevance.
+	ids, err := articleIdsBySearch(ctx, adb.db, search, offset, limit)
+	if err != nil {
+		logger.Errorw("ArticleDB_SearchArticles failed to fetch article ids", "keyword", keyword, "offset", offset, "limit", limit, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	// find total count from given keyword.
+	total, err := countArticleBySearch(ctx, adb.db, search)
+	if err != nil {
+		logger.Errorw("ArticleDB_SearchArticles failed to fetch total count", "keyword", keyword, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	if len(ids) == 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: total,
+		}, nil
+	}
+
+	articles, err := articlesByIds(adb.db, ids)
+	if err != nil {
+		logger.Errorw("ArticleDB_SearchArticles failed to fetch articles with author and tags.", "userID", userID, "ids", ids, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := fillArticlesExtraData(adb.db, user, articles); err != nil {
+		logger.Errorw("ArticleDB_SearchArticles failed to update extra data", "userID", userID, "ids", ids, "err", err)
+	}
+	return &model2.Articles{
+		Articles:      sortArticlesByIds(articles, ids),
+		ArticlesCount: total,
+	}, nil
+}
+
+// articleIdsBySearch returns article ids matched by given search condition ordered by relevance.
+func articleIdsBySearch(ctx context.Context, db *gorm.DB, search *articleSearch, offset, limit int) ([]uint, error) {
+	rows, err := db.WithContext(ctx).Table("articles a").
+		Select("a.article_id, "+search.score+" AS score", search.scoreArgs...).
+		Where("a.deleted_at IS NULL AND a.status = ?", model2.ArticleStatusPublished).
+		Where(search.where, search.whereArgs...).
+		Order("score DESC, a.created_at DESC, a.article_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []uint
+	for rows.Next() {
+		var (
+			id    uint
+			score float64
+		)
+		if err := rows.Scan(&id, &score); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func countArticleBySearch(ctx context.Context, db *gorm.DB, search *articleSearch) (int64, error) {
+	var count int64
+	return count, db.WithContext(ctx).Table("articles a").
+		Where("a.deleted_at IS NULL AND a.status = ?", model2.ArticleStatusPublished).
+		Where(search.where, search.whereArgs...).
+		Count(&count).Error
+}
+
+// sortArticlesByIds returns articles ordered by given ids.
+func sortArticlesByIds(articles []*model2.Article, ids []uint) []*model2.Article {
+	m := make(map[uint]*model2.Article, len(articles))
+	for _, a := range articles {
+		m[a.ID] = a
+	}
+	res := make([]*model2.Article, 0, len(articles))
+	for _, id := range ids {
+		if a, ok := m[id]; ok {
+			res = append(res, a)
+		}
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+)
+
+func (adb *articleDB) FindCurrentSlug(ctx context.Context, user *userModel.User, slug string) (string, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_FindCurrentSlug try to find a current slug", "slug", slug)
+
+	var userID uint
+	if user != nil {
+		userID = user.ID
+	}
+	var current []string
+	if err := adb.db.WithContext(ctx).Table(model2.TableNameSlugHistory+" sh").
+		Joins("JOIN "+model2.TableNameArticle+" a ON a.article_id = sh.article_id").
+		Where("sh.slug = ? AND a.deleted_at IS NULL", slug).
+		Where("a.status = ? OR a.author_id = ?", model2.ArticleStatusPublished, userID).
+		Pluck("a.slug", &current).Error; err != nil {
+		logger.Errorw("ArticleDB_FindCurrentSlug failed to find a current slug", "slug", slug, "err", err)
+		return "", database.WrapError(err)
+	}
+	if len(current) == 0 {
+		return "", database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return current[0], nil
+}
+
+// updateSlug updates a slug of given article a if the title is changed and records the previous slug
+// to slug history. The slug of a is set to the current one.
+func updateSlug(ctx context.Context, db *gorm.DB, user *userModel.User, a *model2.Article) error {
+	var prev model2.Article
+	if err := db.WithContext(ctx).
+		Select("article_id", "slug", "title").
+		Where("article_id = ? AND author_id = ?", a.ID, user.ID).
+		First(&prev).Error; err != nil {
+		return err
+	}
+	if model2.MakeSlug(prev.Title, 1) == model2.MakeSlug(a.Title, 1) {
+		a.Slug = prev.Slug
+		return nil
+	}
+
+	slug, err := uniqueSlug(ctx, db, a.ID, a.Title)
+	if err != nil {
+		return err
+	}
+	a.Slug = slug
+	// the article can take back one of its previous slugs.
+	if err := db.WithContext(ctx).Where("slug = ? AND article_id = ?", slug, a.ID).Delete(new(model2.SlugHistory)).Error; err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Create(&model2.SlugHistory{Slug: prev.Slug, ArticleID: a.ID}).Error
+}
+
+// uniqueSlug returns a slug of given title which is not used by other articles currently or previously.
+// A numeric suffix is appended to the slug if the slug of the title is already used.
+func uniqueSlug(ctx context.Context, db *gorm.DB, articleID uint, title string) (string, error) {
+	var (
+		base    = model2.MakeSlug(title, 1)
+		pattern = base + "-%"
+		used    []string
+		history []string
+	)
+	if err := db.WithContext(ctx).Model(new(model2.Article)).
+		Where("(slug = ? OR slug LIKE ?) AND article_id <> ?", base, pattern, articleID).
+		Pluck("slug", &used).Error; err != nil {
+		return "", err
+	}
+	if err := db.WithContext(ctx).Model(new(model2.SlugHistory)).
+		Where("(slug = ? OR slug LIKE ?) AND article_id <> ?", base, pattern, articleID).
+		Pluck("slug", &history).Error; err != nil {
+		return "", err
+	}
+	usedSet := make(map[string]struct{}, len(used)+len(history))
+	for _, s := range append(used, history...) {
+		usedSet[s] = struct{}{}
+	}
+	for n := 1; ; n++ {
+		slug := model2.MakeSlug(title, n)
+		if _, ok := usedSet[slug]; !ok {
+			return slug, nil
+		}
+	}
+}
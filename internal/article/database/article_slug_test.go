@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+)
+
+func (s *Suite) TestSaveDuplicateTitle() {
+	articles := []*model.Article{
+		newArticle("article1", "description", "body", *s.u1, nil),
+		newArticle("article1", "description", "body", *s.u2, nil),
+		newArticle("article1", "description", "body", *s.u3, nil),
+	}
+
+	// when
+	for _, a := range articles {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+
+	// then
+	s.Equal("article1", articles[0].Slug)
+	s.Equal("article1-2", articles[1].Slug)
+	s.Equal("article1-3", articles[2].Slug)
+	for _, a := range articles {
+		find, err := s.db.FindBySlug(context.TODO(), nil, a.Slug)
+		s.NoError(err)
+		s.Equal(a.ID, find.ID)
+	}
+}
+
+func (s *Suite) TestUpdateSlug() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	other := newArticle("article2", "description", "body", *s.u2, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	s.NoError(s.db.Save(context.TODO(), other))
+
+	// keep the slug if the slug of title is not changed
+	a.Title = "Article1"
+	a.Body = "updated body"
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+	s.Equal("article1", a.Slug)
+	_, err := s.db.FindCurrentSlug(context.TODO(), nil, "article1")
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// change the slug with a suffix if the slug is used by another article
+	a.Title = other.Title
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+	s.Equal("article2-2", a.Slug)
+	current, err := s.db.FindCurrentSlug(context.TODO(), nil, "article1")
+	s.NoError(err)
+	s.Equal("article2-2", current)
+
+	// previous slugs are not used by other articles
+	another := newArticle("article1", "description", "body", *s.u2, nil)
+	s.NoError(s.db.Save(context.TODO(), another))
+	s.Equal("article1-2", another.Slug)
+
+	// take back the previous slug
+	a.Title = "article1"
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+	s.Equal("article1", a.Slug)
+	_, err = s.db.FindCurrentSlug(context.TODO(), nil, "article1")
+	s.Equal(database.ErrRecordNotFound, err)
+	current, err = s.db.FindCurrentSlug(context.TODO(), nil, "article2-2")
+	s.NoError(err)
+	s.Equal("article1", current)
+}
+
+func (s *Suite) TestFindCurrentSlugNotVisible() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	a.Status = model.ArticleStatusDraft
+	s.NoError(s.db.Save(context.TODO(), a))
+	a.Title = "article2"
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+
+	// found by the author
+	current, err := s.db.FindCurrentSlug(context.TODO(), s.u1, "article1")
+	s.NoError(err)
+	s.Equal("article2", current)
+
+	// not found by others
+	_, err = s.db.FindCurrentSlug(context.TODO(), s.u2, "article1")
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// not found after deleted
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, a.Slug))
+	_, err = s.db.FindCurrentSlug(context.TODO(), s.u1, "article1")
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestPurgeDeletedWithSlugHistory() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	a.Title = "article2"
+	s.NoError(s.db.Update(context.TODO(), s.u1, a))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, a.Slug))
+	var deleted model.Article
+	s.NoError(s.originDB.Unscoped().First(&deleted, "article_id = ?", a.ID).Error)
+
+	purged, err := s.db.PurgeDeleted(context.TODO(), deleted.DeletedAt.Time.Add(1), 10)
+
+	s.NoError(err)
+	s.EqualValues(1, purged)
+	var count int64
+	s.NoError(s.originDB.Model(new(model.SlugHistory)).Where("article_id = ?", a.ID).Count(&count).Error)
+	s.Zero(count)
+}
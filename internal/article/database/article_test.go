@@ -8,8 +8,10 @@ import (
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	outboxModel "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"go.uber.org/zap/zapcore"
 	"gorm.io/gorm"
 	"testing"
@@ -45,12 +47,19 @@ func (s *Suite) TearDownSuite() {
 
 func (s *Suite) SetupTest() {
 	err := database.DeleteRecordAll(s.T(), s.originDB, []string{
+		outboxModel.TableNameOutboxEvent, "outbox_id > 0",
+		model.TableNameComment, "parent_id IS NOT NULL",
 		model.TableNameComment, "comment_id > 0",
 		model.TableNameArticleFavorite, "user_id > 0",
 		model.TableNameArticleTag, "article_id > 0",
+		model.TableNameArticleRevision, "revision_id > 0",
+		model.TableNameSlugHistory, "article_id > 0",
+		model.TableNameReaction, "user_id > 0",
+		model.TableNameReport, "report_id > 0",
 		model.TableNameArticle, "article_id > 0",
 		model.TableNameTag, "tag_id > 0",
 		userModel.TableNameFollow, "user_id > 0",
+		userModel.TableNameMute, "user_id > 0",
 		userModel.TableNameUser, "user_id > 0",
 	})
 	s.NoError(err)
@@ -87,31 +96,6 @@ func (s *Suite) TestSave() {
 	s.EqualValues(2, tagCount)
 }
 
-func (s *Suite) TestSaveFail() {
-	exist := newArticle("article1", "", "", *s.u1, nil)
-	s.NoError(s.db.Save(context.TODO(), exist))
-
-	cases := []struct {
-		name    string
-		article *model.Article
-		msg     string
-	}{
-		{
-			name:    "duplicate",
-			article: newArticle(exist.Title, exist.Description, exist.Body, exist.Author, nil),
-			msg:     database.ErrKeyConflict.Error(),
-		},
-	}
-
-	for _, tc := range cases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			err := s.db.Save(context.TODO(), tc.article)
-			assert.Error(t, err)
-			assert.Contains(t, err.Error(), tc.msg)
-		})
-	}
-}
-
 func (s *Suite) TestUpdate() {
 	exist := newArticle("article1", "description", "body", *s.u1, []string{"tag1", "tag2"})
 	s.NoError(s.db.Save(context.TODO(), exist))
@@ -138,6 +122,43 @@ func (s *Suite) TestUpdate() {
 	s.Equal(update.Body, find.Body)
 }
 
+func (s *Suite) TestUpdate_Hidden() {
+	exist := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), exist))
+	update := &model.Article{
+		ID:     exist.ID,
+		Title:  exist.Title,
+		Body:   "updated body",
+		Status: model.ArticleStatusPublished,
+	}
+	// hidden after read
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", exist.ID).
+		UpdateColumn("status", model.ArticleStatusHidden).Error)
+
+	// when
+	err := s.db.Update(context.TODO(), s.u1, update)
+
+	// then
+	s.Equal(database.ErrConcurrentUpdate, err)
+	var find model.Article
+	s.NoError(s.originDB.First(&find, "article_id = ?", exist.ID).Error)
+	s.Equal(model.ArticleStatusHidden, find.Status)
+	s.Equal(exist.Body, find.Body)
+
+	// hidden articles can be updated without changing the status
+	update.Status = model.ArticleStatusHidden
+	s.NoError(s.db.Update(context.TODO(), s.u1, update))
+	s.NoError(s.originDB.First(&find, "article_id = ?", exist.ID).Error)
+	s.Equal(model.ArticleStatusHidden, find.Status)
+	s.Equal(update.Body, find.Body)
+
+	// unhidden after read
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", exist.ID).
+		UpdateColumn("status", model.ArticleStatusPublished).Error)
+	err = s.db.Update(context.TODO(), s.u1, update)
+	s.Equal(database.ErrConcurrentUpdate, err)
+}
+
 func (s *Suite) TestUpdateFail() {
 	articles := []*model.Article{
 		newArticle("article1", "description", "body", *s.u1, nil),
@@ -152,14 +173,6 @@ func (s *Suite) TestUpdateFail() {
 		msg    string
 	}{
 		{
-			name: "duplicate slug",
-			user: s.u1,
-			update: &model.Article{
-				ID:    articles[0].ID,
-				Title: articles[1].Title,
-			},
-			msg: database.ErrKeyConflict.Error(),
-		}, {
 			name: "not found by author",
 			user: s.u2,
 			update: &model.Article{
@@ -199,6 +212,53 @@ func (s *Suite) TestDeleteBySlug() {
 	var find model.Article
 	s.NoError(s.originDB.Unscoped().First(&find, "article_id = ?", exist.ID).Error)
 	s.WithinDuration(now, find.DeletedAt.Time, time.Minute)
+	s.Equal(exist.Author.ID, *find.DeletedBy)
+
+	// already deleted
+	err = s.db.DeleteBySlug(context.TODO(), &exist.Author, exist.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestDeleteBySlug_Moderator() {
+	exist := newArticle("article1", "description", "body", *s.u1, []string{"tag1", "tag2"})
+	s.NoError(s.db.Save(context.TODO(), exist))
+	moderator := &userModel.User{ID: s.u2.ID, Role: authutils.RoleModerator}
+	// when
+	err := s.db.DeleteBySlug(context.TODO(), moderator, exist.Slug)
+	// then
+	s.NoError(err)
+	var find model.Article
+	s.NoError(s.originDB.Unscoped().First(&find, "article_id = ?", exist.ID).Error)
+	s.True(find.DeletedAt.Valid)
+	s.Equal(moderator.ID, *find.DeletedBy)
+}
+
+func (s *Suite) TestCountContentsByAuthor() {
+	published := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), published))
+	draft := newArticle("article2", "", "", *s.u1, nil)
+	draft.Status = model.ArticleStatusDraft
+	s.NoError(s.db.Save(context.TODO(), draft))
+	deleted := newArticle("article3", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), deleted))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, deleted.Slug))
+	other := newArticle("article4", "", "", *s.u2, nil)
+	s.NoError(s.db.Save(context.TODO(), other))
+	s.NoError(s.db.SaveComment(context.TODO(), newComment("comment1", *s.u1, *other)))
+	s.NoError(s.db.SaveComment(context.TODO(), newComment("comment2", *s.u2, *published)))
+	s.NoError(s.db.FavoriteArticle(context.TODO(), s.u1, other.ID))
+
+	// when
+	counts, err := s.db.CountContentsByAuthor(context.TODO(), s.u1.ID)
+	// then
+	s.NoError(err)
+	s.Equal(&model.ContentCounts{
+		Articles:        1,
+		Drafts:          1,
+		DeletedArticles: 1,
+		Comments:        1,
+		Favorites:       1,
+	}, counts)
 }
 
 func (s *Suite) TestDeleteBySlugFail() {
@@ -234,6 +294,39 @@ func (s *Suite) TestDeleteBySlugFail() {
 	}
 }
 
+func (s *Suite) TestFindArticlesByQuery_MutedAuthors() {
+	a1 := newArticle("article1", "description", "body", *s.u1, nil)
+	a2 := newArticle("article2", "description", "body", *s.u2, nil)
+	for _, a := range []*model.Article{a1, a2} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+	s.NoError(s.originDB.Create(&userModel.Mute{UserID: s.u3.ID, MuteID: s.u1.ID}).Error)
+
+	// muting user
+	articles, err := s.db.FindArticlesByQuery(context.TODO(), s.u3, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(1, articles.ArticlesCount)
+	s.Equal(a2.ID, articles.Articles[0].ID)
+
+	articles, err = s.db.FindArticlesByQueryWithCursor(context.TODO(), s.u3, model.ArticleQuery{}, nil, 10)
+	s.NoError(err)
+	s.Len(articles.Articles, 1)
+
+	// muted author is given
+	articles, err = s.db.FindArticlesByQuery(context.TODO(), s.u3, model.ArticleQuery{Author: s.u1.Name}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(1, articles.ArticlesCount)
+	s.Equal(a1.ID, articles.Articles[0].ID)
+
+	// other users
+	articles, err = s.db.FindArticlesByQuery(context.TODO(), s.u2, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	articles, err = s.db.FindArticlesByQuery(context.TODO(), nil, model.ArticleQuery{}, 0, 10)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+}
+
 func (s *Suite) TestFavoriteArticle() {
 	// TODO
 }
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	model2 "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+// deletedByAuthor is a scope for articles deleted by their authors. deleted_by is null if deleted before recorded.
+func deletedByAuthor(db *gorm.DB) *gorm.DB {
+	return db.Where("(deleted_by IS NULL OR deleted_by = author_id)")
+}
+
+func (adb *articleDB) FindDeletedArticles(ctx context.Context, user *userModel.User, offset, limit int) (*model2.Articles, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_FindDeletedArticles no user")
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("ArticleDB_FindDeletedArticles try to find deleted articles", "userID", user.ID, "offset", offset, "limit", limit)
+
+	if limit <= 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: 0,
+		}, nil
+	}
+
+	db := adb.db.Unscoped().WithContext(ctx)
+	// find deleted article ids ordered by deleted time.
+	var ids []uint
+	if err := db.Model(new(model2.Article)).
+		Where("author_id = ? AND deleted_at IS NOT NULL", user.ID).
+		Scopes(deletedByAuthor).
+		Order("deleted_at DESC, article_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Pluck("article_id", &ids).Error; err != nil {
+		logger.Errorw("ArticleDB_FindDeletedArticles failed to fetch article ids", "userID", user.ID, "offset", offset, "limit", limit, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	// find total count of deleted articles.
+	var total int64
+	if err := db.Model(new(model2.Article)).
+		Where("author_id = ? AND deleted_at IS NOT NULL", user.ID).
+		Scopes(deletedByAuthor).
+		Count(&total).Error; err != nil {
+		logger.Errorw("ArticleDB_FindDeletedArticles failed to fetch total count", "userID", user.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	if len(ids) == 0 {
+		return &model2.Articles{
+			Articles:      make([]*model2.Article, 0),
+			ArticlesCount: total,
+		}, nil
+	}
+
+	articles, err := articlesByIds(db, ids)
+	if err != nil {
+		logger.Errorw("ArticleDB_FindDeletedArticles failed to fetch articles with author and tags.", "userID", user.ID, "ids", ids, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := fillArticlesExtraData(db, user, articles); err != nil {
+		logger.Errorw("ArticleDB_FindDeletedArticles failed to update extra data", "userID", user.ID, "ids", ids, "err", err)
+	}
+	return &model2.Articles{
+		Articles:      sortArticlesByIds(articles, ids),
+		ArticlesCount: total,
+	}, nil
+}
+
+func (adb *articleDB) RestoreBySlug(ctx context.Context, user *userModel.User, slug string) error {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("ArticleDB_RestoreBySlug no user")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("ArticleDB_RestoreBySlug try to restore an article", "userID", user.ID, "slug", slug)
+
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	}
+	if err := database.RunInTx(ctx, adb.db, opts, func(txDb *gorm.DB) error {
+		var a model2.Article
+		if err := txDb.WithContext(ctx).Unscoped().
+			Where("slug = ? AND author_id = ? AND deleted_at IS NOT NULL", slug, user.ID).
+			Scopes(deletedByAuthor).
+			Order("deleted_at DESC").
+			First(&a).Error; err != nil {
+			return err
+		}
+		// update a column without hooks to keep the slug and updated time.
+		return txDb.WithContext(ctx).Unscoped().
+			Model(new(model2.Article)).
+			Where("article_id = ?", a.ID).
+			UpdateColumns(map[string]interface{}{
+				"deleted_at": nil,
+				"deleted_by": nil,
+			}).Error
+	}); err != nil {
+		logger.Errorw("ArticleDB_RestoreBySlug failed to restore an article", "userID", user.ID, "slug", slug, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (adb *articleDB) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("ArticleDB_PurgeDeleted try to purge deleted articles and comments", "deletedBefore", deletedBefore, "limit", limit)
+
+	if limit <= 0 {
+		return 0, nil
+	}
+	var (
+		purged int64
+		opts   = &sql.TxOptions{
+			Isolation: sql.LevelReadCommitted,
+			ReadOnly:  false,
+		}
+	)
+	if err := database.RunInTx(ctx, adb.db, opts, func(txDb *gorm.DB) error {
+		db := txDb.Unscoped().WithContext(ctx)
+		purged = 0
+
+		// purge articles with relations
+		var articleIds []uint
+		if err := db.Model(new(model2.Article)).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Order("deleted_at ASC").
+			Limit(limit).
+			Pluck("article_id", &articleIds).Error; err != nil {
+			return err
+		}
+		if len(articleIds) != 0 {
+			if err := db.Where("target_type = ? AND target_id IN (?)", model2.ReactionTargetArticle, articleIds).Delete(new(model2.Reaction)).Error; err != nil {
+				return err
+			}
+			if err := db.Where("target_type = ? AND target_id IN (?)", model2.ReactionTargetComment,
+				db.Model(new(model2.Comment)).Select("comment_id").Where("article_id IN (?)", articleIds)).
+				Delete(new(model2.Reaction)).Error; err != nil {
+				return err
+			}
+			// unlink replies first, so comments can be deleted in any order.
+			if err := db.Model(new(model2.Comment)).Where("article_id IN (?)", articleIds).UpdateColumn("parent_id", nil).Error; err != nil {
+				return err
+			}
+			if err := db.Where("article_id IN (?)", articleIds).Delete(new(model2.Comment)).Error; err != nil {
+				return err
+			}
+			if err := db.Where("article_id IN (?)", articleIds).Delete(new(model2.ArticleTag)).Error; err != nil {
+				return err
+			}
+			if err := db.Where("article_id IN (?)", articleIds).Delete(new(model2.ArticleFavorite)).Error; err != nil {
+				return err
+			}
+			if err := db.Where("article_id IN (?)", articleIds).Delete(new(model2.ArticleRevision)).Error; err != nil {
+				return err
+			}
+			if err := db.Where("article_id IN (?)", articleIds).Delete(new(model2.SlugHistory)).Error; err != nil {
+				return err
+			}
+			result := db.Where("article_id IN (?)", articleIds).Delete(new(model2.Article))
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+
+		// purge comments. comments with replies are kept as placeholders until the replies are purged.
+		var commentIds []uint
+		if err := db.Model(new(model2.Comment)).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Where("NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = comments.comment_id)").
+			Order("deleted_at ASC").
+			Limit(limit).
+			Pluck("comment_id", &commentIds).Error; err != nil {
+			return err
+		}
+		if len(commentIds) != 0 {
+			if err := db.Where("target_type = ? AND target_id IN (?)", model2.ReactionTargetComment, commentIds).Delete(new(model2.Reaction)).Error; err != nil {
+				return err
+			}
+			result := db.Where("comment_id IN (?)", commentIds).Delete(new(model2.Comment))
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	}); err != nil {
+		logger.Errorw("ArticleDB_PurgeDeleted failed to purge deleted articles and comments", "deletedBefore", deletedBefore, "err", err)
+		return 0, database.WrapError(err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"time"
+)
+
+func (s *Suite) TestSaveWithDeletedSlug() {
+	deleted := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), deleted))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, deleted.Slug))
+
+	// when
+	a := newArticle("article1", "description", "body", *s.u2, nil)
+	err := s.db.Save(context.TODO(), a)
+
+	// then
+	s.NoError(err)
+	s.Equal(deleted.Slug, a.Slug)
+	s.NotEqual(deleted.ID, a.ID)
+}
+
+func (s *Suite) TestFindDeletedArticles() {
+	a1 := newArticle("article1", "description", "body", *s.u1, []string{"tag1"})
+	a2 := newArticle("article2", "description", "body", *s.u1, []string{"tag2"})
+	a3 := newArticle("article3", "description", "body", *s.u1, nil)
+	a4 := newArticle("article4", "description", "body", *s.u2, nil)
+	for _, a := range []*model.Article{a1, a2, a3, a4} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+	now := time.Now()
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", a1.ID).Update("deleted_at", now.Add(-time.Hour)).Error)
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", a2.ID).Update("deleted_at", now).Error)
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u2, a4.Slug))
+
+	// first iteration
+	articles, err := s.db.FindDeletedArticles(context.TODO(), s.u1, 0, 1)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(a2.ID, articles.Articles[0].ID)
+	s.True(articles.Articles[0].DeletedAt.Valid)
+	s.Equal(s.u1.Name, articles.Articles[0].Author.Name)
+	s.Len(articles.Articles[0].Tags, 1)
+	s.Equal("tag2", articles.Articles[0].Tags[0].Name)
+
+	// second iteration
+	articles, err = s.db.FindDeletedArticles(context.TODO(), s.u1, 1, 1)
+	s.NoError(err)
+	s.EqualValues(2, articles.ArticlesCount)
+	s.Len(articles.Articles, 1)
+	s.Equal(a1.ID, articles.Articles[0].ID)
+
+	// no deleted articles
+	articles, err = s.db.FindDeletedArticles(context.TODO(), s.u3, 0, 10)
+	s.NoError(err)
+	s.EqualValues(0, articles.ArticlesCount)
+	s.Empty(articles.Articles)
+}
+
+func (s *Suite) TestRestoreBySlug() {
+	a := newArticle("article1", "description", "body", *s.u1, []string{"tag1"})
+	s.NoError(s.db.Save(context.TODO(), a))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, a.Slug))
+
+	// when
+	err := s.db.RestoreBySlug(context.TODO(), s.u1, a.Slug)
+
+	// then
+	s.NoError(err)
+	find, err := s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.NoError(err)
+	s.Equal(a.ID, find.ID)
+	s.False(find.DeletedAt.Valid)
+	s.Len(find.Tags, 1)
+}
+
+func (s *Suite) TestRestoreBySlugFail() {
+	deleted := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), deleted))
+	s.NoError(s.db.DeleteBySlug(context.TODO(), s.u1, deleted.Slug))
+
+	// not found by author
+	err := s.db.RestoreBySlug(context.TODO(), s.u2, deleted.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// not deleted
+	exist := newArticle("article2", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), exist))
+	err = s.db.RestoreBySlug(context.TODO(), s.u1, exist.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// slug is used by another article
+	s.NoError(s.db.Save(context.TODO(), newArticle("article1", "description", "body", *s.u2, nil)))
+	err = s.db.RestoreBySlug(context.TODO(), s.u1, deleted.Slug)
+	s.Equal(database.ErrKeyConflict, err)
+}
+
+func (s *Suite) TestRestoreBySlug_DeletedByModerator() {
+	a := newArticle("article1", "description", "body", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	moderator := &userModel.User{ID: s.u2.ID, Role: authutils.RoleModerator}
+	s.NoError(s.db.DeleteBySlug(context.TODO(), moderator, a.Slug))
+
+	// not in the trash of the author
+	articles, err := s.db.FindDeletedArticles(context.TODO(), s.u1, 0, 10)
+	s.NoError(err)
+	s.EqualValues(0, articles.ArticlesCount)
+	s.Empty(articles.Articles)
+
+	// not restored by the author
+	err = s.db.RestoreBySlug(context.TODO(), s.u1, a.Slug)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestPurgeDeleted() {
+	now := time.Now()
+	old := newArticle("article1", "description", "body", *s.u1, []string{"tag1"})
+	recent := newArticle("article2", "description", "body", *s.u1, nil)
+	exist := newArticle("article3", "description", "body", *s.u1, nil)
+	for _, a := range []*model.Article{old, recent, exist} {
+		s.NoError(s.db.Save(context.TODO(), a))
+	}
+	s.NoError(s.db.FavoriteArticle(context.TODO(), s.u2, old.ID))
+	oldComment := &model.Comment{Body: "comment1", ArticleID: old.ID, AuthorID: s.u2.ID}
+	deletedComment := &model.Comment{Body: "comment2", ArticleID: exist.ID, AuthorID: s.u2.ID}
+	comment := &model.Comment{Body: "comment3", ArticleID: exist.ID, AuthorID: s.u2.ID}
+	for _, c := range []*model.Comment{oldComment, deletedComment, comment} {
+		s.NoError(s.db.SaveComment(context.TODO(), c))
+	}
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", old.ID).Update("deleted_at", now.Add(-48*time.Hour)).Error)
+	s.NoError(s.originDB.Model(new(model.Article)).Where("article_id = ?", recent.ID).Update("deleted_at", now).Error)
+	s.NoError(s.originDB.Model(new(model.Comment)).Where("comment_id = ?", deletedComment.ID).Update("deleted_at", now.Add(-48*time.Hour)).Error)
+
+	// when
+	purged, err := s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+
+	// then
+	s.NoError(err)
+	s.EqualValues(2, purged)
+	var articleIds []uint
+	s.NoError(s.originDB.Unscoped().Model(new(model.Article)).Order("article_id").Pluck("article_id", &articleIds).Error)
+	s.Equal([]uint{recent.ID, exist.ID}, articleIds)
+	var commentIds []uint
+	s.NoError(s.originDB.Unscoped().Model(new(model.Comment)).Pluck("comment_id", &commentIds).Error)
+	s.Equal([]uint{comment.ID}, commentIds)
+	var count int64
+	s.NoError(s.originDB.Model(new(model.ArticleTag)).Where("article_id = ?", old.ID).Count(&count).Error)
+	s.Zero(count)
+	s.NoError(s.originDB.Model(new(model.ArticleFavorite)).Where("article_id = ?", old.ID).Count(&count).Error)
+	s.Zero(count)
+
+	// nothing to purge
+	purged, err = s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+	s.NoError(err)
+	s.Zero(purged)
+}
@@ -2,12 +2,15 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"gorm.io/gorm"
+	"time"
 )
 
 func (adb *articleDB) SaveComment(ctx context.Context, c *model.Comment) error {
@@ -20,22 +23,61 @@ func (adb *articleDB) SaveComment(ctx context.Context, c *model.Comment) error {
 		return errors.New("require article id and author id")
 	}
 
-	if err := adb.db.WithContext(ctx).Create(c).Error; err != nil {
+	if err := database.RunInTx(ctx, adb.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		// a parent comment must exist in the same article and the reply must not be nested too deep.
+		if c.ParentID != nil {
+			var parent model.Comment
+			if err := txDb.WithContext(ctx).Model(new(model.Comment)).
+				Select("comment_id", "parent_id").
+				Where("comment_id = ? AND article_id = ?", *c.ParentID, c.ArticleID).
+				First(&parent).Error; err != nil {
+				return err
+			}
+			if err := checkCommentDepth(ctx, txDb, parent.ParentID, adb.maxCommentDepth); err != nil {
+				return err
+			}
+		}
+		if err := txDb.WithContext(ctx).Create(c).Error; err != nil {
+			return err
+		}
+		return appendCommentEvent(ctx, txDb, c)
+	}); err != nil {
 		logger.Errorw("CommentDB_SaveComment failed to save a comment", "c", c, "err", err)
 		return database.WrapError(err)
 	}
 	return nil
 }
 
+// checkCommentDepth returns model.ErrCommentTooDeep if a reply of a comment having given parentID
+// is nested deeper than maxDepth. Ancestors are followed including deleted ones.
+func checkCommentDepth(ctx context.Context, db *gorm.DB, parentID *uint, maxDepth int) error {
+	// the reply is nested one level under its parent.
+	for depth := 1; parentID != nil; depth++ {
+		if depth >= maxDepth {
+			return model.ErrCommentTooDeep
+		}
+		var ancestor model.Comment
+		if err := db.WithContext(ctx).Unscoped().Model(new(model.Comment)).
+			Select("comment_id", "parent_id").
+			Where("comment_id = ?", *parentID).
+			First(&ancestor).Error; err != nil {
+			return err
+		}
+		parentID = ancestor.ParentID
+	}
+	return nil
+}
+
 func (adb *articleDB) FindCommentsByArticleID(ctx context.Context, articleID uint) ([]*model.Comment, error) {
 	logger := logging.FromContext(ctx)
 	logger.Debugw("CommentDB_FindCommentsByArticleID try to find comments by article id", "articleID", articleID)
 
+	// deleted comments are included to keep placeholders of their replies.
 	var comments []*model.Comment
-	if err := adb.db.WithContext(ctx).Model(new(model.Comment)).
+	if err := adb.db.WithContext(ctx).Unscoped().Model(new(model.Comment)).
 		Joins("Author").
 		Where("article_id = ?", articleID).
-		Order("created_at DESC").
+		Order("comments.created_at DESC").
 		Find(&comments).Error; err != nil {
 		logger.Errorw("CommentDB_FindCommentsByArticleID failed to find comments", "articleID", articleID, "err", err)
 		return nil, database.WrapError(err)
@@ -43,17 +85,157 @@ func (adb *articleDB) FindCommentsByArticleID(ctx context.Context, articleID uin
 	return comments, nil
 }
 
+func (adb *articleDB) FindCommentsByArticleIDWithCursor(ctx context.Context, articleID, userID uint, order string, cursor *model.CommentCursor, limit int) (*model.Comments, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("CommentDB_FindCommentsByArticleIDWithCursor try to find comments by article id",
+		"articleID", articleID, "userID", userID, "order", order, "cursor", cursor, "limit", limit)
+
+	if limit <= 0 {
+		return &model.Comments{
+			Comments:      make([]*model.Comment, 0),
+			CommentsCount: 0,
+		}, nil
+	}
+	db := adb.db.WithContext(ctx)
+	// excludeMuted excludes comments written by users muted by given user.
+	excludeMuted := func(db *gorm.DB) *gorm.DB {
+		if userID == 0 {
+			return db
+		}
+		return db.Where("comments.author_id NOT IN (SELECT m.mute_id FROM mutes m WHERE m.user_id = ?)", userID)
+	}
+
+	// find root comments. deleted or hidden root comments are included if they have replies to keep placeholders.
+	// fetch one more comment to check whether the next page exists or not.
+	rootQuery := db.Unscoped().Model(new(model.Comment)).
+		Joins("Author").
+		Where("comments.article_id = ? AND comments.parent_id IS NULL", articleID).
+		Where("(comments.deleted_at IS NULL AND comments.hidden_at IS NULL) OR EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = comments.comment_id)").
+		Scopes(excludeMuted)
+	if order == model.CommentOrderOldest {
+		if cursor != nil {
+			rootQuery = rootQuery.Where("comments.created_at > ? OR (comments.created_at = ? AND comments.comment_id > ?)",
+				cursor.CreatedAt, cursor.CreatedAt, cursor.CommentID)
+		}
+		rootQuery = rootQuery.Order("comments.created_at ASC, comments.comment_id ASC")
+	} else {
+		if cursor != nil {
+			rootQuery = rootQuery.Where("comments.created_at < ? OR (comments.created_at = ? AND comments.comment_id < ?)",
+				cursor.CreatedAt, cursor.CreatedAt, cursor.CommentID)
+		}
+		rootQuery = rootQuery.Order("comments.created_at DESC, comments.comment_id DESC")
+	}
+	var roots []*model.Comment
+	if err := rootQuery.Limit(limit + 1).Find(&roots).Error; err != nil {
+		logger.Errorw("CommentDB_FindCommentsByArticleIDWithCursor failed to find root comments", "articleID", articleID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	hasNext := len(roots) > limit
+	if hasNext {
+		roots = roots[:limit]
+	}
+
+	// find replies of root comments level by level up to the max depth.
+	comments := roots
+	parentIds := commentIds(roots)
+	for depth := 1; depth <= adb.maxCommentDepth && len(parentIds) != 0; depth++ {
+		var replies []*model.Comment
+		if err := db.Unscoped().Model(new(model.Comment)).
+			Joins("Author").
+			Where("comments.parent_id IN (?)", parentIds).
+			Scopes(excludeMuted).
+			Order("comments.created_at ASC, comments.comment_id ASC").
+			Find(&replies).Error; err != nil {
+			logger.Errorw("CommentDB_FindCommentsByArticleIDWithCursor failed to find replies", "articleID", articleID, "err", err)
+			return nil, database.WrapError(err)
+		}
+		comments = append(comments, replies...)
+		parentIds = commentIds(replies)
+	}
+
+	if err := setCommentReactionCountsBulk(db, comments); err != nil {
+		logger.Errorw("CommentDB_FindCommentsByArticleIDWithCursor failed to fetch reactions", "articleID", articleID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	// find total count of comments.
+	var total int64
+	if err := db.Model(new(model.Comment)).
+		Where("comments.article_id = ? AND comments.hidden_at IS NULL", articleID).
+		Scopes(excludeMuted).
+		Count(&total).Error; err != nil {
+		logger.Errorw("CommentDB_FindCommentsByArticleIDWithCursor failed to fetch total count", "articleID", articleID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	res := &model.Comments{
+		Comments:      model.BuildCommentTree(comments),
+		CommentsCount: total,
+	}
+	if hasNext {
+		res.NextCursor = model.NewCommentCursor(roots[len(roots)-1])
+	}
+	return res, nil
+}
+
+func (adb *articleDB) FindCommentByID(ctx context.Context, articleID, commentID uint) (*model.Comment, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("CommentDB_FindCommentByID try to find a comment", "articleID", articleID, "commentID", commentID)
+
+	var comment model.Comment
+	if err := adb.db.WithContext(ctx).Model(new(model.Comment)).
+		Joins("Author").
+		Where("comment_id = ? AND article_id = ?", commentID, articleID).
+		First(&comment).Error; err != nil {
+		logger.Errorw("CommentDB_FindCommentByID failed to find a comment", "articleID", articleID, "commentID", commentID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	if err := setCommentReactionCountsBulk(adb.db.WithContext(ctx), []*model.Comment{&comment}); err != nil {
+		logger.Errorw("CommentDB_FindCommentByID failed to fetch reactions", "commentID", commentID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &comment, nil
+}
+
+func (adb *articleDB) UpdateComment(ctx context.Context, user *userModel.User, c *model.Comment) error {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("CommentDB_UpdateComment no user provided")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("CommentDB_UpdateComment try to update a comment", "userID", user.ID, "commentID", c.ID)
+
+	now := time.Now()
+	c.EditedAt = &now
+	result := adb.db.WithContext(ctx).
+		Model(c).
+		Select("Body", "EditedAt", "UpdatedAt").
+		Where("comment_id = ? AND author_id = ? AND article_id = ?", c.ID, user.ID, c.ArticleID).
+		Updates(c)
+	if result.Error != nil {
+		logger.Errorw("CommentDB_UpdateComment failed to update", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("CommentDB_UpdateComment failed to update the comment. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
 func (adb *articleDB) DeleteCommentByID(ctx context.Context, user *userModel.User, articleID, commentID uint) error {
 	logger := logging.FromContext(ctx)
 	if user == nil {
 		logger.Error("CommentDB_DeleteCommentByID no user provided")
 		return database.WrapError(gorm.ErrRecordNotFound)
 	}
-	logger.Debugw("CommentDB_DeleteCommentByID try to delete a comment", "userID", user.ID, "commentID", commentID)
+	logger.Debugw("CommentDB_DeleteCommentByID try to delete a comment", "userID", user.ID, "role", user.Role, "commentID", commentID)
 
-	result := adb.db.WithContext(ctx).
-		Where("comment_id = ? AND author_id = ? AND article_id = ?", commentID, user.ID, articleID).
-		Delete(&model.Comment{})
+	db := adb.db.WithContext(ctx).Where("comment_id = ? AND article_id = ?", commentID, articleID)
+	if !authutils.HasPermission(user.Role, authutils.PermissionDeleteAnyComment) {
+		db = db.Where("author_id = ?", user.ID)
+	}
+	result := db.Delete(&model.Comment{})
 	if result.Error != nil {
 		logger.Errorw("CommentDB_DeleteCommentByID failed to delete", "err", result.Error)
 		return database.WrapError(result.Error)
@@ -64,3 +246,11 @@ func (adb *articleDB) DeleteCommentByID(ctx context.Context, user *userModel.Use
 	}
 	return nil
 }
+
+func commentIds(comments []*model.Comment) []uint {
+	ids := make([]uint, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	return ids
+}
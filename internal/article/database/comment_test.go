@@ -2,10 +2,12 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"math"
 	"testing"
 	"time"
@@ -111,6 +113,20 @@ func (s *Suite) TestDeleteCommentByID() {
 	s.NoError(err)
 }
 
+func (s *Suite) TestDeleteCommentByID_Moderator() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+	moderator := &userModel.User{ID: s.u2.ID, Role: authutils.RoleModerator}
+
+	err := s.db.DeleteCommentByID(context.TODO(), moderator, a.ID, c.ID)
+
+	s.NoError(err)
+	_, err = s.db.FindCommentByID(context.TODO(), a.ID, c.ID)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
 func (s *Suite) TestDeleteCommentByID_Fail() {
 	a := newArticle("article1", "", "", *s.u1, nil)
 	s.NoError(s.db.Save(context.TODO(), a))
@@ -187,3 +203,283 @@ func newComment(body string, author userModel.User, a model.Article) *model.Comm
 		AuthorID:  author.ID,
 	}
 }
+
+func (s *Suite) TestSaveCommentReply() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	other := newArticle("article2", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	s.NoError(s.db.Save(context.TODO(), other))
+	parent := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), parent))
+
+	// reply to the parent
+	reply := newComment("reply1", *s.u2, *a)
+	reply.ParentID = &parent.ID
+	s.NoError(s.db.SaveComment(context.TODO(), reply))
+	find, err := s.db.FindCommentsByArticleID(context.TODO(), a.ID)
+	s.NoError(err)
+	s.Len(find, 2)
+	for _, c := range find {
+		if c.ID == reply.ID {
+			s.Equal(parent.ID, *c.ParentID)
+		} else {
+			s.Nil(c.ParentID)
+		}
+	}
+
+	// parent in another article
+	reply = newComment("reply2", *s.u2, *other)
+	reply.ParentID = &parent.ID
+	s.Equal(database.ErrRecordNotFound, s.db.SaveComment(context.TODO(), reply))
+
+	// deleted parent
+	s.NoError(s.db.DeleteCommentByID(context.TODO(), s.u1, a.ID, parent.ID))
+	reply = newComment("reply3", *s.u2, *a)
+	reply.ParentID = &parent.ID
+	s.Equal(database.ErrRecordNotFound, s.db.SaveComment(context.TODO(), reply))
+}
+
+func (s *Suite) TestSaveCommentReplyMaxDepth() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	maxDepth := s.db.(*articleDB).maxCommentDepth
+	parent := newComment("comment", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), parent))
+
+	// nest replies up to the max depth
+	for i := 1; i <= maxDepth; i++ {
+		reply := newComment(fmt.Sprintf("reply%d", i), *s.u2, *a)
+		reply.ParentID = &parent.ID
+		s.NoError(s.db.SaveComment(context.TODO(), reply))
+		parent = reply
+	}
+
+	// deeper than the max depth
+	reply := newComment("too deep", *s.u2, *a)
+	reply.ParentID = &parent.ID
+	s.Equal(model.ErrCommentTooDeep, s.db.SaveComment(context.TODO(), reply))
+
+	// all levels are loaded
+	comments, err := s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderNewest, nil, 10)
+	s.NoError(err)
+	s.EqualValues(maxDepth+1, comments.CommentsCount)
+	s.Len(model.FlattenComments(comments.Comments), maxDepth+1)
+}
+
+func (s *Suite) TestFindCommentsByArticleIDWithDeleted() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	parent := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), parent))
+	reply := newComment("reply1", *s.u2, *a)
+	reply.ParentID = &parent.ID
+	s.NoError(s.db.SaveComment(context.TODO(), reply))
+	s.NoError(s.db.DeleteCommentByID(context.TODO(), s.u1, a.ID, parent.ID))
+
+	find, err := s.db.FindCommentsByArticleID(context.TODO(), a.ID)
+
+	s.NoError(err)
+	roots := model.BuildCommentTree(find)
+	s.Len(roots, 1)
+	s.Equal(parent.ID, roots[0].ID)
+	s.Equal(model.DeletedCommentBody, roots[0].Body)
+	s.Len(roots[0].Replies, 1)
+	s.Equal(reply.ID, roots[0].Replies[0].ID)
+}
+
+func (s *Suite) TestPurgeDeletedCommentWithReplies() {
+	now := time.Now()
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	parent := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), parent))
+	reply := newComment("reply1", *s.u2, *a)
+	reply.ParentID = &parent.ID
+	s.NoError(s.db.SaveComment(context.TODO(), reply))
+	s.NoError(s.originDB.Model(new(model.Comment)).Where("comment_id = ?", parent.ID).Update("deleted_at", now.Add(-48*time.Hour)).Error)
+
+	// keep the parent while the reply exists
+	purged, err := s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+	s.NoError(err)
+	s.Zero(purged)
+
+	// purge both after the reply is deleted
+	s.NoError(s.originDB.Model(new(model.Comment)).Where("comment_id = ?", reply.ID).Update("deleted_at", now.Add(-48*time.Hour)).Error)
+	purged, err = s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+	s.NoError(err)
+	s.EqualValues(1, purged)
+	purged, err = s.db.PurgeDeleted(context.TODO(), now.Add(-24*time.Hour), 10)
+	s.NoError(err)
+	s.EqualValues(1, purged)
+}
+
+func (s *Suite) TestUpdateComment() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u2, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+
+	// when
+	c.Body = "updated comment1"
+	err := s.db.UpdateComment(context.TODO(), s.u2, c)
+
+	// then
+	s.NoError(err)
+	find, err := s.db.FindCommentByID(context.TODO(), a.ID, c.ID)
+	s.NoError(err)
+	s.Equal("updated comment1", find.Body)
+	s.Equal(s.u2.Name, find.Author.Name)
+	s.True(find.IsEdited())
+	s.WithinDuration(time.Now(), *find.EditedAt, time.Minute)
+	s.WithinDuration(*find.EditedAt, find.UpdatedAt, time.Second)
+}
+
+func (s *Suite) TestUpdateCommentFail() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u2, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+
+	cases := []struct {
+		name    string
+		user    *userModel.User
+		comment *model.Comment
+	}{
+		{
+			name:    "mismatch user",
+			user:    s.u1,
+			comment: &model.Comment{ID: c.ID, ArticleID: a.ID, Body: "updated"},
+		}, {
+			name:    "mismatch article id",
+			user:    s.u2,
+			comment: &model.Comment{ID: c.ID, ArticleID: a.ID + 1, Body: "updated"},
+		}, {
+			name:    "no user provided",
+			comment: &model.Comment{ID: c.ID, ArticleID: a.ID, Body: "updated"},
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			err := s.db.UpdateComment(context.TODO(), tc.user, tc.comment)
+
+			assert.Equal(t, database.ErrRecordNotFound, err)
+		})
+	}
+	find, err := s.db.FindCommentByID(context.TODO(), a.ID, c.ID)
+	s.NoError(err)
+	s.Equal("comment1", find.Body)
+	s.False(find.IsEdited())
+}
+
+func (s *Suite) TestFindCommentByIDNotFound() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	c := newComment("comment1", *s.u2, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), c))
+	s.NoError(s.db.DeleteCommentByID(context.TODO(), s.u2, a.ID, c.ID))
+
+	_, err := s.db.FindCommentByID(context.TODO(), a.ID, c.ID)
+
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestFindCommentsByArticleIDWithCursor() {
+	now := time.Now().Truncate(time.Second)
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	var roots []*model.Comment
+	for i := 0; i < 3; i++ {
+		c := newComment(fmt.Sprintf("comment%d", i+1), *s.u1, *a)
+		c.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		s.NoError(s.db.SaveComment(context.TODO(), c))
+		roots = append(roots, c)
+	}
+	reply := newComment("reply1", *s.u2, *a)
+	reply.ParentID = &roots[0].ID
+	s.NoError(s.db.SaveComment(context.TODO(), reply))
+	nested := newComment("reply2", *s.u3, *a)
+	nested.ParentID = &reply.ID
+	s.NoError(s.db.SaveComment(context.TODO(), nested))
+
+	// newest first
+	find, err := s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderNewest, nil, 2)
+	s.NoError(err)
+	s.EqualValues(5, find.CommentsCount)
+	s.Len(find.Comments, 2)
+	s.Equal(roots[2].ID, find.Comments[0].ID)
+	s.Equal(roots[1].ID, find.Comments[1].ID)
+	s.NotNil(find.NextCursor)
+
+	find, err = s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderNewest, find.NextCursor, 2)
+	s.NoError(err)
+	s.Len(find.Comments, 1)
+	s.Equal(roots[0].ID, find.Comments[0].ID)
+	s.Nil(find.NextCursor)
+	s.Len(find.Comments[0].Replies, 1)
+	s.Equal(reply.ID, find.Comments[0].Replies[0].ID)
+	s.Equal(s.u2.Name, find.Comments[0].Replies[0].Author.Name)
+	s.Len(find.Comments[0].Replies[0].Replies, 1)
+	s.Equal(nested.ID, find.Comments[0].Replies[0].Replies[0].ID)
+
+	// oldest first
+	find, err = s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderOldest, nil, 1)
+	s.NoError(err)
+	s.Len(find.Comments, 1)
+	s.Equal(roots[0].ID, find.Comments[0].ID)
+	find, err = s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderOldest, find.NextCursor, 10)
+	s.NoError(err)
+	s.Len(find.Comments, 2)
+	s.Equal(roots[1].ID, find.Comments[0].ID)
+	s.Equal(roots[2].ID, find.Comments[1].ID)
+	s.Nil(find.NextCursor)
+
+	// deleted root comments are placeholders if they have replies
+	s.NoError(s.db.DeleteCommentByID(context.TODO(), s.u1, a.ID, roots[0].ID))
+	s.NoError(s.db.DeleteCommentByID(context.TODO(), s.u1, a.ID, roots[1].ID))
+	find, err = s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, 0, model.CommentOrderOldest, nil, 10)
+	s.NoError(err)
+	s.EqualValues(3, find.CommentsCount)
+	s.Len(find.Comments, 2)
+	s.Equal(roots[0].ID, find.Comments[0].ID)
+	s.Equal(model.DeletedCommentBody, find.Comments[0].Body)
+	s.Equal(roots[2].ID, find.Comments[1].ID)
+
+	// comments count of the article
+	article, err := s.db.FindBySlug(context.TODO(), nil, a.Slug)
+	s.NoError(err)
+	s.Equal(3, article.CommentsCount)
+}
+
+func (s *Suite) TestFindCommentsByArticleIDWithCursor_MutedAuthors() {
+	a := newArticle("article1", "", "", *s.u1, nil)
+	s.NoError(s.db.Save(context.TODO(), a))
+	root1 := newComment("comment1", *s.u1, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), root1))
+	root2 := newComment("comment2", *s.u2, *a)
+	s.NoError(s.db.SaveComment(context.TODO(), root2))
+	reply := newComment("reply1", *s.u2, *a)
+	reply.ParentID = &root1.ID
+	s.NoError(s.db.SaveComment(context.TODO(), reply))
+	nested := newComment("reply2", *s.u1, *a)
+	nested.ParentID = &reply.ID
+	s.NoError(s.db.SaveComment(context.TODO(), nested))
+	// user3 mutes user2
+	s.NoError(s.originDB.Create(&userModel.Mute{UserID: s.u3.ID, MuteID: s.u2.ID}).Error)
+
+	// muted authors are excluded with their replies
+	find, err := s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, s.u3.ID, model.CommentOrderOldest, nil, 1)
+	s.NoError(err)
+	s.EqualValues(2, find.CommentsCount)
+	s.Len(find.Comments, 1)
+	s.Equal(root1.ID, find.Comments[0].ID)
+	s.Empty(find.Comments[0].Replies)
+	s.Nil(find.NextCursor)
+
+	// other users
+	find, err = s.db.FindCommentsByArticleIDWithCursor(context.TODO(), a.ID, s.u1.ID, model.CommentOrderOldest, nil, 10)
+	s.NoError(err)
+	s.EqualValues(4, find.CommentsCount)
+	s.Len(find.Comments, 2)
+	s.Len(find.Comments[0].Replies, 1)
+}
@@ -7,6 +7,8 @@ import (
 
 	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 
+	time "time"
+
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
@@ -17,6 +19,43 @@ type ArticleDB struct {
 	mock.Mock
 }
 
+// AddReaction provides a mock function with given fields: ctx, user, targetType, targetID, kind
+func (_m *ArticleDB) AddReaction(ctx context.Context, user *model.User, targetType string, targetID uint, kind string) error {
+	ret := _m.Called(ctx, user, targetType, targetID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string, uint, string) error); ok {
+		r0 = rf(ctx, user, targetType, targetID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountContentsByAuthor provides a mock function with given fields: ctx, userID
+func (_m *ArticleDB) CountContentsByAuthor(ctx context.Context, userID uint) (*articlemodel.ContentCounts, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *articlemodel.ContentCounts
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *articlemodel.ContentCounts); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.ContentCounts)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteBySlug provides a mock function with given fields: ctx, user, slug
 func (_m *ArticleDB) DeleteBySlug(ctx context.Context, user *model.User, slug string) error {
 	ret := _m.Called(ctx, user, slug)
@@ -45,6 +84,27 @@ func (_m *ArticleDB) DeleteCommentByID(ctx context.Context, user *model.User, ar
 	return r0
 }
 
+// DismissReports provides a mock function with given fields: ctx, user, r
+func (_m *ArticleDB) DismissReports(ctx context.Context, user *model.User, r *articlemodel.Report) (int64, error) {
+	ret := _m.Called(ctx, user, r)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, *articlemodel.Report) int64); ok {
+		r0 = rf(ctx, user, r)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, *articlemodel.Report) error); ok {
+		r1 = rf(ctx, user, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FavoriteArticle provides a mock function with given fields: ctx, user, articleID
 func (_m *ArticleDB) FavoriteArticle(ctx context.Context, user *model.User, articleID uint) error {
 	ret := _m.Called(ctx, user, articleID)
@@ -82,6 +142,29 @@ func (_m *ArticleDB) FindArticlesByAuthors(ctx context.Context, user *model.User
 	return r0, r1
 }
 
+// FindArticlesByAuthorsWithCursor provides a mock function with given fields: ctx, user, authors, cursor, limit
+func (_m *ArticleDB) FindArticlesByAuthorsWithCursor(ctx context.Context, user *model.User, authors []uint, cursor *articlemodel.ArticleCursor, limit int) (*articlemodel.Articles, error) {
+	ret := _m.Called(ctx, user, authors, cursor, limit)
+
+	var r0 *articlemodel.Articles
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, []uint, *articlemodel.ArticleCursor, int) *articlemodel.Articles); ok {
+		r0 = rf(ctx, user, authors, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Articles)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, []uint, *articlemodel.ArticleCursor, int) error); ok {
+		r1 = rf(ctx, user, authors, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindArticlesByQuery provides a mock function with given fields: ctx, user, query, offset, limit
 func (_m *ArticleDB) FindArticlesByQuery(ctx context.Context, user *model.User, query articlemodel.ArticleQuery, offset int, limit int) (*articlemodel.Articles, error) {
 	ret := _m.Called(ctx, user, query, offset, limit)
@@ -105,6 +188,29 @@ func (_m *ArticleDB) FindArticlesByQuery(ctx context.Context, user *model.User,
 	return r0, r1
 }
 
+// FindArticlesByQueryWithCursor provides a mock function with given fields: ctx, user, query, cursor, limit
+func (_m *ArticleDB) FindArticlesByQueryWithCursor(ctx context.Context, user *model.User, query articlemodel.ArticleQuery, cursor *articlemodel.ArticleCursor, limit int) (*articlemodel.Articles, error) {
+	ret := _m.Called(ctx, user, query, cursor, limit)
+
+	var r0 *articlemodel.Articles
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, articlemodel.ArticleQuery, *articlemodel.ArticleCursor, int) *articlemodel.Articles); ok {
+		r0 = rf(ctx, user, query, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Articles)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, articlemodel.ArticleQuery, *articlemodel.ArticleCursor, int) error); ok {
+		r1 = rf(ctx, user, query, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindBySlug provides a mock function with given fields: ctx, user, slug
 func (_m *ArticleDB) FindBySlug(ctx context.Context, user *model.User, slug string) (*articlemodel.Article, error) {
 	ret := _m.Called(ctx, user, slug)
@@ -128,6 +234,29 @@ func (_m *ArticleDB) FindBySlug(ctx context.Context, user *model.User, slug stri
 	return r0, r1
 }
 
+// FindCommentByID provides a mock function with given fields: ctx, articleID, commentID
+func (_m *ArticleDB) FindCommentByID(ctx context.Context, articleID uint, commentID uint) (*articlemodel.Comment, error) {
+	ret := _m.Called(ctx, articleID, commentID)
+
+	var r0 *articlemodel.Comment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *articlemodel.Comment); ok {
+		r0 = rf(ctx, articleID, commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Comment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, articleID, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindCommentsByArticleID provides a mock function with given fields: ctx, articleID
 func (_m *ArticleDB) FindCommentsByArticleID(ctx context.Context, articleID uint) ([]*articlemodel.Comment, error) {
 	ret := _m.Called(ctx, articleID)
@@ -151,6 +280,188 @@ func (_m *ArticleDB) FindCommentsByArticleID(ctx context.Context, articleID uint
 	return r0, r1
 }
 
+// FindCommentsByArticleIDWithCursor provides a mock function with given fields: ctx, articleID, userID, order, cursor, limit
+func (_m *ArticleDB) FindCommentsByArticleIDWithCursor(ctx context.Context, articleID uint, userID uint, order string, cursor *articlemodel.CommentCursor, limit int) (*articlemodel.Comments, error) {
+	ret := _m.Called(ctx, articleID, userID, order, cursor, limit)
+
+	var r0 *articlemodel.Comments
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, string, *articlemodel.CommentCursor, int) *articlemodel.Comments); ok {
+		r0 = rf(ctx, articleID, userID, order, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Comments)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, string, *articlemodel.CommentCursor, int) error); ok {
+		r1 = rf(ctx, articleID, userID, order, cursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCurrentSlug provides a mock function with given fields: ctx, user, slug
+func (_m *ArticleDB) FindCurrentSlug(ctx context.Context, user *model.User, slug string) (string, error) {
+	ret := _m.Called(ctx, user, slug)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string) string); ok {
+		r0 = rf(ctx, user, slug)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, string) error); ok {
+		r1 = rf(ctx, user, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeletedArticles provides a mock function with given fields: ctx, user, offset, limit
+func (_m *ArticleDB) FindDeletedArticles(ctx context.Context, user *model.User, offset int, limit int) (*articlemodel.Articles, error) {
+	ret := _m.Called(ctx, user, offset, limit)
+
+	var r0 *articlemodel.Articles
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, int, int) *articlemodel.Articles); ok {
+		r0 = rf(ctx, user, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Articles)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, int, int) error); ok {
+		r1 = rf(ctx, user, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDraftArticles provides a mock function with given fields: ctx, user, offset, limit
+func (_m *ArticleDB) FindDraftArticles(ctx context.Context, user *model.User, offset int, limit int) (*articlemodel.Articles, error) {
+	ret := _m.Called(ctx, user, offset, limit)
+
+	var r0 *articlemodel.Articles
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, int, int) *articlemodel.Articles); ok {
+		r0 = rf(ctx, user, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Articles)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, int, int) error); ok {
+		r1 = rf(ctx, user, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindReportByID provides a mock function with given fields: ctx, reportID
+func (_m *ArticleDB) FindReportByID(ctx context.Context, reportID uint) (*articlemodel.Report, error) {
+	ret := _m.Called(ctx, reportID)
+
+	var r0 *articlemodel.Report
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *articlemodel.Report); ok {
+		r0 = rf(ctx, reportID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Report)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, reportID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindReports provides a mock function with given fields: ctx, query, offset, limit
+func (_m *ArticleDB) FindReports(ctx context.Context, query articlemodel.ReportQuery, offset int, limit int) (*articlemodel.Reports, error) {
+	ret := _m.Called(ctx, query, offset, limit)
+
+	var r0 *articlemodel.Reports
+	if rf, ok := ret.Get(0).(func(context.Context, articlemodel.ReportQuery, int, int) *articlemodel.Reports); ok {
+		r0 = rf(ctx, query, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Reports)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, articlemodel.ReportQuery, int, int) error); ok {
+		r1 = rf(ctx, query, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRevision provides a mock function with given fields: ctx, articleID, number
+func (_m *ArticleDB) FindRevision(ctx context.Context, articleID uint, number uint) (*articlemodel.ArticleRevision, error) {
+	ret := _m.Called(ctx, articleID, number)
+
+	var r0 *articlemodel.ArticleRevision
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *articlemodel.ArticleRevision); ok {
+		r0 = rf(ctx, articleID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.ArticleRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, articleID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRevisions provides a mock function with given fields: ctx, articleID
+func (_m *ArticleDB) FindRevisions(ctx context.Context, articleID uint) ([]*articlemodel.ArticleRevision, error) {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 []*articlemodel.ArticleRevision
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*articlemodel.ArticleRevision); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*articlemodel.ArticleRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTags provides a mock function with given fields: ctx
 func (_m *ArticleDB) FindTags(ctx context.Context) ([]*articlemodel.Tag, error) {
 	ret := _m.Called(ctx)
@@ -174,6 +485,143 @@ func (_m *ArticleDB) FindTags(ctx context.Context) ([]*articlemodel.Tag, error)
 	return r0, r1
 }
 
+// FindUserReactions provides a mock function with given fields: ctx, user, targetType, targetIDs
+func (_m *ArticleDB) FindUserReactions(ctx context.Context, user *model.User, targetType string, targetIDs []uint) (map[uint][]string, error) {
+	ret := _m.Called(ctx, user, targetType, targetIDs)
+
+	var r0 map[uint][]string
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string, []uint) map[uint][]string); ok {
+		r0 = rf(ctx, user, targetType, targetIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, string, []uint) error); ok {
+		r1 = rf(ctx, user, targetType, targetIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsFavorited provides a mock function with given fields: ctx, user, articleID
+func (_m *ArticleDB) IsFavorited(ctx context.Context, user *model.User, articleID uint) (bool, error) {
+	ret := _m.Called(ctx, user, articleID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, uint) bool); ok {
+		r0 = rf(ctx, user, articleID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, uint) error); ok {
+		r1 = rf(ctx, user, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishScheduled provides a mock function with given fields: ctx, now, limit
+func (_m *ArticleDB) PublishScheduled(ctx context.Context, now time.Time, limit int) ([]*articlemodel.Article, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	var r0 []*articlemodel.Article
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*articlemodel.Article); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*articlemodel.Article)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, deletedBefore, limit
+func (_m *ArticleDB) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore, limit)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = rf(ctx, deletedBefore, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, deletedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveReaction provides a mock function with given fields: ctx, user, targetType, targetID, kind
+func (_m *ArticleDB) RemoveReaction(ctx context.Context, user *model.User, targetType string, targetID uint, kind string) error {
+	ret := _m.Called(ctx, user, targetType, targetID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string, uint, string) error); ok {
+		r0 = rf(ctx, user, targetType, targetID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveReports provides a mock function with given fields: ctx, user, r
+func (_m *ArticleDB) ResolveReports(ctx context.Context, user *model.User, r *articlemodel.Report) (int64, error) {
+	ret := _m.Called(ctx, user, r)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, *articlemodel.Report) int64); ok {
+		r0 = rf(ctx, user, r)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, *articlemodel.Report) error); ok {
+		r1 = rf(ctx, user, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreBySlug provides a mock function with given fields: ctx, user, slug
+func (_m *ArticleDB) RestoreBySlug(ctx context.Context, user *model.User, slug string) error {
+	ret := _m.Called(ctx, user, slug)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string) error); ok {
+		r0 = rf(ctx, user, slug)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, a
 func (_m *ArticleDB) Save(ctx context.Context, a *articlemodel.Article) error {
 	ret := _m.Called(ctx, a)
@@ -202,6 +650,50 @@ func (_m *ArticleDB) SaveComment(ctx context.Context, c *articlemodel.Comment) e
 	return r0
 }
 
+// SaveReport provides a mock function with given fields: ctx, r, threshold
+func (_m *ArticleDB) SaveReport(ctx context.Context, r *articlemodel.Report, threshold int) (bool, error) {
+	ret := _m.Called(ctx, r, threshold)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *articlemodel.Report, int) bool); ok {
+		r0 = rf(ctx, r, threshold)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *articlemodel.Report, int) error); ok {
+		r1 = rf(ctx, r, threshold)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchArticles provides a mock function with given fields: ctx, user, keyword, offset, limit
+func (_m *ArticleDB) SearchArticles(ctx context.Context, user *model.User, keyword string, offset int, limit int) (*articlemodel.Articles, error) {
+	ret := _m.Called(ctx, user, keyword, offset, limit)
+
+	var r0 *articlemodel.Articles
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, string, int, int) *articlemodel.Articles); ok {
+		r0 = rf(ctx, user, keyword, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*articlemodel.Articles)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, string, int, int) error); ok {
+		r1 = rf(ctx, user, keyword, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnFavoriteArticle provides a mock function with given fields: ctx, user, articleID
 func (_m *ArticleDB) UnFavoriteArticle(ctx context.Context, user *model.User, articleID uint) error {
 	ret := _m.Called(ctx, user, articleID)
@@ -229,3 +721,17 @@ func (_m *ArticleDB) Update(ctx context.Context, user *model.User, a *articlemod
 
 	return r0
 }
+
+// UpdateComment provides a mock function with given fields: ctx, user, c
+func (_m *ArticleDB) UpdateComment(ctx context.Context, user *model.User, c *articlemodel.Comment) error {
+	ret := _m.Called(ctx, user, c)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, *articlemodel.Comment) error); ok {
+		r0 = rf(ctx, user, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
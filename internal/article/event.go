@@ -0,0 +1,49 @@
+package article
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	types2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+)
+
+// publishArticle publishes events of given types about the article a with the author profile.
+func (h *Handler) publishArticle(ctx context.Context, a *model.Article, eventTypes ...string) {
+	author, err := h.userDB.FindByID(ctx, a.AuthorID)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("ArticleHandler_publishArticle failed to find the author", "authorID", a.AuthorID, "err", err)
+		return
+	}
+	article := *a
+	article.Author = *author
+	payload := types2.ToArticleResponse(&article)
+	for _, eventType := range eventTypes {
+		h.eventBus.Publish(ctx, &event.Event{
+			Type:      eventType,
+			ActorID:   a.AuthorID,
+			UserID:    a.AuthorID,
+			ArticleID: a.ID,
+			Payload:   payload,
+		})
+	}
+}
+
+// publishComment publishes an event of given comment created on the article a with the author profile.
+func (h *Handler) publishComment(ctx context.Context, a *model.Article, c *model.Comment) {
+	author, err := h.userDB.FindByID(ctx, c.AuthorID)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("ArticleHandler_publishComment failed to find the author", "authorID", c.AuthorID, "err", err)
+		return
+	}
+	created := *c
+	created.Author = *author
+	h.eventBus.Publish(ctx, &event.Event{
+		Type:      event.TypeCommentCreated,
+		ActorID:   c.AuthorID,
+		UserID:    a.AuthorID,
+		ArticleID: a.ID,
+		CommentID: c.ID,
+		Payload:   types2.ToArticleCommentResponse(a, &created),
+	})
+}
@@ -1,17 +1,23 @@
 package article
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
 	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/pubsub"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
 	userDB "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 )
 
 type Handler struct {
 	cfg       *config.Config
 	articleDB articleDB.ArticleDB
 	userDB    userDB.UserDB
+	eventBus  *event.Bus
+	pubSub    pubsub.PubSub
 }
 
 // NewHandler returns a new Handle from given serverenv.ServerEnv and config.Config.
@@ -20,6 +26,8 @@ func NewHandler(env *serverenv.ServerEnv, conf *config.Config) (*Handler, error)
 		cfg:       conf,
 		articleDB: env.GetArticleDB(),
 		userDB:    env.GetUserDB(),
+		eventBus:  env.GetEventBus(),
+		pubSub:    env.GetPubSub(),
 	}, nil
 }
 
@@ -30,20 +38,53 @@ func (h *Handler) Route(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
 	articleGroup.Use(authMiddleware)
 	articleGroup.GET("", h.handleGetArticles)
 	articleGroup.GET("/feed", h.handleGetFeeds)
+	articleGroup.GET("/feed/stream", h.handleFeedStream)
+	articleGroup.GET("/search", h.handleSearchArticles)
 	articleGroup.GET("/:slug", h.handleGetArticle)
 	articleGroup.POST("", h.handleCreateArticle)
 	articleGroup.PUT("/:slug", h.handleUpdateArticle)
 	articleGroup.DELETE("/:slug", h.handleDeleteArticle)
+	articleGroup.POST("/:slug/restore", h.handleRestoreArticle)
 	articleGroup.POST("/:slug/favorite", h.handleFavorite)
 	articleGroup.DELETE("/:slug/favorite", h.handleUnFavorite)
+	articleGroup.POST("/:slug/reactions/:kind", h.handleReactArticle)
+	articleGroup.DELETE("/:slug/reactions/:kind", h.handleUnReactArticle)
+	articleGroup.POST("/:slug/report", h.handleReportArticle)
+	articleGroup.GET("/:slug/revisions", h.handleGetRevisions)
+	articleGroup.GET("/:slug/revisions/:n", h.handleGetRevision)
+	articleGroup.GET("/:slug/revisions/:n/diff", h.handleGetRevisionDiff)
+	articleGroup.POST("/:slug/revisions/:n/restore", h.handleRestoreRevision)
 
 	// comments
 	commentGroup := e.Group("/articles/:slug/comments")
 	commentGroup.Use(authMiddleware)
 	commentGroup.GET("", h.handleGetComments)
 	commentGroup.POST("", h.handleCreateComment)
+	commentGroup.PUT("/:id", h.handleUpdateComment)
 	commentGroup.DELETE("/:id", h.handleDeleteComment)
+	commentGroup.POST("/:id/reactions/:kind", h.handleReactComment)
+	commentGroup.DELETE("/:id/reactions/:kind", h.handleUnReactComment)
+	commentGroup.POST("/:id/report", h.handleReportComment)
+
+	// moderation
+	moderationGroup := e.Group("/moderation/reports")
+	moderationGroup.Use(authMiddleware, authutils.NewPermissionMiddleware(h.findRole, authutils.PermissionModerateContent))
+	moderationGroup.GET("", h.handleGetReports)
+	moderationGroup.GET("/:id", h.handleGetReport)
+	moderationGroup.POST("/:id/resolve", h.handleResolveReport)
+	moderationGroup.POST("/:id/dismiss", h.handleDismissReport)
+
+	// trash
+	e.GET("/user/trash", h.handleGetTrash, authMiddleware)
+
+	// drafts
+	e.GET("/user/drafts", h.handleGetDrafts, authMiddleware)
 
 	// tags
 	e.GET("/tags", h.handleGetTags)
 }
+
+// findRole returns the current role of given user for authutils.NewPermissionMiddleware.
+func (h *Handler) findRole(ctx context.Context, userID uint) (string, error) {
+	return userDB.FindRole(ctx, h.userDB, userID)
+}
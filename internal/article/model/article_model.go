@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"github.com/gosimple/slug"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"gorm.io/gorm"
@@ -13,14 +14,38 @@ const (
 	TableNameTag             = "tags"
 	TableNameArticleTag      = "article_tags"
 	TableNameComment         = "comments"
+	TableNameSlugHistory     = "slug_history"
+)
+
+// Article statuses. Only published articles are visible to users except the author.
+// Scheduled articles are published by the scheduler after PublishAt.
+// Hidden articles are published ones hidden by moderators and can not be published by the author.
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusPublished = "published"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusHidden    = "hidden"
 )
 
 var EmptyArticles = &Articles{Articles: make([]*Article, 0), ArticlesCount: 0}
 
 // Articles represents article list with total size.
+// NextCursor is a position of the last article if more articles exist, otherwise nil.
 type Articles struct {
-	Articles      []*Article `json:"articles"`
-	ArticlesCount int64      `json:"articlesCount"`
+	Articles      []*Article     `json:"articles"`
+	ArticlesCount int64          `json:"articlesCount"`
+	NextCursor    *ArticleCursor `json:"-"`
+}
+
+// ContentCounts represents the number of contents written by an user.
+// Articles are published ones, Drafts are not published ones such as drafts, scheduled or hidden
+// and DeletedArticles are ones in the trash.
+type ContentCounts struct {
+	Articles        int64 `json:"articles"`
+	Drafts          int64 `json:"drafts"`
+	DeletedArticles int64 `json:"deletedArticles"`
+	Comments        int64 `json:"comments"`
+	Favorites       int64 `json:"favorites"`
 }
 
 // Article represents database model for articles.
@@ -35,13 +60,26 @@ type Article struct {
 	Tags        []*Tag            `gorm:"many2many:article_tags;association_autocreate:false"`
 	Favorites   []ArticleFavorite `gorm:"many2many:article_favorites;"`
 	Comment     []Comment         `gorm:"ForeignKey:ArticleID"`
+	Status      string            `gorm:"column:status"`
+	PublishAt   *time.Time        `gorm:"column:publish_at"`
+	PublishedAt *time.Time        `gorm:"column:published_at"`
 
 	CreatedAt time.Time      `gorm:"column:created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+	// DeletedBy is an id of a user who deleted this article.
+	DeletedBy *uint `gorm:"column:deleted_by"`
 
 	Favorited      bool `gorm:"-"`
 	FavoritesCount int  `gorm:"-"`
+	CommentsCount  int  `gorm:"-"`
+
+	// Reactions is the number of reactions by kind and UserReactions is kinds of reactions from a user.
+	Reactions     map[string]int `gorm:"-"`
+	UserReactions []string       `gorm:"-"`
+
+	// Snippet is a highlighted part of the article which is only set by search.
+	Snippet string `gorm:"-"`
 }
 
 func (a *Article) TableName() string {
@@ -49,13 +87,62 @@ func (a *Article) TableName() string {
 }
 
 func (a *Article) BeforeCreate(_ *gorm.DB) error {
-	a.Slug = slug.Make(a.Title)
+	if a.Slug == "" {
+		a.Slug = MakeSlug(a.Title, 1)
+	}
+	if a.Status == "" {
+		a.Status = ArticleStatusPublished
+	}
+	if a.Status == ArticleStatusPublished {
+		now := time.Now()
+		if a.PublishAt == nil {
+			a.PublishAt = &now
+		}
+		if a.PublishedAt == nil {
+			a.PublishedAt = &now
+		}
+	}
 	return nil
 }
 
-func (a *Article) BeforeUpdate(_ *gorm.DB) error {
-	a.Slug = slug.Make(a.Title)
-	return nil
+// IsPublished returns true if this article is visible to all users, otherwise false.
+func (a *Article) IsPublished() bool {
+	return a.Status == "" || a.Status == ArticleStatusPublished
+}
+
+// SortedAt returns a time used for ordering listings which is the published time if published,
+// otherwise the created time.
+func (a *Article) SortedAt() time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	return a.CreatedAt
+}
+
+// IsHidden returns true if this article is hidden by moderators, otherwise false.
+func (a *Article) IsHidden() bool {
+	return a.Status == ArticleStatusHidden
+}
+
+// MakeSlug returns a slug of given title. A suffix "-n" is appended if n is greater than 1
+// to make the slug unique.
+func MakeSlug(title string, n int) string {
+	s := slug.Make(title)
+	if n <= 1 {
+		return s
+	}
+	return fmt.Sprintf("%s-%d", s, n)
+}
+
+// SlugHistory represents a previous slug of an article which is redirected to the current one.
+type SlugHistory struct {
+	Slug      string    `gorm:"column:slug;primaryKey"`
+	ArticleID uint      `gorm:"column:article_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (sh SlugHistory) TableName() string {
+	return TableNameSlugHistory
 }
 
 // ArticleFavorite represents relation articles and favoraties.
@@ -99,11 +186,21 @@ type Comment struct {
 	ID        uint   `gorm:"column:comment_id"`
 	Body      string `gorm:"column:body"`
 	ArticleID uint   `gorm:"column:article_id"`
+	ParentID  *uint  `gorm:"column:parent_id"`
 	Author    userModel.User
 	AuthorID  uint           `gorm:"column:author_id"`
 	CreatedAt time.Time      `gorm:"column:created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+	EditedAt  *time.Time     `gorm:"column:edited_at"`
+	HiddenAt  *time.Time     `gorm:"column:hidden_at"`
+
+	// Reactions is the number of reactions by kind and UserReactions is kinds of reactions from a user.
+	Reactions     map[string]int `gorm:"-"`
+	UserReactions []string       `gorm:"-"`
+
+	// Replies is set by BuildCommentTree.
+	Replies []*Comment `gorm:"-"`
 }
 
 func (c Comment) TableName() string {
@@ -1,8 +1,81 @@
 package model
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor an error if given cursor value can not be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 // ArticleQuery is used for quering recent articles.
 type ArticleQuery struct {
 	Tag         string
 	Author      string
 	FavoritedBy string
 }
+
+// ArticleCursor represents a position of articles ordered by published time and article_id in descending order.
+// Articles published before the position will be returned if use this cursor.
+// The created time is used instead of the published time for articles which are not published.
+type ArticleCursor struct {
+	PublishedAt time.Time
+	ArticleID   uint
+}
+
+// NewArticleCursor returns a new ArticleCursor positioned at given article.
+func NewArticleCursor(a *Article) *ArticleCursor {
+	return &ArticleCursor{
+		PublishedAt: a.SortedAt(),
+		ArticleID:   a.ID,
+	}
+}
+
+// Encode returns an opaque string of this cursor.
+func (c *ArticleCursor) Encode() string {
+	return encodeCursor(c.PublishedAt, c.ArticleID)
+}
+
+// DecodeArticleCursor decodes given cursor string which is generated by ArticleCursor.Encode.
+// ErrInvalidCursor will be returned if given cursor is malformed.
+func DecodeArticleCursor(cursor string) (*ArticleCursor, error) {
+	publishedAt, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &ArticleCursor{
+		PublishedAt: publishedAt,
+		ArticleID:   id,
+	}, nil
+}
+
+// encodeCursor returns an opaque string of a position from created time and id.
+func encodeCursor(createdAt time.Time, id uint) string {
+	raw := fmt.Sprintf("%d:%d", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns created time and id from given cursor string which is generated by encodeCursor.
+func decodeCursor(cursor string) (time.Time, uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	parts := strings.Split(string(data), ":")
+	if len(parts) != 2 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return time.Unix(0, nanos), uint(id), nil
+}
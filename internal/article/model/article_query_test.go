@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestArticleCursor(t *testing.T) {
+	cursor := &ArticleCursor{
+		PublishedAt: time.Date(2021, 8, 1, 10, 20, 30, 0, time.UTC),
+		ArticleID:   15,
+	}
+
+	decoded, err := DecodeArticleCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.True(t, cursor.PublishedAt.Equal(decoded.PublishedAt))
+	assert.Equal(t, cursor.ArticleID, decoded.ArticleID)
+}
+
+func TestDecodeArticleCursor_Fail(t *testing.T) {
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{
+			name:   "not base64",
+			cursor: "!!invalid!!",
+		}, {
+			name:   "missing article id",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("1627813230000000000")),
+		}, {
+			name:   "invalid created at",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("abc:15")),
+		}, {
+			name:   "zero article id",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("1627813230000000000:0")),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := DecodeArticleCursor(tc.cursor)
+			assert.Nil(t, c)
+			assert.Equal(t, ErrInvalidCursor, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"time"
+)
+
+const TableNameArticleRevision = "article_revisions"
+
+// ArticleRevision represents database model for article revisions.
+// A revision is stored whenever an article is saved or updated and Number starts from 1 in each article.
+type ArticleRevision struct {
+	ID          uint      `gorm:"column:revision_id"`
+	ArticleID   uint      `gorm:"column:article_id"`
+	Number      uint      `gorm:"column:number"`
+	Title       string    `gorm:"column:title"`
+	Description string    `gorm:"column:description"`
+	Body        string    `gorm:"column:body"`
+	EditorID    uint      `gorm:"column:editor_id"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (r ArticleRevision) TableName() string {
+	return TableNameArticleRevision
+}
+
+// NewArticleRevision returns a new ArticleRevision from current contents of given article.
+func NewArticleRevision(a *Article, number, editorID uint) *ArticleRevision {
+	return &ArticleRevision{
+		ArticleID:   a.ID,
+		Number:      number,
+		Title:       a.Title,
+		Description: a.Description,
+		Body:        a.Body,
+		EditorID:    editorID,
+	}
+}
+
+// Text returns a plain text of this revision used for diff.
+func (r *ArticleRevision) Text() string {
+	return fmt.Sprintf("Title: %s\nDescription: %s\n\n%s", r.Title, r.Description, r.Body)
+}
+
+// UnifiedDiff returns a unified diff from revision from to revision to with 3 lines of context.
+// An empty string will be returned if the revisions have same contents.
+func UnifiedDiff(from, to *ArticleRevision) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from.Text()),
+		B:        difflib.SplitLines(to.Text()),
+		FromFile: fmt.Sprintf("revision/%d", from.Number),
+		ToFile:   fmt.Sprintf("revision/%d", to.Number),
+		Context:  3,
+	})
+}
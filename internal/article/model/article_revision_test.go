@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	from := &ArticleRevision{Number: 1, Title: "title", Description: "description", Body: "line1\nline2\nline3"}
+	to := &ArticleRevision{Number: 2, Title: "title", Description: "new description", Body: "line1\nline2 updated\nline3"}
+
+	diff, err := UnifiedDiff(from, to)
+
+	assert.NoError(t, err)
+	expected := "--- revision/1\n" +
+		"+++ revision/2\n" +
+		"@@ -1,6 +1,6 @@\n" +
+		" Title: title\n" +
+		"-Description: description\n" +
+		"+Description: new description\n" +
+		" \n" +
+		" line1\n" +
+		"-line2\n" +
+		"+line2 updated\n" +
+		" line3\n"
+	assert.Equal(t, expected, diff)
+}
+
+func TestUnifiedDiffSameContents(t *testing.T) {
+	from := &ArticleRevision{Number: 1, Title: "title", Description: "description", Body: "body"}
+	to := &ArticleRevision{Number: 2, Title: "title", Description: "description", Body: "body"}
+
+	diff, err := UnifiedDiff(from, to)
+
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
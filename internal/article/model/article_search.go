@@ -0,0 +1,159 @@
+package model
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+	snippetEllipsis  = "..."
+)
+
+// ParseSearchTerms splits given keyword into distinct lower case terms.
+// Characters except letters and digits are used as separators, so operators of full-text search are removed.
+func ParseSearchTerms(keyword string) []string {
+	var (
+		terms []string
+		seen  = make(map[string]struct{})
+	)
+	for _, f := range strings.FieldsFunc(keyword, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		term := strings.ToLower(f)
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// ToBooleanModeQuery returns a search string of MySQL's boolean mode full-text search from given terms.
+// Each term is required and matched as a prefix i.e. "+term1* +term2*".
+func ToBooleanModeQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = "+" + term + "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// MakeSnippet returns a html escaped snippet of given text which is around the first matched term
+// and highlights all terms with <em> tag. Leading part of given text will be returned if no terms matched.
+// maxLength is the maximum number of characters in given text to include.
+func MakeSnippet(text string, terms []string, maxLength int) string {
+	if text == "" || maxLength <= 0 {
+		return ""
+	}
+	var (
+		runes = []rune(text)
+		lower = []rune(strings.ToLower(text))
+	)
+	if len(lower) != len(runes) {
+		// lower case of some characters have different length, so match with original text.
+		lower = runes
+	}
+	matches := findMatches(lower, terms)
+
+	// find a window of the snippet.
+	start := 0
+	if len(matches) != 0 && len(runes) > maxLength {
+		start = matches[0].start - maxLength/4
+		if start < 0 {
+			start = 0
+		}
+		if start+maxLength > len(runes) {
+			start = len(runes) - maxLength
+		}
+	}
+	end := start + maxLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString(snippetEllipsis)
+	}
+	pos := start
+	for _, m := range matches {
+		if m.start < pos || m.end > end {
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		sb.WriteString(highlightPreTag)
+		sb.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		sb.WriteString(highlightPostTag)
+		pos = m.end
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString(snippetEllipsis)
+	}
+	return sb.String()
+}
+
+type match struct {
+	start int
+	end   int
+}
+
+// findMatches returns non overlapped positions of given terms in text ordered by start position.
+func findMatches(text []rune, terms []string) []match {
+	var matches []match
+	for _, term := range terms {
+		t := []rune(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(text); i++ {
+			if string(text[i:i+len(t)]) == term {
+				matches = append(matches, match{start: i, end: i + len(t)})
+				i += len(t) - 1
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start == matches[j].start {
+			return matches[i].end > matches[j].end
+		}
+		return matches[i].start < matches[j].start
+	})
+
+	var res []match
+	for _, m := range matches {
+		if len(res) != 0 && m.start < res[len(res)-1].end {
+			continue
+		}
+		res = append(res, m)
+	}
+	return res
+}
+
+// firstMatchedText returns the first text which contains any given terms, otherwise the last non empty text.
+func firstMatchedText(terms []string, texts ...string) string {
+	var last string
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		last = text
+		lower := strings.ToLower(text)
+		for _, term := range terms {
+			if term != "" && strings.Contains(lower, term) {
+				return text
+			}
+		}
+	}
+	return last
+}
+
+// SetSnippet sets Snippet field from body or description which contains given terms.
+func (a *Article) SetSnippet(terms []string, maxLength int) {
+	a.Snippet = MakeSnippet(firstMatchedText(terms, a.Body, a.Description), terms, maxLength)
+}
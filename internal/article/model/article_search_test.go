@@ -0,0 +1,103 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	cases := []struct {
+		name    string
+		keyword string
+		// expected
+		terms []string
+	}{
+		{
+			name:    "single word",
+			keyword: "golang",
+			terms:   []string{"golang"},
+		}, {
+			name:    "lower case and distinct",
+			keyword: "Go  echo go",
+			terms:   []string{"go", "echo"},
+		}, {
+			name:    "remove operators",
+			keyword: `+gorm -"echo" realworld*`,
+			terms:   []string{"gorm", "echo", "realworld"},
+		}, {
+			name:    "empty",
+			keyword: " +-*\"  ",
+			terms:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.terms, ParseSearchTerms(tc.keyword))
+		})
+	}
+}
+
+func TestToBooleanModeQuery(t *testing.T) {
+	assert.Equal(t, "+gorm* +echo*", ToBooleanModeQuery([]string{"gorm", "echo"}))
+}
+
+func TestMakeSnippet(t *testing.T) {
+	cases := []struct {
+		name      string
+		text      string
+		terms     []string
+		maxLength int
+		// expected
+		snippet string
+	}{
+		{
+			name:      "highlight all terms",
+			text:      "Gorm with echo framework and gorm",
+			terms:     []string{"gorm", "echo"},
+			maxLength: 100,
+			snippet:   "<em>Gorm</em> with <em>echo</em> framework and <em>gorm</em>",
+		}, {
+			name:      "around the first match",
+			text:      "0123456789 gorm 0123456789",
+			terms:     []string{"gorm"},
+			maxLength: 12,
+			snippet:   "...89 <em>gorm</em> 0123...",
+		}, {
+			name:      "leading part if no match",
+			text:      "0123456789",
+			terms:     []string{"gorm"},
+			maxLength: 5,
+			snippet:   "01234...",
+		}, {
+			name:      "escape html",
+			text:      "<b>gorm</b>",
+			terms:     []string{"gorm"},
+			maxLength: 100,
+			snippet:   "&lt;b&gt;<em>gorm</em>&lt;/b&gt;",
+		}, {
+			name:      "empty text",
+			text:      "",
+			terms:     []string{"gorm"},
+			maxLength: 100,
+			snippet:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.snippet, MakeSnippet(tc.text, tc.terms, tc.maxLength))
+		})
+	}
+}
+
+func TestSetSnippet(t *testing.T) {
+	a := Article{
+		Description: "about echo",
+		Body:        "body of article",
+	}
+
+	a.SetSnippet([]string{"echo"}, 100)
+
+	assert.Equal(t, "about <em>echo</em>", a.Snippet)
+}
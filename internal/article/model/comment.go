@@ -0,0 +1,137 @@
+package model
+
+import (
+	"errors"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"time"
+)
+
+// ErrCommentTooDeep an error if a reply is nested deeper than the max depth.
+var ErrCommentTooDeep = errors.New("comment is nested too deep")
+
+// DeletedCommentBody is a body of deleted comments which are kept as placeholders of their replies.
+const DeletedCommentBody = "[deleted]"
+
+// HiddenCommentBody is a body of hidden comments which are kept as placeholders of their replies.
+const HiddenCommentBody = "[hidden]"
+
+// Orders of root comments. Replies are always ordered by created time ascending.
+const (
+	CommentOrderNewest = "newest"
+	CommentOrderOldest = "oldest"
+)
+
+// Comments represents root comments with their replies and total count of comments.
+// NextCursor is a position of the last root comment if more root comments exist, otherwise nil.
+type Comments struct {
+	Comments      []*Comment     `json:"comments"`
+	CommentsCount int64          `json:"commentsCount"`
+	NextCursor    *CommentCursor `json:"-"`
+}
+
+// CommentCursor represents a position of root comments ordered by created_at and comment_id.
+// Root comments after the position in the requested order will be returned if use this cursor.
+type CommentCursor struct {
+	CreatedAt time.Time
+	CommentID uint
+}
+
+// NewCommentCursor returns a new CommentCursor positioned at given comment.
+func NewCommentCursor(c *Comment) *CommentCursor {
+	return &CommentCursor{
+		CreatedAt: c.CreatedAt,
+		CommentID: c.ID,
+	}
+}
+
+// Encode returns an opaque string of this cursor.
+func (c *CommentCursor) Encode() string {
+	return encodeCursor(c.CreatedAt, c.CommentID)
+}
+
+// DecodeCommentCursor decodes given cursor string which is generated by CommentCursor.Encode.
+// ErrInvalidCursor will be returned if given cursor is malformed.
+func DecodeCommentCursor(cursor string) (*CommentCursor, error) {
+	createdAt, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentCursor{
+		CreatedAt: createdAt,
+		CommentID: id,
+	}, nil
+}
+
+// IsDeleted returns true if this comment is deleted, otherwise false.
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt.Valid
+}
+
+// IsHidden returns true if this comment is hidden by moderators, otherwise false.
+func (c *Comment) IsHidden() bool {
+	return c.HiddenAt != nil
+}
+
+// IsEdited returns true if the body of this comment is edited after created, otherwise false.
+func (c *Comment) IsEdited() bool {
+	return c.EditedAt != nil
+}
+
+// IsEditable returns true if this comment can be edited at given time within given window after created.
+// The window is unlimited if zero.
+func (c *Comment) IsEditable(now time.Time, window time.Duration) bool {
+	return window <= 0 || !now.After(c.CreatedAt.Add(window))
+}
+
+// FlattenComments returns given comments and all of their replies.
+func FlattenComments(comments []*Comment) []*Comment {
+	var flatten []*Comment
+	for _, c := range comments {
+		flatten = append(flatten, c)
+		flatten = append(flatten, FlattenComments(c.Replies)...)
+	}
+	return flatten
+}
+
+// BuildCommentTree links given comments to their parents and returns root comments.
+// Deleted or hidden comments without replies are removed and ones with replies are kept
+// with DeletedCommentBody or HiddenCommentBody and without the author. Orders of given comments are kept in roots and replies.
+func BuildCommentTree(comments []*Comment) []*Comment {
+	byID := make(map[uint]*Comment, len(comments))
+	for _, c := range comments {
+		c.Replies = nil
+		byID[c.ID] = c
+	}
+
+	var roots []*Comment
+	for _, c := range comments {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return pruneDeletedComments(roots)
+}
+
+func pruneDeletedComments(comments []*Comment) []*Comment {
+	pruned := make([]*Comment, 0, len(comments))
+	for _, c := range comments {
+		c.Replies = pruneDeletedComments(c.Replies)
+		if c.IsDeleted() || c.IsHidden() {
+			if len(c.Replies) == 0 {
+				continue
+			}
+			c.Body = DeletedCommentBody
+			if !c.IsDeleted() {
+				c.Body = HiddenCommentBody
+			}
+			c.Author = userModel.User{}
+			c.AuthorID = 0
+		}
+		pruned = append(pruned, c)
+	}
+	return pruned
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestBuildCommentTree(t *testing.T) {
+	parentID := func(id uint) *uint { return &id }
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	author := userModel.User{ID: 1, Name: "user1"}
+	comments := []*Comment{
+		{ID: 1, Body: "comment1", Author: author, AuthorID: author.ID},
+		{ID: 2, Body: "reply1", ParentID: parentID(1), Author: author, AuthorID: author.ID},
+		{ID: 3, Body: "reply2", ParentID: parentID(2), Author: author, AuthorID: author.ID},
+		{ID: 4, Body: "deleted with reply", Author: author, AuthorID: author.ID, DeletedAt: deletedAt},
+		{ID: 5, Body: "reply3", ParentID: parentID(4), Author: author, AuthorID: author.ID},
+		{ID: 6, Body: "deleted", Author: author, AuthorID: author.ID, DeletedAt: deletedAt},
+		{ID: 7, Body: "deleted with deleted reply", Author: author, AuthorID: author.ID, DeletedAt: deletedAt},
+		{ID: 8, Body: "deleted reply", ParentID: parentID(7), Author: author, AuthorID: author.ID, DeletedAt: deletedAt},
+		{ID: 9, Body: "hidden with reply", Author: author, AuthorID: author.ID, HiddenAt: &deletedAt.Time},
+		{ID: 10, Body: "reply4", ParentID: parentID(9), Author: author, AuthorID: author.ID},
+		{ID: 11, Body: "hidden", Author: author, AuthorID: author.ID, HiddenAt: &deletedAt.Time},
+	}
+
+	roots := BuildCommentTree(comments)
+
+	assert.Len(t, roots, 3)
+	// comment1 -> reply1 -> reply2
+	assert.EqualValues(t, 1, roots[0].ID)
+	assert.Len(t, roots[0].Replies, 1)
+	assert.EqualValues(t, 2, roots[0].Replies[0].ID)
+	assert.Len(t, roots[0].Replies[0].Replies, 1)
+	assert.EqualValues(t, 3, roots[0].Replies[0].Replies[0].ID)
+	// placeholder -> reply3
+	assert.EqualValues(t, 4, roots[1].ID)
+	assert.Equal(t, DeletedCommentBody, roots[1].Body)
+	assert.Zero(t, roots[1].AuthorID)
+	assert.Empty(t, roots[1].Author.Name)
+	assert.Len(t, roots[1].Replies, 1)
+	assert.EqualValues(t, 5, roots[1].Replies[0].ID)
+	// hidden placeholder -> reply4
+	assert.EqualValues(t, 9, roots[2].ID)
+	assert.Equal(t, HiddenCommentBody, roots[2].Body)
+	assert.Zero(t, roots[2].AuthorID)
+	assert.Len(t, roots[2].Replies, 1)
+}
+
+func TestCommentIsEditable(t *testing.T) {
+	now := time.Now()
+	c := &Comment{CreatedAt: now.Add(-10 * time.Minute)}
+
+	assert.True(t, c.IsEditable(now, 0))
+	assert.True(t, c.IsEditable(now, 15*time.Minute))
+	assert.True(t, c.IsEditable(now, 10*time.Minute))
+	assert.False(t, c.IsEditable(now, 5*time.Minute))
+}
+
+func TestCommentCursor(t *testing.T) {
+	cursor := &CommentCursor{
+		CreatedAt: time.Date(2021, 8, 1, 10, 20, 30, 0, time.UTC),
+		CommentID: 15,
+	}
+
+	decoded, err := DecodeCommentCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.CommentID, decoded.CommentID)
+
+	_, err = DecodeCommentCursor("!!invalid!!")
+	assert.Equal(t, ErrInvalidCursor, err)
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const TableNameReaction = "reactions"
+
+// Reaction target types.
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+// Reaction represents database model for reactions of users to articles and comments.
+// Kind is one of the configured reaction kinds and a user can react with multiple kinds to a target.
+type Reaction struct {
+	UserID     uint      `gorm:"column:user_id;primaryKey"`
+	TargetType string    `gorm:"column:target_type;primaryKey"`
+	TargetID   uint      `gorm:"column:target_id;primaryKey"`
+	Kind       string    `gorm:"column:kind;primaryKey"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (r Reaction) TableName() string {
+	return TableNameReaction
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"time"
+)
+
+const TableNameReport = "reports"
+
+// Report target types.
+const (
+	ReportTargetArticle = "article"
+	ReportTargetComment = "comment"
+)
+
+// Report statuses. Pending reports are resolved or dismissed by moderators.
+// Resolved reports hide the reported content and dismissed reports restore the content if hidden.
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Report represents database model for reports of users to articles and comments.
+// ArticleID is the article of the target which is same as TargetID if the target is an article.
+// A user can report a target only once.
+type Report struct {
+	ID         uint           `gorm:"column:report_id"`
+	Reporter   userModel.User `gorm:"foreignKey:ReporterID"`
+	ReporterID uint           `gorm:"column:reporter_id"`
+	TargetType string         `gorm:"column:target_type"`
+	TargetID   uint           `gorm:"column:target_id"`
+	ArticleID  uint           `gorm:"column:article_id"`
+	Reason     string         `gorm:"column:reason"`
+	Detail     string         `gorm:"column:detail"`
+	Status     string         `gorm:"column:status"`
+	ResolverID *uint          `gorm:"column:resolver_id"`
+	ResolvedAt *time.Time     `gorm:"column:resolved_at"`
+	CreatedAt  time.Time      `gorm:"column:created_at"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at"`
+
+	// ArticleSlug is the current slug of the article which is set by queries not database field.
+	ArticleSlug string `gorm:"-"`
+}
+
+func (r Report) TableName() string {
+	return TableNameReport
+}
+
+// Reports represents report list with total size.
+type Reports struct {
+	Reports      []*Report
+	ReportsCount int64
+}
+
+// ReportQuery represents a query to find reports. Empty fields are not filtered.
+type ReportQuery struct {
+	Status     string
+	TargetType string
+}
@@ -0,0 +1,94 @@
+package article
+
+import (
+	"context"
+	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
+)
+
+// ScheduledPublisher publishes scheduled articles which publish time has come
+// and "article.published" events of them to the event bus.
+type ScheduledPublisher struct {
+	conf      config.PublishConfig
+	articleDB articleDB.ArticleDB
+	eventBus  *event.Bus
+	now       func() time.Time
+}
+
+// NewScheduledPublisher returns a new ScheduledPublisher from given serverenv.ServerEnv and config.Config.
+func NewScheduledPublisher(env *serverenv.ServerEnv, conf *config.Config) *ScheduledPublisher {
+	return &ScheduledPublisher{
+		conf:      conf.ArticleConfig.Publish,
+		articleDB: env.GetArticleDB(),
+		eventBus:  env.GetEventBus(),
+		now:       time.Now,
+	}
+}
+
+// Start publishes scheduled articles every interval until given ctx is done.
+// Nothing will be published if the interval is not positive.
+func (p *ScheduledPublisher) Start(ctx context.Context) {
+	logger := logging.DefaultLogger()
+	if p.conf.Interval <= 0 {
+		logger.Info("ScheduledPublisher is disabled")
+		return
+	}
+	logger.Infow("Starting ScheduledPublisher", "interval", p.conf.Interval)
+
+	ticker := time.NewTicker(p.conf.Interval)
+	defer ticker.Stop()
+	for {
+		if published, err := p.Publish(ctx); err != nil {
+			logger.Errorw("ScheduledPublisher failed to publish scheduled articles", "published", published, "err", err)
+		} else if published != 0 {
+			logger.Infow("ScheduledPublisher published scheduled articles", "published", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping ScheduledPublisher")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish publishes all scheduled articles which publish time is before now in batches
+// and returns the number of published articles.
+func (p *ScheduledPublisher) Publish(ctx context.Context) (int64, error) {
+	var (
+		now   = p.now()
+		batch = p.conf.Batch
+		total int64
+	)
+	if batch <= 0 {
+		batch = 100
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		published, err := p.articleDB.PublishScheduled(ctx, now, batch)
+		if err != nil {
+			return total, err
+		}
+		for _, a := range published {
+			p.eventBus.Publish(ctx, &event.Event{
+				Type:      event.TypeArticlePublished,
+				ActorID:   a.AuthorID,
+				UserID:    a.AuthorID,
+				ArticleID: a.ID,
+				Payload:   types.ToArticleResponse(a),
+			})
+		}
+		total += int64(len(published))
+		if len(published) < batch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"testing"
+	"time"
+)
+
+func TestScheduledPublisherPublish(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.ArticleDB{}
+		bus    = event.NewBus()
+		p      = &ScheduledPublisher{
+			conf:      config.PublishConfig{Batch: 2},
+			articleDB: dbMock,
+			eventBus:  bus,
+			now: func() time.Time {
+				return now
+			},
+		}
+		events []*event.Event
+	)
+	bus.Subscribe(func(_ context.Context, e *event.Event) error {
+		events = append(events, e)
+		return nil
+	})
+	dbMock.On("PublishScheduled", mock.Anything, now, 2).Return(newPublishedArticles(1, 2), nil).Once()
+	dbMock.On("PublishScheduled", mock.Anything, now, 2).Return(newPublishedArticles(3), nil).Once()
+
+	published, err := p.Publish(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, published)
+	dbMock.AssertNumberOfCalls(t, "PublishScheduled", 2)
+	assert.Len(t, events, 3)
+	for i, e := range events {
+		assert.Equal(t, event.TypeArticlePublished, e.Type)
+		assert.EqualValues(t, i+1, e.ArticleID)
+		assert.EqualValues(t, 10, e.ActorID)
+		assert.EqualValues(t, 10, e.UserID)
+		assert.IsType(t, &types.ArticleResponse{}, e.Payload)
+	}
+}
+
+func TestScheduledPublisherPublishFail(t *testing.T) {
+	var (
+		dbMock = &mocks.ArticleDB{}
+		p      = &ScheduledPublisher{
+			conf:      config.PublishConfig{Batch: 1},
+			articleDB: dbMock,
+			now:       time.Now,
+		}
+		publishErr = errors.New("publish error")
+	)
+	dbMock.On("PublishScheduled", mock.Anything, mock.Anything, 1).Return(newPublishedArticles(1), nil).Once()
+	dbMock.On("PublishScheduled", mock.Anything, mock.Anything, 1).Return(nil, publishErr).Once()
+
+	published, err := p.Publish(context.TODO())
+
+	assert.Equal(t, publishErr, err)
+	assert.EqualValues(t, 1, published)
+}
+
+func newPublishedArticles(ids ...uint) []*model.Article {
+	articles := make([]*model.Article, len(ids))
+	for i, id := range ids {
+		articles[i] = &model.Article{
+			ID:       id,
+			Slug:     fmt.Sprintf("article%d", id),
+			Status:   model.ArticleStatusPublished,
+			AuthorID: 10,
+			Author:   userModel.User{ID: 10, Name: "user10"},
+		}
+	}
+	return articles
+}
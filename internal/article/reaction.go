@@ -0,0 +1,145 @@
+package article
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	types2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strconv"
+)
+
+// handleReactArticle handles "POST /api/articles/:slug/reactions/:kind" to react to an article.
+func (h *Handler) handleReactArticle(c echo.Context) error {
+	return h.reactOrUnReactArticle(c, true)
+}
+
+// handleUnReactArticle handles "DELETE /api/articles/:slug/reactions/:kind" to delete a reaction to an article.
+func (h *Handler) handleUnReactArticle(c echo.Context) error {
+	return h.reactOrUnReactArticle(c, false)
+}
+
+// handleReactComment handles "POST /api/articles/:slug/comments/:id/reactions/:kind" to react to a comment.
+func (h *Handler) handleReactComment(c echo.Context) error {
+	return h.reactOrUnReactComment(c, true)
+}
+
+// handleUnReactComment handles "DELETE /api/articles/:slug/comments/:id/reactions/:kind" to delete a reaction to a comment.
+func (h *Handler) handleUnReactComment(c echo.Context) error {
+	return h.reactOrUnReactComment(c, false)
+}
+
+func (h *Handler) reactOrUnReactArticle(c echo.Context, isReact bool) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+		kind        = c.Param("kind")
+	)
+
+	// Bind request
+	if err := h.validateReactionKind(kind); err != nil {
+		return err
+	}
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+
+	// Update reaction
+	if err := h.updateReaction(c, articlemodel.ReactionTargetArticle, article.ID, kind, isReact); err != nil {
+		return err
+	}
+
+	// Query article again
+	article, err = h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, types2.ToArticleResponse(article))
+}
+
+func (h *Handler) reactOrUnReactComment(c echo.Context, isReact bool) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+		commentID   = c.Param("id")
+		kind        = c.Param("kind")
+	)
+
+	// Bind request
+	cid, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		logger.Errorw("ArticleHandler_reactOrUnReactComment invalid comment id", "commentID", commentID, "err", err)
+		return httputils.NewBindError("id", "uint")
+	}
+	if err := h.validateReactionKind(kind); err != nil {
+		return err
+	}
+
+	// Query article and comment
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	comment, err := h.getComment(ctx, article.ID, uint(cid))
+	if err != nil {
+		return err
+	}
+
+	// Update reaction
+	if err := h.updateReaction(c, articlemodel.ReactionTargetComment, comment.ID, kind, isReact); err != nil {
+		return err
+	}
+
+	// Query comment again
+	comment, err = h.getComment(ctx, article.ID, comment.ID)
+	if err != nil {
+		return err
+	}
+	if err := h.setUserReactionsToComments(ctx, currentUser, comment); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToCommentResponse(comment))
+}
+
+// updateReaction adds or removes a reaction of current user to given target.
+func (h *Handler) updateReaction(c echo.Context, targetType string, targetID uint, kind string, isReact bool) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		err         error
+	)
+	if isReact {
+		err = h.articleDB.AddReaction(ctx, currentUser, targetType, targetID, kind)
+	} else {
+		err = h.articleDB.RemoveReaction(ctx, currentUser, targetType, targetID, kind)
+	}
+	if err != nil {
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("already reacted with %s", kind))
+		}
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("reaction(%s) not found", kind))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// validateReactionKind returns an unprocessable entity error if given kind is not allowed.
+func (h *Handler) validateReactionKind(kind string) error {
+	for _, k := range h.cfg.ArticleConfig.Reaction.Kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("reaction kind(%s) is not allowed", kind))
+}
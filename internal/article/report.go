@@ -0,0 +1,216 @@
+package article
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	types2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strconv"
+)
+
+// handleReportArticle handles "POST /api/articles/:slug/report" to report an article.
+func (h *Handler) handleReportArticle(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	if article.AuthorID == currentUser.ID {
+		return httputils.NewStatusUnprocessableEntity("can not report your own article")
+	}
+	return h.saveReport(c, articlemodel.ReportTargetArticle, article.ID, article.ID)
+}
+
+// handleReportComment handles "POST /api/articles/:slug/comments/:id/report" to report a comment.
+func (h *Handler) handleReportComment(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+		commentID   = c.Param("id")
+	)
+
+	// Bind request
+	cid, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		logger.Errorw("ArticleHandler_handleReportComment invalid comment id", "commentID", commentID, "err", err)
+		return httputils.NewBindError("id", "uint")
+	}
+
+	// Query article and comment
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	comment, err := h.getComment(ctx, article.ID, uint(cid))
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID == currentUser.ID {
+		return httputils.NewStatusUnprocessableEntity("can not report your own comment")
+	}
+	return h.saveReport(c, articlemodel.ReportTargetComment, comment.ID, article.ID)
+}
+
+// handleGetReports handles "GET /api/moderation/reports?status=&targetType=&limit=&offset=" to get reports
+// in the moderation queue.
+func (h *Handler) handleGetReports(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = ReportQuery{PageableQuery: &PageableQuery{}}
+		query  articlemodel.ReportQuery
+	)
+
+	// Bind request
+	if err := req.Bind(c, &query); err != nil {
+		logger.Errorw("ArticleHandler_handleGetReports failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query reports
+	reports, err := h.articleDB.FindReports(ctx, query, req.Offset, req.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToReportsResponse(reports))
+}
+
+// handleGetReport handles "GET /api/moderation/reports/:id" to get a report.
+func (h *Handler) handleGetReport(c echo.Context) error {
+	// Query report
+	report, err := h.getReport(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, types2.ToReportResponse(report))
+}
+
+// handleResolveReport handles "POST /api/moderation/reports/:id/resolve" to resolve all pending reports
+// to the target of a report and hide the target.
+func (h *Handler) handleResolveReport(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+	)
+
+	// Query report
+	report, err := h.getReport(c)
+	if err != nil {
+		return err
+	}
+	if report.Status != articlemodel.ReportStatusPending {
+		return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("report(%d) is already %s", report.ID, report.Status))
+	}
+
+	// Resolve reports
+	resolved, err := h.articleDB.ResolveReports(ctx, currentUser, report)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToStatusResponse(types2.StatusResolved, map[string]interface{}{
+		"reportsCount": resolved,
+	}))
+}
+
+// handleDismissReport handles "POST /api/moderation/reports/:id/dismiss" to dismiss all pending reports
+// to the target of a report and restore the target if hidden.
+func (h *Handler) handleDismissReport(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+	)
+
+	// Query report
+	report, err := h.getReport(c)
+	if err != nil {
+		return err
+	}
+	if report.Status != articlemodel.ReportStatusPending {
+		return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("report(%d) is already %s", report.ID, report.Status))
+	}
+
+	// Dismiss reports
+	dismissed, err := h.articleDB.DismissReports(ctx, currentUser, report)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToStatusResponse(types2.StatusDismissed, map[string]interface{}{
+		"reportsCount": dismissed,
+	}))
+}
+
+// saveReport binds a report request and saves a report of current user to given target.
+// The target is hidden if the number of pending reports reaches the threshold in the configuration.
+func (h *Handler) saveReport(c echo.Context, targetType string, targetID, articleID uint) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+		req         = CreateReportRequest{}
+		report      = articlemodel.Report{
+			ReporterID: currentUser.ID,
+			TargetType: targetType,
+			TargetID:   targetID,
+			ArticleID:  articleID,
+		}
+	)
+
+	// Bind request
+	if err := req.Bind(c, &report, h.cfg.ArticleConfig.Report.Reasons); err != nil {
+		logger.Errorw("ArticleHandler_saveReport failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Save report
+	hidden, err := h.articleDB.SaveReport(ctx, &report, h.cfg.ArticleConfig.Report.Threshold)
+	if err != nil {
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("already reported the %s", targetType))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	if hidden {
+		logger.Infow("ArticleHandler_saveReport hid a reported content", "targetType", targetType, "targetID", targetID)
+	}
+
+	// Query report again
+	saved, err := h.articleDB.FindReportByID(ctx, report.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusCreated, types2.ToReportResponse(saved))
+}
+
+// getReport returns a report of "id" path parameter, otherwise wrapped http error.
+func (h *Handler) getReport(c echo.Context) (*articlemodel.Report, error) {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		param  = c.Param("id")
+	)
+	id, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		logger.Errorw("ArticleHandler_getReport invalid report id", "id", param, "err", err)
+		return nil, httputils.NewBindError("id", "uint")
+	}
+	report, err := h.articleDB.FindReportByID(ctx, uint(id))
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, httputils.NewNotFoundError(fmt.Sprintf("report(%d) not found", id))
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	return report, nil
+}
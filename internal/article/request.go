@@ -1,19 +1,27 @@
 package article
 
 import (
+	"fmt"
 	"github.com/labstack/echo/v4"
 	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	httputils2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"time"
 )
 
 //----------------------------------------------
 // Common requests
 //----------------------------------------------
 
+// PageableQuery represents pagination query parameters.
+// Cursor is an opaque value of "nextCursor" in previous response and can not be used with offset.
 type PageableQuery struct {
-	Limit  int `query:"limit"`
-	Offset int `query:"offset"`
+	Limit  int    `query:"limit"`
+	Offset int    `query:"offset"`
+	Cursor string `query:"cursor"`
+
+	// decodedCursor is used for keyset pagination if not nil.
+	decodedCursor *articlemodel.ArticleCursor
 }
 
 func (r *PageableQuery) Bind(ctx echo.Context) error {
@@ -33,12 +41,27 @@ func (r *PageableQuery) Validate() error {
 	if r.Offset < 0 {
 		return httputils2.NewStatusUnprocessableEntity("offset must greater than or equals to 0")
 	}
+	if r.Cursor != "" {
+		if r.Offset != 0 {
+			return httputils2.NewStatusUnprocessableEntity("cursor can not be used with offset")
+		}
+		cursor, err := articlemodel.DecodeArticleCursor(r.Cursor)
+		if err != nil {
+			return httputils2.NewStatusUnprocessableEntity(err.Error())
+		}
+		r.decodedCursor = cursor
+	}
 	if r.Limit == 0 {
 		r.Limit = 20
 	}
 	return nil
 }
 
+// IsCursorMode returns true if given query has a cursor, otherwise false.
+func (r *PageableQuery) IsCursorMode() bool {
+	return r.decodedCursor != nil
+}
+
 //----------------------------------------------
 // Article requests
 //----------------------------------------------
@@ -60,13 +83,71 @@ func (r *ArticleQuery) Bind(ctx echo.Context) error {
 	return nil
 }
 
+// SearchArticleQuery represents query parameters of searching articles.
+// Articles are ordered by relevance, so only offset based pagination is supported.
+type SearchArticleQuery struct {
+	*PageableQuery
+	Keyword string `query:"q"`
+}
+
+func (r *SearchArticleQuery) Bind(ctx echo.Context) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.PageableQuery.Cursor != "" {
+		return httputils2.NewStatusUnprocessableEntity("cursor can not be used with search")
+	}
+	if err := r.PageableQuery.Validate(); err != nil {
+		return err
+	}
+	if len(articlemodel.ParseSearchTerms(r.Keyword)) == 0 {
+		return httputils2.NewStatusUnprocessableEntity("q must contain at least one word")
+	}
+	return nil
+}
+
+// TrashQuery represents query parameters of getting deleted articles.
+// Articles are ordered by deleted time, so only offset based pagination is supported.
+type TrashQuery struct {
+	*PageableQuery
+}
+
+func (r *TrashQuery) Bind(ctx echo.Context) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.PageableQuery.Cursor != "" {
+		return httputils2.NewStatusUnprocessableEntity("cursor can not be used with trash")
+	}
+	return r.PageableQuery.Validate()
+}
+
+// DraftQuery represents query parameters of getting draft articles.
+// Articles are ordered by updated time, so only offset based pagination is supported.
+type DraftQuery struct {
+	*PageableQuery
+}
+
+func (r *DraftQuery) Bind(ctx echo.Context) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.PageableQuery.Cursor != "" {
+		return httputils2.NewStatusUnprocessableEntity("cursor can not be used with drafts")
+	}
+	return r.PageableQuery.Validate()
+}
+
 // CreateArticleRequest represents request body data of creating an article.
+// Status is "published" if empty, or "scheduled" if empty and PublishAt is provided.
 type CreateArticleRequest struct {
 	Article struct {
-		Title       string   `json:"title" validate:"required"`
-		Description string   `json:"description" validate:"required"`
-		Body        string   `json:"body" validate:"required"`
-		Tags        []string `json:"tagList"`
+		Title       string     `json:"title" validate:"required"`
+		Description string     `json:"description" validate:"required"`
+		Body        string     `json:"body" validate:"required"`
+		Tags        []string   `json:"tagList"`
+		Status      string     `json:"status" validate:"omitempty,oneof=draft published scheduled"`
+		PublishAt   *time.Time `json:"publishAt"`
 	} `json:"article" validate:"required"`
 }
 
@@ -82,16 +163,23 @@ func (r *CreateArticleRequest) Bind(ctx echo.Context, a *articlemodel.Article, u
 			Name: tag,
 		})
 	}
+	if err := bindArticleStatus(a, r.Article.Status, r.Article.PublishAt, time.Now()); err != nil {
+		return err
+	}
 	a.AuthorID = u.ID
 	a.Author = *u
 	return nil
 }
 
+// UpdateArticleRequest represents request body data of updating an article.
+// Status and PublishAt are same as CreateArticleRequest and not changed if both are empty.
 type UpdateArticleRequest struct {
 	Article struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Body        string `json:"body"`
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		Body        string     `json:"body"`
+		Status      string     `json:"status" validate:"omitempty,oneof=draft published scheduled"`
+		PublishAt   *time.Time `json:"publishAt"`
 	} `json:"article" validate:"required"`
 }
 
@@ -108,6 +196,44 @@ func (r *UpdateArticleRequest) Bind(ctx echo.Context, a *articlemodel.Article, u
 	if r.Article.Body != "" {
 		a.Body = r.Article.Body
 	}
+	return bindArticleStatus(a, r.Article.Status, r.Article.PublishAt, time.Now())
+}
+
+// bindArticleStatus sets given status and publishAt to the article a.
+// publishAt is required for scheduled articles and must be after given now.
+// The status of hidden articles can only be changed by moderators.
+func bindArticleStatus(a *articlemodel.Article, status string, publishAt *time.Time, now time.Time) error {
+	if status == "" {
+		if publishAt == nil {
+			return nil
+		}
+		status = articlemodel.ArticleStatusScheduled
+	}
+	if a.IsHidden() {
+		return httputils2.NewStatusUnprocessableEntity("can not change the status of hidden articles")
+	}
+	switch status {
+	case articlemodel.ArticleStatusScheduled:
+		if publishAt == nil {
+			return httputils2.NewStatusUnprocessableEntity("publishAt is required for scheduled articles")
+		}
+		if !publishAt.After(now) {
+			return httputils2.NewStatusUnprocessableEntity("publishAt must be a future time")
+		}
+		a.PublishAt = publishAt
+		a.PublishedAt = nil
+	case articlemodel.ArticleStatusPublished:
+		if !a.IsPublished() || a.PublishAt == nil {
+			a.PublishAt = &now
+		}
+		if !a.IsPublished() || a.PublishedAt == nil {
+			a.PublishedAt = &now
+		}
+	case articlemodel.ArticleStatusDraft:
+		a.PublishAt = nil
+		a.PublishedAt = nil
+	}
+	a.Status = status
 	return nil
 }
 
@@ -115,10 +241,45 @@ func (r *UpdateArticleRequest) Bind(ctx echo.Context, a *articlemodel.Article, u
 // Comment requests
 //----------------------------------------------
 
+// CommentQuery represents query parameters of getting comments.
+// Root comments are paginated by cursor in given order which is "newest" if empty.
+type CommentQuery struct {
+	Limit  int    `query:"limit"`
+	Cursor string `query:"cursor"`
+	Order  string `query:"order" validate:"omitempty,oneof=oldest newest"`
+
+	// decodedCursor is the position of root comments if not nil.
+	decodedCursor *articlemodel.CommentCursor
+}
+
+func (r *CommentQuery) Bind(ctx echo.Context) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.Limit < 0 {
+		return httputils2.NewStatusUnprocessableEntity("limit must greater than or equals to 0")
+	}
+	if r.Cursor != "" {
+		cursor, err := articlemodel.DecodeCommentCursor(r.Cursor)
+		if err != nil {
+			return httputils2.NewStatusUnprocessableEntity(err.Error())
+		}
+		r.decodedCursor = cursor
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Order == "" {
+		r.Order = articlemodel.CommentOrderNewest
+	}
+	return nil
+}
+
 // CreateCommentRequest represents request body data of creating a comment.
 type CreateCommentRequest struct {
 	Comment struct {
-		Body string `json:"body" validate:"required"`
+		Body     string `json:"body" validate:"required"`
+		ParentID *uint  `json:"parentId" validate:"omitempty,min=1"`
 	} `json:"comment" validate:"required"`
 }
 
@@ -128,7 +289,81 @@ func (r *CreateCommentRequest) Bind(ctx echo.Context, a *articlemodel.Article, c
 	}
 	c.ArticleID = a.ID
 	c.Body = r.Comment.Body
+	c.ParentID = r.Comment.ParentID
 	c.AuthorID = u.ID
 	c.Author = *u
 	return nil
 }
+
+// UpdateCommentRequest represents request body data of updating a comment.
+type UpdateCommentRequest struct {
+	Comment struct {
+		Body string `json:"body" validate:"required"`
+	} `json:"comment" validate:"required"`
+}
+
+func (r *UpdateCommentRequest) Bind(ctx echo.Context, c *articlemodel.Comment) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	c.Body = r.Comment.Body
+	return nil
+}
+
+//----------------------------------------------
+// Report requests
+//----------------------------------------------
+
+// ReportQuery represents query parameters of getting reports in the moderation queue.
+// Status is "pending" if empty and reports are ordered by created time, so only offset based pagination is supported.
+type ReportQuery struct {
+	*PageableQuery
+	Status     string `query:"status" validate:"omitempty,oneof=pending resolved dismissed"`
+	TargetType string `query:"targetType" validate:"omitempty,oneof=article comment"`
+}
+
+func (r *ReportQuery) Bind(ctx echo.Context, query *articlemodel.ReportQuery) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.PageableQuery.Cursor != "" {
+		return httputils2.NewStatusUnprocessableEntity("cursor can not be used with reports")
+	}
+	if err := r.PageableQuery.Validate(); err != nil {
+		return err
+	}
+	if r.Status == "" {
+		r.Status = articlemodel.ReportStatusPending
+	}
+	query.Status = r.Status
+	query.TargetType = r.TargetType
+	return nil
+}
+
+// CreateReportRequest represents request body data of reporting an article or a comment.
+// Reason must be one of reasons in the configuration.
+type CreateReportRequest struct {
+	Report struct {
+		Reason string `json:"reason" validate:"required"`
+		Detail string `json:"detail" validate:"max=1000"`
+	} `json:"report" validate:"required"`
+}
+
+func (r *CreateReportRequest) Bind(ctx echo.Context, report *articlemodel.Report, reasons []string) error {
+	if err := httputils2.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	allowed := false
+	for _, reason := range reasons {
+		if reason == r.Report.Reason {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return httputils2.NewStatusUnprocessableEntity(fmt.Sprintf("report reason(%s) is not allowed", r.Report.Reason))
+	}
+	report.Reason = r.Report.Reason
+	report.Detail = r.Report.Detail
+	return nil
+}
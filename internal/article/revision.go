@@ -0,0 +1,187 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	types2 "github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strconv"
+)
+
+// handleGetRevisions handles "GET /api/articles/:slug/revisions" to get revisions of an article.
+func (h *Handler) handleGetRevisions(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+
+	// Query revisions
+	revisions, err := h.articleDB.FindRevisions(ctx, article.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToRevisionsResponse(revisions))
+}
+
+// handleGetRevision handles "GET /api/articles/:slug/revisions/:n" to get a revision of an article.
+func (h *Handler) handleGetRevision(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Bind request
+	number, err := bindRevisionNumber(c, "n", c.Param("n"))
+	if err != nil {
+		return err
+	}
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+
+	// Query revision
+	revision, err := h.getRevision(ctx, article.ID, number)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, types2.ToRevisionResponse(revision))
+}
+
+// handleGetRevisionDiff handles "GET /api/articles/:slug/revisions/:n/diff?from=" to get a unified diff
+// from revision "from" to revision n. The previous revision of n is used if "from" is empty.
+func (h *Handler) handleGetRevisionDiff(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Bind request
+	to, err := bindRevisionNumber(c, "n", c.Param("n"))
+	if err != nil {
+		return err
+	}
+	from := to - 1
+	if c.QueryParam("from") != "" {
+		if from, err = bindRevisionNumber(c, "from", c.QueryParam("from")); err != nil {
+			return err
+		}
+	}
+	if from == 0 {
+		return httputils.NewStatusUnprocessableEntity("no previous revision")
+	}
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+
+	// Query revisions
+	fromRevision, err := h.getRevision(ctx, article.ID, from)
+	if err != nil {
+		return err
+	}
+	toRevision, err := h.getRevision(ctx, article.ID, to)
+	if err != nil {
+		return err
+	}
+
+	// Make diff
+	diff, err := articlemodel.UnifiedDiff(fromRevision, toRevision)
+	if err != nil {
+		logger.Errorw("ArticleHandler_handleGetRevisionDiff failed to make a diff", "from", from, "to", to, "err", err)
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types2.ToRevisionDiffResponse(fromRevision, toRevision, diff))
+}
+
+// handleRestoreRevision handles "POST /api/articles/:slug/revisions/:n/restore" to restore an article to a revision.
+// A new revision is saved with contents of the restored revision.
+func (h *Handler) handleRestoreRevision(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+		slug        = c.Param("slug")
+	)
+
+	// Bind request
+	number, err := bindRevisionNumber(c, "n", c.Param("n"))
+	if err != nil {
+		return err
+	}
+
+	// Query article
+	article, err := h.getArticleBySlug(ctx, currentUser, slug)
+	if err != nil {
+		return err
+	}
+	if article.AuthorID != currentUser.ID {
+		return httputils.NewNotFoundError(fmt.Sprintf("article(%s) not found", slug))
+	}
+
+	// Query revision
+	revision, err := h.getRevision(ctx, article.ID, number)
+	if err != nil {
+		return err
+	}
+
+	// Update article
+	article.Title = revision.Title
+	article.Description = revision.Description
+	article.Body = revision.Body
+	if err := h.articleDB.Update(ctx, currentUser, article); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("article(%s) not found", slug))
+		}
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity("duplicate title")
+		}
+		if err == database.ErrConcurrentUpdate {
+			return httputils.NewError(http.StatusConflict, "article was updated concurrently")
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	h.publishArticle(ctx, article, event.TypeArticleUpdated)
+	return c.JSON(http.StatusOK, types2.ToArticleResponse(article))
+}
+
+// getRevision returns a revision if exists, otherwise wrapped http error
+func (h *Handler) getRevision(ctx context.Context, articleID, number uint) (*articlemodel.ArticleRevision, error) {
+	revision, err := h.articleDB.FindRevision(ctx, articleID, number)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, httputils.NewNotFoundError(fmt.Sprintf("revision(%d) not found", number))
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	return revision, nil
+}
+
+// bindRevisionNumber returns a revision number parsed from given value.
+func bindRevisionNumber(c echo.Context, field, value string) (uint, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n == 0 {
+		logging.FromContext(c.Request().Context()).Errorw("ArticleHandler invalid revision number", field, value, "err", err)
+		return 0, httputils.NewBindError(field, "uint")
+	}
+	return uint(n), nil
+}
@@ -0,0 +1,169 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/pubsub"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"time"
+)
+
+// Event names of feed streams.
+const (
+	feedEventArticle = "article"
+	feedEventComment = "comment"
+)
+
+// feedMessage represents a message published to feed streams.
+// Data is sent as the data of a server-sent event named Event.
+type feedMessage struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// handleFeedStream handles "GET /api/articles/feed/stream" to push new articles of followed authors and
+// new comments on articles of current user as server-sent events.
+// Followed authors are resolved when connected, so following changes are applied after reconnecting.
+// The write deadline of the server is extended before every write, so the stream is kept open until
+// the client disconnects but is closed if the client stops reading.
+func (h *Handler) handleFeedStream(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		currentUser = h.currentUser(c)
+	)
+
+	// Find followers
+	followers, err := h.userDB.FindFollowerIDs(ctx, currentUser.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Subscribe channels
+	channels := []string{commentsChannel(currentUser.ID)}
+	for _, follower := range followers {
+		channels = append(channels, articlesChannel(follower))
+	}
+	sub, err := h.pubSub.Subscribe(ctx, channels...)
+	if err != nil {
+		logger.Errorw("ArticleHandler_handleFeedStream failed to subscribe channels", "channels", channels, "err", err)
+		return httputils.NewInternalServerError(err)
+	}
+	defer sub.Close()
+
+	var (
+		rc           = http.NewResponseController(c.Response().Writer)
+		writeTimeout = h.cfg.ServerConfig.WriteTimeout
+		extend       = func() error { return nil }
+	)
+	if writeTimeout > 0 {
+		if err := rc.SetWriteDeadline(time.Now().Add(writeTimeout)); err == nil {
+			extend = func() error {
+				return rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+			}
+		} else {
+			// close the stream before the write timeout if the deadline cannot be extended.
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, writeTimeout*9/10)
+			defer cancel()
+		}
+	}
+	var keepAlive <-chan time.Time
+	if interval := h.cfg.ArticleConfig.Stream.KeepAlive; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive:
+			if err := extend(); err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return nil
+			}
+			var m feedMessage
+			if err := json.Unmarshal(msg.Payload, &m); err != nil {
+				logger.Errorw("ArticleHandler_handleFeedStream failed to decode a message", "channel", msg.Channel, "err", err)
+				continue
+			}
+			if err := extend(); err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", m.Event, m.Data); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+// FeedBroadcaster publishes published articles and created comments in events to feed streams.
+type FeedBroadcaster struct {
+	pubSub pubsub.PubSub
+}
+
+// NewFeedBroadcaster returns a new FeedBroadcaster from given serverenv.ServerEnv.
+func NewFeedBroadcaster(env *serverenv.ServerEnv) *FeedBroadcaster {
+	return &FeedBroadcaster{
+		pubSub: env.GetPubSub(),
+	}
+}
+
+// Handle publishes the payload of given event e to a feed channel.
+// Other events and comments of the article author itself are ignored.
+func (b *FeedBroadcaster) Handle(ctx context.Context, e *event.Event) error {
+	var channel, name string
+	switch e.Type {
+	case event.TypeArticlePublished:
+		channel, name = articlesChannel(e.ActorID), feedEventArticle
+	case event.TypeCommentCreated:
+		if e.UserID == e.ActorID {
+			return nil
+		}
+		channel, name = commentsChannel(e.UserID), feedEventComment
+	default:
+		return nil
+	}
+	data, err := json.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(&feedMessage{Event: name, Data: data})
+	if err != nil {
+		return err
+	}
+	return b.pubSub.Publish(ctx, channel, msg)
+}
+
+// articlesChannel returns a pubsub channel of articles published by given author.
+func articlesChannel(authorID uint) string {
+	return fmt.Sprintf("feed.articles.%d", authorID)
+}
+
+// commentsChannel returns a pubsub channel of comments created on articles of given author.
+func commentsChannel(authorID uint) string {
+	return fmt.Sprintf("feed.comments.%d", authorID)
+}
@@ -0,0 +1,169 @@
+package article
+
+import (
+	"bufio"
+	"context"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/pubsub"
+	userMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleFeedStream(t *testing.T) {
+	cfg, err := config.Load("")
+	assert.NoError(t, err)
+	cfg.ServerConfig.WriteTimeout = 0
+	cfg.ArticleConfig.Stream.KeepAlive = 0
+
+	var (
+		user1  = &userModel.User{ID: 1, Name: "user1"}
+		user2  = &userModel.User{ID: 2, Name: "user2"}
+		user3  = &userModel.User{ID: 3, Name: "user3"}
+		userDB = &userMocks.UserDB{}
+		bus    = event.NewBus()
+		ps     = pubsub.NewMemoryPubSub()
+		h      = &Handler{cfg: cfg, userDB: userDB, eventBus: bus, pubSub: ps}
+	)
+	bus.Subscribe((&FeedBroadcaster{pubSub: ps}).Handle)
+	userDB.On("FindFollowerIDs", mock.Anything, user1.ID).Return([]uint{user2.ID}, nil)
+	userDB.On("FindByID", mock.Anything, user2.ID).Return(user2, nil)
+	userDB.On("FindByID", mock.Anything, user3.ID).Return(user3, nil)
+
+	e := echo.New()
+	h.Route(e.Group("/api"), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", &jwt.Token{Claims: &authutils.JWTClaims{UserID: user1.ID}})
+			return next(c)
+		}
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/articles/feed/stream", nil)
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get(echo.HeaderContentType))
+
+	// when
+	myArticle := &model.Article{ID: 1, Slug: "article1", Title: "article1", AuthorID: user1.ID}
+	h.publishArticle(context.TODO(), &model.Article{ID: 2, Slug: "article2", Title: "article2", AuthorID: user2.ID}, event.TypeArticlePublished)
+	h.publishArticle(context.TODO(), &model.Article{ID: 3, Slug: "article3", Title: "article3", AuthorID: user3.ID}, event.TypeArticlePublished)
+	h.publishComment(context.TODO(), myArticle, &model.Comment{ID: 1, Body: "comment1", ArticleID: myArticle.ID, AuthorID: user3.ID})
+
+	// then
+	reader := bufio.NewReader(res.Body)
+	name, data := readServerSentEvent(t, reader)
+	assert.Equal(t, feedEventArticle, name)
+	assert.Equal(t, "article2", gjson.Get(data, "article.slug").String())
+	assert.Equal(t, user2.Name, gjson.Get(data, "article.author.username").String())
+
+	name, data = readServerSentEvent(t, reader)
+	assert.Equal(t, feedEventComment, name)
+	assert.Equal(t, myArticle.Slug, gjson.Get(data, "article.slug").String())
+	assert.Equal(t, "comment1", gjson.Get(data, "comment.body").String())
+	assert.Equal(t, user3.Name, gjson.Get(data, "comment.author.username").String())
+}
+
+func TestHandleFeedStream_WriteTimeout(t *testing.T) {
+	cfg, err := config.Load("")
+	assert.NoError(t, err)
+	cfg.ServerConfig.WriteTimeout = 200 * time.Millisecond
+	cfg.ArticleConfig.Stream.KeepAlive = 50 * time.Millisecond
+
+	var (
+		user1  = &userModel.User{ID: 1, Name: "user1"}
+		user2  = &userModel.User{ID: 2, Name: "user2"}
+		userDB = &userMocks.UserDB{}
+		bus    = event.NewBus()
+		ps     = pubsub.NewMemoryPubSub()
+		h      = &Handler{cfg: cfg, userDB: userDB, eventBus: bus, pubSub: ps}
+	)
+	bus.Subscribe((&FeedBroadcaster{pubSub: ps}).Handle)
+	userDB.On("FindFollowerIDs", mock.Anything, user1.ID).Return([]uint{user2.ID}, nil)
+	userDB.On("FindByID", mock.Anything, user2.ID).Return(user2, nil)
+
+	e := echo.New()
+	h.Route(e.Group("/api"), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", &jwt.Token{Claims: &authutils.JWTClaims{UserID: user1.ID}})
+			return next(c)
+		}
+	})
+	srv := httptest.NewUnstartedServer(e)
+	srv.Config.WriteTimeout = cfg.ServerConfig.WriteTimeout
+	srv.Start()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/articles/feed/stream", nil)
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// when
+	time.Sleep(3 * cfg.ServerConfig.WriteTimeout)
+	h.publishArticle(context.TODO(), &model.Article{ID: 2, Slug: "article2", Title: "article2", AuthorID: user2.ID}, event.TypeArticlePublished)
+
+	// then: the stream is kept open after the write timeout
+	reader := bufio.NewReader(res.Body)
+	var name, data string
+	for i := 0; name == "" && i < 100; i++ {
+		name, data = readServerSentEvent(t, reader)
+	}
+	assert.Equal(t, feedEventArticle, name)
+	assert.Equal(t, "article2", gjson.Get(data, "article.slug").String())
+}
+
+func TestFeedBroadcasterIgnoreEvents(t *testing.T) {
+	var (
+		ps = pubsub.NewMemoryPubSub()
+		b  = &FeedBroadcaster{pubSub: ps}
+	)
+	sub, err := ps.Subscribe(context.TODO(), commentsChannel(1), articlesChannel(1))
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	// comment of the article author itself
+	assert.NoError(t, b.Handle(context.TODO(), &event.Event{Type: event.TypeCommentCreated, ActorID: 1, UserID: 1}))
+	// not feed events
+	assert.NoError(t, b.Handle(context.TODO(), &event.Event{Type: event.TypeArticleFavorited, ActorID: 1, UserID: 1}))
+
+	assert.Len(t, sub.Messages(), 0)
+}
+
+// readServerSentEvent reads a server-sent event and returns the event name and data.
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package article
+
+import (
+	"context"
+	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
+)
+
+// TrashPurger permanently deletes articles and comments which have been deleted longer than the retention.
+type TrashPurger struct {
+	conf      config.TrashConfig
+	articleDB articleDB.ArticleDB
+	now       func() time.Time
+}
+
+// NewTrashPurger returns a new TrashPurger from given serverenv.ServerEnv and config.Config.
+func NewTrashPurger(env *serverenv.ServerEnv, conf *config.Config) *TrashPurger {
+	return &TrashPurger{
+		conf:      conf.ArticleConfig.Trash,
+		articleDB: env.GetArticleDB(),
+		now:       time.Now,
+	}
+}
+
+// Start purges deleted articles and comments every purge interval until given ctx is done.
+// Nothing will be purged if the purge interval is not positive.
+func (p *TrashPurger) Start(ctx context.Context) {
+	logger := logging.DefaultLogger()
+	if p.conf.PurgeInterval <= 0 {
+		logger.Info("TrashPurger is disabled")
+		return
+	}
+	logger.Infow("Starting TrashPurger", "retention", p.conf.Retention, "purgeInterval", p.conf.PurgeInterval)
+
+	ticker := time.NewTicker(p.conf.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		if purged, err := p.Purge(ctx); err != nil {
+			logger.Errorw("TrashPurger failed to purge deleted articles and comments", "purged", purged, "err", err)
+		} else if purged != 0 {
+			logger.Infow("TrashPurger purged deleted articles and comments", "purged", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping TrashPurger")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes all articles and comments deleted before the retention in batches
+// and returns the number of purged articles and comments.
+func (p *TrashPurger) Purge(ctx context.Context) (int64, error) {
+	var (
+		deletedBefore = p.now().Add(-p.conf.Retention)
+		batch         = p.conf.PurgeBatch
+		total         int64
+	)
+	if batch <= 0 {
+		batch = 100
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		purged, err := p.articleDB.PurgeDeleted(ctx, deletedBefore, batch)
+		if err != nil {
+			return total, err
+		}
+		total += purged
+		if purged == 0 {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/article/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"testing"
+	"time"
+)
+
+func TestTrashPurgerPurge(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.ArticleDB{}
+		p      = &TrashPurger{
+			conf: config.TrashConfig{
+				Retention:  24 * time.Hour,
+				PurgeBatch: 2,
+			},
+			articleDB: dbMock,
+			now: func() time.Time {
+				return now
+			},
+		}
+	)
+	deletedBefore := now.Add(-24 * time.Hour)
+	dbMock.On("PurgeDeleted", mock.Anything, deletedBefore, 2).Return(int64(4), nil).Once()
+	dbMock.On("PurgeDeleted", mock.Anything, deletedBefore, 2).Return(int64(1), nil).Once()
+	dbMock.On("PurgeDeleted", mock.Anything, deletedBefore, 2).Return(int64(0), nil).Once()
+
+	purged, err := p.Purge(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, purged)
+	dbMock.AssertNumberOfCalls(t, "PurgeDeleted", 3)
+}
+
+func TestTrashPurgerPurgeFail(t *testing.T) {
+	var (
+		dbMock = &mocks.ArticleDB{}
+		p      = &TrashPurger{
+			conf:      config.TrashConfig{Retention: time.Hour, PurgeBatch: 1},
+			articleDB: dbMock,
+			now:       time.Now,
+		}
+		purgeErr = errors.New("purge error")
+	)
+	dbMock.On("PurgeDeleted", mock.Anything, mock.Anything, 1).Return(int64(1), nil).Once()
+	dbMock.On("PurgeDeleted", mock.Anything, mock.Anything, 1).Return(int64(0), purgeErr).Once()
+
+	purged, err := p.Purge(context.TODO())
+
+	assert.Equal(t, purgeErr, err)
+	assert.EqualValues(t, 1, purged)
+}
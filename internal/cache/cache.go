@@ -2,19 +2,73 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/go-redis/cache/v8"
 	"github.com/go-redis/redis/v8"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
 )
 
-// NewCache creates a new redis.UniversalClient and cache.Cache.
-func NewCache(conf *config.Config) (redis.UniversalClient, *cache.Cache, error) {
+const (
+	TypeRedis  = "redis"
+	TypeMemory = "memory"
+)
+
+// ErrCacheMiss an error if given key does not exist or is expired.
+var ErrCacheMiss = errors.New("cache: key is missing")
+
+// Cache is a key value store used by cache layer DBs.
+// Values are encoded when stored, so callers always get a copy of the value.
+// Zero or negative ttl means that the value never expires.
+type Cache interface {
+	// Get decodes a cached value of given key into value.
+	// ErrCacheMiss will be returned if not exists.
+	Get(ctx context.Context, key string, value interface{}) error
+
+	// Set stores a given value with ttl.
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+
+	// SetXX stores a given value with ttl only if the key already exists.
+	SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+
+	// Once decodes a cached value of given key into value if exists.
+	// Otherwise, calls do and stores the result with ttl. do is called only once for concurrent callers of the same key.
+	// An error from do will be returned as it is and is not cached.
+	Once(ctx context.Context, key string, value interface{}, ttl time.Duration, do func() (interface{}, error)) error
+
+	// Exists returns a true if given key exists, otherwise false.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Delete deletes given keys. Not exist keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+
+	// Close releases resources of this cache.
+	Close() error
+}
+
+// NewCache creates a new Cache from cache.type in given config.
+// The redis type is backed by given redis client which is shared with other components.
+func NewCache(conf *config.Config, cli redis.UniversalClient) (Cache, error) {
 	if !conf.CacheConfig.Enabled {
-		return nil, nil, fmt.Errorf("disabled cache in config")
+		return nil, fmt.Errorf("disabled cache in config")
 	}
 
+	switch conf.CacheConfig.Type {
+	case TypeRedis:
+		if cli == nil {
+			return nil, fmt.Errorf("redis client is required for %s cache", TypeRedis)
+		}
+		return NewRedisCache(cli), nil
+	case TypeMemory:
+		return NewMemoryCache(conf.CacheConfig.MemoryConfig.Size), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache type: %s", conf.CacheConfig.Type)
+	}
+}
+
+// NewRedisClient creates a new redis.UniversalClient from redis configs in given config.
+func NewRedisClient(conf *config.Config) redis.UniversalClient {
 	var (
 		redisConf = conf.CacheConfig.RedisConfig
 		cli       redis.UniversalClient
@@ -50,8 +104,5 @@ func NewCache(conf *config.Config) (redis.UniversalClient, *cache.Cache, error)
 	} else {
 		logging.DefaultLogger().Info("connected to redis")
 	}
-
-	return cli, cache.New(&cache.Options{
-		Redis: cli,
-	}), nil
+	return cli
 }
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"testing"
+	"time"
+)
+
+func TestNewCache(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+
+	// disabled
+	_, err = NewCache(conf, nil)
+	assert.Error(t, err)
+
+	// memory
+	conf.CacheConfig.Enabled = true
+	conf.CacheConfig.Type = TypeMemory
+	c, err := NewCache(conf, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryCache{}, c)
+	assert.Equal(t, conf.CacheConfig.MemoryConfig.Size, c.(*memoryCache).size)
+
+	// redis without a client
+	conf.CacheConfig.Type = TypeRedis
+	_, err = NewCache(conf, nil)
+	assert.Error(t, err)
+
+	// redis
+	cli := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer cli.Close()
+	c, err = NewCache(conf, cli)
+	assert.NoError(t, err)
+	assert.IsType(t, &redisCache{}, c)
+
+	// unsupported
+	conf.CacheConfig.Type = "unknown"
+	_, err = NewCache(conf, nil)
+	assert.Error(t, err)
+}
+
+func TestRedisTTL(t *testing.T) {
+	assert.Equal(t, time.Duration(-1), redisTTL(0))
+	assert.Equal(t, time.Duration(-1), redisTTL(-time.Second))
+	assert.Equal(t, time.Second, redisTTL(500*time.Millisecond))
+	assert.Equal(t, time.Minute, redisTTL(time.Minute))
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// DefaultMemoryCacheSize is the maximum number of entries of memory cache if not configured.
+const DefaultMemoryCacheSize = 10000
+
+// NewMemoryCache creates a new in-process Cache which keeps at most size entries.
+// The least recently used entry is evicted if the cache is full.
+func NewMemoryCache(size int) Cache {
+	if size <= 0 {
+		size = DefaultMemoryCacheSize
+	}
+	return &memoryCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+type memoryCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+	group singleflight.Group
+	now   func() time.Time
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e *memoryEntry) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func (mc *memoryCache) Get(_ context.Context, key string, value interface{}) error {
+	b, ok := mc.getBytes(key)
+	if !ok {
+		return ErrCacheMiss
+	}
+	return msgpack.Unmarshal(b, value)
+}
+
+func (mc *memoryCache) Set(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	b, err := msgpack.Marshal(value)
+	if err != nil {
+		return err
+	}
+	mc.setBytes(key, b, ttl, false)
+	return nil
+}
+
+func (mc *memoryCache) SetXX(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	b, err := msgpack.Marshal(value)
+	if err != nil {
+		return err
+	}
+	mc.setBytes(key, b, ttl, true)
+	return nil
+}
+
+func (mc *memoryCache) Once(_ context.Context, key string, value interface{}, ttl time.Duration, do func() (interface{}, error)) error {
+	v, err, _ := mc.group.Do(key, func() (interface{}, error) {
+		if b, ok := mc.getBytes(key); ok {
+			return b, nil
+		}
+		v, err := do()
+		if err != nil {
+			return nil, err
+		}
+		b, err := msgpack.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		mc.setBytes(key, b, ttl, false)
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+	return msgpack.Unmarshal(v.([]byte), value)
+}
+
+func (mc *memoryCache) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := mc.getBytes(key)
+	return ok, nil
+}
+
+func (mc *memoryCache) Delete(_ context.Context, keys ...string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for _, key := range keys {
+		if elt, ok := mc.items[key]; ok {
+			mc.removeElement(elt)
+		}
+	}
+	return nil
+}
+
+func (mc *memoryCache) Close() error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.ll.Init()
+	mc.items = make(map[string]*list.Element)
+	return nil
+}
+
+// getBytes returns encoded value of given key and updates the entry to the most recently used one.
+func (mc *memoryCache) getBytes(key string) ([]byte, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	elt, ok := mc.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elt.Value.(*memoryEntry)
+	if entry.isExpired(mc.now()) {
+		mc.removeElement(elt)
+		return nil, false
+	}
+	mc.ll.MoveToFront(elt)
+	return entry.value, true
+}
+
+// setBytes stores given encoded value and evicts the least recently used entries if the cache is full.
+// The value will be stored only if the key already exists if onlyExists is true.
+func (mc *memoryCache) setBytes(key string, b []byte, ttl time.Duration, onlyExists bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	now := mc.now()
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+
+	if elt, ok := mc.items[key]; ok {
+		entry := elt.Value.(*memoryEntry)
+		if onlyExists && entry.isExpired(now) {
+			mc.removeElement(elt)
+			return
+		}
+		entry.value = b
+		entry.expiresAt = expiresAt
+		mc.ll.MoveToFront(elt)
+		return
+	}
+	if onlyExists {
+		return
+	}
+	mc.items[key] = mc.ll.PushFront(&memoryEntry{
+		key:       key,
+		value:     b,
+		expiresAt: expiresAt,
+	})
+	for mc.ll.Len() > mc.size {
+		mc.removeElement(mc.ll.Back())
+	}
+}
+
+func (mc *memoryCache) removeElement(elt *list.Element) {
+	mc.ll.Remove(elt)
+	delete(mc.items, elt.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testValue struct {
+	Name  string
+	Count int
+}
+
+func TestMemoryCache_SetAndGet(t *testing.T) {
+	c := NewMemoryCache(10)
+	v := testValue{Name: "name", Count: 1}
+
+	assert.NoError(t, c.Set(context.TODO(), "key", &v, time.Minute))
+	v.Count = 2 // stored value must not be changed.
+
+	var find testValue
+	assert.NoError(t, c.Get(context.TODO(), "key", &find))
+	assert.Equal(t, testValue{Name: "name", Count: 1}, find)
+
+	exists, err := c.Exists(context.TODO(), "key")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.Equal(t, ErrCacheMiss, c.Get(context.TODO(), "not-exist", &find))
+}
+
+func TestMemoryCache_Expire(t *testing.T) {
+	c := NewMemoryCache(10).(*memoryCache)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set(context.TODO(), "key1", "value1", time.Minute))
+	assert.NoError(t, c.Set(context.TODO(), "key2", "value2", 0))
+
+	now = now.Add(time.Minute)
+	var find string
+	assert.Equal(t, ErrCacheMiss, c.Get(context.TODO(), "key1", &find))
+	assert.NoError(t, c.Get(context.TODO(), "key2", &find))
+	assert.Equal(t, "value2", find)
+	assert.Equal(t, 1, c.ll.Len())
+}
+
+func TestMemoryCache_EvictLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	var find string
+
+	assert.NoError(t, c.Set(context.TODO(), "key1", "value1", time.Minute))
+	assert.NoError(t, c.Set(context.TODO(), "key2", "value2", time.Minute))
+	assert.NoError(t, c.Get(context.TODO(), "key1", &find))
+	assert.NoError(t, c.Set(context.TODO(), "key3", "value3", time.Minute))
+
+	assert.NoError(t, c.Get(context.TODO(), "key1", &find))
+	assert.Equal(t, ErrCacheMiss, c.Get(context.TODO(), "key2", &find))
+	assert.NoError(t, c.Get(context.TODO(), "key3", &find))
+}
+
+func TestMemoryCache_SetXX(t *testing.T) {
+	c := NewMemoryCache(10)
+	var find string
+
+	assert.NoError(t, c.SetXX(context.TODO(), "key", "value1", time.Minute))
+	assert.Equal(t, ErrCacheMiss, c.Get(context.TODO(), "key", &find))
+
+	assert.NoError(t, c.Set(context.TODO(), "key", "value1", time.Minute))
+	assert.NoError(t, c.SetXX(context.TODO(), "key", "value2", time.Minute))
+	assert.NoError(t, c.Get(context.TODO(), "key", &find))
+	assert.Equal(t, "value2", find)
+}
+
+func TestMemoryCache_Delete(t *testing.T) {
+	c := NewMemoryCache(10)
+	assert.NoError(t, c.Set(context.TODO(), "key1", "value1", time.Minute))
+	assert.NoError(t, c.Set(context.TODO(), "key2", "value2", time.Minute))
+
+	assert.NoError(t, c.Delete(context.TODO(), "key1", "key2", "not-exist"))
+
+	for _, key := range []string{"key1", "key2"} {
+		exists, err := c.Exists(context.TODO(), key)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestMemoryCache_Once(t *testing.T) {
+	var (
+		c     = NewMemoryCache(10)
+		calls int32
+		wg    sync.WaitGroup
+	)
+	do := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &testValue{Name: "name", Count: 1}, nil
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var find testValue
+			assert.NoError(t, c.Once(context.TODO(), "key", &find, time.Minute, do))
+			assert.Equal(t, "name", find.Name)
+		}()
+	}
+	wg.Wait()
+
+	var find testValue
+	assert.NoError(t, c.Once(context.TODO(), "key", &find, time.Minute, do))
+	assert.Equal(t, "name", find.Name)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestMemoryCache_OnceError(t *testing.T) {
+	c := NewMemoryCache(10)
+	forceErr := errors.New("force error")
+
+	var find testValue
+	err := c.Once(context.TODO(), "key", &find, time.Minute, func() (interface{}, error) {
+		return nil, forceErr
+	})
+
+	assert.Equal(t, forceErr, err)
+	exists, err := c.Exists(context.TODO(), "key")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
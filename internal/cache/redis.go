@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"github.com/go-redis/cache/v8"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// NewRedisCache creates a new Cache backed by given redis client.
+// The client is owned by the caller and not closed by this cache.
+func NewRedisCache(cli redis.UniversalClient) Cache {
+	return &redisCache{
+		cli:   cli,
+		cache: cache.New(&cache.Options{Redis: cli}),
+	}
+}
+
+type redisCache struct {
+	cli   redis.UniversalClient
+	cache *cache.Cache
+}
+
+func (rc *redisCache) Get(ctx context.Context, key string, value interface{}) error {
+	if err := rc.cache.Get(ctx, key, value); err != nil {
+		if err == cache.ErrCacheMiss {
+			return ErrCacheMiss
+		}
+		return err
+	}
+	return nil
+}
+
+func (rc *redisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return rc.cache.Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: value,
+		TTL:   redisTTL(ttl),
+	})
+}
+
+func (rc *redisCache) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return rc.cache.Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: value,
+		TTL:   redisTTL(ttl),
+		SetXX: true,
+	})
+}
+
+func (rc *redisCache) Once(ctx context.Context, key string, value interface{}, ttl time.Duration, do func() (interface{}, error)) error {
+	return rc.cache.Once(&cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: value,
+		TTL:   redisTTL(ttl),
+		Do: func(_ *cache.Item) (interface{}, error) {
+			return do()
+		},
+	})
+}
+
+func (rc *redisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := rc.cli.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (rc *redisCache) Delete(ctx context.Context, keys ...string) error {
+	// delete keys one by one because keys may be in different slots of a cluster.
+	for _, key := range keys {
+		if err := rc.cli.Del(ctx, key).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rc *redisCache) Close() error {
+	return nil
+}
+
+// redisTTL converts given ttl to cache.Item's TTL following the Cache contract.
+// cache.Item uses 1 hour for zero or less than a second TTL, so zero or negative ttl is converted to -1
+// which never expires and less than a second is rounded up to a second.
+func redisTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return -1
+	}
+	if ttl < time.Second {
+		return time.Second
+	}
+	return ttl
+}
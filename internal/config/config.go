@@ -29,6 +29,13 @@ type Config struct {
 	JWTConfig     JWTConfig     `json:"jwt"`
 	DBConfig      DBConfig      `json:"db"`
 	CacheConfig   CacheConfig   `json:"cache"`
+	PubSubConfig  PubSubConfig  `json:"pubsub"`
+	WebhookConfig WebhookConfig `json:"webhook"`
+	OutboxConfig  OutboxConfig  `json:"outbox"`
+	MailConfig    MailConfig    `json:"mail"`
+	UserConfig    UserConfig    `json:"user"`
+	OIDCConfig    OIDCConfig    `json:"oidc"`
+	ArticleConfig ArticleConfig `json:"article"`
 }
 
 type LoggingConfig struct {
@@ -37,23 +44,55 @@ type LoggingConfig struct {
 	Development bool   `json:"development"`
 }
 
+// ServerConfig represents configs of the http server.
+// TrustedProxies are CIDRs of reverse proxies. Client ips are taken from X-Forwarded-For header only if requests
+// are sent from the trusted proxies, otherwise the remote address of connections is used.
 type ServerConfig struct {
-	Port         int           `json:"port"`
-	Timeout      time.Duration `json:"timeout"`
-	ReadTimeout  time.Duration `json:"readTimeout"`
-	WriteTimeout time.Duration `json:"writeTimeout"`
-	Docs         struct {
+	Port           int           `json:"port"`
+	Timeout        time.Duration `json:"timeout"`
+	ReadTimeout    time.Duration `json:"readTimeout"`
+	WriteTimeout   time.Duration `json:"writeTimeout"`
+	TrustedProxies []string      `json:"trustedProxies"`
+	Docs           struct {
 		Enabled bool   `json:"enabled"`
 		Path    string `json:"path"`
 	} `json:"docs"`
+	RateLimit RateLimitConfig `json:"rateLimit"`
+}
+
+// RateLimitConfig represents configs of request rate limiting.
+// Type is one of "memory" and "redis" and the redis type uses a redis client of cache.redis configs.
+// Requests are limited per group of Groups and keyed by an authenticated user or a client ip.
+type RateLimitConfig struct {
+	Enabled bool                     `json:"enabled"`
+	Type    string                   `json:"type"`
+	Prefix  string                   `json:"prefix"`
+	Groups  map[string]RateLimitRule `json:"groups"`
+}
+
+// RateLimitRule represents a token bucket limit of a route group.
+// Routes are "{METHOD} {path}" or "{path}" matched to registered echo paths and "*" matches all other routes.
+// Rate tokens are refilled every Period up to Burst. Burst is the same as Rate if 0.
+type RateLimitRule struct {
+	Routes []string      `json:"routes"`
+	Rate   int           `json:"rate"`
+	Period time.Duration `json:"period"`
+	Burst  int           `json:"burst"`
 }
 
+// JWTConfig represents configs of auth tokens.
+// AccessTokenTimeout is a lifetime of JWT access tokens and
+// SessionTimeout is a lifetime of refresh tokens used for issuing new access tokens.
 type JWTConfig struct {
-	Secret         string        `json:"secret"`
-	SessionTimeout time.Duration `json:"sessionTimeout"`
+	Secret             string        `json:"secret"`
+	SessionTimeout     time.Duration `json:"sessionTimeout"`
+	AccessTokenTimeout time.Duration `json:"accessTokenTimeout"`
 }
 
+// DBConfig represents database configs.
+// Driver is one of "mysql", "postgres" and "sqlite" and migrations are loaded from Migrate.Dir/{Driver}.
 type DBConfig struct {
+	Driver         string `json:"driver"`
 	DataSourceName string `json:"dataSourceName"`
 	Migrate        struct {
 		Enable bool   `json:"enable"`
@@ -66,12 +105,21 @@ type DBConfig struct {
 	} `json:"pool"`
 }
 
+// CacheConfig represents cache configs.
+// Type is one of "redis" and "memory". "memory" is an in-process cache for a single node.
 type CacheConfig struct {
-	Enabled     bool          `json:"enabled"`
-	Prefix      string        `json:"prefix"`
-	Type        string        `json:"type"`
-	TTL         time.Duration `json:"ttl"`
-	RedisConfig RedisConfig   `json:"redis"`
+	Enabled      bool          `json:"enabled"`
+	Prefix       string        `json:"prefix"`
+	Type         string        `json:"type"`
+	TTL          time.Duration `json:"ttl"`
+	RedisConfig  RedisConfig   `json:"redis"`
+	MemoryConfig MemoryConfig  `json:"memory"`
+}
+
+// MemoryConfig represents in-process cache configs.
+// Size is the maximum number of entries.
+type MemoryConfig struct {
+	Size int `json:"size"`
 }
 
 type RedisConfig struct {
@@ -86,6 +134,178 @@ type RedisConfig struct {
 	IdleTimeout  time.Duration `json:"idleTimeout"`
 }
 
+// PubSubConfig represents configs of publish/subscribe messaging between server instances.
+// Type is one of "redis" and "memory". "memory" delivers messages within a single node and
+// "redis" uses the redis configs in CacheConfig to deliver messages to all nodes.
+type PubSubConfig struct {
+	Type string `json:"type"`
+}
+
+// WebhookConfig represents configs of outgoing webhooks.
+// Pending deliveries are sent every Interval in batches by up to Workers concurrent requests.
+// The delivery job is disabled if Interval is 0.
+// A failed delivery is retried after Backoff which is doubled for each attempt until MaxAttempts.
+// Webhooks to loopback, private and link-local addresses are rejected unless AllowPrivate is true.
+type WebhookConfig struct {
+	Interval     time.Duration `json:"interval"`
+	Batch        int           `json:"batch"`
+	Workers      int           `json:"workers"`
+	Timeout      time.Duration `json:"timeout"`
+	MaxAttempts  int           `json:"maxAttempts"`
+	Backoff      time.Duration `json:"backoff"`
+	AllowPrivate bool          `json:"allowPrivate"`
+}
+
+// OutboxConfig represents configs of the transactional outbox of domain events.
+// Pending events are relayed to Sinks every Interval in batches. The relay job is disabled if Interval is 0.
+// A failed event is retried after Backoff which is doubled for each attempt up to MaxBackoff.
+// Relayed events older than Retention are deleted, or kept forever if Retention is 0.
+type OutboxConfig struct {
+	Interval   time.Duration       `json:"interval"`
+	Batch      int                 `json:"batch"`
+	Backoff    time.Duration       `json:"backoff"`
+	MaxBackoff time.Duration       `json:"maxBackoff"`
+	Retention  time.Duration       `json:"retention"`
+	Sinks      []string            `json:"sinks"`
+	Webhook    OutboxWebhookConfig `json:"webhook"`
+	Redis      OutboxRedisConfig   `json:"redis"`
+}
+
+// OutboxWebhookConfig represents configs of the "webhook" outbox sink.
+// Events are posted to URL with a signature of Secret.
+type OutboxWebhookConfig struct {
+	URL     string        `json:"url"`
+	Secret  string        `json:"secret"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+// OutboxRedisConfig represents configs of the "redis" outbox sink.
+// Events are added to Stream which is trimmed to approximately MaxLen entries if MaxLen is positive.
+// The redis configs in CacheConfig are used to connect.
+type OutboxRedisConfig struct {
+	Stream string `json:"stream"`
+	MaxLen int64  `json:"maxLen"`
+}
+
+// MailConfig represents configs of outgoing emails.
+// Type is one of "smtp", "file" and "log". "file" writes emails to File.Dir and "log" writes emails to logs,
+// which are useful for local development. Links in emails are built from BaseURL of the frontend.
+type MailConfig struct {
+	Type    string         `json:"type"`
+	From    string         `json:"from"`
+	BaseURL string         `json:"baseURL"`
+	SMTP    SMTPMailConfig `json:"smtp"`
+	File    FileMailConfig `json:"file"`
+}
+
+// SMTPMailConfig represents configs of the "smtp" mailer.
+// PLAIN authentication is used if Username is not empty. Sending an email is aborted after Timeout.
+type SMTPMailConfig struct {
+	Host     string        `json:"host"`
+	Port     int           `json:"port"`
+	Username string        `json:"username"`
+	Password string        `json:"password"`
+	Timeout  time.Duration `json:"timeout"`
+}
+
+// FileMailConfig represents configs of the "file" mailer.
+type FileMailConfig struct {
+	Dir string `json:"dir"`
+}
+
+// UserConfig represents configs of users.
+type UserConfig struct {
+	Verification  VerificationConfig  `json:"verification"`
+	PasswordReset PasswordResetConfig `json:"passwordReset"`
+}
+
+// VerificationConfig represents configs of email verification.
+// Users can't sign in until their emails are verified if Required is true.
+// A verification token is valid for TokenTTL.
+type VerificationConfig struct {
+	Required bool          `json:"required"`
+	TokenTTL time.Duration `json:"tokenTTL"`
+}
+
+// PasswordResetConfig represents configs of password reset.
+// A password reset token is valid for TokenTTL.
+type PasswordResetConfig struct {
+	TokenTTL time.Duration `json:"tokenTTL"`
+}
+
+// OIDCConfig represents configs of social login with OpenID Connect providers.
+// Providers are keyed by a name used in "/api/auth/{name}/**" paths.
+// A login must be completed within StateTTL and requests to providers time out after Timeout.
+type OIDCConfig struct {
+	StateTTL  time.Duration                 `json:"stateTTL"`
+	Timeout   time.Duration                 `json:"timeout"`
+	Providers map[string]OIDCProviderConfig `json:"providers"`
+}
+
+// OIDCProviderConfig represents configs of an OpenID Connect provider.
+// Endpoints are discovered from Issuer and RedirectURL is the callback url registered to the provider.
+// "openid", "email" and "profile" scopes are requested if Scopes is empty.
+type OIDCProviderConfig struct {
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientID"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectURL"`
+	Scopes       []string `json:"scopes"`
+}
+
+// ArticleConfig represents configs of articles.
+type ArticleConfig struct {
+	Trash    TrashConfig    `json:"trash"`
+	Publish  PublishConfig  `json:"publish"`
+	Comment  CommentConfig  `json:"comment"`
+	Reaction ReactionConfig `json:"reaction"`
+	Report   ReportConfig   `json:"report"`
+	Stream   StreamConfig   `json:"stream"`
+}
+
+// TrashConfig represents configs of soft-deleted articles and comments.
+// Deleted items older than Retention are purged every PurgeInterval. The purge job is disabled if PurgeInterval is 0.
+type TrashConfig struct {
+	Retention     time.Duration `json:"retention"`
+	PurgeInterval time.Duration `json:"purgeInterval"`
+	PurgeBatch    int           `json:"purgeBatch"`
+}
+
+// PublishConfig represents configs of scheduled articles.
+// Scheduled articles are published every Interval. The publish job is disabled if Interval is 0.
+type PublishConfig struct {
+	Interval time.Duration `json:"interval"`
+	Batch    int           `json:"batch"`
+}
+
+// CommentConfig represents configs of comments.
+// Comments can be edited by the author within EditWindow after created. No time limit if EditWindow is 0.
+// Replies can be nested up to MaxDepth levels under a root comment.
+type CommentConfig struct {
+	EditWindow time.Duration `json:"editWindow"`
+	MaxDepth   int           `json:"maxDepth"`
+}
+
+// ReactionConfig represents configs of reactions to articles and comments.
+// Kinds is the set of allowed reaction kinds.
+type ReactionConfig struct {
+	Kinds []string `json:"kinds"`
+}
+
+// ReportConfig represents configs of reports to articles and comments.
+// Reasons is the set of allowed report reasons. Reported content is hidden automatically
+// when the number of pending reports reaches Threshold. Disabled if Threshold is 0.
+type ReportConfig struct {
+	Reasons   []string `json:"reasons"`
+	Threshold int      `json:"threshold"`
+}
+
+// StreamConfig represents configs of server-sent event streams.
+// A comment is sent every KeepAlive to keep idle connections open. Disabled if KeepAlive is 0.
+type StreamConfig struct {
+	KeepAlive time.Duration `json:"keepAlive"`
+}
+
 // Load loads configs in given order.
 // 1. defaultConfig
 // 2. environment having "REALWORLD_APP_" prefix
@@ -172,15 +392,29 @@ func WithConfigEnv(prefix string) Option {
 // MarshalJSON returns a flat json data with masking values such as db password or jwt.secret config.
 func (c *Config) MarshalJSON() ([]byte, error) {
 	cfg := struct {
-		ServerConfig ServerConfig `json:"server"`
-		JWTConfig    JWTConfig    `json:"jwt"`
-		DBConfig     DBConfig     `json:"db"`
-		CacheConfig  CacheConfig  `json:"cache"`
+		ServerConfig  ServerConfig  `json:"server"`
+		JWTConfig     JWTConfig     `json:"jwt"`
+		DBConfig      DBConfig      `json:"db"`
+		CacheConfig   CacheConfig   `json:"cache"`
+		PubSubConfig  PubSubConfig  `json:"pubsub"`
+		WebhookConfig WebhookConfig `json:"webhook"`
+		OutboxConfig  OutboxConfig  `json:"outbox"`
+		MailConfig    MailConfig    `json:"mail"`
+		UserConfig    UserConfig    `json:"user"`
+		OIDCConfig    OIDCConfig    `json:"oidc"`
+		ArticleConfig ArticleConfig `json:"article"`
 	}{
-		ServerConfig: c.ServerConfig,
-		JWTConfig:    c.JWTConfig,
-		DBConfig:     c.DBConfig,
-		CacheConfig:  c.CacheConfig,
+		ServerConfig:  c.ServerConfig,
+		JWTConfig:     c.JWTConfig,
+		DBConfig:      c.DBConfig,
+		CacheConfig:   c.CacheConfig,
+		PubSubConfig:  c.PubSubConfig,
+		WebhookConfig: c.WebhookConfig,
+		OutboxConfig:  c.OutboxConfig,
+		MailConfig:    c.MailConfig,
+		UserConfig:    c.UserConfig,
+		OIDCConfig:    c.OIDCConfig,
+		ArticleConfig: c.ArticleConfig,
 	}
 	data, err := json.Marshal(&cfg)
 	if err != nil {
@@ -199,7 +433,9 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 
 	maskKeys := map[string]struct{}{
 		// add keys if u want to mask some properties.
-		"jwt.secret": {},
+		"jwt.secret":            {},
+		"outbox.webhook.secret": {},
+		"mail.smtp.password":    {},
 	}
 
 	for key, val := range m {
@@ -209,6 +445,10 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		if _, ok := maskKeys[key]; ok {
 			m[key] = "****"
 		}
+		// secrets of oidc providers having dynamic keys.
+		if strings.HasPrefix(key, "oidc.providers.") && strings.HasSuffix(key, ".clientSecret") {
+			m[key] = "****"
+		}
 	}
 	return json.Marshal(&m)
 }
@@ -23,14 +23,30 @@ func TestLoad(t *testing.T) {
 	equal(t, 5*time.Second, defaultConfig["server.timeout"].(time.Duration), cfg.ServerConfig.Timeout)
 	equal(t, 5*time.Second, defaultConfig["server.readTimeout"].(time.Duration), cfg.ServerConfig.ReadTimeout)
 	equal(t, 10*time.Second, defaultConfig["server.writeTimeout"].(time.Duration), cfg.ServerConfig.WriteTimeout)
+	equal(t, []string{}, defaultConfig["server.trustedProxies"].([]string), cfg.ServerConfig.TrustedProxies)
 	equal(t, true, defaultConfig["server.docs.enabled"].(bool), cfg.ServerConfig.Docs.Enabled)
 	equal(t, "/config/doc.html", defaultConfig["server.docs.path"].(string), cfg.ServerConfig.Docs.Path)
+	equal(t, true, defaultConfig["server.rateLimit.enabled"].(bool), cfg.ServerConfig.RateLimit.Enabled)
+	equal(t, "memory", defaultConfig["server.rateLimit.type"].(string), cfg.ServerConfig.RateLimit.Type)
+	equal(t, "realworld-ratelimit-", defaultConfig["server.rateLimit.prefix"].(string), cfg.ServerConfig.RateLimit.Prefix)
+	equal(t, []string{"POST /api/users/login", "POST /api/users", "POST /api/users/password/forgot", "POST /api/users/password/reset"},
+		defaultConfig["server.rateLimit.groups.auth.routes"].([]string), cfg.ServerConfig.RateLimit.Groups["auth"].Routes)
+	equal(t, 10, defaultConfig["server.rateLimit.groups.auth.rate"].(int), cfg.ServerConfig.RateLimit.Groups["auth"].Rate)
+	equal(t, 1*time.Minute, defaultConfig["server.rateLimit.groups.auth.period"].(time.Duration), cfg.ServerConfig.RateLimit.Groups["auth"].Period)
+	equal(t, 5, defaultConfig["server.rateLimit.groups.auth.burst"].(int), cfg.ServerConfig.RateLimit.Groups["auth"].Burst)
+	equal(t, []string{"POST /api/articles/:slug/comments"},
+		defaultConfig["server.rateLimit.groups.comment.routes"].([]string), cfg.ServerConfig.RateLimit.Groups["comment"].Routes)
+	equal(t, 30, defaultConfig["server.rateLimit.groups.comment.rate"].(int), cfg.ServerConfig.RateLimit.Groups["comment"].Rate)
+	equal(t, 1*time.Minute, defaultConfig["server.rateLimit.groups.comment.period"].(time.Duration), cfg.ServerConfig.RateLimit.Groups["comment"].Period)
+	equal(t, 10, defaultConfig["server.rateLimit.groups.comment.burst"].(int), cfg.ServerConfig.RateLimit.Groups["comment"].Burst)
 	// jwt configs
 	equal(t, "secret-key", defaultConfig["jwt.secret"].(string), cfg.JWTConfig.Secret)
 	equal(t, 240*time.Hour, defaultConfig["jwt.sessionTimeout"].(time.Duration), cfg.JWTConfig.SessionTimeout)
+	equal(t, 15*time.Minute, defaultConfig["jwt.accessTokenTimeout"].(time.Duration), cfg.JWTConfig.AccessTokenTimeout)
 	// db configs
 	equal(t, "root:password@tcp(127.0.0.1:3306)/local_db?charset=utf8&parseTime=True&multiStatements=true",
 		defaultConfig["db.dataSourceName"].(string), cfg.DBConfig.DataSourceName)
+	equal(t, "mysql", defaultConfig["db.driver"].(string), cfg.DBConfig.Driver)
 	equal(t, false, defaultConfig["db.migrate.enable"].(bool), cfg.DBConfig.Migrate.Enable)
 	equal(t, "", defaultConfig["db.migrate.dir"].(string), cfg.DBConfig.Migrate.Dir)
 	equal(t, 50, defaultConfig["db.pool.maxOpen"].(int), cfg.DBConfig.Pool.MaxOpen)
@@ -50,6 +66,59 @@ func TestLoad(t *testing.T) {
 	equal(t, 1*time.Minute, defaultConfig["cache.redis.poolTimeout"].(time.Duration), cfg.CacheConfig.RedisConfig.PoolTimeout)
 	equal(t, 0, defaultConfig["cache.redis.maxConnAge"].(time.Duration), cfg.CacheConfig.RedisConfig.MaxConnAge)
 	equal(t, 5*time.Minute, defaultConfig["cache.redis.idleTimeout"].(time.Duration), cfg.CacheConfig.RedisConfig.IdleTimeout)
+	equal(t, 10000, defaultConfig["cache.memory.size"].(int), cfg.CacheConfig.MemoryConfig.Size)
+	// pubsub configs
+	equal(t, "memory", defaultConfig["pubsub.type"].(string), cfg.PubSubConfig.Type)
+	// webhook configs
+	equal(t, 10*time.Second, defaultConfig["webhook.interval"].(time.Duration), cfg.WebhookConfig.Interval)
+	equal(t, 100, defaultConfig["webhook.batch"].(int), cfg.WebhookConfig.Batch)
+	equal(t, 4, defaultConfig["webhook.workers"].(int), cfg.WebhookConfig.Workers)
+	equal(t, 5*time.Second, defaultConfig["webhook.timeout"].(time.Duration), cfg.WebhookConfig.Timeout)
+	equal(t, 5, defaultConfig["webhook.maxAttempts"].(int), cfg.WebhookConfig.MaxAttempts)
+	equal(t, 30*time.Second, defaultConfig["webhook.backoff"].(time.Duration), cfg.WebhookConfig.Backoff)
+	equal(t, false, defaultConfig["webhook.allowPrivate"].(bool), cfg.WebhookConfig.AllowPrivate)
+	// outbox configs
+	equal(t, 5*time.Second, defaultConfig["outbox.interval"].(time.Duration), cfg.OutboxConfig.Interval)
+	equal(t, 100, defaultConfig["outbox.batch"].(int), cfg.OutboxConfig.Batch)
+	equal(t, 10*time.Second, defaultConfig["outbox.backoff"].(time.Duration), cfg.OutboxConfig.Backoff)
+	equal(t, 10*time.Minute, defaultConfig["outbox.maxBackoff"].(time.Duration), cfg.OutboxConfig.MaxBackoff)
+	equal(t, 168*time.Hour, defaultConfig["outbox.retention"].(time.Duration), cfg.OutboxConfig.Retention)
+	equal(t, []string{"log"}, defaultConfig["outbox.sinks"].([]string), cfg.OutboxConfig.Sinks)
+	equal(t, "", defaultConfig["outbox.webhook.url"].(string), cfg.OutboxConfig.Webhook.URL)
+	equal(t, "", defaultConfig["outbox.webhook.secret"].(string), cfg.OutboxConfig.Webhook.Secret)
+	equal(t, 5*time.Second, defaultConfig["outbox.webhook.timeout"].(time.Duration), cfg.OutboxConfig.Webhook.Timeout)
+	equal(t, "realworld-outbox", defaultConfig["outbox.redis.stream"].(string), cfg.OutboxConfig.Redis.Stream)
+	equal(t, 10000, defaultConfig["outbox.redis.maxLen"].(int), cfg.OutboxConfig.Redis.MaxLen)
+	// mail configs
+	equal(t, "log", defaultConfig["mail.type"].(string), cfg.MailConfig.Type)
+	equal(t, "noreply@realworld.io", defaultConfig["mail.from"].(string), cfg.MailConfig.From)
+	equal(t, "http://localhost:3000", defaultConfig["mail.baseURL"].(string), cfg.MailConfig.BaseURL)
+	equal(t, "localhost", defaultConfig["mail.smtp.host"].(string), cfg.MailConfig.SMTP.Host)
+	equal(t, 587, defaultConfig["mail.smtp.port"].(int), cfg.MailConfig.SMTP.Port)
+	equal(t, "", defaultConfig["mail.smtp.username"].(string), cfg.MailConfig.SMTP.Username)
+	equal(t, "", defaultConfig["mail.smtp.password"].(string), cfg.MailConfig.SMTP.Password)
+	equal(t, 10*time.Second, defaultConfig["mail.smtp.timeout"].(time.Duration), cfg.MailConfig.SMTP.Timeout)
+	equal(t, "mails", defaultConfig["mail.file.dir"].(string), cfg.MailConfig.File.Dir)
+	// user configs
+	equal(t, true, defaultConfig["user.verification.required"].(bool), cfg.UserConfig.Verification.Required)
+	equal(t, 24*time.Hour, defaultConfig["user.verification.tokenTTL"].(time.Duration), cfg.UserConfig.Verification.TokenTTL)
+	equal(t, 1*time.Hour, defaultConfig["user.passwordReset.tokenTTL"].(time.Duration), cfg.UserConfig.PasswordReset.TokenTTL)
+	// oidc configs
+	equal(t, 10*time.Minute, defaultConfig["oidc.stateTTL"].(time.Duration), cfg.OIDCConfig.StateTTL)
+	equal(t, 10*time.Second, defaultConfig["oidc.timeout"].(time.Duration), cfg.OIDCConfig.Timeout)
+	assert.Empty(t, cfg.OIDCConfig.Providers)
+	// article configs
+	equal(t, 720*time.Hour, defaultConfig["article.trash.retention"].(time.Duration), cfg.ArticleConfig.Trash.Retention)
+	equal(t, 1*time.Hour, defaultConfig["article.trash.purgeInterval"].(time.Duration), cfg.ArticleConfig.Trash.PurgeInterval)
+	equal(t, 100, defaultConfig["article.trash.purgeBatch"].(int), cfg.ArticleConfig.Trash.PurgeBatch)
+	equal(t, 1*time.Minute, defaultConfig["article.publish.interval"].(time.Duration), cfg.ArticleConfig.Publish.Interval)
+	equal(t, 100, defaultConfig["article.publish.batch"].(int), cfg.ArticleConfig.Publish.Batch)
+	equal(t, time.Duration(0), defaultConfig["article.comment.editWindow"].(time.Duration), cfg.ArticleConfig.Comment.EditWindow)
+	equal(t, 5, defaultConfig["article.comment.maxDepth"].(int), cfg.ArticleConfig.Comment.MaxDepth)
+	equal(t, []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"}, defaultConfig["article.reaction.kinds"].([]string), cfg.ArticleConfig.Reaction.Kinds)
+	equal(t, []string{"spam", "abuse", "harassment", "offensive", "other"}, defaultConfig["article.report.reasons"].([]string), cfg.ArticleConfig.Report.Reasons)
+	equal(t, 5, defaultConfig["article.report.threshold"].(int), cfg.ArticleConfig.Report.Threshold)
+	equal(t, 15*time.Second, defaultConfig["article.stream.keepAlive"].(time.Duration), cfg.ArticleConfig.Stream.KeepAlive)
 }
 
 func equal(t *testing.T, expected, defaultValue, actualValue interface{}) {
@@ -95,3 +164,27 @@ func TestMarshalJSON(t *testing.T) {
 	assert.Equal(t, "root:****@tcp(127.0.0.1:3306)/local_db?charset=utf8&parseTime=True&multiStatements=true", m["db.dataSourceName"])
 	assert.Equal(t, "****", m["jwt.secret"])
 }
+
+func TestLoadOIDCProviders(t *testing.T) {
+	cfg, err := LoadWithOptions(WithConfigMap(map[string]interface{}{
+		"oidc.providers.google.issuer":       "https://accounts.google.com",
+		"oidc.providers.google.clientID":     "client-id",
+		"oidc.providers.google.clientSecret": "client-secret",
+		"oidc.providers.google.redirectURL":  "http://localhost:8080/api/auth/google/callback",
+	}))
+	assert.NoError(t, err)
+
+	p, ok := cfg.OIDCConfig.Providers["google"]
+	assert.True(t, ok)
+	assert.Equal(t, "https://accounts.google.com", p.Issuer)
+	assert.Equal(t, "client-id", p.ClientID)
+	assert.Equal(t, "client-secret", p.ClientSecret)
+	assert.Equal(t, "http://localhost:8080/api/auth/google/callback", p.RedirectURL)
+
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, "****", m["oidc.providers.google.clientSecret"])
+	assert.Equal(t, "client-id", m["oidc.providers.google.clientID"])
+}
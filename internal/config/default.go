@@ -7,16 +7,31 @@ var defaultConfig = map[string]interface{}{
 	"logging.encoding":    "console",
 	"logging.development": true,
 
-	"server.port":         8080,
-	"server.timeout":      5 * time.Second,
-	"server.readTimeout":  5 * time.Second,
-	"server.writeTimeout": 10 * time.Second,
-	"server.docs.enabled": true,
-	"server.docs.path":    "/config/doc.html",
+	"server.port":           8080,
+	"server.timeout":        5 * time.Second,
+	"server.readTimeout":    5 * time.Second,
+	"server.writeTimeout":   10 * time.Second,
+	"server.trustedProxies": []string{},
+	"server.docs.enabled":   true,
+	"server.docs.path":      "/config/doc.html",
 
-	"jwt.secret":         "secret-key",
-	"jwt.sessionTimeout": 240 * time.Hour,
+	"server.rateLimit.enabled":               true,
+	"server.rateLimit.type":                  "memory",
+	"server.rateLimit.prefix":                "realworld-ratelimit-",
+	"server.rateLimit.groups.auth.routes":    []string{"POST /api/users/login", "POST /api/users", "POST /api/users/password/forgot", "POST /api/users/password/reset"},
+	"server.rateLimit.groups.auth.rate":      10,
+	"server.rateLimit.groups.auth.period":    1 * time.Minute,
+	"server.rateLimit.groups.auth.burst":     5,
+	"server.rateLimit.groups.comment.routes": []string{"POST /api/articles/:slug/comments"},
+	"server.rateLimit.groups.comment.rate":   30,
+	"server.rateLimit.groups.comment.period": 1 * time.Minute,
+	"server.rateLimit.groups.comment.burst":  10,
 
+	"jwt.secret":             "secret-key",
+	"jwt.sessionTimeout":     240 * time.Hour,
+	"jwt.accessTokenTimeout": 15 * time.Minute,
+
+	"db.driver":           "mysql",
 	"db.dataSourceName":   "root:password@tcp(127.0.0.1:3306)/local_db?charset=utf8&parseTime=True&multiStatements=true",
 	"db.migrate.enable":   false,
 	"db.migrate.dir":      "",
@@ -37,4 +52,56 @@ var defaultConfig = map[string]interface{}{
 	"cache.redis.poolTimeout":  1 * time.Minute,
 	"cache.redis.maxConnAge":   time.Duration(0),
 	"cache.redis.idleTimeout":  5 * time.Minute,
+	"cache.memory.size":        10000,
+
+	"pubsub.type": "memory",
+
+	"webhook.interval":     10 * time.Second,
+	"webhook.batch":        100,
+	"webhook.workers":      4,
+	"webhook.timeout":      5 * time.Second,
+	"webhook.maxAttempts":  5,
+	"webhook.backoff":      30 * time.Second,
+	"webhook.allowPrivate": false,
+
+	"outbox.interval":        5 * time.Second,
+	"outbox.batch":           100,
+	"outbox.backoff":         10 * time.Second,
+	"outbox.maxBackoff":      10 * time.Minute,
+	"outbox.retention":       168 * time.Hour,
+	"outbox.sinks":           []string{"log"},
+	"outbox.webhook.url":     "",
+	"outbox.webhook.secret":  "",
+	"outbox.webhook.timeout": 5 * time.Second,
+	"outbox.redis.stream":    "realworld-outbox",
+	"outbox.redis.maxLen":    10000,
+
+	"mail.type":          "log",
+	"mail.from":          "noreply@realworld.io",
+	"mail.baseURL":       "http://localhost:3000",
+	"mail.smtp.host":     "localhost",
+	"mail.smtp.port":     587,
+	"mail.smtp.username": "",
+	"mail.smtp.password": "",
+	"mail.smtp.timeout":  10 * time.Second,
+	"mail.file.dir":      "mails",
+
+	"user.verification.required":  true,
+	"user.verification.tokenTTL":  24 * time.Hour,
+	"user.passwordReset.tokenTTL": 1 * time.Hour,
+
+	"oidc.stateTTL": 10 * time.Minute,
+	"oidc.timeout":  10 * time.Second,
+
+	"article.trash.retention":     720 * time.Hour,
+	"article.trash.purgeInterval": 1 * time.Hour,
+	"article.trash.purgeBatch":    100,
+	"article.publish.interval":    1 * time.Minute,
+	"article.publish.batch":       100,
+	"article.comment.editWindow":  time.Duration(0),
+	"article.comment.maxDepth":    5,
+	"article.reaction.kinds":      []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"},
+	"article.report.reasons":      []string{"spam", "abuse", "harassment", "offensive", "other"},
+	"article.report.threshold":    5,
+	"article.stream.keepAlive":    15 * time.Second,
 }
@@ -1,13 +1,22 @@
 package database
 
 import (
+	"fmt"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
 	"go.uber.org/zap/zapcore"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"time"
 )
 
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 // NewDatabase creates a new gorm.DB from given config.
 func NewDatabase(conf *config.Config) (*gorm.DB, error) {
 	var (
@@ -16,8 +25,13 @@ func NewDatabase(conf *config.Config) (*gorm.DB, error) {
 		logger = NewLogger(time.Second, true, zapcore.InfoLevel)
 	)
 
+	dialector, err := newDialector(conf.DBConfig.Driver, conf.DBConfig.DataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
 	for i := 0; i < 10; i++ {
-		db, err = gorm.Open(mysql.Open(conf.DBConfig.DataSourceName), &gorm.Config{
+		db, err = gorm.Open(dialector, &gorm.Config{
 			Logger: logger,
 		})
 		if err == nil {
@@ -38,10 +52,24 @@ func NewDatabase(conf *config.Config) (*gorm.DB, error) {
 	sqlDB.SetConnMaxLifetime(conf.DBConfig.Pool.MaxLifetime)
 
 	if conf.DBConfig.Migrate.Enable {
-		err := migrateDB(conf.DBConfig.DataSourceName, conf.DBConfig.Migrate.Dir)
+		err := migrateDB(conf.DBConfig.Driver, conf.DBConfig.DataSourceName, conf.DBConfig.Migrate.Dir)
 		if err != nil {
 			return nil, err
 		}
 	}
 	return db, nil
 }
+
+// newDialector returns a gorm.Dialector of given driver.
+func newDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverMySQL:
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
@@ -3,6 +3,8 @@ package database
 import (
 	"errors"
 	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
 	"gorm.io/gorm"
 )
 
@@ -13,22 +15,53 @@ var (
 	ErrKeyConflict = errors.New("conflict key")
 	// ErrFKConstraint an error if foreign key constraint failed.
 	ErrFKConstraint = errors.New("a foreign key constraint fails")
+	// ErrConcurrentUpdate an error if a record is updated by others concurrently.
+	ErrConcurrentUpdate = errors.New("concurrent update")
 )
 
 // WrapError wrap database error to handle cause.
 // ErrRecordNotFound is returned if err is a gorm.ErrRecordNotFound
 // If conflict key error, then ErrKeyConflict will return.
+// If foreign key constraint error, then ErrFKConstraint will return.
 func WrapError(err error) error {
 	if err == gorm.ErrRecordNotFound {
 		return ErrRecordNotFound
 	}
-	if e, ok := err.(*mysql.MySQLError); ok {
-		switch e.Number {
+
+	// mysql
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
 		case 1062:
 			return ErrKeyConflict
 		case 1452:
 			return ErrFKConstraint
 		}
+		return err
+	}
+
+	// postgres
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return ErrKeyConflict
+		case "23503": // foreign_key_violation
+			return ErrFKConstraint
+		}
+		return err
+	}
+
+	// sqlite
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return ErrKeyConflict
+		case sqlite3.ErrConstraintForeignKey:
+			return ErrFKConstraint
+		}
+		return err
 	}
 	return err
 }
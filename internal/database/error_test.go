@@ -0,0 +1,38 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestWrapError(t *testing.T) {
+	otherErr := errors.New("other error")
+	cases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "record not found", err: gorm.ErrRecordNotFound, expected: ErrRecordNotFound},
+		{name: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062}, expected: ErrKeyConflict},
+		{name: "mysql foreign key", err: &mysql.MySQLError{Number: 1452}, expected: ErrFKConstraint},
+		{name: "postgres unique violation", err: &pgconn.PgError{Code: "23505"}, expected: ErrKeyConflict},
+		{name: "postgres foreign key violation", err: &pgconn.PgError{Code: "23503"}, expected: ErrFKConstraint},
+		{name: "sqlite unique", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, expected: ErrKeyConflict},
+		{name: "sqlite primary key", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}, expected: ErrKeyConflict},
+		{name: "sqlite foreign key", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}, expected: ErrFKConstraint},
+		{name: "wrapped error", err: fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "23505"}), expected: ErrKeyConflict},
+		{name: "other error", err: otherErr, expected: otherErr},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, WrapError(tc.err))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"sync"
+	"time"
+)
+
+// Event types published by handlers.
+const (
+	TypeUserFollowed     = "user.followed"
+	TypeArticleCreated   = "article.created"
+	TypeArticleUpdated   = "article.updated"
+	TypeArticlePublished = "article.published"
+	TypeArticleFavorited = "article.favorited"
+	TypeCommentCreated   = "comment.created"
+)
+
+// Event represents something happened by an actor to a target user.
+// ArticleID and CommentID are zero if the event is not related to them.
+// Payload is a JSON serializable resource of the event such as a created article.
+type Event struct {
+	Type      string
+	ActorID   uint
+	UserID    uint
+	ArticleID uint
+	CommentID uint
+	Payload   interface{}
+	CreatedAt time.Time
+}
+
+// Handler handles a published event.
+type Handler func(ctx context.Context, e *Event) error
+
+// Bus is an in-process event bus which delivers published events to all subscribers.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus returns a new Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers given handler to receive published events.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish delivers given event to subscribers synchronously in subscribed order.
+// Errors from subscribers are logged and not returned, so publishers are not affected by them.
+// Publishing to a nil Bus does nothing.
+func (b *Bus) Publish(ctx context.Context, e *Event) {
+	if b == nil {
+		return
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			logging.FromContext(ctx).Errorw("EventBus failed to handle an event", "type", e.Type, "actorID", e.ActorID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	var (
+		bus    = NewBus()
+		called []string
+	)
+	bus.Subscribe(func(ctx context.Context, e *Event) error {
+		called = append(called, "first:"+e.Type)
+		return errors.New("force error")
+	})
+	bus.Subscribe(func(ctx context.Context, e *Event) error {
+		called = append(called, "second:"+e.Type)
+		return nil
+	})
+	e := &Event{Type: TypeUserFollowed, ActorID: 1, UserID: 2}
+
+	// when
+	bus.Publish(context.TODO(), e)
+
+	// then
+	assert.Equal(t, []string{"first:" + TypeUserFollowed, "second:" + TypeUserFollowed}, called)
+	assert.False(t, e.CreatedAt.IsZero())
+}
+
+func TestPublishNilBus(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() {
+		bus.Publish(context.TODO(), &Event{Type: TypeUserFollowed})
+	})
+}
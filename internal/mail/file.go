@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// NewFileMailer creates a new Mailer which writes emails from given address to files in dir.
+// Each email is written to "{unix nano}-{to}.eml" file.
+func NewFileMailer(from, dir string) Mailer {
+	return &fileMailer{
+		from: from,
+		dir:  dir,
+	}
+}
+
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func (fm *fileMailer) Send(_ context.Context, m *Message) error {
+	if err := os.MkdirAll(fm.dir, 0755); err != nil {
+		return err
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%d-%s.eml", now.UnixNano(), unsafeFileChars.ReplaceAllString(m.To, "_"))
+	return ioutil.WriteFile(filepath.Join(fm.dir, name), format(fm.from, m, now), 0644)
+}
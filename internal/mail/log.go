@@ -0,0 +1,18 @@
+package mail
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+)
+
+// NewLogMailer creates a new Mailer which writes emails to the logger.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+type logMailer struct{}
+
+func (lm *logMailer) Send(ctx context.Context, m *Message) error {
+	logging.FromContext(ctx).Infow("Mail", "to", m.To, "subject", m.Subject, "body", m.Body)
+	return nil
+}
@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"strings"
+	"time"
+)
+
+const (
+	TypeSMTP = "smtp"
+	TypeFile = "file"
+	TypeLog  = "log"
+)
+
+// Message represents a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to users.
+type Mailer interface {
+	// Send sends a given message m.
+	Send(ctx context.Context, m *Message) error
+}
+
+// NewMailer creates a new Mailer from mail.type in given config.
+func NewMailer(conf *config.Config) (Mailer, error) {
+	mc := conf.MailConfig
+	switch mc.Type {
+	case TypeSMTP:
+		if mc.SMTP.Host == "" {
+			return nil, fmt.Errorf("mail.smtp.host is required for %s mailer", mc.Type)
+		}
+		return NewSMTPMailer(mc.From, mc.SMTP), nil
+	case TypeFile:
+		if mc.File.Dir == "" {
+			return nil, fmt.Errorf("mail.file.dir is required for %s mailer", mc.Type)
+		}
+		return NewFileMailer(mc.From, mc.File.Dir), nil
+	case TypeLog:
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail type: %s", mc.Type)
+	}
+}
+
+// format returns a RFC 5322 formatted message m sent from given address at now.
+// Line breaks in headers are removed to prevent header injection.
+func format(from string, m *Message, now time.Time) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(m.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return b.Bytes()
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"io/ioutil"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewMailer(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+
+	// log
+	conf.MailConfig.Type = TypeLog
+	m, err := NewMailer(conf)
+	assert.NoError(t, err)
+	assert.IsType(t, &logMailer{}, m)
+
+	// smtp
+	conf.MailConfig.Type = TypeSMTP
+	m, err = NewMailer(conf)
+	assert.NoError(t, err)
+	assert.IsType(t, &smtpMailer{}, m)
+
+	// smtp without host
+	conf.MailConfig.SMTP.Host = ""
+	_, err = NewMailer(conf)
+	assert.Error(t, err)
+
+	// file without dir
+	conf.MailConfig.Type = TypeFile
+	conf.MailConfig.File.Dir = ""
+	_, err = NewMailer(conf)
+	assert.Error(t, err)
+
+	// unsupported
+	conf.MailConfig.Type = "unknown"
+	_, err = NewMailer(conf)
+	assert.Error(t, err)
+}
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := &Message{
+		To:      "user@gmail.com",
+		Subject: "Hello\r\nBcc: other@gmail.com",
+		Body:    "line1\nline2",
+	}
+
+	data := string(format("noreply@realworld.io", m, now))
+
+	assert.Contains(t, data, "From: noreply@realworld.io\r\n")
+	assert.Contains(t, data, "To: user@gmail.com\r\n")
+	assert.Contains(t, data, "Subject: HelloBcc: other@gmail.com\r\n")
+	assert.Contains(t, data, "Date: Sat, 02 Jan 2021 03:04:05 +0000\r\n")
+	assert.True(t, strings.HasSuffix(data, "\r\n\r\nline1\r\nline2"))
+}
+
+func TestSMTPMailer(t *testing.T) {
+	var (
+		addr        string
+		from        string
+		to          []string
+		msg         []byte
+		hasDeadline bool
+	)
+	m := NewSMTPMailer("noreply@realworld.io", config.SMTPMailConfig{
+		Host:     "localhost",
+		Port:     2525,
+		Username: "user",
+		Password: "password",
+		Timeout:  time.Second,
+	}).(*smtpMailer)
+	m.sendMail = func(ctx context.Context, a string, _ smtp.Auth, f string, t []string, data []byte) error {
+		_, hasDeadline = ctx.Deadline()
+		addr, from, to, msg = a, f, t, data
+		return nil
+	}
+
+	err := m.Send(context.TODO(), &Message{To: "user@gmail.com", Subject: "subject", Body: "body"})
+
+	assert.NoError(t, err)
+	assert.True(t, hasDeadline)
+	assert.NotNil(t, m.auth)
+	assert.Equal(t, "localhost:2525", addr)
+	assert.Equal(t, "noreply@realworld.io", from)
+	assert.Equal(t, []string{"user@gmail.com"}, to)
+	assert.Contains(t, string(msg), "Subject: subject\r\n")
+}
+
+func TestSMTPMailerTimeout(t *testing.T) {
+	// a server accepting connections without responding
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	addr := l.Addr().(*net.TCPAddr)
+	m := NewSMTPMailer("noreply@realworld.io", config.SMTPMailConfig{
+		Host:    "127.0.0.1",
+		Port:    addr.Port,
+		Timeout: 100 * time.Millisecond,
+	})
+
+	start := time.Now()
+	err = m.Send(context.TODO(), &Message{To: "user@gmail.com", Subject: "subject", Body: "body"})
+
+	assert.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestFileMailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mails")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	m := NewFileMailer("noreply@realworld.io", filepath.Join(dir, "sub"))
+
+	err = m.Send(context.TODO(), &Message{To: "user/../@gmail.com", Subject: "subject", Body: "body"})
+
+	assert.NoError(t, err)
+	files, err := filepath.Glob(filepath.Join(dir, "sub", "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0], "-user_.._@gmail.com.eml"))
+	data, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: subject\r\n")
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// NewSMTPMailer creates a new Mailer which sends emails from given address through a SMTP server.
+func NewSMTPMailer(from string, conf config.SMTPMailConfig) Mailer {
+	var auth smtp.Auth
+	if conf.Username != "" {
+		auth = smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
+	}
+	return &smtpMailer{
+		from:     from,
+		addr:     fmt.Sprintf("%s:%d", conf.Host, conf.Port),
+		auth:     auth,
+		timeout:  conf.Timeout,
+		sendMail: sendMail,
+	}
+}
+
+type smtpMailer struct {
+	from     string
+	addr     string
+	auth     smtp.Auth
+	timeout  time.Duration
+	sendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Send sends given message m. Sending is aborted when the timeout has elapsed or the deadline of given ctx is exceeded.
+func (sm *smtpMailer) Send(ctx context.Context, m *Message) error {
+	if sm.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sm.timeout)
+		defer cancel()
+	}
+	return sm.sendMail(ctx, sm.addr, sm.auth, sm.from, []string{m.To}, format(sm.from, m, time.Now()))
+}
+
+// sendMail is the same as smtp.SendMail except that dialing and all reads and writes are bounded by
+// the deadline of given ctx, so a slow SMTP server cannot block callers forever.
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification/model"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockery --name NotificationDB --filename notification_mock.go
+type NotificationDB interface {
+	// Save saves a given notification n.
+	Save(ctx context.Context, n *model.Notification) error
+
+	// FindNotifications returns notifications of given user ordered by created time in descending order.
+	// Only unread notifications will be returned if unreadOnly is true.
+	// database.ErrRecordNotFound will be returned if user is nil.
+	FindNotifications(ctx context.Context, user *userModel.User, unreadOnly bool, offset, limit int) (*model.Notifications, error)
+
+	// CountUnread returns the number of unread notifications of given user.
+	CountUnread(ctx context.Context, user *userModel.User) (int64, error)
+
+	// MarkAsRead marks unread notifications of given user matched by ids as read at now.
+	// All unread notifications will be marked if ids is empty.
+	// The number of marked notifications will be returned.
+	MarkAsRead(ctx context.Context, user *userModel.User, ids []uint, now time.Time) (int64, error)
+}
+
+// NewNotificationDB creates a new NotificationDB with given gorm.DB
+func NewNotificationDB(_ *config.Config, db *gorm.DB) NotificationDB {
+	return &notificationDB{
+		db: db,
+	}
+}
+
+type notificationDB struct {
+	db *gorm.DB
+}
+
+func (ndb *notificationDB) Save(ctx context.Context, n *model.Notification) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("NotificationDB_Save try to save a notification", "userID", n.UserID, "actorID", n.ActorID, "type", n.Type)
+
+	if err := ndb.db.WithContext(ctx).Omit("Actor", "Article").Create(n).Error; err != nil {
+		logger.Errorw("NotificationDB_Save failed to save a notification", "userID", n.UserID, "actorID", n.ActorID, "type", n.Type, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (ndb *notificationDB) FindNotifications(ctx context.Context, user *userModel.User, unreadOnly bool, offset, limit int) (*model.Notifications, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("NotificationDB_FindNotifications no user")
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("NotificationDB_FindNotifications try to find notifications", "userID", user.ID, "unreadOnly", unreadOnly, "offset", offset, "limit", limit)
+
+	db := ndb.db.WithContext(ctx)
+	query := func() *gorm.DB {
+		q := db.Model(new(model.Notification)).Where("notifications.user_id = ?", user.ID)
+		if unreadOnly {
+			q = q.Where("notifications.read_at IS NULL")
+		}
+		return q
+	}
+
+	// find total count of notifications.
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		logger.Errorw("NotificationDB_FindNotifications failed to fetch total count", "userID", user.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	unread := total
+	if !unreadOnly {
+		var err error
+		if unread, err = countUnread(db, user.ID); err != nil {
+			logger.Errorw("NotificationDB_FindNotifications failed to fetch unread count", "userID", user.ID, "err", err)
+			return nil, database.WrapError(err)
+		}
+	}
+
+	notifications := make([]*model.Notification, 0)
+	if limit > 0 && total > int64(offset) {
+		if err := query().
+			Joins("Actor").
+			Preload("Article").
+			Order("notifications.created_at DESC, notifications.notification_id DESC").
+			Offset(offset).
+			Limit(limit).
+			Find(&notifications).Error; err != nil {
+			logger.Errorw("NotificationDB_FindNotifications failed to find notifications", "userID", user.ID, "offset", offset, "limit", limit, "err", err)
+			return nil, database.WrapError(err)
+		}
+	}
+	return &model.Notifications{
+		Notifications:      notifications,
+		NotificationsCount: total,
+		UnreadCount:        unread,
+	}, nil
+}
+
+func (ndb *notificationDB) CountUnread(ctx context.Context, user *userModel.User) (int64, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("NotificationDB_CountUnread no user")
+		return 0, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("NotificationDB_CountUnread try to count unread notifications", "userID", user.ID)
+
+	count, err := countUnread(ndb.db.WithContext(ctx), user.ID)
+	if err != nil {
+		logger.Errorw("NotificationDB_CountUnread failed to count unread notifications", "userID", user.ID, "err", err)
+		return 0, database.WrapError(err)
+	}
+	return count, nil
+}
+
+func (ndb *notificationDB) MarkAsRead(ctx context.Context, user *userModel.User, ids []uint, now time.Time) (int64, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("NotificationDB_MarkAsRead no user")
+		return 0, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("NotificationDB_MarkAsRead try to mark notifications as read", "userID", user.ID, "ids", ids)
+
+	db := ndb.db.WithContext(ctx).Model(new(model.Notification)).
+		Where("user_id = ? AND read_at IS NULL", user.ID)
+	if len(ids) != 0 {
+		db = db.Where("notification_id IN (?)", ids)
+	}
+	result := db.Update("read_at", now)
+	if result.Error != nil {
+		logger.Errorw("NotificationDB_MarkAsRead failed to mark notifications as read", "userID", user.ID, "err", result.Error)
+		return 0, database.WrapError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// countUnread returns the number of unread notifications of given user id.
+func countUnread(db *gorm.DB, userID uint) (int64, error) {
+	var count int64
+	err := db.Model(new(model.Notification)).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	articleModel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification/model"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type Suite struct {
+	suite.Suite
+	db         NotificationDB
+	originDB   *gorm.DB
+	dbTeardown database.CloseFunc
+	u1, u2     *userModel.User
+	article    *articleModel.Article
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	cfg, _ := config.Load("")
+	logging.SetConfig(&logging.Config{
+		Encoding:    "console",
+		Level:       zapcore.FatalLevel,
+		Development: false,
+	})
+	s.originDB, s.dbTeardown = database.NewTestDatabase(s.T(), true)
+	s.db = NewNotificationDB(cfg, s.originDB)
+}
+
+func (s *Suite) TearDownSuite() {
+	s.dbTeardown()
+}
+
+func (s *Suite) SetupTest() {
+	err := database.DeleteRecordAll(s.T(), s.originDB, []string{
+		model.TableNameNotification, "notification_id > 0",
+		articleModel.TableNameArticle, "article_id > 0",
+		userModel.TableNameUser, "user_id > 0",
+	})
+	s.NoError(err)
+
+	s.u1 = &userModel.User{Email: "user1@email.com", Name: "user1", Password: "user1password"}
+	s.u2 = &userModel.User{Email: "user2@email.com", Name: "user2", Password: "user2password"}
+	s.NoError(s.originDB.Create([]*userModel.User{s.u1, s.u2}).Error)
+	s.article = &articleModel.Article{Title: "article1", Description: "description", Body: "body", Author: *s.u1}
+	s.NoError(s.originDB.Create(s.article).Error)
+}
+
+func (s *Suite) TestSave() {
+	n := &model.Notification{
+		UserID:    s.u1.ID,
+		ActorID:   s.u2.ID,
+		Type:      model.NotificationTypeFavorite,
+		ArticleID: &s.article.ID,
+	}
+
+	// when
+	err := s.db.Save(context.TODO(), n)
+
+	// then
+	s.NoError(err)
+	s.NotZero(n.ID)
+	var find model.Notification
+	s.NoError(s.originDB.First(&find, "notification_id = ?", n.ID).Error)
+	s.Equal(s.u1.ID, find.UserID)
+	s.Equal(s.u2.ID, find.ActorID)
+	s.Equal(model.NotificationTypeFavorite, find.Type)
+	s.Equal(s.article.ID, *find.ArticleID)
+	s.Nil(find.CommentID)
+	s.False(find.IsRead())
+}
+
+func (s *Suite) TestFindNotifications() {
+	now := time.Now()
+	notifications := []*model.Notification{
+		{UserID: s.u1.ID, ActorID: s.u2.ID, Type: model.NotificationTypeFollow, CreatedAt: now.Add(-3 * time.Minute)},
+		{UserID: s.u1.ID, ActorID: s.u2.ID, Type: model.NotificationTypeFavorite, ArticleID: &s.article.ID, CreatedAt: now.Add(-2 * time.Minute), ReadAt: &now},
+		{UserID: s.u1.ID, ActorID: s.u2.ID, Type: model.NotificationTypeComment, ArticleID: &s.article.ID, CreatedAt: now.Add(-time.Minute)},
+		{UserID: s.u2.ID, ActorID: s.u1.ID, Type: model.NotificationTypeFollow, CreatedAt: now},
+	}
+	for _, n := range notifications {
+		s.NoError(s.db.Save(context.TODO(), n))
+	}
+
+	// when
+	all, err := s.db.FindNotifications(context.TODO(), s.u1, false, 0, 2)
+	// then
+	s.NoError(err)
+	s.EqualValues(3, all.NotificationsCount)
+	s.EqualValues(2, all.UnreadCount)
+	s.Len(all.Notifications, 2)
+	s.Equal(notifications[2].ID, all.Notifications[0].ID)
+	s.Equal(s.u2.Name, all.Notifications[0].Actor.Name)
+	s.Equal(s.article.Slug, all.Notifications[0].Article.Slug)
+	s.Equal(notifications[1].ID, all.Notifications[1].ID)
+	s.True(all.Notifications[1].IsRead())
+
+	// when
+	unread, err := s.db.FindNotifications(context.TODO(), s.u1, true, 0, 10)
+	// then
+	s.NoError(err)
+	s.EqualValues(2, unread.NotificationsCount)
+	s.EqualValues(2, unread.UnreadCount)
+	s.Len(unread.Notifications, 2)
+	s.Equal(notifications[2].ID, unread.Notifications[0].ID)
+	s.Equal(notifications[0].ID, unread.Notifications[1].ID)
+	s.Nil(unread.Notifications[1].Article)
+
+	// deleted articles are not returned
+	s.NoError(s.originDB.Delete(s.article).Error)
+	all, err = s.db.FindNotifications(context.TODO(), s.u1, false, 0, 1)
+	s.NoError(err)
+	s.Nil(all.Notifications[0].Article)
+}
+
+func (s *Suite) TestMarkAsRead() {
+	notifications := []*model.Notification{
+		{UserID: s.u1.ID, ActorID: s.u2.ID, Type: model.NotificationTypeFollow},
+		{UserID: s.u1.ID, ActorID: s.u2.ID, Type: model.NotificationTypeFavorite, ArticleID: &s.article.ID},
+		{UserID: s.u2.ID, ActorID: s.u1.ID, Type: model.NotificationTypeFollow},
+	}
+	for _, n := range notifications {
+		s.NoError(s.db.Save(context.TODO(), n))
+	}
+
+	// when mark given ids including other's notification
+	marked, err := s.db.MarkAsRead(context.TODO(), s.u1, []uint{notifications[0].ID, notifications[2].ID}, time.Now())
+	// then
+	s.NoError(err)
+	s.EqualValues(1, marked)
+	count, err := s.db.CountUnread(context.TODO(), s.u1)
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	// when mark all
+	marked, err = s.db.MarkAsRead(context.TODO(), s.u1, nil, time.Now())
+	// then
+	s.NoError(err)
+	s.EqualValues(1, marked)
+	count, err = s.db.CountUnread(context.TODO(), s.u1)
+	s.NoError(err)
+	s.EqualValues(0, count)
+	count, err = s.db.CountUnread(context.TODO(), s.u2)
+	s.NoError(err)
+	s.EqualValues(1, count)
+}
+
+func (s *Suite) TestNoUser() {
+	_, err := s.db.FindNotifications(context.TODO(), nil, false, 0, 10)
+	s.Equal(database.ErrRecordNotFound, err)
+	_, err = s.db.CountUnread(context.TODO(), nil)
+	s.Equal(database.ErrRecordNotFound, err)
+	_, err = s.db.MarkAsRead(context.TODO(), nil, nil, time.Now())
+	s.Equal(database.ErrRecordNotFound, err)
+}
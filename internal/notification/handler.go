@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	notificationDB "github.com/zacscoding/echo-gorm-realworld-app/internal/notification/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"time"
+)
+
+type Handler struct {
+	cfg            *config.Config
+	notificationDB notificationDB.NotificationDB
+	now            func() time.Time
+}
+
+// NewHandler returns a new Handle from given serverenv.ServerEnv and config.Config.
+func NewHandler(env *serverenv.ServerEnv, conf *config.Config) (*Handler, error) {
+	return &Handler{
+		cfg:            conf,
+		notificationDB: env.GetNotificationDB(),
+		now:            time.Now,
+	}, nil
+}
+
+// Route configures route given "/api" echo.Group to "/api/user/notifications/**" paths.
+func (h *Handler) Route(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	notificationGroup := e.Group("/user/notifications")
+	notificationGroup.Use(authMiddleware)
+	notificationGroup.GET("", h.handleGetNotifications)
+	notificationGroup.POST("/read", h.handleReadNotifications)
+}
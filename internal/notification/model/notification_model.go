@@ -0,0 +1,50 @@
+package model
+
+import (
+	articleModel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"time"
+)
+
+const (
+	TableNameNotification = "notifications"
+)
+
+// Notification types.
+const (
+	NotificationTypeFollow   = "follow"
+	NotificationTypeFavorite = "favorite"
+	NotificationTypeComment  = "comment"
+)
+
+// Notifications represents notification list of a user with total size and the number of unread notifications.
+type Notifications struct {
+	Notifications      []*Notification
+	NotificationsCount int64
+	UnreadCount        int64
+}
+
+// Notification represents database model for notifications.
+// UserID is the recipient and ActorID is the user who caused this notification.
+// Article is nil if the article is deleted or the notification is not related to articles.
+type Notification struct {
+	ID        uint                  `gorm:"column:notification_id"`
+	UserID    uint                  `gorm:"column:user_id"`
+	ActorID   uint                  `gorm:"column:actor_id"`
+	Actor     userModel.User        `gorm:"foreignKey:ActorID"`
+	Type      string                `gorm:"column:type"`
+	ArticleID *uint                 `gorm:"column:article_id"`
+	Article   *articleModel.Article `gorm:"foreignKey:ArticleID"`
+	CommentID *uint                 `gorm:"column:comment_id"`
+	ReadAt    *time.Time            `gorm:"column:read_at"`
+	CreatedAt time.Time             `gorm:"column:created_at"`
+}
+
+func (n *Notification) TableName() string {
+	return TableNameNotification
+}
+
+// IsRead returns true if this notification has been read, otherwise false.
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
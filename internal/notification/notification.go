@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"github.com/labstack/echo/v4"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+)
+
+// handleGetNotifications handles "GET /api/user/notifications?limit=&offset=&unread=" to get notifications of current user.
+func (h *Handler) handleGetNotifications(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		query       = NotificationQuery{}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("NotificationHandler_handleGetNotifications failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query notifications
+	notifications, err := h.notificationDB.FindNotifications(ctx, currentUser, query.Unread, query.Offset, query.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToNotificationsResponse(notifications))
+}
+
+// handleReadNotifications handles "POST /api/user/notifications/read" to mark notifications of current user as read.
+// All unread notifications are marked if no ids are given.
+func (h *Handler) handleReadNotifications(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		req         = &ReadNotificationsRequest{}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("NotificationHandler_handleReadNotifications failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Mark as read
+	if _, err := h.notificationDB.MarkAsRead(ctx, currentUser, req.IDs, h.now()); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Query unread count
+	unread, err := h.notificationDB.CountUnread(ctx, currentUser)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, &types.UnreadNotificationsResponse{UnreadCount: unread})
+}
+
+func (h *Handler) currentUser(c echo.Context) *userModel.User {
+	uid := authutils.CurrentUser(c)
+	if uid == 0 {
+		return nil
+	}
+	return &userModel.User{
+		ID: uid,
+	}
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	notificationDB "github.com/zacscoding/echo-gorm-realworld-app/internal/notification/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+)
+
+// Notifier saves notifications to target users of published events.
+type Notifier struct {
+	notificationDB notificationDB.NotificationDB
+}
+
+// NewNotifier returns a new Notifier from given serverenv.ServerEnv.
+func NewNotifier(env *serverenv.ServerEnv) *Notifier {
+	return &Notifier{
+		notificationDB: env.GetNotificationDB(),
+	}
+}
+
+// Handle saves a notification from given event e.
+// Unknown events and events caused by the target user itself are ignored.
+func (n *Notifier) Handle(ctx context.Context, e *event.Event) error {
+	var (
+		articleID    = e.ArticleID
+		commentID    = e.CommentID
+		notification = model.Notification{
+			UserID:    e.UserID,
+			ActorID:   e.ActorID,
+			CreatedAt: e.CreatedAt,
+		}
+	)
+	switch e.Type {
+	case event.TypeUserFollowed:
+		notification.Type = model.NotificationTypeFollow
+	case event.TypeArticleFavorited:
+		notification.Type = model.NotificationTypeFavorite
+		notification.ArticleID = &articleID
+	case event.TypeCommentCreated:
+		notification.Type = model.NotificationTypeComment
+		notification.ArticleID = &articleID
+		notification.CommentID = &commentID
+	default:
+		return nil
+	}
+	if e.UserID == 0 || e.UserID == e.ActorID {
+		return nil
+	}
+	return n.notificationDB.Save(ctx, &notification)
+}
@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification/model"
+	"testing"
+	"time"
+)
+
+func TestNotifierHandle(t *testing.T) {
+	now := time.Now()
+	uintPtr := func(v uint) *uint { return &v }
+
+	cases := []struct {
+		name     string
+		event    *event.Event
+		expected *model.Notification
+	}{
+		{
+			name:  "follow",
+			event: &event.Event{Type: event.TypeUserFollowed, ActorID: 1, UserID: 2, CreatedAt: now},
+			expected: &model.Notification{
+				UserID:    2,
+				ActorID:   1,
+				Type:      model.NotificationTypeFollow,
+				CreatedAt: now,
+			},
+		}, {
+			name:  "favorite",
+			event: &event.Event{Type: event.TypeArticleFavorited, ActorID: 1, UserID: 2, ArticleID: 3, CreatedAt: now},
+			expected: &model.Notification{
+				UserID:    2,
+				ActorID:   1,
+				Type:      model.NotificationTypeFavorite,
+				ArticleID: uintPtr(3),
+				CreatedAt: now,
+			},
+		}, {
+			name:  "comment",
+			event: &event.Event{Type: event.TypeCommentCreated, ActorID: 1, UserID: 2, ArticleID: 3, CommentID: 4, CreatedAt: now},
+			expected: &model.Notification{
+				UserID:    2,
+				ActorID:   1,
+				Type:      model.NotificationTypeComment,
+				ArticleID: uintPtr(3),
+				CommentID: uintPtr(4),
+				CreatedAt: now,
+			},
+		}, {
+			name:  "self",
+			event: &event.Event{Type: event.TypeCommentCreated, ActorID: 1, UserID: 1, ArticleID: 3, CommentID: 4, CreatedAt: now},
+		}, {
+			name:  "unknown event",
+			event: &event.Event{Type: "unknown", ActorID: 1, UserID: 2, CreatedAt: now},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &mocks.NotificationDB{}
+			m.On("Save", mock.Anything, mock.Anything).Return(nil)
+			n := &Notifier{notificationDB: m}
+
+			// when
+			err := n.Handle(context.TODO(), tc.event)
+
+			// then
+			assert.NoError(t, err)
+			if tc.expected == nil {
+				m.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+				return
+			}
+			m.AssertCalled(t, "Save", mock.Anything, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+)
+
+// NotificationQuery represents query parameters of getting notifications.
+// Notifications are ordered by created time, so only offset based pagination is supported.
+type NotificationQuery struct {
+	Limit  int  `query:"limit"`
+	Offset int  `query:"offset"`
+	Unread bool `query:"unread"`
+}
+
+func (r *NotificationQuery) Bind(ctx echo.Context) error {
+	if err := httputils.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.Limit < 0 {
+		return httputils.NewStatusUnprocessableEntity("limit must greater than or equals to 0")
+	}
+	if r.Offset < 0 {
+		return httputils.NewStatusUnprocessableEntity("offset must greater than or equals to 0")
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return nil
+}
+
+// ReadNotificationsRequest represents request body data of marking notifications as read.
+// All unread notifications are marked if IDs is empty.
+type ReadNotificationsRequest struct {
+	IDs []uint `json:"ids" validate:"dive,min=1"`
+}
+
+func (r *ReadNotificationsRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
@@ -0,0 +1,63 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// clockSkew is an allowed clock difference between this server and providers.
+const clockSkew = time.Minute
+
+// Claims represents claims of an ID token.
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          Audience `json:"aud"`
+	ExpiresAt         int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// Valid returns an error if the token is expired or issued in the future.
+func (c *Claims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return errors.New("token is expired")
+	}
+	if c.IssuedAt != 0 && now.Add(clockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("token used before issued")
+	}
+	return nil
+}
+
+// Audience represents "aud" claim which is a string or an array of strings.
+type Audience []string
+
+// Contains returns true if this audience contains given value, otherwise false.
+func (a Audience) Contains(v string) bool {
+	for _, aud := range a {
+		if aud == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
@@ -0,0 +1,189 @@
+// Package oidctest provides a stub OpenID Connect provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// KeyID is a key id of ID tokens signed by Server.
+const KeyID = "oidctest-key"
+
+// Server is a stub OpenID Connect provider supporting discovery and the authorization code flow.
+// Users are authenticated without interaction as the claims set by SetUser.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	user  map[string]interface{}
+	codes map[string]*authorization
+}
+
+type authorization struct {
+	claims      map[string]interface{}
+	nonce       string
+	redirectURI string
+}
+
+// NewServer starts a new Server issuing ID tokens to given client.
+// The caller should call Close when finished.
+func NewServer(clientID, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		user:         map[string]interface{}{"sub": "subject"},
+		codes:        make(map[string]*authorization),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/authorize", s.handleAuthorize)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetUser sets claims of the user authenticated by next authorizations such as "sub" and "email".
+func (s *Server) SetUser(claims map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = claims
+}
+
+// SignIDToken returns an ID token signed by this server with given claims.
+// "iss", "aud", "iat" and "exp" claims are set if not exist.
+func (s *Server) SignIDToken(claims map[string]interface{}) string {
+	mc := jwt.MapClaims{
+		"iss": s.URL,
+		"aud": s.ClientID,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		mc[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, mc)
+	token.Header["kid"] = KeyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.ClientID || q.Get("response_type") != "code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = &authorization{
+		claims:      s.user,
+		nonce:       q.Get("nonce"),
+		redirectURI: redirect.String(),
+	}
+	s.mu.Unlock()
+
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	auth, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+	if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]interface{}{}
+	for k, v := range auth.claims {
+		claims[k] = v
+	}
+	if auth.nonce != "" {
+		claims["nonce"] = auth.nonce
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     s.SignIDToken(claims),
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,264 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidIDToken is returned if an ID token is not issued to this client by the provider.
+	ErrInvalidIDToken = errors.New("invalid id token")
+
+	defaultScopes = []string{"openid", "email", "profile"}
+)
+
+// Provider is a client of an OpenID Connect provider for the authorization code flow.
+// Endpoints and signing keys are discovered from the issuer when used first and cached.
+type Provider struct {
+	name   string
+	conf   config.OIDCProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]*rsa.PublicKey
+}
+
+// metadata represents a provider metadata of OpenID Connect Discovery.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProviders creates providers from oidc.providers in given config keyed by their names.
+func NewProviders(conf *config.Config) map[string]*Provider {
+	providers := make(map[string]*Provider, len(conf.OIDCConfig.Providers))
+	for name, pc := range conf.OIDCConfig.Providers {
+		providers[name] = NewProvider(name, pc, conf.OIDCConfig.Timeout)
+	}
+	return providers
+}
+
+// NewProvider creates a new Provider with given name and config.
+// Requests to the provider time out after timeout.
+func NewProvider(name string, conf config.OIDCProviderConfig, timeout time.Duration) *Provider {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = defaultScopes
+	}
+	return &Provider{
+		name:   name,
+		conf:   conf,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns a name of this provider.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns an url of the provider to redirect users for authentication.
+// The provider redirects users back to the redirect url with given state and the ID token will contain given nonce.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.conf.ClientID)
+	q.Set("redirect_uri", p.conf.RedirectURL)
+	q.Set("scope", strings.Join(p.conf.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange exchanges given authorization code for an ID token and returns verified claims of the token.
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*Claims, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.conf.RedirectURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(p.conf.ClientID), url.QueryEscape(p.conf.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("exchange code: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("exchange code: no id_token in response")
+	}
+	return p.Verify(ctx, token.IDToken, nonce)
+}
+
+// Verify verifies a signature and claims of given raw ID token and returns the claims.
+// ErrInvalidIDToken will be returned if the token is not issued to this client or nonce is mismatched.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	claims := new(Claims)
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	switch {
+	case claims.Issuer != p.conf.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer %s", ErrInvalidIDToken, claims.Issuer)
+	case !claims.Audience.Contains(p.conf.ClientID):
+		return nil, fmt.Errorf("%w: unexpected audience %v", ErrInvalidIDToken, claims.Audience)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// getMetadata returns the provider metadata discovered from the issuer.
+func (p *Provider) getMetadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	var md metadata
+	if err := p.doJSON(req, &md); err != nil {
+		return nil, fmt.Errorf("discover provider: %v", err)
+	}
+	if md.Issuer != p.conf.Issuer {
+		return nil, fmt.Errorf("discover provider: issuer mismatch. expected: %s, actual: %s", p.conf.Issuer, md.Issuer)
+	}
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// getKey returns a public key matched by given key id. Keys are fetched again if the key id is unknown
+// because providers rotate keys. The only key is returned if the key id is empty.
+func (p *Provider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	md, err := p.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.findKey(kid); ok {
+		return key, nil
+	}
+	keys, err := p.fetchKeys(ctx, md.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.findKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id: %s", kid)
+}
+
+func (p *Provider) findKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// fetchKeys fetches RSA signing keys from given JWK set url.
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.doJSON(req, &jwks); err != nil {
+		return nil, fmt.Errorf("fetch keys: %v", err)
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("fetch keys: invalid modulus of key %s: %v", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("fetch keys: invalid exponent of key %s: %v", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// doJSON sends given request and decodes a json response body to v.
+func (p *Provider) doJSON(req *http.Request, v interface{}) error {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status %d from %s: %s", res.StatusCode, req.URL, body)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,189 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/oidc/oidctest"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const redirectURL = "http://localhost:8080/api/auth/stub/callback"
+
+func TestNewProviders(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+	conf.OIDCConfig.Providers = map[string]config.OIDCProviderConfig{
+		"google": {Issuer: "https://accounts.google.com"},
+		"gitlab": {Issuer: "https://gitlab.com", Scopes: []string{"openid"}},
+	}
+
+	providers := NewProviders(conf)
+
+	assert.Len(t, providers, 2)
+	assert.Equal(t, "google", providers["google"].Name())
+	assert.Equal(t, defaultScopes, providers["google"].conf.Scopes)
+	assert.Equal(t, []string{"openid"}, providers["gitlab"].conf.Scopes)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	srv, p := newTestProvider()
+	defer srv.Close()
+
+	authURL, err := p.AuthCodeURL(context.TODO(), "state1", "nonce1")
+
+	assert.NoError(t, err)
+	u, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "client-id", q.Get("client_id"))
+	assert.Equal(t, redirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, "state1", q.Get("state"))
+	assert.Equal(t, "nonce1", q.Get("nonce"))
+}
+
+func TestAuthCodeURL_IssuerMismatch(t *testing.T) {
+	srv := oidctest.NewServer("client-id", "client-secret")
+	defer srv.Close()
+	p := NewProvider("stub", config.OIDCProviderConfig{Issuer: srv.URL + "/", ClientID: "client-id"}, time.Second)
+
+	_, err := p.AuthCodeURL(context.TODO(), "state", "nonce")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "issuer mismatch")
+}
+
+func TestExchange(t *testing.T) {
+	srv, p := newTestProvider()
+	defer srv.Close()
+	srv.SetUser(map[string]interface{}{
+		"sub":                "subject1",
+		"email":              "user@gmail.com",
+		"email_verified":     true,
+		"name":               "User",
+		"preferred_username": "user1",
+	})
+	code := authorize(t, srv, p, "nonce1")
+
+	claims, err := p.Exchange(context.TODO(), code, "nonce1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL, claims.Issuer)
+	assert.Equal(t, "subject1", claims.Subject)
+	assert.Equal(t, "user@gmail.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "User", claims.Name)
+	assert.Equal(t, "user1", claims.PreferredUsername)
+
+	// code can be used once
+	_, err = p.Exchange(context.TODO(), code, "nonce1")
+	assert.Error(t, err)
+}
+
+func TestExchange_Fail(t *testing.T) {
+	srv, p := newTestProvider()
+	defer srv.Close()
+
+	// nonce mismatch
+	code := authorize(t, srv, p, "nonce1")
+	_, err := p.Exchange(context.TODO(), code, "nonce2")
+	assert.True(t, errors.Is(err, ErrInvalidIDToken))
+
+	// invalid client secret
+	wrong := NewProvider("stub", config.OIDCProviderConfig{
+		Issuer:       srv.URL,
+		ClientID:     "client-id",
+		ClientSecret: "wrong",
+		RedirectURL:  redirectURL,
+	}, time.Second)
+	code = authorize(t, srv, p, "nonce1")
+	_, err = wrong.Exchange(context.TODO(), code, "nonce1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 401")
+}
+
+func TestVerify(t *testing.T) {
+	srv, p := newTestProvider()
+	defer srv.Close()
+
+	cases := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "nonce"},
+			valid:  true,
+		}, {
+			name:   "audience array",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "nonce", "aud": []string{"other", "client-id"}},
+			valid:  true,
+		}, {
+			name:   "other audience",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "nonce", "aud": "other"},
+		}, {
+			name:   "other issuer",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "nonce", "iss": "https://other.com"},
+		}, {
+			name:   "expired",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "nonce", "exp": time.Now().Add(-time.Hour).Unix()},
+		}, {
+			name:   "nonce mismatch",
+			claims: map[string]interface{}{"sub": "subject1", "nonce": "other"},
+		}, {
+			name:   "no subject",
+			claims: map[string]interface{}{"nonce": "nonce"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := p.Verify(context.TODO(), srv.SignIDToken(tc.claims), "nonce")
+			if !tc.valid {
+				assert.True(t, errors.Is(err, ErrInvalidIDToken), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "subject1", claims.Subject)
+		})
+	}
+
+	// tampered token
+	token := srv.SignIDToken(map[string]interface{}{"sub": "subject1", "nonce": "nonce"})
+	_, err := p.Verify(context.TODO(), token[:len(token)-4]+"AAAA", "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidIDToken))
+}
+
+func newTestProvider() (*oidctest.Server, *Provider) {
+	srv := oidctest.NewServer("client-id", "client-secret")
+	return srv, NewProvider("stub", config.OIDCProviderConfig{
+		Issuer:       srv.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  redirectURL,
+	}, time.Second)
+}
+
+// authorize follows the authorization url of given provider and returns an issued code.
+func authorize(t *testing.T, srv *oidctest.Server, p *Provider, nonce string) string {
+	authURL, err := p.AuthCodeURL(context.TODO(), "state", nonce)
+	assert.NoError(t, err)
+	cli := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := cli.Get(authURL)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	location, err := url.Parse(res.Header.Get("Location"))
+	assert.NoError(t, err)
+	return location.Query().Get("code")
+}
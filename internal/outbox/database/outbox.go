@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockery --name OutboxDB --filename outbox_mock.go
+type OutboxDB interface {
+	// FindPending returns at most limit pending events which next attempt time is before now in written order.
+	FindPending(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEvent, error)
+
+	// ClaimEvent increases attempts of given pending event and postpones the next attempt to until.
+	// false will be returned if the event is already claimed by others.
+	ClaimEvent(ctx context.Context, e *model.OutboxEvent, until time.Time) (bool, error)
+
+	// UpdateEvent updates a result of the last attempt of given event.
+	UpdateEvent(ctx context.Context, e *model.OutboxEvent) error
+
+	// DeletePublished deletes at most limit events published before given time.
+	// returns the number of deleted events.
+	DeletePublished(ctx context.Context, publishedBefore time.Time, limit int) (int64, error)
+}
+
+// Append saves given events with txDb which must be the transaction of the domain change,
+// so the events are written only if the domain change is committed.
+func Append(ctx context.Context, txDb *gorm.DB, events ...*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return txDb.WithContext(ctx).Create(&events).Error
+}
+
+// NewOutboxDB creates a new OutboxDB with given gorm.DB
+func NewOutboxDB(_ *config.Config, db *gorm.DB) OutboxDB {
+	return &outboxDB{
+		db: db,
+	}
+}
+
+type outboxDB struct {
+	db *gorm.DB
+}
+
+func (odb *outboxDB) FindPending(ctx context.Context, now time.Time, limit int) ([]*model.OutboxEvent, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("OutboxDB_FindPending try to find pending events", "now", now, "limit", limit)
+
+	var events []*model.OutboxEvent
+	if err := odb.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", model.StatusPending, now).
+		Order("outbox_id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		logger.Errorw("OutboxDB_FindPending failed to find pending events", "err", err)
+		return nil, database.WrapError(err)
+	}
+	return events, nil
+}
+
+func (odb *outboxDB) ClaimEvent(ctx context.Context, e *model.OutboxEvent, until time.Time) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("OutboxDB_ClaimEvent try to claim an event", "outboxID", e.ID, "attempts", e.Attempts)
+
+	// attempts is used as a version to prevent relaying an event concurrently.
+	result := odb.db.WithContext(ctx).Model(new(model.OutboxEvent)).
+		Where("outbox_id = ? AND status = ? AND attempts = ?", e.ID, model.StatusPending, e.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        e.Attempts + 1,
+			"next_attempt_at": until,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		logger.Errorw("OutboxDB_ClaimEvent failed to claim an event", "outboxID", e.ID, "err", result.Error)
+		return false, database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	e.Attempts++
+	e.NextAttemptAt = until
+	return true, nil
+}
+
+func (odb *outboxDB) UpdateEvent(ctx context.Context, e *model.OutboxEvent) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("OutboxDB_UpdateEvent try to update an event", "outboxID", e.ID, "status", e.Status)
+
+	if err := odb.db.WithContext(ctx).Model(e).
+		Select("Status", "NextAttemptAt", "LastError", "PublishedAt", "UpdatedAt").
+		Updates(e).Error; err != nil {
+		logger.Errorw("OutboxDB_UpdateEvent failed to update an event", "outboxID", e.ID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (odb *outboxDB) DeletePublished(ctx context.Context, publishedBefore time.Time, limit int) (int64, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("OutboxDB_DeletePublished try to delete published events", "publishedBefore", publishedBefore, "limit", limit)
+
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	// ids are found first because postgres and sqlite don't support limit in delete statements.
+	var ids []uint
+	if err := odb.db.WithContext(ctx).Model(new(model.OutboxEvent)).
+		Where("status = ? AND published_at < ?", model.StatusPublished, publishedBefore).
+		Order("outbox_id ASC").
+		Limit(limit).
+		Pluck("outbox_id", &ids).Error; err != nil {
+		logger.Errorw("OutboxDB_DeletePublished failed to find published events", "err", err)
+		return 0, database.WrapError(err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := odb.db.WithContext(ctx).Where("outbox_id IN (?)", ids).Delete(new(model.OutboxEvent))
+	if result.Error != nil {
+		logger.Errorw("OutboxDB_DeletePublished failed to delete published events", "err", result.Error)
+		return 0, database.WrapError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
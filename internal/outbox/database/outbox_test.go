@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type Suite struct {
+	suite.Suite
+	db         OutboxDB
+	originDB   *gorm.DB
+	dbTeardown database.CloseFunc
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	cfg, _ := config.Load("")
+	logging.SetConfig(&logging.Config{
+		Encoding:    "console",
+		Level:       zapcore.FatalLevel,
+		Development: false,
+	})
+	s.originDB, s.dbTeardown = database.NewTestDatabase(s.T(), true)
+	s.db = NewOutboxDB(cfg, s.originDB)
+}
+
+func (s *Suite) TearDownSuite() {
+	s.dbTeardown()
+}
+
+func (s *Suite) SetupTest() {
+	err := database.DeleteRecordAll(s.T(), s.originDB, []string{
+		model.TableNameOutboxEvent, "outbox_id > 0",
+	})
+	s.NoError(err)
+}
+
+func (s *Suite) TestAppend() {
+	e := newEvent(s.T(), time.Now())
+
+	// when rolled back
+	err := database.RunInTx(context.TODO(), s.originDB, &sql.TxOptions{}, func(txDb *gorm.DB) error {
+		if err := Append(context.TODO(), txDb, e); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	// then
+	s.Error(err)
+	var count int64
+	s.NoError(s.originDB.Model(new(model.OutboxEvent)).Count(&count).Error)
+	s.EqualValues(0, count)
+
+	// when committed
+	e = newEvent(s.T(), time.Now())
+	err = database.RunInTx(context.TODO(), s.originDB, &sql.TxOptions{}, func(txDb *gorm.DB) error {
+		return Append(context.TODO(), txDb, e)
+	})
+	// then
+	s.NoError(err)
+	var find model.OutboxEvent
+	s.NoError(s.originDB.First(&find, "outbox_id = ?", e.ID).Error)
+	s.Equal(e.IdempotencyKey, find.IdempotencyKey)
+	s.Equal(e.Payload, find.Payload)
+	s.Equal(model.StatusPending, find.Status)
+}
+
+func (s *Suite) TestFindPending() {
+	now := time.Now()
+	events := []*model.OutboxEvent{
+		newEvent(s.T(), now.Add(-time.Minute)),
+		newEvent(s.T(), now.Add(time.Minute)),
+		newEvent(s.T(), now),
+		newEvent(s.T(), now.Add(-time.Minute)),
+	}
+	events[3].Publish(now)
+	s.NoError(Append(context.TODO(), s.originDB, events...))
+
+	// when
+	find, err := s.db.FindPending(context.TODO(), now, 10)
+
+	// then
+	s.NoError(err)
+	s.Len(find, 2)
+	s.Equal(events[0].ID, find[0].ID)
+	s.Equal(events[2].ID, find[1].ID)
+}
+
+func (s *Suite) TestClaimAndUpdateEvent() {
+	now := time.Now()
+	e := newEvent(s.T(), now)
+	s.NoError(Append(context.TODO(), s.originDB, e))
+	stale := *e
+
+	// when
+	claimed, err := s.db.ClaimEvent(context.TODO(), e, now.Add(time.Minute))
+	// then
+	s.NoError(err)
+	s.True(claimed)
+	s.Equal(1, e.Attempts)
+
+	// when claim with stale attempts
+	claimed, err = s.db.ClaimEvent(context.TODO(), &stale, now.Add(time.Minute))
+	// then
+	s.NoError(err)
+	s.False(claimed)
+
+	// when
+	e.Publish(now)
+	err = s.db.UpdateEvent(context.TODO(), e)
+	// then
+	s.NoError(err)
+	var find model.OutboxEvent
+	s.NoError(s.originDB.First(&find, "outbox_id = ?", e.ID).Error)
+	s.Equal(model.StatusPublished, find.Status)
+	s.Equal(1, find.Attempts)
+	s.NotNil(find.PublishedAt)
+}
+
+func (s *Suite) TestDeletePublished() {
+	now := time.Now()
+	events := []*model.OutboxEvent{
+		newEvent(s.T(), now),
+		newEvent(s.T(), now),
+		newEvent(s.T(), now),
+		newEvent(s.T(), now),
+	}
+	events[0].Publish(now.Add(-2 * time.Hour))
+	events[1].Publish(now.Add(-2 * time.Hour))
+	events[2].Publish(now)
+	s.NoError(Append(context.TODO(), s.originDB, events...))
+
+	// when
+	deleted, err := s.db.DeletePublished(context.TODO(), now.Add(-time.Hour), 1)
+	// then
+	s.NoError(err)
+	s.EqualValues(1, deleted)
+
+	// when
+	deleted, err = s.db.DeletePublished(context.TODO(), now.Add(-time.Hour), 10)
+	// then
+	s.NoError(err)
+	s.EqualValues(1, deleted)
+	var count int64
+	s.NoError(s.originDB.Model(new(model.OutboxEvent)).Count(&count).Error)
+	s.EqualValues(2, count)
+}
+
+func newEvent(t *testing.T, next time.Time) *model.OutboxEvent {
+	e, err := model.NewOutboxEvent(model.AggregateTypeArticle, 1, "article.created", map[string]uint{"articleId": 1}, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+)
+
+// NewLogSink creates a new Sink which writes events to the logger.
+func NewLogSink() Sink {
+	return &logSink{}
+}
+
+type logSink struct{}
+
+func (ls *logSink) Name() string {
+	return SinkLog
+}
+
+func (ls *logSink) Publish(ctx context.Context, e *model.OutboxEvent) error {
+	logging.FromContext(ctx).Infow("Outbox event", "idempotencyKey", e.IdempotencyKey,
+		"aggregateType", e.AggregateType, "aggregateID", e.AggregateID, "eventType", e.EventType, "payload", e.Payload)
+	return nil
+}
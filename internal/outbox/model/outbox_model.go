@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+const TableNameOutboxEvent = "outbox"
+
+// Aggregate types of outbox events.
+const (
+	AggregateTypeArticle = "article"
+	AggregateTypeComment = "comment"
+)
+
+// Outbox event statuses. Pending events are relayed until published.
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+)
+
+// OutboxEvent represents database model for domain events in the transactional outbox.
+// IdempotencyKey is unique for each event and sent to sinks, so consumers can ignore redelivered events.
+// Attempts is increased when an event is claimed to relay and NextAttemptAt is the time to be relayed.
+type OutboxEvent struct {
+	ID             uint       `gorm:"column:outbox_id"`
+	IdempotencyKey string     `gorm:"column:idempotency_key"`
+	AggregateType  string     `gorm:"column:aggregate_type"`
+	AggregateID    uint       `gorm:"column:aggregate_id"`
+	EventType      string     `gorm:"column:event_type"`
+	Payload        string     `gorm:"column:payload"`
+	Status         string     `gorm:"column:status"`
+	Attempts       int        `gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	LastError      string     `gorm:"column:last_error"`
+	PublishedAt    *time.Time `gorm:"column:published_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+func (e *OutboxEvent) TableName() string {
+	return TableNameOutboxEvent
+}
+
+// NewOutboxEvent returns a new pending OutboxEvent with a random idempotency key
+// and the JSON encoded payload.
+func NewOutboxEvent(aggregateType string, aggregateID uint, eventType string, payload interface{}, now time.Time) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		IdempotencyKey: uuid.NewString(),
+		AggregateType:  aggregateType,
+		AggregateID:    aggregateID,
+		EventType:      eventType,
+		Payload:        string(data),
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// Publish marks this event as published at now.
+func (e *OutboxEvent) Publish(now time.Time) {
+	e.Status = StatusPublished
+	e.LastError = ""
+	e.PublishedAt = &now
+}
+
+// Fail records a failed attempt with given error. The event is retried after backoff doubled for each attempt
+// which is limited to maxBackoff if positive.
+func (e *OutboxEvent) Fail(now time.Time, err string, backoff, maxBackoff time.Duration) {
+	e.Status = StatusPending
+	e.LastError = err
+	for i := 1; i < e.Attempts && (maxBackoff <= 0 || backoff < maxBackoff); i++ {
+		backoff <<= 1
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	e.NextAttemptAt = now.Add(backoff)
+}
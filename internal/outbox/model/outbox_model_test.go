@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewOutboxEvent(t *testing.T) {
+	now := time.Now()
+
+	e1, err := NewOutboxEvent(AggregateTypeArticle, 1, "article.created", map[string]uint{"articleId": 1}, now)
+	assert.NoError(t, err)
+	e2, err := NewOutboxEvent(AggregateTypeArticle, 1, "article.created", map[string]uint{"articleId": 1}, now)
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, e1.IdempotencyKey)
+	assert.NotEqual(t, e1.IdempotencyKey, e2.IdempotencyKey)
+	assert.Equal(t, `{"articleId":1}`, e1.Payload)
+	assert.Equal(t, StatusPending, e1.Status)
+	assert.Equal(t, now, e1.NextAttemptAt)
+}
+
+func TestOutboxEventFail(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name       string
+		attempts   int
+		maxBackoff time.Duration
+		expected   time.Duration
+	}{
+		{name: "first attempt", attempts: 1, expected: time.Second},
+		{name: "third attempt", attempts: 3, expected: 4 * time.Second},
+		{name: "max backoff", attempts: 10, maxBackoff: 5 * time.Second, expected: 5 * time.Second},
+		{name: "no max backoff", attempts: 10, expected: 512 * time.Second},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := OutboxEvent{Status: StatusPending, Attempts: tc.attempts}
+
+			e.Fail(now, "error", time.Second, tc.maxBackoff)
+
+			assert.Equal(t, StatusPending, e.Status)
+			assert.Equal(t, "error", e.LastError)
+			assert.Equal(t, now.Add(tc.expected), e.NextAttemptAt)
+		})
+	}
+}
+
+func TestOutboxEventPublish(t *testing.T) {
+	now := time.Now()
+	e := OutboxEvent{Status: StatusPending, LastError: "error"}
+
+	e.Publish(now)
+
+	assert.Equal(t, StatusPublished, e.Status)
+	assert.Empty(t, e.LastError)
+	assert.Equal(t, now, *e.PublishedAt)
+}
@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+)
+
+// NewRedisSink creates a new Sink which adds events to the configured redis stream of given client.
+func NewRedisSink(cli redis.UniversalClient, conf config.OutboxRedisConfig) Sink {
+	return &redisSink{
+		cli:    cli,
+		stream: conf.Stream,
+		maxLen: conf.MaxLen,
+	}
+}
+
+type redisSink struct {
+	cli    redis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+func (rs *redisSink) Name() string {
+	return SinkRedis
+}
+
+func (rs *redisSink) Publish(ctx context.Context, e *model.OutboxEvent) error {
+	args := &redis.XAddArgs{
+		Stream: rs.stream,
+		Values: map[string]interface{}{
+			"idempotencyKey": e.IdempotencyKey,
+			"aggregateType":  e.AggregateType,
+			"aggregateId":    e.AggregateID,
+			"eventType":      e.EventType,
+			"payload":        e.Payload,
+		},
+	}
+	if rs.maxLen > 0 {
+		args.MaxLen = rs.maxLen
+		args.Approx = true
+	}
+	return rs.cli.XAdd(ctx, args).Err()
+}
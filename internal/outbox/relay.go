@@ -0,0 +1,175 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	outboxDB "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
+)
+
+// maxErrorLength is the max length of an error message recorded to an event.
+const maxErrorLength = 1024
+
+// claimTimeout is the time to relay a claimed event before others can claim it again.
+const claimTimeout = time.Minute
+
+// Relay publishes pending outbox events to sinks with at-least-once delivery.
+// An event is marked as published only after all sinks published it, so the event is published again to
+// all sinks if any of them fails. Events are relayed in written order but failed events don't block later events.
+type Relay struct {
+	conf     config.OutboxConfig
+	outboxDB outboxDB.OutboxDB
+	sinks    []Sink
+	now      func() time.Time
+}
+
+// NewRelay returns a new Relay from given serverenv.ServerEnv and config.Config.
+func NewRelay(env *serverenv.ServerEnv, conf *config.Config) (*Relay, error) {
+	sinks, err := NewSinks(conf, env.GetRedisClient())
+	if err != nil {
+		return nil, err
+	}
+	return &Relay{
+		conf:     conf.OutboxConfig,
+		outboxDB: env.GetOutboxDB(),
+		sinks:    sinks,
+		now:      time.Now,
+	}, nil
+}
+
+// Start relays pending events and deletes old published events every interval until given ctx is done.
+// Nothing will be relayed if the interval is not positive or no sinks.
+func (r *Relay) Start(ctx context.Context) {
+	logger := logging.DefaultLogger()
+	if r.conf.Interval <= 0 || len(r.sinks) == 0 {
+		logger.Info("OutboxRelay is disabled")
+		return
+	}
+	logger.Infow("Starting OutboxRelay", "interval", r.conf.Interval, "sinks", r.conf.Sinks)
+
+	ticker := time.NewTicker(r.conf.Interval)
+	defer ticker.Stop()
+	for {
+		if relayed, err := r.Relay(ctx); err != nil {
+			logger.Errorw("OutboxRelay failed to relay events", "relayed", relayed, "err", err)
+		} else if relayed != 0 {
+			logger.Infow("OutboxRelay relayed events", "relayed", relayed)
+		}
+		if deleted, err := r.Cleanup(ctx); err != nil {
+			logger.Errorw("OutboxRelay failed to delete published events", "deleted", deleted, "err", err)
+		} else if deleted != 0 {
+			logger.Infow("OutboxRelay deleted published events", "deleted", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping OutboxRelay")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes all pending events which next attempt time is before now in batches
+// and returns the number of relayed events regardless of results.
+func (r *Relay) Relay(ctx context.Context) (int64, error) {
+	var (
+		now   = r.now()
+		batch = r.batch()
+		total int64
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		events, err := r.outboxDB.FindPending(ctx, now, batch)
+		if err != nil {
+			return total, err
+		}
+		for _, e := range events {
+			relayed, err := r.relay(ctx, e)
+			if err != nil {
+				return total, err
+			}
+			if relayed {
+				total++
+			}
+		}
+		if len(events) < batch {
+			return total, nil
+		}
+	}
+}
+
+// Cleanup deletes all events published before the retention in batches and returns the number of deleted events.
+// Nothing will be deleted if the retention is not positive.
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	if r.conf.Retention <= 0 {
+		return 0, nil
+	}
+	var (
+		publishedBefore = r.now().Add(-r.conf.Retention)
+		batch           = r.batch()
+		total           int64
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		deleted, err := r.outboxDB.DeletePublished(ctx, publishedBefore, batch)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if deleted < int64(batch) {
+			return total, nil
+		}
+	}
+}
+
+// relay claims given event and publishes it to all sinks, then records the result.
+// false will be returned if the event is claimed by others.
+func (r *Relay) relay(ctx context.Context, e *model.OutboxEvent) (bool, error) {
+	claimed, err := r.outboxDB.ClaimEvent(ctx, e, r.now().Add(claimTimeout))
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	now := r.now()
+	if err := r.publish(ctx, e); err != nil {
+		msg := err.Error()
+		if len(msg) > maxErrorLength {
+			msg = msg[:maxErrorLength]
+		}
+		logging.FromContext(ctx).Warnw("OutboxRelay failed to publish an event", "outboxID", e.ID,
+			"attempts", e.Attempts, "err", msg)
+		e.Fail(now, msg, r.conf.Backoff, r.conf.MaxBackoff)
+	} else {
+		e.Publish(now)
+	}
+	e.UpdatedAt = now
+	return true, r.outboxDB.UpdateEvent(ctx, e)
+}
+
+func (r *Relay) publish(ctx context.Context, e *model.OutboxEvent) error {
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, e); err != nil {
+			return fmt.Errorf("%s sink: %v", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Relay) batch() int {
+	if r.conf.Batch <= 0 {
+		return 100
+	}
+	return r.conf.Batch
+}
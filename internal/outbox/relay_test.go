@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"testing"
+	"time"
+)
+
+// testSink records published events and fails if err is not nil.
+type testSink struct {
+	published []*model.OutboxEvent
+	err       error
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Publish(_ context.Context, e *model.OutboxEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.published = append(s.published, e)
+	return nil
+}
+
+func newTestRelay(dbMock *mocks.OutboxDB, now time.Time, sinks ...Sink) *Relay {
+	return &Relay{
+		conf: config.OutboxConfig{
+			Batch:      2,
+			Backoff:    time.Second,
+			MaxBackoff: time.Minute,
+			Retention:  time.Hour,
+		},
+		outboxDB: dbMock,
+		sinks:    sinks,
+		now: func() time.Time {
+			return now
+		},
+	}
+}
+
+func claim(args mock.Arguments) {
+	args.Get(1).(*model.OutboxEvent).Attempts++
+}
+
+func TestRelayRelay(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.OutboxDB{}
+		sink1  = &testSink{}
+		sink2  = &testSink{}
+		r      = newTestRelay(dbMock, now, sink1, sink2)
+		events = []*model.OutboxEvent{newEvent(1), newEvent(2), newEvent(3)}
+	)
+	dbMock.On("FindPending", mock.Anything, now, 2).Return(events[:2], nil).Once()
+	dbMock.On("FindPending", mock.Anything, now, 2).Return(events[2:], nil).Once()
+	dbMock.On("ClaimEvent", mock.Anything, mock.Anything, now.Add(claimTimeout)).Run(claim).Return(true, nil)
+	dbMock.On("UpdateEvent", mock.Anything, mock.Anything).Return(nil)
+
+	relayed, err := r.Relay(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, relayed)
+	assert.Equal(t, events, sink1.published)
+	assert.Equal(t, events, sink2.published)
+	for _, e := range events {
+		assert.Equal(t, model.StatusPublished, e.Status)
+		assert.Equal(t, now, *e.PublishedAt)
+		assert.Equal(t, 1, e.Attempts)
+	}
+	dbMock.AssertNumberOfCalls(t, "UpdateEvent", 3)
+}
+
+func TestRelayRelayFail(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.OutboxDB{}
+		sink1  = &testSink{}
+		sink2  = &testSink{err: errors.New("unavailable")}
+		r      = newTestRelay(dbMock, now, sink1, sink2)
+		e      = newEvent(1)
+	)
+	e.Attempts = 2
+	dbMock.On("FindPending", mock.Anything, now, 2).Return([]*model.OutboxEvent{e}, nil)
+	dbMock.On("ClaimEvent", mock.Anything, e, mock.Anything).Run(claim).Return(true, nil)
+	dbMock.On("UpdateEvent", mock.Anything, e).Return(nil)
+
+	relayed, err := r.Relay(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, relayed)
+	assert.Len(t, sink1.published, 1)
+	assert.Equal(t, model.StatusPending, e.Status)
+	assert.Equal(t, "test sink: unavailable", e.LastError)
+	assert.Equal(t, now.Add(4*time.Second), e.NextAttemptAt)
+	assert.Nil(t, e.PublishedAt)
+}
+
+func TestRelayRelayNotClaimed(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.OutboxDB{}
+		sink   = &testSink{}
+		r      = newTestRelay(dbMock, now, sink)
+	)
+	dbMock.On("FindPending", mock.Anything, now, 2).Return([]*model.OutboxEvent{newEvent(1)}, nil)
+	dbMock.On("ClaimEvent", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	relayed, err := r.Relay(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, relayed)
+	assert.Empty(t, sink.published)
+	dbMock.AssertNotCalled(t, "UpdateEvent", mock.Anything, mock.Anything)
+}
+
+func TestRelayCleanup(t *testing.T) {
+	var (
+		now    = time.Now()
+		dbMock = &mocks.OutboxDB{}
+		r      = newTestRelay(dbMock, now)
+	)
+	dbMock.On("DeletePublished", mock.Anything, now.Add(-time.Hour), 2).Return(int64(2), nil).Once()
+	dbMock.On("DeletePublished", mock.Anything, now.Add(-time.Hour), 2).Return(int64(1), nil).Once()
+
+	deleted, err := r.Cleanup(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, deleted)
+	dbMock.AssertNumberOfCalls(t, "DeletePublished", 2)
+}
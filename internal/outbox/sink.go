@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+	SinkRedis   = "redis"
+)
+
+// Sink publishes outbox events to another system.
+// An event may be published more than once, so consumers should ignore events with a seen idempotency key.
+type Sink interface {
+	// Name returns a name of this sink.
+	Name() string
+
+	// Publish publishes a given event e.
+	Publish(ctx context.Context, e *model.OutboxEvent) error
+}
+
+// NewSinks creates sinks from outbox.sinks in given config.
+// The redis sink adds events with given redis client which is shared with other components.
+func NewSinks(conf *config.Config, cli redis.UniversalClient) ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range conf.OutboxConfig.Sinks {
+		switch name {
+		case SinkLog:
+			sinks = append(sinks, NewLogSink())
+		case SinkWebhook:
+			if conf.OutboxConfig.Webhook.URL == "" {
+				return nil, fmt.Errorf("outbox.webhook.url is required for %s sink", name)
+			}
+			sinks = append(sinks, NewWebhookSink(conf.OutboxConfig.Webhook))
+		case SinkRedis:
+			if cli == nil {
+				return nil, fmt.Errorf("redis client is required for %s sink", name)
+			}
+			sinks = append(sinks, NewRedisSink(cli, conf.OutboxConfig.Redis))
+		default:
+			return nil, fmt.Errorf("unsupported outbox sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
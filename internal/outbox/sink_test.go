@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewSinks(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+
+	// log
+	conf.OutboxConfig.Sinks = []string{SinkLog}
+	sinks, err := NewSinks(conf, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, SinkLog, sinks[0].Name())
+
+	// webhook without url
+	conf.OutboxConfig.Sinks = []string{SinkWebhook}
+	_, err = NewSinks(conf, nil)
+	assert.Error(t, err)
+
+	// redis without a client
+	conf.OutboxConfig.Sinks = []string{SinkRedis}
+	_, err = NewSinks(conf, nil)
+	assert.Error(t, err)
+
+	// redis
+	cli := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer cli.Close()
+	sinks, err = NewSinks(conf, cli)
+	assert.NoError(t, err)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, SinkRedis, sinks[0].Name())
+
+	// unsupported
+	conf.OutboxConfig.Sinks = []string{"unknown"}
+	_, err = NewSinks(conf, nil)
+	assert.Error(t, err)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+		status = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	sink := NewWebhookSink(config.OutboxWebhookConfig{URL: srv.URL, Secret: "secret", Timeout: time.Second})
+	e := newEvent(1)
+
+	// when
+	err := sink.Publish(context.TODO(), e)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, e.Payload, string(body))
+	assert.Equal(t, e.IdempotencyKey, header.Get(HeaderIdempotencyKey))
+	assert.Equal(t, e.EventType, header.Get(HeaderEventType))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), header.Get(HeaderSignature))
+
+	// when failed
+	status = http.StatusServiceUnavailable
+	err = sink.Publish(context.TODO(), e)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestRedisSink(t *testing.T) {
+	s := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer cli.Close()
+	sink := NewRedisSink(cli, config.OutboxRedisConfig{Stream: "outbox", MaxLen: 10})
+	e := newEvent(1)
+
+	// when
+	err := sink.Publish(context.TODO(), e)
+
+	// then
+	assert.NoError(t, err)
+	messages, err := cli.XRange(context.TODO(), "outbox", "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, e.IdempotencyKey, messages[0].Values["idempotencyKey"])
+	assert.Equal(t, e.EventType, messages[0].Values["eventType"])
+	assert.Equal(t, e.Payload, messages[0].Values["payload"])
+}
+
+func newEvent(id uint) *model.OutboxEvent {
+	e, _ := model.NewOutboxEvent(model.AggregateTypeArticle, 1, "article.created", map[string]uint{"articleId": 1}, time.Now())
+	e.ID = id
+	return e
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Headers of requests sent by the webhook sink.
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderEventType      = "X-Outbox-Event"
+	HeaderSignature      = "X-Outbox-Signature"
+)
+
+// NewWebhookSink creates a new Sink which posts payloads of events to the configured url.
+// Requests are signed with HMAC-SHA256 of the secret if the secret is not empty.
+func NewWebhookSink(conf config.OutboxWebhookConfig) Sink {
+	return &webhookSink{
+		url:    conf.URL,
+		secret: conf.Secret,
+		client: &http.Client{Timeout: conf.Timeout},
+	}
+}
+
+type webhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func (ws *webhookSink) Name() string {
+	return SinkWebhook
+}
+
+func (ws *webhookSink) Publish(ctx context.Context, e *model.OutboxEvent) error {
+	payload := []byte(e.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderIdempotencyKey, e.IdempotencyKey)
+	req.Header.Set(HeaderEventType, e.EventType)
+	if ws.secret != "" {
+		mac := hmac.New(sha256.New, []byte(ws.secret))
+		mac.Write(payload)
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := ws.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// drain the body to reuse the connection.
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptionBufferSize is the number of messages buffered for a subscription.
+// Messages to a subscription with full buffer are dropped, so slow subscribers don't block publishers.
+const subscriptionBufferSize = 64
+
+// NewMemoryPubSub creates a new PubSub delivering messages within this process.
+func NewMemoryPubSub() PubSub {
+	return &memoryPubSub{
+		subscriptions: make(map[string]map[*memorySubscription]struct{}),
+	}
+}
+
+type memoryPubSub struct {
+	mu            sync.RWMutex
+	subscriptions map[string]map[*memorySubscription]struct{}
+}
+
+func (mp *memoryPubSub) Publish(_ context.Context, channel string, payload []byte) error {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	for s := range mp.subscriptions[channel] {
+		select {
+		case s.messages <- &Message{Channel: channel, Payload: payload}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (mp *memoryPubSub) Subscribe(_ context.Context, channels ...string) (Subscription, error) {
+	s := &memorySubscription{
+		pubsub:   mp,
+		channels: channels,
+		messages: make(chan *Message, subscriptionBufferSize),
+	}
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	for _, channel := range channels {
+		if _, ok := mp.subscriptions[channel]; !ok {
+			mp.subscriptions[channel] = make(map[*memorySubscription]struct{})
+		}
+		mp.subscriptions[channel][s] = struct{}{}
+	}
+	return s, nil
+}
+
+func (mp *memoryPubSub) Close() error {
+	return nil
+}
+
+// unsubscribe removes given subscription from all subscribed channels and closes its messages.
+func (mp *memoryPubSub) unsubscribe(s *memorySubscription) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	for _, channel := range s.channels {
+		delete(mp.subscriptions[channel], s)
+		if len(mp.subscriptions[channel]) == 0 {
+			delete(mp.subscriptions, channel)
+		}
+	}
+	close(s.messages)
+}
+
+type memorySubscription struct {
+	pubsub   *memoryPubSub
+	channels []string
+	messages chan *Message
+	once     sync.Once
+}
+
+func (ms *memorySubscription) Messages() <-chan *Message {
+	return ms.messages
+}
+
+func (ms *memorySubscription) Close() error {
+	ms.once.Do(func() {
+		ms.pubsub.unsubscribe(ms)
+	})
+	return nil
+}
@@ -0,0 +1,58 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+)
+
+const (
+	TypeRedis  = "redis"
+	TypeMemory = "memory"
+)
+
+// Message represents a message published to a channel.
+type Message struct {
+	Channel string
+	Payload []byte
+}
+
+// PubSub delivers messages published to a channel to all subscribers of the channel.
+// Messages are not persisted, so subscribers only receive messages published while subscribing.
+type PubSub interface {
+	// Publish publishes a given payload to the channel.
+	Publish(ctx context.Context, channel string, payload []byte) error
+
+	// Subscribe subscribes given channels until the returned Subscription is closed.
+	Subscribe(ctx context.Context, channels ...string) (Subscription, error)
+
+	// Close releases resources of this pubsub.
+	Close() error
+}
+
+// Subscription represents subscribed channels.
+type Subscription interface {
+	// Messages returns a channel of messages published to subscribed channels.
+	// The channel is closed after this subscription is closed.
+	Messages() <-chan *Message
+
+	// Close unsubscribes all channels of this subscription.
+	Close() error
+}
+
+// NewPubSub creates a new PubSub from pubsub.type in given config.
+// The redis type is backed by given redis client which is shared with other components.
+func NewPubSub(conf *config.Config, cli redis.UniversalClient) (PubSub, error) {
+	switch conf.PubSubConfig.Type {
+	case TypeRedis:
+		if cli == nil {
+			return nil, fmt.Errorf("redis client is required for %s pubsub", TypeRedis)
+		}
+		return NewRedisPubSub(cli), nil
+	case TypeMemory:
+		return NewMemoryPubSub(), nil
+	default:
+		return nil, fmt.Errorf("unsupported pubsub type: %s", conf.PubSubConfig.Type)
+	}
+}
@@ -0,0 +1,94 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"testing"
+	"time"
+)
+
+func TestNewPubSub(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+
+	// memory
+	conf.PubSubConfig.Type = TypeMemory
+	ps, err := NewPubSub(conf, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryPubSub{}, ps)
+
+	// redis without a client
+	conf.PubSubConfig.Type = TypeRedis
+	_, err = NewPubSub(conf, nil)
+	assert.Error(t, err)
+
+	// redis
+	cli := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer cli.Close()
+	ps, err = NewPubSub(conf, cli)
+	assert.NoError(t, err)
+	assert.IsType(t, &redisPubSub{}, ps)
+
+	// unsupported
+	conf.PubSubConfig.Type = "unknown"
+	_, err = NewPubSub(conf, nil)
+	assert.Error(t, err)
+}
+
+func TestMemoryPubSub(t *testing.T) {
+	testPubSub(t, NewMemoryPubSub())
+}
+
+func TestRedisPubSub(t *testing.T) {
+	s := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer cli.Close()
+	ps := NewRedisPubSub(cli)
+	defer ps.Close()
+
+	testPubSub(t, ps)
+}
+
+func testPubSub(t *testing.T, ps PubSub) {
+	ctx := context.TODO()
+	s1, err := ps.Subscribe(ctx, "channel1", "channel2")
+	assert.NoError(t, err)
+	s2, err := ps.Subscribe(ctx, "channel2")
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, ps.Publish(ctx, "channel1", []byte("message1")))
+	assert.NoError(t, ps.Publish(ctx, "channel2", []byte("message2")))
+	assert.NoError(t, ps.Publish(ctx, "channel3", []byte("message3")))
+
+	// then
+	assertMessage(t, s1, "channel1", "message1")
+	assertMessage(t, s1, "channel2", "message2")
+	assertMessage(t, s2, "channel2", "message2")
+
+	// closed subscription
+	assert.NoError(t, s1.Close())
+	assert.NoError(t, s1.Close())
+	assert.NoError(t, ps.Publish(ctx, "channel2", []byte("message4")))
+	assertMessage(t, s2, "channel2", "message4")
+	select {
+	case _, ok := <-s1.Messages():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "messages of closed subscription are not closed")
+	}
+	assert.NoError(t, s2.Close())
+}
+
+func assertMessage(t *testing.T, s Subscription, channel, payload string) {
+	select {
+	case msg := <-s.Messages():
+		assert.Equal(t, channel, msg.Channel)
+		assert.Equal(t, payload, string(msg.Payload))
+	case <-time.After(time.Second):
+		assert.Failf(t, "no message", "channel: %s, payload: %s", channel, payload)
+	}
+}
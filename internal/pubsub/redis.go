@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"sync"
+)
+
+// NewRedisPubSub creates a new PubSub backed by redis pub/sub of given client.
+// Messages are delivered to subscribers of all processes connected to the same redis.
+// The client is owned by the caller and not closed by this pubsub.
+func NewRedisPubSub(cli redis.UniversalClient) PubSub {
+	return &redisPubSub{
+		cli: cli,
+	}
+}
+
+type redisPubSub struct {
+	cli redis.UniversalClient
+}
+
+func (rp *redisPubSub) Publish(ctx context.Context, channel string, payload []byte) error {
+	return rp.cli.Publish(ctx, channel, payload).Err()
+}
+
+func (rp *redisPubSub) Subscribe(ctx context.Context, channels ...string) (Subscription, error) {
+	ps := rp.cli.Subscribe(ctx, channels...)
+	// wait for confirmation that subscription is created.
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, err
+	}
+	s := &redisSubscription{
+		ps:       ps,
+		messages: make(chan *Message, subscriptionBufferSize),
+	}
+	go s.receive()
+	return s, nil
+}
+
+func (rp *redisPubSub) Close() error {
+	return nil
+}
+
+type redisSubscription struct {
+	ps       *redis.PubSub
+	messages chan *Message
+	once     sync.Once
+}
+
+// receive converts redis messages to Message until the redis subscription is closed.
+// Messages are dropped if the buffer is full same as memory pubsub.
+func (rs *redisSubscription) receive() {
+	defer close(rs.messages)
+	for msg := range rs.ps.Channel() {
+		select {
+		case rs.messages <- &Message{Channel: msg.Channel, Payload: []byte(msg.Payload)}:
+		default:
+		}
+	}
+}
+
+func (rs *redisSubscription) Messages() <-chan *Message {
+	return rs.messages
+}
+
+func (rs *redisSubscription) Close() error {
+	var err error
+	rs.once.Do(func() {
+		err = rs.ps.Close()
+	})
+	return err
+}
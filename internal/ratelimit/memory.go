@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is an interval to remove buckets which are refilled to full from memory.
+const sweepInterval = time.Minute
+
+// NewMemoryLimiter creates a new Limiter which keeps buckets within this process.
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is the time when this bucket is refilled to full.
+	full time.Time
+}
+
+func (ml *memoryLimiter) Allow(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	now := ml.now()
+	ml.sweep(now)
+
+	interval := limit.interval()
+	b, ok := ml.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		ml.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(interval)
+		if b.tokens > float64(limit.Burst) {
+			b.tokens = float64(limit.Burst)
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(interval)), nil
+	}
+	b.tokens--
+	b.full = now.Add(time.Duration((float64(limit.Burst) - b.tokens) * float64(interval)))
+	return true, 0, nil
+}
+
+// sweep removes full buckets if sweepInterval has elapsed since the last sweep.
+func (ml *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(ml.lastSweep) < sweepInterval {
+		return
+	}
+	ml.lastSweep = now
+	for key, b := range ml.buckets {
+		if !now.Before(b.full) {
+			delete(ml.buckets, key)
+		}
+	}
+}
+
+func (ml *memoryLimiter) Close() error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net"
+	"strconv"
+	"time"
+)
+
+// allRoutes matches all routes which are not matched to other groups.
+const allRoutes = "*"
+
+// KeyFunc returns a key of a client sending a request of given echo.Context.
+type KeyFunc func(c echo.Context) string
+
+// NewKeyFunc returns a KeyFunc which returns "user:{id}" of an authenticated user, otherwise "ip:{client ip}".
+// The user is resolved from authutils.CurrentUser or a token signed with given secret in Authorization header,
+// because this middleware can be applied before auth middlewares of route groups.
+func NewKeyFunc(secret string) KeyFunc {
+	return func(c echo.Context) string {
+		userID := authutils.CurrentUser(c)
+		if userID == 0 {
+			if header := c.Request().Header.Get("Authorization"); header != "" {
+				if claims, err := authutils.ParseAuthHeader(header, secret); err == nil {
+					userID = claims.UserID
+				}
+			}
+		}
+		if userID != 0 {
+			return fmt.Sprintf("user:%d", userID)
+		}
+		return "ip:" + c.RealIP()
+	}
+}
+
+// NewIPExtractor returns an echo.IPExtractor which returns the remote address of a connection if no
+// trusted proxies are given. Otherwise, a client ip is taken from X-Forwarded-For header only if a request
+// is sent from given trusted proxies, so clients cannot change their ip with forged headers.
+func NewIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(network))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+type group struct {
+	name  string
+	limit Limit
+}
+
+// NewMiddleware returns a middleware limiting requests of route groups in given config with given limiter.
+// Requests over the limit are rejected with 429 Too Many Requests and "Retry-After" header.
+// Requests are allowed if failed to take a token from the limiter.
+func NewMiddleware(limiter Limiter, conf config.RateLimitConfig, keyFunc KeyFunc) echo.MiddlewareFunc {
+	var (
+		routes   = make(map[string]*group)
+		fallback *group
+	)
+	for name, rule := range conf.Groups {
+		if rule.Rate <= 0 || rule.Period <= 0 {
+			continue
+		}
+		g := &group{name: name, limit: NewLimit(rule)}
+		for _, route := range rule.Routes {
+			if route == allRoutes {
+				fallback = g
+				continue
+			}
+			routes[route] = g
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			g, ok := routes[c.Request().Method+" "+c.Path()]
+			if !ok {
+				g, ok = routes[c.Path()]
+			}
+			if !ok {
+				g = fallback
+			}
+			if g == nil {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := conf.Prefix + g.name + ":" + keyFunc(c)
+			allowed, retryAfter, err := limiter.Allow(ctx, key, g.limit)
+			if err != nil {
+				logging.FromContext(ctx).Errorw("RateLimit failed to take a token", "key", key, "err", err)
+				return next(c)
+			}
+			if !allowed {
+				c.Response().Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+				return httputils.NewTooManyRequests()
+			}
+			return next(c)
+		}
+	}
+}
+
+// retryAfterSeconds returns given d in seconds rounded up and at least 1.
+func retryAfterSeconds(d time.Duration) string {
+	secs := int64((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(secs, 10)
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const secret = "secret-key"
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(NewMiddleware(NewMemoryLimiter(), config.RateLimitConfig{
+		Prefix: "test-",
+		Groups: map[string]config.RateLimitRule{
+			"auth": {
+				Routes: []string{"POST /api/users/login"},
+				Rate:   1,
+				Period: time.Minute,
+				Burst:  2,
+			},
+			"comment": {
+				Routes: []string{"/api/articles/:slug/comments"},
+				Rate:   1,
+				Period: time.Minute,
+			},
+			"default": {
+				Routes: []string{"*"},
+				Rate:   3,
+				Period: time.Minute,
+			},
+		},
+	}, NewKeyFunc(secret)))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.POST("/api/users/login", ok)
+	e.GET("/api/users/login", ok)
+	e.POST("/api/articles/:slug/comments", ok)
+	e.GET("/api/tags", ok)
+
+	token1, err := authutils.MakeJWTToken(1, "", []byte(secret), time.Minute)
+	assert.NoError(t, err)
+	token2, err := authutils.MakeJWTToken(2, "", []byte(secret), time.Minute)
+	assert.NoError(t, err)
+	invalid, err := authutils.MakeJWTToken(3, "", []byte("invalid"), time.Minute)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		ip         string
+		token      string
+		code       int
+		retryAfter string
+	}{
+		{name: "ip1 login 1", method: http.MethodPost, path: "/api/users/login", ip: "10.0.0.1", code: http.StatusOK},
+		{name: "ip1 login 2", method: http.MethodPost, path: "/api/users/login", ip: "10.0.0.1", code: http.StatusOK},
+		{name: "ip1 login limited", method: http.MethodPost, path: "/api/users/login", ip: "10.0.0.1", code: http.StatusTooManyRequests, retryAfter: "60"},
+		{name: "ip2 login", method: http.MethodPost, path: "/api/users/login", ip: "10.0.0.2", code: http.StatusOK},
+		{name: "ip1 other method", method: http.MethodGet, path: "/api/users/login", ip: "10.0.0.1", code: http.StatusOK},
+		{name: "user1 comment", method: http.MethodPost, path: "/api/articles/a/comments", ip: "10.0.0.1", token: token1, code: http.StatusOK},
+		{name: "user1 comment limited", method: http.MethodPost, path: "/api/articles/b/comments", ip: "10.0.0.2", token: token1, code: http.StatusTooManyRequests, retryAfter: "60"},
+		{name: "user2 comment", method: http.MethodPost, path: "/api/articles/a/comments", ip: "10.0.0.1", token: token2, code: http.StatusOK},
+		{name: "invalid token uses ip", method: http.MethodPost, path: "/api/articles/a/comments", ip: "10.0.0.3", token: invalid, code: http.StatusOK},
+		{name: "ip1 tags 1", method: http.MethodGet, path: "/api/tags", ip: "10.0.0.1", code: http.StatusOK},
+		{name: "ip1 tags 2", method: http.MethodGet, path: "/api/tags", ip: "10.0.0.1", code: http.StatusOK},
+		{name: "ip1 tags limited", method: http.MethodGet, path: "/api/tags", ip: "10.0.0.1", code: http.StatusTooManyRequests, retryAfter: "20"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.RemoteAddr = tc.ip + ":1234"
+			if tc.token != "" {
+				authutils.SetAuthToken(req, tc.token)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code)
+			assert.Equal(t, tc.retryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestMiddleware_ForwardedFor(t *testing.T) {
+	newEcho := func(trustedProxies []string) *echo.Echo {
+		e := echo.New()
+		extractor, err := NewIPExtractor(trustedProxies)
+		assert.NoError(t, err)
+		e.IPExtractor = extractor
+		e.Use(NewMiddleware(NewMemoryLimiter(), config.RateLimitConfig{
+			Groups: map[string]config.RateLimitRule{
+				"default": {
+					Routes: []string{"*"},
+					Rate:   1,
+					Period: time.Minute,
+				},
+			},
+		}, NewKeyFunc(secret)))
+		e.GET("/api/tags", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		return e
+	}
+	request := func(e *echo.Echo, remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// forged headers are ignored without trusted proxies
+	e := newEcho(nil)
+	assert.Equal(t, http.StatusOK, request(e, "10.0.0.1:1234", ""))
+	assert.Equal(t, http.StatusTooManyRequests, request(e, "10.0.0.1:1234", "1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(e, "10.0.0.1:1234", "2.2.2.2"))
+
+	// clients behind trusted proxies are limited separately
+	e = newEcho([]string{"10.0.0.0/24"})
+	assert.Equal(t, http.StatusOK, request(e, "10.0.0.1:1234", "1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(e, "10.0.0.2:1234", "1.1.1.1"))
+	assert.Equal(t, http.StatusOK, request(e, "10.0.0.1:1234", "2.2.2.2"))
+
+	// forged headers are ignored if not sent from trusted proxies
+	assert.Equal(t, http.StatusOK, request(e, "10.0.1.1:1234", "3.3.3.3"))
+	assert.Equal(t, http.StatusTooManyRequests, request(e, "10.0.1.1:1234", "4.4.4.4"))
+}
+
+func TestNewIPExtractor(t *testing.T) {
+	_, err := NewIPExtractor([]string{"invalid"})
+	assert.Error(t, err)
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, "1", retryAfterSeconds(0))
+	assert.Equal(t, "1", retryAfterSeconds(300*time.Millisecond))
+	assert.Equal(t, "1", retryAfterSeconds(time.Second))
+	assert.Equal(t, "2", retryAfterSeconds(1001*time.Millisecond))
+}
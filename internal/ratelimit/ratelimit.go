@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"time"
+)
+
+const (
+	TypeRedis  = "redis"
+	TypeMemory = "memory"
+)
+
+// Limit represents a token bucket which holds up to Burst tokens and is refilled Rate tokens every Period.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// NewLimit returns a new Limit from given rule. Burst is the same as Rate if not provided.
+func NewLimit(rule config.RateLimitRule) Limit {
+	l := Limit{
+		Rate:   rule.Rate,
+		Period: rule.Period,
+		Burst:  rule.Burst,
+	}
+	if l.Burst <= 0 {
+		l.Burst = l.Rate
+	}
+	return l
+}
+
+// interval returns a duration to refill a single token.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Limiter limits requests of keys with token buckets.
+type Limiter interface {
+	// Allow takes a token from a bucket of given key and returns true if taken.
+	// Otherwise, returns false with a duration to wait until a token is available.
+	Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+
+	// Close releases resources of this limiter.
+	Close() error
+}
+
+// NewLimiter creates a new Limiter from server.rateLimit.type in given config.
+// Given redis client is required for the redis type and is not closed by the Limiter.
+func NewLimiter(conf *config.Config, cli redis.UniversalClient) (Limiter, error) {
+	switch conf.ServerConfig.RateLimit.Type {
+	case TypeRedis:
+		if cli == nil {
+			return nil, fmt.Errorf("redis client is required for %s rate limiter", TypeRedis)
+		}
+		return NewRedisLimiter(cli), nil
+	case TypeMemory:
+		return NewMemoryLimiter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit type: %s", conf.ServerConfig.RateLimit.Type)
+	}
+}
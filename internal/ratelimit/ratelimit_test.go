@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"testing"
+	"time"
+)
+
+func TestNewLimiter(t *testing.T) {
+	conf, err := config.Load("")
+	assert.NoError(t, err)
+
+	// memory
+	conf.ServerConfig.RateLimit.Type = TypeMemory
+	l, err := NewLimiter(conf, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryLimiter{}, l)
+
+	// redis
+	s := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer cli.Close()
+	conf.ServerConfig.RateLimit.Type = TypeRedis
+	l, err = NewLimiter(conf, cli)
+	assert.NoError(t, err)
+	assert.IsType(t, &redisLimiter{}, l)
+	// the shared client is not closed
+	assert.NoError(t, l.Close())
+	assert.NoError(t, cli.Ping(context.TODO()).Err())
+
+	// redis without a client
+	_, err = NewLimiter(conf, nil)
+	assert.Error(t, err)
+
+	// unsupported
+	conf.ServerConfig.RateLimit.Type = "unknown"
+	_, err = NewLimiter(conf, nil)
+	assert.Error(t, err)
+}
+
+func TestNewLimit(t *testing.T) {
+	l := NewLimit(config.RateLimitRule{Rate: 10, Period: time.Minute})
+	assert.Equal(t, Limit{Rate: 10, Period: time.Minute, Burst: 10}, l)
+	assert.Equal(t, 6*time.Second, l.interval())
+
+	l = NewLimit(config.RateLimitRule{Rate: 10, Period: time.Minute, Burst: 3})
+	assert.Equal(t, 3, l.Burst)
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	l := NewMemoryLimiter().(*memoryLimiter)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	testLimiter(t, l, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	l := NewMemoryLimiter().(*memoryLimiter)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Period: time.Second, Burst: 2}
+
+	allowed, _, err := l.Allow(context.TODO(), "key1", limit)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Len(t, l.buckets, 1)
+
+	// key1 is refilled to full
+	now = now.Add(sweepInterval)
+	allowed, _, err = l.Allow(context.TODO(), "key2", limit)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Len(t, l.buckets, 1)
+	assert.Contains(t, l.buckets, "key2")
+}
+
+func TestRedisLimiter(t *testing.T) {
+	s := miniredis.RunT(t)
+	cli := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer cli.Close()
+	l := NewRedisLimiter(cli).(*redisLimiter)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	testLimiter(t, l, func(d time.Duration) {
+		now = now.Add(d)
+		s.FastForward(d)
+	})
+
+	// bucket expires when refilled to full
+	s.FastForward(time.Minute)
+	assert.False(t, s.Exists("key1"))
+}
+
+func testLimiter(t *testing.T, l Limiter, sleep func(d time.Duration)) {
+	var (
+		ctx   = context.TODO()
+		limit = Limit{Rate: 2, Period: time.Second, Burst: 3}
+	)
+
+	// take all tokens of burst
+	for i := 0; i < limit.Burst; i++ {
+		allowed, retryAfter, err := l.Allow(ctx, "key1", limit)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.EqualValues(t, 0, retryAfter)
+	}
+	allowed, retryAfter, err := l.Allow(ctx, "key1", limit)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// other keys have own buckets
+	allowed, _, err = l.Allow(ctx, "key2", limit)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	// refilled a single token
+	sleep(250 * time.Millisecond)
+	allowed, retryAfter, err = l.Allow(ctx, "key1", limit)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+
+	sleep(250 * time.Millisecond)
+	allowed, _, err = l.Allow(ctx, "key1", limit)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, _, err = l.Allow(ctx, "key1", limit)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	// refilled up to burst
+	sleep(10 * time.Second)
+	for i := 0; i < limit.Burst; i++ {
+		allowed, _, err = l.Allow(ctx, "key1", limit)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, _, err = l.Allow(ctx, "key1", limit)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// tokenBucketScript takes a token from a bucket stored as a hash of remaining tokens and last refilled time in ms.
+// Returns {1, 0} if taken, otherwise {0, milliseconds to wait}. A bucket expires when refilled to full.
+//
+// KEYS[1]: a key of the bucket
+// ARGV[1]: milliseconds to refill a single token
+// ARGV[2]: burst
+// ARGV[3]: current time in ms
+var tokenBucketScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local values = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(values[1])
+local last = tonumber(values[2])
+if tokens == nil or last == nil then
+	tokens = burst
+	last = now
+end
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) / interval)
+	last = now
+end
+if tokens < 1 then
+	return {0, math.ceil((1 - tokens) * interval)}
+end
+tokens = tokens - 1
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(last))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) * interval))
+return {1, 0}
+`)
+
+// NewRedisLimiter creates a new Limiter which keeps buckets in given redis client.
+// Requests are limited across all processes connected to the same redis. Given client is not closed by the limiter.
+func NewRedisLimiter(cli redis.UniversalClient) Limiter {
+	return &redisLimiter{
+		cli: cli,
+		now: time.Now,
+	}
+}
+
+type redisLimiter struct {
+	cli redis.UniversalClient
+	now func() time.Time
+}
+
+func (rl *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	var (
+		interval = float64(limit.interval()) / float64(time.Millisecond)
+		now      = rl.now().UnixNano() / int64(time.Millisecond)
+	)
+	res, err := tokenBucketScript.Run(ctx, rl.cli, []string{key}, interval, limit.Burst, now).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	values := res.([]interface{})
+	if values[0].(int64) == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(values[1].(int64)) * time.Millisecond, nil
+}
+
+func (rl *redisLimiter) Close() error {
+	return nil
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/article"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/notification"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/ratelimit"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/user"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
@@ -16,8 +19,11 @@ import (
 
 type Server struct {
 	*echo.Echo
-	articleHandler *article.Handler
-	userHandler    *user.Handler
+	articleHandler      *article.Handler
+	userHandler         *user.Handler
+	notificationHandler *notification.Handler
+	webhookHandler      *webhook.Handler
+	limiter             ratelimit.Limiter
 }
 
 // New returns a new Server from given
@@ -35,8 +41,30 @@ func New(env *serverenv.ServerEnv, conf *config.Config) (*Server, error) {
 		},
 	}))
 	e.Validator = httputils.NewValidator()
+	ipExtractor, err := ratelimit.NewIPExtractor(conf.ServerConfig.TrustedProxies)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialize ip extractor")
+	}
+	e.IPExtractor = ipExtractor
+	var limiter ratelimit.Limiter
+	if rateLimitConf := conf.ServerConfig.RateLimit; rateLimitConf.Enabled {
+		l, err := ratelimit.NewLimiter(conf, env.GetRedisClient())
+		if err != nil {
+			return nil, errors.Wrap(err, "initialize rate limiter")
+		}
+		limiter = l
+		e.Use(ratelimit.NewMiddleware(limiter, rateLimitConf, ratelimit.NewKeyFunc(conf.JWTConfig.Secret)))
+	}
 	v1 := e.Group("/api")
-	authMiddleware := authutils.NewJWTMiddleware(
+	var isRevoked authutils.RevocationChecker
+	if tokenDB := env.GetTokenDB(); tokenDB != nil {
+		isRevoked = tokenDB.IsAccessTokenRevoked
+	}
+	var isDisabled authutils.DisabledChecker
+	if userDB := env.GetUserDB(); userDB != nil {
+		isDisabled = userDB.IsDisabled
+	}
+	authMiddleware := authutils.NewJWTMiddlewareWithCheckers(
 		map[string]struct{}{
 			"/api/profiles/:username":      {},
 			"/api/articles":                {},
@@ -44,6 +72,8 @@ func New(env *serverenv.ServerEnv, conf *config.Config) (*Server, error) {
 			"/api/articles/:slug/comments": {},
 		},
 		conf.JWTConfig.Secret,
+		isRevoked,
+		isDisabled,
 	)
 
 	// Setup handlers and route.
@@ -59,14 +89,34 @@ func New(env *serverenv.ServerEnv, conf *config.Config) (*Server, error) {
 	}
 	articleHandler.Route(v1, authMiddleware)
 
+	notificationHandler, err := notification.NewHandler(env, conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialize notification handlers")
+	}
+	notificationHandler.Route(v1, authMiddleware)
+
+	webhookHandler, err := webhook.NewHandler(env, conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "initialize webhook handlers")
+	}
+	webhookHandler.Route(v1, authMiddleware)
+
+	// Setup event subscribers.
+	env.GetEventBus().Subscribe(notification.NewNotifier(env).Handle)
+	env.GetEventBus().Subscribe(article.NewFeedBroadcaster(env).Handle)
+	env.GetEventBus().Subscribe(webhook.NewEnqueuer(env).Handle)
+
 	// Serve api docs if enabled.
 	if conf.ServerConfig.Docs.Enabled {
 		e.Static("/docs", conf.ServerConfig.Docs.Path)
 	}
 
 	return &Server{
-		Echo:           e,
-		userHandler:    userHandler,
-		articleHandler: articleHandler,
+		Echo:                e,
+		userHandler:         userHandler,
+		articleHandler:      articleHandler,
+		notificationHandler: notificationHandler,
+		webhookHandler:      webhookHandler,
+		limiter:             limiter,
 	}, nil
 }
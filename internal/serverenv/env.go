@@ -1,22 +1,44 @@
 package serverenv
 
 import (
+	"github.com/go-redis/redis/v8"
 	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/mail"
+	notificationDB "github.com/zacscoding/echo-gorm-realworld-app/internal/notification/database"
+	outboxDB "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/pubsub"
 	userDB "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database"
+	webhookDB "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database"
 	"gorm.io/gorm"
 )
 
 type ServerEnv struct {
 	db        *gorm.DB
 	userDB    userDB.UserDB
+	tokenDB   userDB.TokenDB
 	articleDB articleDB.ArticleDB
+
+	identityDB     userDB.IdentityDB
+	notificationDB notificationDB.NotificationDB
+	webhookDB      webhookDB.WebhookDB
+	outboxDB       outboxDB.OutboxDB
+	eventBus       *event.Bus
+	pubSub         pubsub.PubSub
+	mailer         mail.Mailer
+	redisCli       redis.UniversalClient
 }
 
 type Option func(env *ServerEnv)
 
-// NewServerEnv returns a new ServerEnv applied given options
+// NewServerEnv returns a new ServerEnv applied given options.
+// An event bus without subscribers, an in-process pubsub and a mailer writing to logs are used if not provided.
 func NewServerEnv(opts ...Option) *ServerEnv {
-	env := &ServerEnv{}
+	env := &ServerEnv{
+		eventBus: event.NewBus(),
+		pubSub:   pubsub.NewMemoryPubSub(),
+		mailer:   mail.NewLogMailer(),
+	}
 	for _, opt := range opts {
 		opt(env)
 	}
@@ -37,6 +59,20 @@ func WithUserDB(userDB userDB.UserDB) Option {
 	}
 }
 
+// WithTokenDB sets database.TokenDB to ServerEnv.
+func WithTokenDB(tokenDB userDB.TokenDB) Option {
+	return func(env *ServerEnv) {
+		env.tokenDB = tokenDB
+	}
+}
+
+// WithIdentityDB sets database.IdentityDB to ServerEnv.
+func WithIdentityDB(identityDB userDB.IdentityDB) Option {
+	return func(env *ServerEnv) {
+		env.identityDB = identityDB
+	}
+}
+
 // WithArticleDB sets database.ArticleDB to ServerEnv.
 func WithArticleDB(articleDB articleDB.ArticleDB) Option {
 	return func(env *ServerEnv) {
@@ -44,6 +80,56 @@ func WithArticleDB(articleDB articleDB.ArticleDB) Option {
 	}
 }
 
+// WithNotificationDB sets database.NotificationDB to ServerEnv.
+func WithNotificationDB(notificationDB notificationDB.NotificationDB) Option {
+	return func(env *ServerEnv) {
+		env.notificationDB = notificationDB
+	}
+}
+
+// WithWebhookDB sets database.WebhookDB to ServerEnv.
+func WithWebhookDB(webhookDB webhookDB.WebhookDB) Option {
+	return func(env *ServerEnv) {
+		env.webhookDB = webhookDB
+	}
+}
+
+// WithOutboxDB sets database.OutboxDB to ServerEnv.
+func WithOutboxDB(outboxDB outboxDB.OutboxDB) Option {
+	return func(env *ServerEnv) {
+		env.outboxDB = outboxDB
+	}
+}
+
+// WithEventBus sets event.Bus to ServerEnv.
+func WithEventBus(bus *event.Bus) Option {
+	return func(env *ServerEnv) {
+		env.eventBus = bus
+	}
+}
+
+// WithPubSub sets pubsub.PubSub to ServerEnv.
+func WithPubSub(pubSub pubsub.PubSub) Option {
+	return func(env *ServerEnv) {
+		env.pubSub = pubSub
+	}
+}
+
+// WithMailer sets mail.Mailer to ServerEnv.
+func WithMailer(mailer mail.Mailer) Option {
+	return func(env *ServerEnv) {
+		env.mailer = mailer
+	}
+}
+
+// WithRedisClient sets redis.UniversalClient shared by redis backed components to ServerEnv.
+// The client is closed when ServerEnv is closed.
+func WithRedisClient(cli redis.UniversalClient) Option {
+	return func(env *ServerEnv) {
+		env.redisCli = cli
+	}
+}
+
 // GetDB returns a gorm.DB in ServerEnv.
 func (se *ServerEnv) GetDB() *gorm.DB {
 	return se.db
@@ -54,12 +140,63 @@ func (se *ServerEnv) GetUserDB() userDB.UserDB {
 	return se.userDB
 }
 
+// GetTokenDB returns a database.TokenDB in ServerEnv.
+func (se *ServerEnv) GetTokenDB() userDB.TokenDB {
+	return se.tokenDB
+}
+
+// GetIdentityDB returns a database.IdentityDB in ServerEnv.
+func (se *ServerEnv) GetIdentityDB() userDB.IdentityDB {
+	return se.identityDB
+}
+
 // GetArticleDB returns a database.ArticleDB in ServerEnv.
 func (se *ServerEnv) GetArticleDB() articleDB.ArticleDB {
 	return se.articleDB
 }
 
+// GetNotificationDB returns a database.NotificationDB in ServerEnv.
+func (se *ServerEnv) GetNotificationDB() notificationDB.NotificationDB {
+	return se.notificationDB
+}
+
+// GetWebhookDB returns a database.WebhookDB in ServerEnv.
+func (se *ServerEnv) GetWebhookDB() webhookDB.WebhookDB {
+	return se.webhookDB
+}
+
+// GetOutboxDB returns a database.OutboxDB in ServerEnv.
+func (se *ServerEnv) GetOutboxDB() outboxDB.OutboxDB {
+	return se.outboxDB
+}
+
+// GetEventBus returns an event.Bus in ServerEnv.
+func (se *ServerEnv) GetEventBus() *event.Bus {
+	return se.eventBus
+}
+
+// GetPubSub returns a pubsub.PubSub in ServerEnv.
+func (se *ServerEnv) GetPubSub() pubsub.PubSub {
+	return se.pubSub
+}
+
+// GetMailer returns a mail.Mailer in ServerEnv.
+func (se *ServerEnv) GetMailer() mail.Mailer {
+	return se.mailer
+}
+
+// GetRedisClient returns a redis.UniversalClient in ServerEnv. nil if redis is not used.
+func (se *ServerEnv) GetRedisClient() redis.UniversalClient {
+	return se.redisCli
+}
+
 // Close shuts down this server environments.
 func (se *ServerEnv) Close() error {
-	return nil
+	err := se.pubSub.Close()
+	if se.redisCli != nil {
+		if err1 := se.redisCli.Close(); err == nil {
+			err = err1
+		}
+	}
+	return err
 }
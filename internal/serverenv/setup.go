@@ -1,11 +1,19 @@
 package serverenv
 
 import (
+	"github.com/go-redis/redis/v8"
 	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/mail"
+	notificationDB "github.com/zacscoding/echo-gorm-realworld-app/internal/notification/database"
+	outboxDB "github.com/zacscoding/echo-gorm-realworld-app/internal/outbox/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/pubsub"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/ratelimit"
 	userDB "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database"
+	webhookDB "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 )
 
@@ -23,22 +31,94 @@ func SetupWith(conf *config.Config) (*ServerEnv, error) {
 	}
 	opts = append(opts, WithDB(db))
 
-	// Setup userDB
-	udb := userDB.NewUserDB(conf, db)
+	// Setup redis client shared by redis backed components
+	var redisCli redis.UniversalClient
+	if usesRedis(conf) {
+		redisCli = cache.NewRedisClient(conf)
+		opts = append(opts, WithRedisClient(redisCli))
+	}
+
+	// Setup cache
+	var c cache.Cache
 	if conf.CacheConfig.Enabled {
-		redisCli, _, err := cache.NewCache(conf)
+		c, err = cache.NewCache(conf, redisCli)
 		if err != nil {
-			logger.Errorw("failed to create a redis client", "err", err)
+			logger.Errorw("failed to create a cache", "type", conf.CacheConfig.Type, "err", err)
 			return nil, err
 		}
-		udb = userDB.NewUserCacheDB(conf, redisCli, udb)
+	}
+
+	// Setup userDB
+	udb := userDB.NewUserDB(conf, db)
+	if c != nil {
+		udb = userDB.NewUserCacheDB(conf, c, udb)
 	}
 	opts = append(opts, WithUserDB(udb))
 
+	// Setup tokenDB
+	tdb := userDB.NewTokenDB(conf, db)
+	if c != nil {
+		tdb = userDB.NewTokenCacheDB(conf, c, tdb)
+	}
+	opts = append(opts, WithTokenDB(tdb))
+
+	// Setup identityDB
+	opts = append(opts, WithIdentityDB(userDB.NewIdentityDB(conf, db)))
+
 	// Setup articleDB
 	adb := articleDB.NewArticleDB(conf, db)
-	// TODO: add cache layer DB.
+	if c != nil {
+		adb = articleDB.NewArticleCacheDB(conf, c, adb)
+	}
 	opts = append(opts, WithArticleDB(adb))
 
+	// Setup notificationDB
+	opts = append(opts, WithNotificationDB(notificationDB.NewNotificationDB(conf, db)))
+
+	// Setup webhookDB
+	opts = append(opts, WithWebhookDB(webhookDB.NewWebhookDB(conf, db)))
+
+	// Setup outboxDB
+	opts = append(opts, WithOutboxDB(outboxDB.NewOutboxDB(conf, db)))
+
+	// Setup event bus. subscribers are registered by the server.
+	opts = append(opts, WithEventBus(event.NewBus()))
+
+	// Setup pubsub
+	ps, err := pubsub.NewPubSub(conf, redisCli)
+	if err != nil {
+		logger.Errorw("failed to create a pubsub", "type", conf.PubSubConfig.Type, "err", err)
+		return nil, err
+	}
+	opts = append(opts, WithPubSub(ps))
+
+	// Setup mailer
+	mailer, err := mail.NewMailer(conf)
+	if err != nil {
+		logger.Errorw("failed to create a mailer", "type", conf.MailConfig.Type, "err", err)
+		return nil, err
+	}
+	opts = append(opts, WithMailer(mailer))
+
 	return NewServerEnv(opts...), nil
 }
+
+// usesRedis returns true if any component is configured to use redis, otherwise false.
+func usesRedis(conf *config.Config) bool {
+	if conf.CacheConfig.Enabled && conf.CacheConfig.Type == cache.TypeRedis {
+		return true
+	}
+	if conf.PubSubConfig.Type == pubsub.TypeRedis {
+		return true
+	}
+	if conf.ServerConfig.RateLimit.Enabled && conf.ServerConfig.RateLimit.Type == ratelimit.TypeRedis {
+		return true
+	}
+	// the redis outbox sink is named same as the redis type.
+	for _, sink := range conf.OutboxConfig.Sinks {
+		if sink == cache.TypeRedis {
+			return true
+		}
+	}
+	return false
+}
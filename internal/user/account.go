@@ -0,0 +1,245 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/mail"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// handleVerifyEmail handles "POST /api/users/verify-email" to verify an email of the user with a token
+// sent by email.
+func (h *Handler) handleVerifyEmail(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &VerifyEmailRequest{}
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleVerifyEmail failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Use given token
+	user, err := h.useUserToken(ctx, userModel.TokenPurposeVerifyEmail, req.User.Token)
+	if err != nil {
+		return err
+	}
+
+	// Verify an email of the user
+	if !user.IsVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := h.userDB.Update(ctx, user); err != nil {
+			return httputils.NewInternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusVerified, nil))
+}
+
+// handleResendVerification handles "POST /api/users/verify-email/resend" to send a verification email again.
+// The response is the same whether the user exists or not and even if failed to send an email,
+// so registered emails can't be discovered. Failures are only logged.
+func (h *Handler) handleResendVerification(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &ResendVerificationRequest{}
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleResendVerification failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Find an user from given email
+	user, err := h.userDB.FindByEmail(ctx, req.User.Email)
+	if err != nil && err != database.ErrRecordNotFound {
+		logger.Errorw("UserHandler_handleResendVerification failed to find an user", "err", err)
+	}
+
+	// Send a verification email
+	if user != nil && !user.Disabled && !user.IsVerified() {
+		if err := h.sendVerificationMail(ctx, user); err != nil {
+			logger.Errorw("UserHandler_handleResendVerification failed to send a verification email", "userID", user.ID, "err", err)
+		}
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusSent, nil))
+}
+
+// handleForgotPassword handles "POST /api/users/password/forgot" to send a password reset email.
+// The response is the same whether the user exists or not and even if failed to send an email,
+// so registered emails can't be discovered. Failures are only logged.
+func (h *Handler) handleForgotPassword(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &ForgotPasswordRequest{}
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleForgotPassword failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Find an user from given email
+	user, err := h.userDB.FindByEmail(ctx, req.User.Email)
+	if err != nil && err != database.ErrRecordNotFound {
+		logger.Errorw("UserHandler_handleForgotPassword failed to find an user", "err", err)
+	}
+
+	// Send a password reset email
+	if user != nil && !user.Disabled {
+		if err := h.sendPasswordResetMail(ctx, user); err != nil {
+			logger.Errorw("UserHandler_handleForgotPassword failed to send a password reset email", "userID", user.ID, "err", err)
+		}
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusSent, nil))
+}
+
+// handleResetPassword handles "POST /api/users/password/reset" to change a password of the user with a token
+// sent by email. All refresh tokens of the user are revoked after changed.
+func (h *Handler) handleResetPassword(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &ResetPasswordRequest{}
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleResetPassword failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+	password, err := hashutils.EncodePassword(req.User.Password)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Use given token
+	user, err := h.useUserToken(ctx, userModel.TokenPurposeResetPassword, req.User.Token)
+	if err != nil {
+		return err
+	}
+
+	// Update a password. The email is regarded as verified because the token was sent to it.
+	user.Password = password
+	if !user.IsVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := h.userDB.Update(ctx, user); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Revoke all sessions
+	if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, user.ID); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusUpdated, nil))
+}
+
+// useUserToken marks an active token of given purpose as used and returns the owner of the token.
+// echo.HTTPError will be returned if the token is invalid or the email of the owner is changed after sent.
+func (h *Handler) useUserToken(ctx context.Context, purpose, token string) (*userModel.User, error) {
+	t, err := h.tokenDB.FindUserToken(ctx, purpose, hashutils.HashToken(token))
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, newInvalidUserTokenError()
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	if !t.IsActive(time.Now()) {
+		return nil, newInvalidUserTokenError()
+	}
+	if err := h.tokenDB.UseUserToken(ctx, t); err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, newInvalidUserTokenError()
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	user, err := h.userDB.FindByID(ctx, t.UserID)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, newInvalidUserTokenError()
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	if t.Email != user.Email {
+		return nil, newInvalidUserTokenError()
+	}
+	return user, nil
+}
+
+// sendVerificationMail issues a verification token of given user and sends it by email.
+func (h *Handler) sendVerificationMail(ctx context.Context, user *userModel.User) error {
+	ttl := h.cfg.UserConfig.Verification.TokenTTL
+	token, err := h.issueUserToken(ctx, user, userModel.TokenPurposeVerifyEmail, ttl)
+	if err != nil {
+		return err
+	}
+	return h.mailer.Send(ctx, &mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email by opening the link below.\n%s\n\nThe link expires in %s.\n",
+			user.Name, h.makeMailLink("/verify-email", token), ttl),
+	})
+}
+
+// sendPasswordResetMail issues a password reset token of given user and sends it by email.
+func (h *Handler) sendPasswordResetMail(ctx context.Context, user *userModel.User) error {
+	ttl := h.cfg.UserConfig.PasswordReset.TokenTTL
+	token, err := h.issueUserToken(ctx, user, userModel.TokenPurposeResetPassword, ttl)
+	if err != nil {
+		return err
+	}
+	return h.mailer.Send(ctx, &mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nYou can reset your password by opening the link below.\n%s\n\n"+
+			"The link expires in %s. Please ignore this email if you didn't request it.\n",
+			user.Name, h.makeMailLink("/reset-password", token), ttl),
+	})
+}
+
+// issueUserToken saves a new token of given purpose which expires after ttl and returns the raw token.
+func (h *Handler) issueUserToken(ctx context.Context, user *userModel.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := authutils.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	if err := h.tokenDB.SaveUserToken(ctx, &userModel.UserToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Purpose:   purpose,
+		TokenHash: hashutils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// makeMailLink returns a link to given path of the frontend with the token.
+func (h *Handler) makeMailLink(path, token string) string {
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimSuffix(h.cfg.MailConfig.BaseURL, "/"), path, url.QueryEscape(token))
+}
+
+func newInvalidUserTokenError() error {
+	return httputils.NewStatusUnprocessableEntity("invalid or expired token")
+}
@@ -0,0 +1,383 @@
+package user
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func (s *TestSuite) TestHandleVerifyEmail() {
+	u := copyUser(defaultUsers[0])
+	u.EmailVerifiedAt = nil
+	t := newUserToken(u, userModel.TokenPurposeVerifyEmail, "token", time.Hour)
+	s.t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, t.TokenHash).Return(t, nil)
+	s.t.On("UseUserToken", mock.Anything, t).Return(nil)
+	s.u.On("FindByID", mock.Anything, u.ID).Return(u, nil)
+	s.u.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.postJSON("/api/users/verify-email", map[string]interface{}{
+		"user": map[string]interface{}{"token": "token"},
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("verified", gjson.Get(rec.Body.String(), "status").String())
+	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(updated *userModel.User) bool {
+		return updated.ID == u.ID && updated.IsVerified()
+	}))
+}
+
+func (s *TestSuite) TestHandleVerifyEmail_Fail() {
+	cases := []struct {
+		name      string
+		token     string
+		setupMock func(u *userMocks.UserDB, t *userMocks.TokenDB)
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:      "missing token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {},
+			code:      http.StatusUnprocessableEntity,
+			msg:       "Token validation error. reason: required",
+		}, {
+			name:  "token not found",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:  "expired token",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "token", -time.Minute), nil)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:  "already used token",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "token", time.Hour), nil)
+				t.On("UseUserToken", mock.Anything, mock.Anything).Return(database.ErrRecordNotFound)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:  "disabled user",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "token", time.Hour), nil)
+				t.On("UseUserToken", mock.Anything, mock.Anything).Return(nil)
+				u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:  "email changed",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				changed := copyUser(defaultUsers[0])
+				changed.Email = "changed@email.com"
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "token", time.Hour), nil)
+				t.On("UseUserToken", mock.Anything, mock.Anything).Return(nil)
+				u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(changed, nil)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:  "any error",
+			token: "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeVerifyEmail, mock.Anything).Return(nil, errors.New("force error"))
+			},
+			code: http.StatusInternalServerError,
+			msg:  "force error",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.u, s.t)
+
+			rec := s.postJSON("/api/users/verify-email", map[string]interface{}{
+				"user": map[string]interface{}{"token": tc.token},
+			})
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleResendVerification() {
+	unverified := copyUser(defaultUsers[0])
+	unverified.EmailVerifiedAt = nil
+	disabled := copyUser(unverified)
+	disabled.Disabled = true
+
+	cases := []struct {
+		name  string
+		user  *userModel.User
+		err   error
+		sends bool
+	}{
+		{name: "unverified user", user: unverified, sends: true},
+		{name: "verified user", user: copyUser(defaultUsers[0])},
+		{name: "disabled user", user: disabled},
+		{name: "not found", err: database.ErrRecordNotFound},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(tc.user, tc.err)
+			s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+
+			rec := s.postJSON("/api/users/verify-email/resend", map[string]interface{}{
+				"user": map[string]interface{}{"email": defaultUsers[0].Email},
+			})
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "sent", gjson.Get(rec.Body.String(), "status").String())
+			if tc.sends {
+				s.assertUserTokenSent(tc.user, userModel.TokenPurposeVerifyEmail, "/verify-email?token=")
+			} else {
+				assert.Empty(t, s.m.messages)
+			}
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleForgotPassword() {
+	s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.postJSON("/api/users/password/forgot", map[string]interface{}{
+		"user": map[string]interface{}{"email": defaultUsers[0].Email},
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("sent", gjson.Get(rec.Body.String(), "status").String())
+	s.assertUserTokenSent(defaultUsers[0], userModel.TokenPurposeResetPassword, "/reset-password?token=")
+}
+
+func (s *TestSuite) TestHandleForgotPassword_Fail() {
+	cases := []struct {
+		name      string
+		email     string
+		setupMock func(u *userMocks.UserDB, t *userMocks.TokenDB)
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:      "invalid email",
+			email:     "not email pattern",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {},
+			code:      http.StatusUnprocessableEntity,
+			msg:       "Email validation error. reason: email",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.u, s.t)
+
+			rec := s.postJSON("/api/users/password/forgot", map[string]interface{}{
+				"user": map[string]interface{}{"email": tc.email},
+			})
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleForgotPassword_SameResponse() {
+	disabled := copyUser(defaultUsers[0])
+	disabled.Disabled = true
+
+	cases := []struct {
+		name      string
+		setupMock func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer)
+	}{
+		{
+			name: "not found",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer) {
+				u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(nil, database.ErrRecordNotFound)
+			},
+		}, {
+			name: "disabled user",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer) {
+				u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(disabled, nil)
+			},
+		}, {
+			name: "find error",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer) {
+				u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(nil, errors.New("force error"))
+			},
+		}, {
+			name: "save token error",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer) {
+				u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+				t.On("SaveUserToken", mock.Anything, mock.Anything).Return(errors.New("force error"))
+			},
+		}, {
+			name: "mail error",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB, m *testMailer) {
+				u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+				t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+				m.err = errors.New("force error")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.u, s.t, s.m)
+
+			rec := s.postJSON("/api/users/password/forgot", map[string]interface{}{
+				"user": map[string]interface{}{"email": defaultUsers[0].Email},
+			})
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "sent", gjson.Get(rec.Body.String(), "status").String())
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleResetPassword() {
+	u := copyUser(defaultUsers[0])
+	u.EmailVerifiedAt = nil
+	t := newUserToken(u, userModel.TokenPurposeResetPassword, "token", time.Hour)
+	s.t.On("FindUserToken", mock.Anything, userModel.TokenPurposeResetPassword, t.TokenHash).Return(t, nil)
+	s.t.On("UseUserToken", mock.Anything, t).Return(nil)
+	s.t.On("RevokeRefreshTokensByUser", mock.Anything, u.ID).Return(nil)
+	s.u.On("FindByID", mock.Anything, u.ID).Return(u, nil)
+	s.u.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.postJSON("/api/users/password/reset", map[string]interface{}{
+		"user": map[string]interface{}{"token": "token", "password": "new-password"},
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("updated", gjson.Get(rec.Body.String(), "status").String())
+	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(updated *userModel.User) bool {
+		return updated.ID == u.ID &&
+			hashutils.MatchesPassword(updated.Password, "new-password") == nil &&
+			updated.IsVerified()
+	}))
+	s.t.AssertCalled(s.T(), "RevokeRefreshTokensByUser", mock.Anything, u.ID)
+}
+
+func (s *TestSuite) TestHandleResetPassword_Fail() {
+	cases := []struct {
+		name      string
+		token     string
+		password  string
+		setupMock func(u *userMocks.UserDB, t *userMocks.TokenDB)
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:      "missing password",
+			token:     "token",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {},
+			code:      http.StatusUnprocessableEntity,
+			msg:       "Password validation error. reason: required",
+		}, {
+			name:     "token not found",
+			token:    "token",
+			password: "new-password",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeResetPassword, mock.Anything).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:     "email changed",
+			token:    "token",
+			password: "new-password",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				changed := copyUser(defaultUsers[0])
+				changed.Email = "changed@email.com"
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeResetPassword, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeResetPassword, "token", time.Hour), nil)
+				t.On("UseUserToken", mock.Anything, mock.Anything).Return(nil)
+				u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(changed, nil)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "invalid or expired token",
+		}, {
+			name:     "revoke error",
+			token:    "token",
+			password: "new-password",
+			setupMock: func(u *userMocks.UserDB, t *userMocks.TokenDB) {
+				t.On("FindUserToken", mock.Anything, userModel.TokenPurposeResetPassword, mock.Anything).
+					Return(newUserToken(defaultUsers[0], userModel.TokenPurposeResetPassword, "token", time.Hour), nil)
+				t.On("UseUserToken", mock.Anything, mock.Anything).Return(nil)
+				t.On("RevokeRefreshTokensByUser", mock.Anything, defaultUsers[0].ID).Return(errors.New("force error"))
+				u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(copyUser(defaultUsers[0]), nil)
+				u.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+			code: http.StatusInternalServerError,
+			msg:  "force error",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.u, s.t)
+
+			rec := s.postJSON("/api/users/password/reset", map[string]interface{}{
+				"user": map[string]interface{}{"token": tc.token, "password": tc.password},
+			})
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) postJSON(uri string, body map[string]interface{}) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, uri, toJsonReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newUserToken(u *userModel.User, purpose, token string, ttl time.Duration) *userModel.UserToken {
+	return &userModel.UserToken{
+		ID:        1,
+		UserID:    u.ID,
+		Email:     u.Email,
+		Purpose:   purpose,
+		TokenHash: hashutils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
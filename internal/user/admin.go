@@ -0,0 +1,216 @@
+package user
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strconv"
+)
+
+// handleAdminGetUsers handles "GET /api/admin/users?keyword=&role=&disabled=&limit=&offset=" to search users
+// including disabled users.
+func (h *Handler) handleAdminGetUsers(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &UserListQuery{}
+		query  userModel.UserQuery
+	)
+
+	// Bind request
+	if err := req.Bind(c, &query); err != nil {
+		logger.Errorw("UserHandler_handleAdminGetUsers failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query users
+	users, err := h.userDB.FindUsers(ctx, query, req.Offset, req.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToAdminUsersResponse(users))
+}
+
+// handleAdminGetUser handles "GET /api/admin/users/:id" to get an user with counts of the user's contents.
+func (h *Handler) handleAdminGetUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Query user
+	user, err := h.getUserForAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	// Query contents
+	counts, err := h.articleDB.CountContentsByAuthor(ctx, user.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToAdminUserResponse(user, counts))
+}
+
+// handleAdminDisableUser handles "POST /api/admin/users/:id/disable" to disable an user.
+// All refresh tokens of the user are revoked and access tokens are rejected by auth middleware.
+func (h *Handler) handleAdminDisableUser(c echo.Context) error {
+	var (
+		ctx           = c.Request().Context()
+		currentUserID = authutils.CurrentUser(c)
+	)
+
+	// Query user
+	user, err := h.getUserForAdmin(c)
+	if err != nil {
+		return err
+	}
+	if user.ID == currentUserID {
+		return httputils.NewStatusUnprocessableEntity("can not disable yourself")
+	}
+
+	// Disable the user
+	if err := h.userDB.SetDisabled(ctx, user.ID, true); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Revoke all sessions
+	if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, user.ID); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusDisabled, nil))
+}
+
+// handleAdminEnableUser handles "POST /api/admin/users/:id/enable" to enable a disabled user.
+func (h *Handler) handleAdminEnableUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// Query user
+	user, err := h.getUserForAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	// Enable the user
+	if err := h.userDB.SetDisabled(ctx, user.ID, false); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusEnabled, nil))
+}
+
+// handleAdminResetPassword handles "POST /api/admin/users/:id/password/reset" to force an user to reset a password.
+// The current password is replaced with a random one, all refresh tokens of the user are revoked
+// and a password reset email is sent to the user.
+func (h *Handler) handleAdminResetPassword(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+	)
+
+	// Query user
+	user, err := h.getUserForAdmin(c)
+	if err != nil {
+		return err
+	}
+	if user.Disabled {
+		return httputils.NewStatusUnprocessableEntity("user is disabled")
+	}
+
+	// Replace a password
+	password, err := makeRandomPassword()
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	user.Password = password
+	if err := h.userDB.Update(ctx, user); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Revoke all sessions
+	if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, user.ID); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Send a password reset email
+	if err := h.sendPasswordResetMail(ctx, user); err != nil {
+		logger.Errorw("UserHandler_handleAdminResetPassword failed to send a password reset email", "userID", user.ID, "err", err)
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusSent, nil))
+}
+
+// handleAdminUpdateRole handles "PUT /api/admin/users/:id/role" to change a role of an user.
+// All refresh tokens of the user are revoked, so tokens having the previous role can't be refreshed.
+func (h *Handler) handleAdminUpdateRole(c echo.Context) error {
+	var (
+		ctx           = c.Request().Context()
+		logger        = logging.FromContext(ctx)
+		req           = &UpdateRoleRequest{}
+		currentUserID = authutils.CurrentUser(c)
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleAdminUpdateRole failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query user
+	user, err := h.getUserForAdmin(c)
+	if err != nil {
+		return err
+	}
+	if user.ID == currentUserID {
+		return httputils.NewStatusUnprocessableEntity("can not change your own role")
+	}
+
+	// Update a role
+	if err := h.userDB.UpdateRole(ctx, user.ID, req.User.Role); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("user(%d) not found", user.ID))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	user.Role = req.User.Role
+
+	// Revoke all sessions
+	if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, user.ID); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToAdminUserResponse(user, nil))
+}
+
+// getUserForAdmin returns an user of "id" path parameter including disabled users, otherwise wrapped http error.
+func (h *Handler) getUserForAdmin(c echo.Context) (*userModel.User, error) {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		param  = c.Param("id")
+	)
+	id, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		logger.Errorw("UserHandler_getUserForAdmin invalid user id", "id", param, "err", err)
+		return nil, httputils.NewBindError("id", "uint")
+	}
+	user, err := h.userDB.FindByIDIncludeDisabled(ctx, uint(id))
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return nil, httputils.NewNotFoundError(fmt.Sprintf("user(%d) not found", id))
+		}
+		return nil, httputils.NewInternalServerError(err)
+	}
+	return user, nil
+}
+
+// makeRandomPassword returns an encoded random password which is not known to anyone.
+func makeRandomPassword() (string, error) {
+	raw, err := authutils.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	return hashutils.EncodePassword(raw)
+}
@@ -0,0 +1,360 @@
+package user
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
+	articleModel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (s *TestSuite) TestHandleAdmin_Forbidden() {
+	moderator := newUser(100, "moderator", false)
+	moderator.Role = authutils.RoleModerator
+
+	cases := []struct {
+		name        string
+		currentUser *userModel.User
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name: "anonymous",
+			code: http.StatusUnauthorized,
+			msg:  "auth required",
+		}, {
+			name:        "user",
+			currentUser: defaultUsers[0],
+			code:        http.StatusForbidden,
+			msg:         "permission denied",
+		}, {
+			name:        "moderator",
+			currentUser: moderator,
+			code:        http.StatusForbidden,
+			msg:         "permission denied",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+
+			rec := s.adminRequest(http.MethodGet, "/api/admin/users", nil, tc.currentUser)
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+			s.u.AssertNotCalled(t, "FindUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleAdmin_RoleChanged() {
+	// the role is changed after the access token was issued
+	admin := newAdmin()
+	token, _ := s.h.makeJWTToken(admin)
+	admin.Role = authutils.RoleUser
+	s.u.On("FindByID", mock.Anything, admin.ID).Return(admin, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	authutils.SetAuthToken(req, token)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+
+	assertErrorResponse(s.T(), rec, http.StatusForbidden, "permission denied")
+	s.u.AssertNotCalled(s.T(), "FindUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuite) TestHandleAdmin_DisabledAdmin() {
+	s.u.ExpectedCalls = nil
+	s.u.On("IsDisabled", mock.Anything, newAdmin().ID).Return(true, nil)
+
+	rec := s.adminRequest(http.MethodGet, "/api/admin/users", nil, newAdmin())
+
+	assertErrorResponse(s.T(), rec, http.StatusUnauthorized, "auth required")
+	s.u.AssertNotCalled(s.T(), "FindUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuite) TestHandleAdminGetUsers() {
+	disabled := newUser(6, "user-6", true)
+	s.u.On("FindUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&userModel.Users{
+		Users:      []*userModel.User{disabled},
+		UsersCount: 1,
+	}, nil)
+
+	// when
+	rec := s.adminRequest(http.MethodGet, "/api/admin/users?keyword=user&disabled=true&limit=5&offset=10", nil, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.u.AssertCalled(s.T(), "FindUsers", mock.Anything, mock.MatchedBy(func(q userModel.UserQuery) bool {
+		return q.Keyword == "user" && q.Role == "" && q.Disabled != nil && *q.Disabled
+	}), 10, 5)
+	body := rec.Body.String()
+	s.EqualValues(1, gjson.Get(body, "usersCount").Int())
+	s.EqualValues(disabled.ID, gjson.Get(body, "users.0.id").Int())
+	s.Equal(disabled.Email, gjson.Get(body, "users.0.email").String())
+	s.True(gjson.Get(body, "users.0.disabled").Bool())
+	s.False(gjson.Get(body, "users.0.contents").Exists())
+}
+
+func (s *TestSuite) TestHandleAdminGetUsers_Fail() {
+	cases := []struct {
+		name  string
+		query string
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:  "invalid role",
+			query: "role=owner",
+			code:  http.StatusUnprocessableEntity,
+			msg:   "Role validation error. reason: oneof",
+		}, {
+			name:  "invalid disabled",
+			query: "disabled=yes",
+			code:  http.StatusUnprocessableEntity,
+			msg:   "Disabled validation error. reason: oneof",
+		}, {
+			name:  "negative limit",
+			query: "limit=-1",
+			code:  http.StatusUnprocessableEntity,
+			msg:   "limit must greater than or equals to 0",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+
+			rec := s.adminRequest(http.MethodGet, "/api/admin/users?"+tc.query, nil, newAdmin())
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleAdminGetUser() {
+	u := newUser(6, "user-6", true)
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(u, nil)
+	s.a.On("CountContentsByAuthor", mock.Anything, u.ID).Return(&articleModel.ContentCounts{
+		Articles:        3,
+		Drafts:          2,
+		DeletedArticles: 1,
+		Comments:        5,
+		Favorites:       4,
+	}, nil)
+
+	// when
+	rec := s.adminRequest(http.MethodGet, "/api/admin/users/6", nil, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	user := gjson.Get(rec.Body.String(), "user")
+	s.Equal(u.Name, user.Get("username").String())
+	s.True(user.Get("disabled").Bool())
+	s.True(user.Get("emailVerified").Bool())
+	s.EqualValues(3, user.Get("contents.articles").Int())
+	s.EqualValues(2, user.Get("contents.drafts").Int())
+	s.EqualValues(1, user.Get("contents.deletedArticles").Int())
+	s.EqualValues(5, user.Get("contents.comments").Int())
+	s.EqualValues(4, user.Get("contents.favorites").Int())
+}
+
+func (s *TestSuite) TestHandleAdminGetUser_Fail() {
+	cases := []struct {
+		name      string
+		id        string
+		setupMock func(u *userMocks.UserDB)
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:      "invalid id",
+			id:        "abc",
+			setupMock: func(u *userMocks.UserDB) {},
+			code:      http.StatusUnprocessableEntity,
+			msg:       "id validation error. reason: uint",
+		}, {
+			name: "not found",
+			id:   "100",
+			setupMock: func(u *userMocks.UserDB) {
+				u.On("FindByIDIncludeDisabled", mock.Anything, uint(100)).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusNotFound,
+			msg:  "user(100) not found",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.u)
+
+			rec := s.adminRequest(http.MethodGet, "/api/admin/users/"+tc.id, nil, newAdmin())
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleAdminDisableUser() {
+	u := defaultUsers[0]
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(copyUser(u), nil)
+	s.u.On("SetDisabled", mock.Anything, u.ID, true).Return(nil)
+	s.t.On("RevokeRefreshTokensByUser", mock.Anything, u.ID).Return(nil)
+
+	// when
+	rec := s.adminRequest(http.MethodPost, "/api/admin/users/1/disable", nil, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("disabled", gjson.Get(rec.Body.String(), "status").String())
+	s.u.AssertCalled(s.T(), "SetDisabled", mock.Anything, u.ID, true)
+	s.t.AssertCalled(s.T(), "RevokeRefreshTokensByUser", mock.Anything, u.ID)
+}
+
+func (s *TestSuite) TestHandleAdminDisableUser_Self() {
+	admin := newAdmin()
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, admin.ID).Return(admin, nil)
+
+	rec := s.adminRequest(http.MethodPost, "/api/admin/users/100/disable", nil, admin)
+
+	assertErrorResponse(s.T(), rec, http.StatusUnprocessableEntity, "can not disable yourself")
+	s.u.AssertNotCalled(s.T(), "SetDisabled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuite) TestHandleAdminEnableUser() {
+	u := newUser(6, "user-6", true)
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(u, nil)
+	s.u.On("SetDisabled", mock.Anything, u.ID, false).Return(nil)
+
+	// when
+	rec := s.adminRequest(http.MethodPost, "/api/admin/users/6/enable", nil, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("enabled", gjson.Get(rec.Body.String(), "status").String())
+	s.u.AssertCalled(s.T(), "SetDisabled", mock.Anything, u.ID, false)
+}
+
+func (s *TestSuite) TestHandleAdminResetPassword() {
+	u := copyUser(defaultUsers[0])
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(u, nil)
+	s.u.On("Update", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("RevokeRefreshTokensByUser", mock.Anything, u.ID).Return(nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.adminRequest(http.MethodPost, "/api/admin/users/1/password/reset", nil, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("sent", gjson.Get(rec.Body.String(), "status").String())
+	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(updated *userModel.User) bool {
+		return updated.ID == u.ID && hashutils.MatchesPassword(updated.Password, defaultUsers[0].Name) != nil
+	}))
+	s.t.AssertCalled(s.T(), "RevokeRefreshTokensByUser", mock.Anything, u.ID)
+	s.assertUserTokenSent(u, userModel.TokenPurposeResetPassword, "/reset-password?token=")
+}
+
+func (s *TestSuite) TestHandleAdminResetPassword_Disabled() {
+	u := newUser(6, "user-6", true)
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(u, nil)
+
+	rec := s.adminRequest(http.MethodPost, "/api/admin/users/6/password/reset", nil, newAdmin())
+
+	assertErrorResponse(s.T(), rec, http.StatusUnprocessableEntity, "user is disabled")
+	s.u.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything)
+	s.Empty(s.m.messages)
+}
+
+func (s *TestSuite) TestHandleAdminUpdateRole() {
+	u := copyUser(defaultUsers[0])
+	s.u.On("FindByIDIncludeDisabled", mock.Anything, u.ID).Return(u, nil)
+	s.u.On("UpdateRole", mock.Anything, u.ID, authutils.RoleModerator).Return(nil)
+	s.t.On("RevokeRefreshTokensByUser", mock.Anything, u.ID).Return(nil)
+
+	// when
+	rec := s.adminRequest(http.MethodPut, "/api/admin/users/1/role", map[string]interface{}{
+		"user": map[string]interface{}{"role": "moderator"},
+	}, newAdmin())
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("moderator", gjson.Get(rec.Body.String(), "user.role").String())
+	s.u.AssertCalled(s.T(), "UpdateRole", mock.Anything, u.ID, authutils.RoleModerator)
+	s.t.AssertCalled(s.T(), "RevokeRefreshTokensByUser", mock.Anything, u.ID)
+}
+
+func (s *TestSuite) TestHandleAdminUpdateRole_Fail() {
+	cases := []struct {
+		name string
+		id   string
+		role string
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name: "invalid role",
+			id:   "1",
+			role: "owner",
+			code: http.StatusUnprocessableEntity,
+			msg:  "Role validation error. reason: oneof",
+		}, {
+			name: "own role",
+			id:   "100",
+			role: "user",
+			code: http.StatusUnprocessableEntity,
+			msg:  "can not change your own role",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			s.u.On("FindByIDIncludeDisabled", mock.Anything, uint(100)).Return(newAdmin(), nil)
+
+			rec := s.adminRequest(http.MethodPut, "/api/admin/users/"+tc.id+"/role", map[string]interface{}{
+				"user": map[string]interface{}{"role": tc.role},
+			}, newAdmin())
+
+			assertErrorResponse(t, rec, tc.code, tc.msg)
+			s.u.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// adminRequest serves a request of given method, uri and body with an access token of given currentUser if not nil.
+// The currentUser is also returned from FindByID to check the role.
+func (s *TestSuite) adminRequest(method, uri string, body map[string]interface{}, currentUser *userModel.User) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		reader = toJsonReader(body)
+	}
+	req, _ := http.NewRequest(method, uri, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if currentUser != nil {
+		token, _ := s.h.makeJWTToken(currentUser)
+		authutils.SetAuthToken(req, token)
+		s.u.On("FindByID", mock.Anything, currentUser.ID).Return(copyUser(currentUser), nil).Maybe()
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newAdmin() *userModel.User {
+	u := newUser(100, "admin", false)
+	u.Role = authutils.RoleAdmin
+	return u
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name IdentityDB --filename identity_mock.go
+type IdentityDB interface {
+	// FindIdentity returns a model.UserIdentity matched by given provider and subject.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+
+	// SaveIdentity links a given identity i to an existing user.
+	// database.ErrKeyConflict will be returned if the identity is already linked.
+	// database.ErrFKConstraint will be returned if not exist user id.
+	SaveIdentity(ctx context.Context, i *model.UserIdentity) error
+
+	// SaveUserWithIdentity saves a given new user u and links a given identity i to the user.
+	// database.ErrKeyConflict will be returned if duplicate email, username or identity.
+	SaveUserWithIdentity(ctx context.Context, u *model.User, i *model.UserIdentity) error
+}
+
+// NewIdentityDB creates a new IdentityDB with given gorm.DB
+func NewIdentityDB(_ *config.Config, db *gorm.DB) IdentityDB {
+	return &identityDB{
+		db: db,
+	}
+}
+
+type identityDB struct {
+	db *gorm.DB
+}
+
+func (db *identityDB) FindIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("IdentityDB_FindIdentity try to find an identity", "provider", provider, "subject", subject)
+
+	var i model.UserIdentity
+	if err := db.db.WithContext(ctx).First(&i, "provider = ? AND subject = ?", provider, subject).Error; err != nil {
+		logger.Errorw("IdentityDB_FindIdentity failed to find an identity", "provider", provider, "subject", subject, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &i, nil
+}
+
+func (db *identityDB) SaveIdentity(ctx context.Context, i *model.UserIdentity) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("IdentityDB_SaveIdentity try to save an identity", "userID", i.UserID, "provider", i.Provider, "subject", i.Subject)
+
+	if err := db.db.WithContext(ctx).Create(i).Error; err != nil {
+		logger.Errorw("IdentityDB_SaveIdentity failed to save an identity", "userID", i.UserID, "provider", i.Provider, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *identityDB) SaveUserWithIdentity(ctx context.Context, u *model.User, i *model.UserIdentity) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("IdentityDB_SaveUserWithIdentity try to save an user with identity", "user", u, "provider", i.Provider, "subject", i.Subject)
+
+	err := database.RunInTx(ctx, db.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		if err := txDb.Create(u).Error; err != nil {
+			return err
+		}
+		i.UserID = u.ID
+		return txDb.Create(i).Error
+	})
+	if err != nil {
+		logger.Errorw("IdentityDB_SaveUserWithIdentity failed to save an user with identity", "provider", i.Provider, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
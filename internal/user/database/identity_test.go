@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+)
+
+func (s *Suite) TestSaveIdentity() {
+	i := &model.UserIdentity{UserID: defaultUser.ID, Provider: "google", Subject: "subject1", Email: defaultUser.Email}
+
+	err := s.identityDB.SaveIdentity(context.TODO(), i)
+
+	s.NoError(err)
+	find, err := s.identityDB.FindIdentity(context.TODO(), "google", "subject1")
+	s.NoError(err)
+	s.Equal(i.ID, find.ID)
+	s.Equal(defaultUser.ID, find.UserID)
+	s.Equal(defaultUser.Email, find.Email)
+
+	// same subject of other provider
+	_, err = s.identityDB.FindIdentity(context.TODO(), "github", "subject1")
+	s.Equal(database.ErrRecordNotFound, err)
+
+	err = s.identityDB.SaveIdentity(context.TODO(), &model.UserIdentity{UserID: defaultUser2.ID, Provider: "google", Subject: "subject1"})
+	s.Equal(database.ErrKeyConflict, err)
+
+	err = s.identityDB.SaveIdentity(context.TODO(), &model.UserIdentity{UserID: 10000, Provider: "google", Subject: "subject2"})
+	s.Equal(database.ErrFKConstraint, err)
+}
+
+func (s *Suite) TestSaveUserWithIdentity() {
+	u := newTestUser("identity@gmail.com", false)
+	i := &model.UserIdentity{Provider: "google", Subject: "subject1", Email: u.Email}
+
+	err := s.identityDB.SaveUserWithIdentity(context.TODO(), u, i)
+
+	s.NoError(err)
+	s.NotZero(u.ID)
+	find, err := s.identityDB.FindIdentity(context.TODO(), "google", "subject1")
+	s.NoError(err)
+	s.Equal(u.ID, find.UserID)
+	findUser, err := s.db.FindByID(context.TODO(), u.ID)
+	s.NoError(err)
+	s.Equal(u.Email, findUser.Email)
+}
+
+func (s *Suite) TestSaveUserWithIdentity_Rollback() {
+	s.NoError(s.identityDB.SaveIdentity(context.TODO(), &model.UserIdentity{UserID: defaultUser.ID, Provider: "google", Subject: "subject1"}))
+	u := newTestUser("identity@gmail.com", false)
+
+	err := s.identityDB.SaveUserWithIdentity(context.TODO(), u, &model.UserIdentity{Provider: "google", Subject: "subject1"})
+
+	s.Equal(database.ErrKeyConflict, err)
+	_, err = s.db.FindByEmail(context.TODO(), "identity@gmail.com")
+	s.Equal(database.ErrRecordNotFound, err)
+}
@@ -15,6 +15,20 @@ type UserDB struct {
 	mock.Mock
 }
 
+// Block provides a mock function with given fields: ctx, userID, blockID
+func (_m *UserDB) Block(ctx context.Context, userID uint, blockID uint) error {
+	ret := _m.Called(ctx, userID, blockID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, userID, blockID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindByEmail provides a mock function with given fields: ctx, email
 func (_m *UserDB) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	ret := _m.Called(ctx, email)
@@ -61,6 +75,29 @@ func (_m *UserDB) FindByID(ctx context.Context, userID uint) (*model.User, error
 	return r0, r1
 }
 
+// FindByIDIncludeDisabled provides a mock function with given fields: ctx, userID
+func (_m *UserDB) FindByIDIncludeDisabled(ctx context.Context, userID uint) (*model.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByName provides a mock function with given fields: ctx, username
 func (_m *UserDB) FindByName(ctx context.Context, username string) (*model.User, error) {
 	ret := _m.Called(ctx, username)
@@ -107,6 +144,52 @@ func (_m *UserDB) FindFollowerIDs(ctx context.Context, userID uint) ([]uint, err
 	return r0, r1
 }
 
+// FindMutedIDs provides a mock function with given fields: ctx, userID
+func (_m *UserDB) FindMutedIDs(ctx context.Context, userID uint) ([]uint, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []uint
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []uint); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUsers provides a mock function with given fields: ctx, query, offset, limit
+func (_m *UserDB) FindUsers(ctx context.Context, query model.UserQuery, offset int, limit int) (*model.Users, error) {
+	ret := _m.Called(ctx, query, offset, limit)
+
+	var r0 *model.Users
+	if rf, ok := ret.Get(0).(func(context.Context, model.UserQuery, int, int) *model.Users); ok {
+		r0 = rf(ctx, query, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Users)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, model.UserQuery, int, int) error); ok {
+		r1 = rf(ctx, query, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Follow provides a mock function with given fields: ctx, userID, followerID
 func (_m *UserDB) Follow(ctx context.Context, userID uint, followerID uint) error {
 	ret := _m.Called(ctx, userID, followerID)
@@ -121,6 +204,48 @@ func (_m *UserDB) Follow(ctx context.Context, userID uint, followerID uint) erro
 	return r0
 }
 
+// IsBlocked provides a mock function with given fields: ctx, userID, blockID
+func (_m *UserDB) IsBlocked(ctx context.Context, userID uint, blockID uint) (bool, error) {
+	ret := _m.Called(ctx, userID, blockID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) bool); ok {
+		r0 = rf(ctx, userID, blockID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, userID, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsDisabled provides a mock function with given fields: ctx, userID
+func (_m *UserDB) IsDisabled(ctx context.Context, userID uint) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uint) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsFollow provides a mock function with given fields: ctx, userID, followerID
 func (_m *UserDB) IsFollow(ctx context.Context, userID uint, followerID uint) (bool, error) {
 	ret := _m.Called(ctx, userID, followerID)
@@ -165,6 +290,20 @@ func (_m *UserDB) IsFollows(ctx context.Context, userID uint, followerIDs []uint
 	return r0, r1
 }
 
+// Mute provides a mock function with given fields: ctx, userID, muteID
+func (_m *UserDB) Mute(ctx context.Context, userID uint, muteID uint) error {
+	ret := _m.Called(ctx, userID, muteID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, userID, muteID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, u
 func (_m *UserDB) Save(ctx context.Context, u *model.User) error {
 	ret := _m.Called(ctx, u)
@@ -179,6 +318,34 @@ func (_m *UserDB) Save(ctx context.Context, u *model.User) error {
 	return r0
 }
 
+// SetDisabled provides a mock function with given fields: ctx, userID, disabled
+func (_m *UserDB) SetDisabled(ctx context.Context, userID uint, disabled bool) error {
+	ret := _m.Called(ctx, userID, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, bool) error); ok {
+		r0 = rf(ctx, userID, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnBlock provides a mock function with given fields: ctx, userID, blockID
+func (_m *UserDB) UnBlock(ctx context.Context, userID uint, blockID uint) error {
+	ret := _m.Called(ctx, userID, blockID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, userID, blockID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnFollow provides a mock function with given fields: ctx, userID, followerID
 func (_m *UserDB) UnFollow(ctx context.Context, userID uint, followerID uint) error {
 	ret := _m.Called(ctx, userID, followerID)
@@ -193,6 +360,20 @@ func (_m *UserDB) UnFollow(ctx context.Context, userID uint, followerID uint) er
 	return r0
 }
 
+// UnMute provides a mock function with given fields: ctx, userID, muteID
+func (_m *UserDB) UnMute(ctx context.Context, userID uint, muteID uint) error {
+	ret := _m.Called(ctx, userID, muteID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, userID, muteID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u
 func (_m *UserDB) Update(ctx context.Context, u *model.User) error {
 	ret := _m.Called(ctx, u)
@@ -206,3 +387,17 @@ func (_m *UserDB) Update(ctx context.Context, u *model.User) error {
 
 	return r0
 }
+
+// UpdateRole provides a mock function with given fields: ctx, userID, role
+func (_m *UserDB) UpdateRole(ctx context.Context, userID uint, role string) error {
+	ret := _m.Called(ctx, userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+)
+
+type RelationDB interface {
+	// Block blocks given blockID by userID and deletes following relations between them.
+	// database.ErrKeyConflict will be returned if already blocked.
+	// database.ErrFKConstraint will be returned if not exist blockID.
+	Block(ctx context.Context, userID, blockID uint) error
+
+	// UnBlock deletes the blocking relation of userID to blockID.
+	// database.ErrRecordNotFound will be returned if userID does not block.
+	UnBlock(ctx context.Context, userID, blockID uint) error
+
+	// IsBlocked returns a true if userID blocks blockID, otherwise false.
+	IsBlocked(ctx context.Context, userID, blockID uint) (bool, error)
+
+	// Mute mutes given muteID by userID.
+	// database.ErrKeyConflict will be returned if already muted.
+	// database.ErrFKConstraint will be returned if not exist muteID.
+	Mute(ctx context.Context, userID, muteID uint) error
+
+	// UnMute deletes the muting relation of userID to muteID.
+	// database.ErrRecordNotFound will be returned if userID does not mute.
+	UnMute(ctx context.Context, userID, muteID uint) error
+
+	// FindMutedIDs returns user ids muted by given user.
+	FindMutedIDs(ctx context.Context, userID uint) ([]uint, error)
+}
+
+func (db *userDB) Block(ctx context.Context, userID, blockID uint) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_Block try to insert the blocking relation", "userID", userID, "blockID", blockID)
+
+	if err := database.RunInTx(ctx, db.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		if err := txDb.WithContext(ctx).Create(&model.Block{
+			UserID:  userID,
+			BlockID: blockID,
+		}).Error; err != nil {
+			return err
+		}
+		return txDb.WithContext(ctx).Unscoped().
+			Where("(user_id = ? AND follow_id = ?) OR (user_id = ? AND follow_id = ?)", userID, blockID, blockID, userID).
+			Delete(new(model.Follow)).Error
+	}); err != nil {
+		logger.Errorw("UserDB_Block failed to insert the blocking relation", "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *userDB) UnBlock(ctx context.Context, userID, blockID uint) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_UnBlock try to delete the blocking relation", "userID", userID, "blockID", blockID)
+
+	result := db.db.WithContext(ctx).Unscoped().Where("user_id = ? AND block_id = ?", userID, blockID).Delete(new(model.Block))
+	if result.Error != nil {
+		logger.Errorw("UserDB_UnBlock failed to delete", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("UserDB_UnBlock failed to delete the relation. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (db *userDB) IsBlocked(ctx context.Context, userID, blockID uint) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_IsBlocked try to check the blocking relation", "userID", userID, "blockID", blockID)
+
+	var count int64
+	if err := db.db.WithContext(ctx).Model(new(model.Block)).
+		Where("user_id = ? AND block_id = ?", userID, blockID).
+		Count(&count).Error; err != nil {
+		logger.Errorw("UserDB_IsBlocked failed to find the blocking relation", "err", err)
+		return false, database.WrapError(err)
+	}
+	return count == 1, nil
+}
+
+func (db *userDB) Mute(ctx context.Context, userID, muteID uint) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_Mute try to insert the muting relation", "userID", userID, "muteID", muteID)
+
+	if err := db.db.WithContext(ctx).Create(&model.Mute{
+		UserID: userID,
+		MuteID: muteID,
+	}).Error; err != nil {
+		logger.Errorw("UserDB_Mute failed to insert the muting relation", "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *userDB) UnMute(ctx context.Context, userID, muteID uint) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_UnMute try to delete the muting relation", "userID", userID, "muteID", muteID)
+
+	result := db.db.WithContext(ctx).Unscoped().Where("user_id = ? AND mute_id = ?", userID, muteID).Delete(new(model.Mute))
+	if result.Error != nil {
+		logger.Errorw("UserDB_UnMute failed to delete", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("UserDB_UnMute failed to delete the relation. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (db *userDB) FindMutedIDs(ctx context.Context, userID uint) ([]uint, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_FindMutedIDs try to find muted ids", "userID", userID)
+
+	var muted []uint
+	if err := db.db.WithContext(ctx).
+		Table(model.TableNameMute).
+		Select("mute_id").
+		Where("user_id = ?", userID).
+		Find(&muted).Error; err != nil {
+		logger.Errorw("UserDB_FindMutedIDs failed to find muted ids", "userID", userID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return muted, nil
+}
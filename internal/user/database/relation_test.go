@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+)
+
+func (s *Suite) TestBlock() {
+	u1, u2, u3 := defaultUser, defaultUser2, defaultUser3
+	s.NoError(s.db.Follow(context.TODO(), u1.ID, u2.ID))
+	s.NoError(s.db.Follow(context.TODO(), u2.ID, u1.ID))
+	s.NoError(s.db.Follow(context.TODO(), u2.ID, u3.ID))
+
+	// when
+	err := s.db.Block(context.TODO(), u1.ID, u2.ID)
+
+	// then
+	s.NoError(err)
+	blocked, err := s.db.IsBlocked(context.TODO(), u1.ID, u2.ID)
+	s.NoError(err)
+	s.True(blocked)
+	blocked, err = s.db.IsBlocked(context.TODO(), u2.ID, u1.ID)
+	s.NoError(err)
+	s.False(blocked)
+	// following relations between u1 and u2 are deleted
+	following, err := s.db.FindFollowerIDs(context.TODO(), u1.ID)
+	s.NoError(err)
+	s.Empty(following)
+	following, err = s.db.FindFollowerIDs(context.TODO(), u2.ID)
+	s.NoError(err)
+	s.Equal([]uint{u3.ID}, following)
+
+	err = s.db.Block(context.TODO(), u1.ID, u2.ID)
+	s.Equal(database.ErrKeyConflict, err)
+
+	err = s.db.Block(context.TODO(), u1.ID, 10000)
+	s.Equal(database.ErrFKConstraint, err)
+}
+
+func (s *Suite) TestUnBlock() {
+	u1, u2 := defaultUser, defaultUser2
+	s.NoError(s.db.Block(context.TODO(), u1.ID, u2.ID))
+
+	s.NoError(s.db.UnBlock(context.TODO(), u1.ID, u2.ID))
+	blocked, err := s.db.IsBlocked(context.TODO(), u1.ID, u2.ID)
+	s.NoError(err)
+	s.False(blocked)
+
+	err = s.db.UnBlock(context.TODO(), u1.ID, u2.ID)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestMute() {
+	u1, u2, u3 := defaultUser, defaultUser2, defaultUser3
+	s.NoError(s.db.Follow(context.TODO(), u1.ID, u2.ID))
+
+	// when
+	s.NoError(s.db.Mute(context.TODO(), u1.ID, u2.ID))
+	s.NoError(s.db.Mute(context.TODO(), u1.ID, u3.ID))
+
+	// then
+	muted, err := s.db.FindMutedIDs(context.TODO(), u1.ID)
+	s.NoError(err)
+	s.ElementsMatch([]uint{u2.ID, u3.ID}, muted)
+	muted, err = s.db.FindMutedIDs(context.TODO(), u2.ID)
+	s.NoError(err)
+	s.Empty(muted)
+	// following relations are kept
+	following, err := s.db.IsFollow(context.TODO(), u1.ID, u2.ID)
+	s.NoError(err)
+	s.True(following)
+
+	err = s.db.Mute(context.TODO(), u1.ID, u2.ID)
+	s.Equal(database.ErrKeyConflict, err)
+
+	err = s.db.Mute(context.TODO(), u1.ID, 10000)
+	s.Equal(database.ErrFKConstraint, err)
+}
+
+func (s *Suite) TestUnMute() {
+	u1, u2 := defaultUser, defaultUser2
+	s.NoError(s.db.Mute(context.TODO(), u1.ID, u2.ID))
+
+	s.NoError(s.db.UnMute(context.TODO(), u1.ID, u2.ID))
+	muted, err := s.db.FindMutedIDs(context.TODO(), u1.ID)
+	s.NoError(err)
+	s.Empty(muted)
+
+	err = s.db.UnMute(context.TODO(), u1.ID, u2.ID)
+	s.Equal(database.ErrRecordNotFound, err)
+}
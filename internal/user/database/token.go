@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockery --name TokenDB --filename token_mock.go
+type TokenDB interface {
+	// SaveRefreshToken saves a given refresh token t.
+	// database.ErrKeyConflict will be returned if duplicate token hash.
+	// database.ErrFKConstraint will be returned if not exist user id.
+	SaveRefreshToken(ctx context.Context, t *model.RefreshToken) error
+
+	// FindRefreshToken returns a model.RefreshToken matched by given token hash.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+
+	// RevokeRefreshToken revokes an active refresh token matched by given user id and token hash.
+	// database.ErrRecordNotFound will be returned if not exists or already revoked.
+	RevokeRefreshToken(ctx context.Context, userID uint, tokenHash string) error
+
+	// RevokeRefreshTokensByUser revokes all active refresh tokens of given user id.
+	RevokeRefreshTokensByUser(ctx context.Context, userID uint) error
+
+	// RevokeAccessToken revokes an access token with given token id(jti) until expiresAt.
+	// Revoking already revoked token is not an error.
+	RevokeAccessToken(ctx context.Context, userID uint, tokenID string, expiresAt time.Time) error
+
+	// IsAccessTokenRevoked returns a true if given token id(jti) is revoked, otherwise false.
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+
+	// SaveUserToken saves a given one-time user token t.
+	// database.ErrKeyConflict will be returned if duplicate token hash.
+	// database.ErrFKConstraint will be returned if not exist user id.
+	SaveUserToken(ctx context.Context, t *model.UserToken) error
+
+	// FindUserToken returns a model.UserToken matched by given purpose and token hash.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindUserToken(ctx context.Context, purpose, tokenHash string) (*model.UserToken, error)
+
+	// UseUserToken marks a given unused user token t as used.
+	// database.ErrRecordNotFound will be returned if not exists or already used.
+	UseUserToken(ctx context.Context, t *model.UserToken) error
+}
+
+// NewTokenDB creates a new TokenDB with given gorm.DB
+func NewTokenDB(_ *config.Config, db *gorm.DB) TokenDB {
+	return &tokenDB{
+		db: db,
+	}
+}
+
+type tokenDB struct {
+	db *gorm.DB
+}
+
+func (db *tokenDB) SaveRefreshToken(ctx context.Context, t *model.RefreshToken) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_SaveRefreshToken try to save a refresh token", "userID", t.UserID, "expiresAt", t.ExpiresAt)
+
+	if err := db.db.WithContext(ctx).Create(t).Error; err != nil {
+		logger.Errorw("TokenDB_SaveRefreshToken failed to save a refresh token", "userID", t.UserID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *tokenDB) FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debug("TokenDB_FindRefreshToken try to find a refresh token")
+
+	var t model.RefreshToken
+	if err := db.db.WithContext(ctx).First(&t, "token_hash = ?", tokenHash).Error; err != nil {
+		logger.Errorw("TokenDB_FindRefreshToken failed to find a refresh token", "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &t, nil
+}
+
+func (db *tokenDB) RevokeRefreshToken(ctx context.Context, userID uint, tokenHash string) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_RevokeRefreshToken try to revoke a refresh token", "userID", userID)
+
+	result := db.db.WithContext(ctx).
+		Model(new(model.RefreshToken)).
+		Where("user_id = ? AND token_hash = ? AND revoked_at IS NULL", userID, tokenHash).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		logger.Errorw("TokenDB_RevokeRefreshToken failed to revoke a refresh token", "userID", userID, "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("TokenDB_RevokeRefreshToken failed to revoke a refresh token. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (db *tokenDB) RevokeRefreshTokensByUser(ctx context.Context, userID uint) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_RevokeRefreshTokensByUser try to revoke refresh tokens", "userID", userID)
+
+	if err := db.db.WithContext(ctx).
+		Model(new(model.RefreshToken)).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		logger.Errorw("TokenDB_RevokeRefreshTokensByUser failed to revoke refresh tokens", "userID", userID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *tokenDB) RevokeAccessToken(ctx context.Context, userID uint, tokenID string, expiresAt time.Time) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_RevokeAccessToken try to revoke an access token", "userID", userID, "tokenID", tokenID)
+
+	err := db.db.WithContext(ctx).Create(&model.RevokedToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}).Error
+	if err != nil {
+		if err := database.WrapError(err); err == database.ErrKeyConflict {
+			return nil
+		}
+		logger.Errorw("TokenDB_RevokeAccessToken failed to revoke an access token", "userID", userID, "tokenID", tokenID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *tokenDB) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_IsAccessTokenRevoked try to check an access token", "tokenID", tokenID)
+
+	var count int64
+	if err := db.db.WithContext(ctx).Model(new(model.RevokedToken)).
+		Where("token_id = ?", tokenID).
+		Count(&count).Error; err != nil {
+		logger.Errorw("TokenDB_IsAccessTokenRevoked failed to find a revoked token", "tokenID", tokenID, "err", err)
+		return false, database.WrapError(err)
+	}
+	return count == 1, nil
+}
+
+func (db *tokenDB) SaveUserToken(ctx context.Context, t *model.UserToken) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_SaveUserToken try to save a user token", "userID", t.UserID, "purpose", t.Purpose, "expiresAt", t.ExpiresAt)
+
+	if err := db.db.WithContext(ctx).Create(t).Error; err != nil {
+		logger.Errorw("TokenDB_SaveUserToken failed to save a user token", "userID", t.UserID, "purpose", t.Purpose, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (db *tokenDB) FindUserToken(ctx context.Context, purpose, tokenHash string) (*model.UserToken, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_FindUserToken try to find a user token", "purpose", purpose)
+
+	var t model.UserToken
+	if err := db.db.WithContext(ctx).First(&t, "purpose = ? AND token_hash = ?", purpose, tokenHash).Error; err != nil {
+		logger.Errorw("TokenDB_FindUserToken failed to find a user token", "purpose", purpose, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &t, nil
+}
+
+func (db *tokenDB) UseUserToken(ctx context.Context, t *model.UserToken) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("TokenDB_UseUserToken try to use a user token", "tokenID", t.ID, "userID", t.UserID)
+
+	now := time.Now()
+	// used_at condition prevents a token from being used concurrently.
+	result := db.db.WithContext(ctx).
+		Model(new(model.UserToken)).
+		Where("token_id = ? AND used_at IS NULL", t.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		logger.Errorw("TokenDB_UseUserToken failed to use a user token", "tokenID", t.ID, "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Error("TokenDB_UseUserToken failed to use a user token. zero rows affected")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	t.UsedAt = &now
+	return nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"time"
+)
+
+// NewTokenCacheDB creates a new TokenDB which stores revoked access tokens to cache with delegate TokenDB.
+// Revoked tokens are kept in cache until the tokens are expired, so middlewares can check revoked tokens
+// without querying the database. Only revoked tokens are cached, so tokens missing from the cache
+// (e.g. evicted or revoked by other processes) are checked with the delegate TokenDB.
+func NewTokenCacheDB(conf *config.Config, c cache.Cache, delegate TokenDB) TokenDB {
+	return &tokenCache{
+		conf:     conf,
+		prefix:   conf.CacheConfig.Prefix,
+		cache:    c,
+		delegate: delegate,
+	}
+}
+
+type tokenCache struct {
+	conf     *config.Config
+	prefix   string
+	cache    cache.Cache
+	delegate TokenDB
+}
+
+func (tc *tokenCache) SaveRefreshToken(ctx context.Context, t *userModel.RefreshToken) error {
+	return tc.delegate.SaveRefreshToken(ctx, t)
+}
+
+func (tc *tokenCache) FindRefreshToken(ctx context.Context, tokenHash string) (*userModel.RefreshToken, error) {
+	return tc.delegate.FindRefreshToken(ctx, tokenHash)
+}
+
+func (tc *tokenCache) RevokeRefreshToken(ctx context.Context, userID uint, tokenHash string) error {
+	return tc.delegate.RevokeRefreshToken(ctx, userID, tokenHash)
+}
+
+func (tc *tokenCache) RevokeRefreshTokensByUser(ctx context.Context, userID uint) error {
+	return tc.delegate.RevokeRefreshTokensByUser(ctx, userID)
+}
+
+func (tc *tokenCache) RevokeAccessToken(ctx context.Context, userID uint, tokenID string, expiresAt time.Time) error {
+	if err := tc.delegate.RevokeAccessToken(ctx, userID, tokenID, expiresAt); err != nil {
+		return err
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := tc.cache.Set(ctx, tc.getRevokedTokenCacheKey(tokenID), userID, ttl); err != nil {
+		logging.FromContext(ctx).Errorw("TokenCache_RevokeAccessToken failed to set a revoked token", "tokenID", tokenID, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (tc *tokenCache) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	logger := logging.FromContext(ctx)
+	key := tc.getRevokedTokenCacheKey(tokenID)
+	exists, err := tc.cache.Exists(ctx, key)
+	if err != nil {
+		logger.Errorw("TokenCache_IsAccessTokenRevoked failed to check a revoked token", "tokenID", tokenID, "err", err)
+	}
+	if exists {
+		return true, nil
+	}
+
+	revoked, err := tc.delegate.IsAccessTokenRevoked(ctx, tokenID)
+	if err != nil || !revoked {
+		return revoked, err
+	}
+	// the token is valid at most access token timeout after revoked.
+	if ttl := tc.conf.JWTConfig.AccessTokenTimeout; ttl > 0 {
+		if err := tc.cache.Set(ctx, key, true, ttl); err != nil {
+			logger.Errorw("TokenCache_IsAccessTokenRevoked failed to set a revoked token", "tokenID", tokenID, "err", err)
+		}
+	}
+	return true, nil
+}
+
+func (tc *tokenCache) SaveUserToken(ctx context.Context, t *userModel.UserToken) error {
+	return tc.delegate.SaveUserToken(ctx, t)
+}
+
+func (tc *tokenCache) FindUserToken(ctx context.Context, purpose, tokenHash string) (*userModel.UserToken, error) {
+	return tc.delegate.FindUserToken(ctx, purpose, tokenHash)
+}
+
+func (tc *tokenCache) UseUserToken(ctx context.Context, t *userModel.UserToken) error {
+	return tc.delegate.UseUserToken(ctx, t)
+}
+
+func (tc *tokenCache) getRevokedTokenCacheKey(tokenID string) string {
+	return fmt.Sprintf("%srevoked_tokens.%s", tc.prefix, tokenID)
+}
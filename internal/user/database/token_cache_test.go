@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+type TokenCacheSuite struct {
+	suite.Suite
+	cacheDB    TokenDB
+	cacheClose cache.CloseFunc
+	cli        redis.UniversalClient
+	dbMock     *mocks.TokenDB
+}
+
+func TestTokenCacheSuite(t *testing.T) {
+	suite.Run(t, new(TokenCacheSuite))
+}
+
+func (s *TokenCacheSuite) SetupTest() {
+	conf, _ := config.Load("")
+	logging.SetConfig(&logging.Config{
+		Encoding:    "console",
+		Level:       zapcore.FatalLevel,
+		Development: false,
+	})
+
+	// setup database mock
+	s.dbMock = &mocks.TokenDB{}
+
+	cli, c, closeFn := cache.NewTestCache(s.T())
+	s.cacheDB = NewTokenCacheDB(conf, c, s.dbMock)
+	s.cacheClose = closeFn
+	s.cli = cli
+}
+
+func (s *TokenCacheSuite) TearDownTest() {
+	if s.cacheClose != nil {
+		s.cacheClose()
+	}
+}
+
+func (s *TokenCacheSuite) TestRevokeAccessToken() {
+	expiresAt := time.Now().Add(time.Hour)
+	s.dbMock.On("RevokeAccessToken", mock.Anything, uint(1), "token-id", expiresAt).Return(nil)
+
+	err := s.cacheDB.RevokeAccessToken(context.TODO(), 1, "token-id", expiresAt)
+
+	s.NoError(err)
+	s.Len(s.getCacheKeys(), 1)
+	revoked, err := s.cacheDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+	s.NoError(err)
+	s.True(revoked)
+	s.dbMock.AssertNotCalled(s.T(), "IsAccessTokenRevoked", mock.Anything, mock.Anything)
+}
+
+func (s *TokenCacheSuite) TestRevokeAccessTokenFail() {
+	expiresAt := time.Now().Add(time.Hour)
+	s.dbMock.On("RevokeAccessToken", mock.Anything, uint(1), "token-id", expiresAt).Return(errors.New("force error"))
+
+	err := s.cacheDB.RevokeAccessToken(context.TODO(), 1, "token-id", expiresAt)
+
+	s.Error(err)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *TokenCacheSuite) TestRevokeAccessTokenCacheFail() {
+	expiresAt := time.Now().Add(time.Hour)
+	s.dbMock.On("RevokeAccessToken", mock.Anything, uint(1), "token-id", expiresAt).Return(nil)
+	s.NoError(s.cli.Close())
+
+	err := s.cacheDB.RevokeAccessToken(context.TODO(), 1, "token-id", expiresAt)
+
+	s.Error(err)
+}
+
+func (s *TokenCacheSuite) TestIsAccessTokenRevokedNotRevoked() {
+	s.dbMock.On("IsAccessTokenRevoked", mock.Anything, "token-id").Return(false, nil)
+
+	revoked, err := s.cacheDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+
+	s.NoError(err)
+	s.False(revoked)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *TokenCacheSuite) TestIsAccessTokenRevokedCacheMiss() {
+	s.dbMock.On("IsAccessTokenRevoked", mock.Anything, "token-id").Return(true, nil).Once()
+
+	// revoked by other processes or evicted from the cache
+	revoked, err := s.cacheDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+
+	s.NoError(err)
+	s.True(revoked)
+	s.Len(s.getCacheKeys(), 1)
+
+	// cached
+	revoked, err = s.cacheDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+	s.NoError(err)
+	s.True(revoked)
+	s.dbMock.AssertNumberOfCalls(s.T(), "IsAccessTokenRevoked", 1)
+}
+
+func (s *TokenCacheSuite) TestIsAccessTokenRevokedFail() {
+	s.dbMock.On("IsAccessTokenRevoked", mock.Anything, "token-id").Return(false, errors.New("force error"))
+
+	_, err := s.cacheDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+
+	s.Error(err)
+	s.Empty(s.getCacheKeys())
+}
+
+func (s *TokenCacheSuite) TestRefreshTokenNoCache() {
+	s.dbMock.On("RevokeRefreshToken", mock.Anything, uint(1), "hash").Return(nil)
+
+	err := s.cacheDB.RevokeRefreshToken(context.TODO(), 1, "hash")
+
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+	s.dbMock.AssertCalled(s.T(), "RevokeRefreshToken", mock.Anything, uint(1), "hash")
+}
+
+func (s *TokenCacheSuite) getCacheKeys() []string {
+	return s.cli.Keys(context.Background(), "*").Val()
+}
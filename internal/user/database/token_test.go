@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"time"
+)
+
+func (s *Suite) TestSaveRefreshToken() {
+	t := newTestRefreshToken("token1", defaultUser.ID)
+
+	err := s.tokenDB.SaveRefreshToken(context.TODO(), t)
+
+	s.NoError(err)
+	find, err := s.tokenDB.FindRefreshToken(context.TODO(), t.TokenHash)
+	s.NoError(err)
+	s.Equal(t.ID, find.ID)
+	s.Equal(t.UserID, find.UserID)
+	s.Nil(find.RevokedAt)
+	s.WithinDuration(t.ExpiresAt, find.ExpiresAt, time.Second)
+
+	err = s.tokenDB.SaveRefreshToken(context.TODO(), newTestRefreshToken("token1", defaultUser.ID))
+	s.Equal(database.ErrKeyConflict, err)
+
+	err = s.tokenDB.SaveRefreshToken(context.TODO(), newTestRefreshToken("token2", 10000))
+	s.Equal(database.ErrFKConstraint, err)
+}
+
+func (s *Suite) TestFindRefreshToken_NotFound() {
+	find, err := s.tokenDB.FindRefreshToken(context.TODO(), "not-exist")
+
+	s.Nil(find)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestRevokeRefreshToken() {
+	t := newTestRefreshToken("token1", defaultUser.ID)
+	s.NoError(s.tokenDB.SaveRefreshToken(context.TODO(), t))
+
+	// mismatch user
+	err := s.tokenDB.RevokeRefreshToken(context.TODO(), defaultUser2.ID, t.TokenHash)
+	s.Equal(database.ErrRecordNotFound, err)
+
+	err = s.tokenDB.RevokeRefreshToken(context.TODO(), defaultUser.ID, t.TokenHash)
+	s.NoError(err)
+	find, err := s.tokenDB.FindRefreshToken(context.TODO(), t.TokenHash)
+	s.NoError(err)
+	s.NotNil(find.RevokedAt)
+
+	// already revoked
+	err = s.tokenDB.RevokeRefreshToken(context.TODO(), defaultUser.ID, t.TokenHash)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestRevokeRefreshTokensByUser() {
+	tokens := []*model.RefreshToken{
+		newTestRefreshToken("token1", defaultUser.ID),
+		newTestRefreshToken("token2", defaultUser.ID),
+		newTestRefreshToken("token3", defaultUser2.ID),
+	}
+	for _, t := range tokens {
+		s.NoError(s.tokenDB.SaveRefreshToken(context.TODO(), t))
+	}
+
+	err := s.tokenDB.RevokeRefreshTokensByUser(context.TODO(), defaultUser.ID)
+
+	s.NoError(err)
+	for _, t := range tokens {
+		find, err := s.tokenDB.FindRefreshToken(context.TODO(), t.TokenHash)
+		s.NoError(err)
+		s.Equal(t.UserID == defaultUser.ID, find.RevokedAt != nil)
+	}
+}
+
+func (s *Suite) TestRevokeAccessToken() {
+	expiresAt := time.Now().Add(time.Hour)
+
+	revoked, err := s.tokenDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+	s.NoError(err)
+	s.False(revoked)
+
+	s.NoError(s.tokenDB.RevokeAccessToken(context.TODO(), defaultUser.ID, "token-id", expiresAt))
+	// revoke again
+	s.NoError(s.tokenDB.RevokeAccessToken(context.TODO(), defaultUser.ID, "token-id", expiresAt))
+
+	revoked, err = s.tokenDB.IsAccessTokenRevoked(context.TODO(), "token-id")
+	s.NoError(err)
+	s.True(revoked)
+}
+
+func (s *Suite) TestSaveUserToken() {
+	t := newTestUserToken(model.TokenPurposeVerifyEmail, "token1", defaultUser.ID)
+
+	err := s.tokenDB.SaveUserToken(context.TODO(), t)
+
+	s.NoError(err)
+	find, err := s.tokenDB.FindUserToken(context.TODO(), model.TokenPurposeVerifyEmail, t.TokenHash)
+	s.NoError(err)
+	s.Equal(t.ID, find.ID)
+	s.Equal(t.UserID, find.UserID)
+	s.Equal(t.Email, find.Email)
+	s.Equal(model.TokenPurposeVerifyEmail, find.Purpose)
+	s.Nil(find.UsedAt)
+	s.WithinDuration(t.ExpiresAt, find.ExpiresAt, time.Second)
+
+	// mismatch purpose
+	_, err = s.tokenDB.FindUserToken(context.TODO(), model.TokenPurposeResetPassword, t.TokenHash)
+	s.Equal(database.ErrRecordNotFound, err)
+
+	err = s.tokenDB.SaveUserToken(context.TODO(), newTestUserToken(model.TokenPurposeResetPassword, "token1", defaultUser.ID))
+	s.Equal(database.ErrKeyConflict, err)
+
+	err = s.tokenDB.SaveUserToken(context.TODO(), newTestUserToken(model.TokenPurposeVerifyEmail, "token2", 10000))
+	s.Equal(database.ErrFKConstraint, err)
+}
+
+func (s *Suite) TestUseUserToken() {
+	t := newTestUserToken(model.TokenPurposeResetPassword, "token1", defaultUser.ID)
+	s.NoError(s.tokenDB.SaveUserToken(context.TODO(), t))
+
+	err := s.tokenDB.UseUserToken(context.TODO(), t)
+
+	s.NoError(err)
+	s.NotNil(t.UsedAt)
+	find, err := s.tokenDB.FindUserToken(context.TODO(), model.TokenPurposeResetPassword, t.TokenHash)
+	s.NoError(err)
+	s.NotNil(find.UsedAt)
+	s.False(find.IsActive(time.Now()))
+
+	// already used
+	err = s.tokenDB.UseUserToken(context.TODO(), find)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func newTestRefreshToken(tokenHash string, userID uint) *model.RefreshToken {
+	return &model.RefreshToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func newTestUserToken(purpose, tokenHash string, userID uint) *model.UserToken {
+	return &model.UserToken{
+		UserID:    userID,
+		Email:     "user@email.com",
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
@@ -7,16 +7,22 @@ import (
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 //go:generate mockery --name UserDB --filename user_mock.go
 type UserDB interface {
+	RelationDB
+
 	// Save saves a given user usr.
 	// database.ErrKeyConflict will be returned if duplicate emails.
 	Save(ctx context.Context, u *model.User) error
 
-	// Update updates given model.User from id.
+	// Update updates given model.User from id. All updatable fields are written even if zero values,
+	// so given user should be loaded first.
 	// database.ErrRecordNotFound will be returned if not exists.
 	Update(ctx context.Context, u *model.User) error
 
@@ -29,6 +35,7 @@ type UserDB interface {
 	FindByName(ctx context.Context, username string) (*model.User, error)
 
 	// FindByEmail returns a model.User if exists with given email.
+	// Unlike other finders, disabled users are also returned so callers can tell them apart.
 	// database.ErrRecordNotFound will be returned if not exists.
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 
@@ -49,6 +56,25 @@ type UserDB interface {
 
 	// FindFollowerIDs returns follower ids from given user.
 	FindFollowerIDs(ctx context.Context, userID uint) ([]uint, error)
+
+	// FindByIDIncludeDisabled returns a model.User if exists with given userID even if the user is disabled.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindByIDIncludeDisabled(ctx context.Context, userID uint) (*model.User, error)
+
+	// FindUsers returns (*model.Users, error) matched by given query including disabled users.
+	// users are ordered by user id.
+	FindUsers(ctx context.Context, query model.UserQuery, offset, limit int) (*model.Users, error)
+
+	// IsDisabled returns a true if given user is disabled or not exists, otherwise false.
+	IsDisabled(ctx context.Context, userID uint) (bool, error)
+
+	// SetDisabled disables or enables given user.
+	// database.ErrRecordNotFound will be returned if not exists.
+	SetDisabled(ctx context.Context, userID uint, disabled bool) error
+
+	// UpdateRole updates the role of given user.
+	// database.ErrRecordNotFound will be returned if not exists.
+	UpdateRole(ctx context.Context, userID uint, role string) error
 }
 
 // NewUserDB creates a new UserDB with given gorm.DB
@@ -58,6 +84,19 @@ func NewUserDB(_ *config.Config, db *gorm.DB) UserDB {
 	}
 }
 
+// FindRole returns the role of given user from given db to check permissions with the current role.
+// An empty role which has no permissions is returned if the user is not found or disabled.
+func FindRole(ctx context.Context, db UserDB, userID uint) (string, error) {
+	u, err := db.FindByID(ctx, userID)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return u.Role, nil
+}
+
 type userDB struct {
 	db *gorm.DB
 }
@@ -80,14 +119,16 @@ func (db *userDB) Update(ctx context.Context, u *model.User) error {
 	result := db.db.WithContext(ctx).
 		Model(new(model.User)).
 		Where("user_id = ?", u.ID).
+		Select("Email", "Name", "Password", "Bio", "Image", "UpdatedAt", "Disabled", "EmailVerifiedAt").
 		Updates(model.User{
-			Email:     u.Email,
-			Name:      u.Name,
-			Password:  u.Password,
-			Bio:       u.Bio,
-			Image:     u.Image,
-			UpdatedAt: time.Now(),
-			Disabled:  u.Disabled,
+			Email:           u.Email,
+			Name:            u.Name,
+			Password:        u.Password,
+			Bio:             u.Bio,
+			Image:           u.Image,
+			UpdatedAt:       time.Now(),
+			Disabled:        u.Disabled,
+			EmailVerifiedAt: u.EmailVerifiedAt,
 		})
 	if result.Error != nil {
 		logger.Errorw("UserDB_Update failed to update an user", "err", result.Error)
@@ -124,9 +165,6 @@ func (db *userDB) FindByEmail(ctx context.Context, email string) (*model.User, e
 		logger.Errorw("UserDB_FindByEmail failed to find an user", "err", err)
 		return nil, database.WrapError(err)
 	}
-	if u.Disabled {
-		return nil, database.WrapError(gorm.ErrRecordNotFound)
-	}
 	return &u, nil
 }
 
@@ -227,3 +265,108 @@ func (db *userDB) FindFollowerIDs(ctx context.Context, userID uint) ([]uint, err
 	}
 	return followers, nil
 }
+
+func (db *userDB) FindByIDIncludeDisabled(ctx context.Context, userID uint) (*model.User, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_FindByIDIncludeDisabled try to find an user", "userID", userID)
+
+	var u model.User
+	if err := db.db.WithContext(ctx).First(&u, "user_id = ?", userID).Error; err != nil {
+		logger.Errorw("UserDB_FindByIDIncludeDisabled failed to find an user", "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &u, nil
+}
+
+func (db *userDB) FindUsers(ctx context.Context, query model.UserQuery, offset, limit int) (*model.Users, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_FindUsers try to find users", "query", query, "offset", offset, "limit", limit)
+
+	q := db.db.WithContext(ctx).Model(new(model.User))
+	if query.Keyword != "" {
+		// "!" is used as an escape character because it has no special meaning in all drivers.
+		keyword := "%" + likeEscaper.Replace(strings.ToLower(query.Keyword)) + "%"
+		q = q.Where("(LOWER(email) LIKE ? ESCAPE '!' OR LOWER(name) LIKE ? ESCAPE '!')", keyword, keyword)
+	}
+	if query.Role != "" {
+		q = q.Where("role = ?", query.Role)
+	}
+	if query.Disabled != nil {
+		q = q.Where("disabled = ?", *query.Disabled)
+	}
+
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		logger.Errorw("UserDB_FindUsers failed to count users", "err", err)
+		return nil, database.WrapError(err)
+	}
+	users := make([]*model.User, 0)
+	if count != 0 {
+		if err := q.Order("user_id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+			logger.Errorw("UserDB_FindUsers failed to find users", "err", err)
+			return nil, database.WrapError(err)
+		}
+	}
+	return &model.Users{
+		Users:      users,
+		UsersCount: count,
+	}, nil
+}
+
+func (db *userDB) IsDisabled(ctx context.Context, userID uint) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_IsDisabled try to check an user", "userID", userID)
+
+	var count int64
+	if err := db.db.WithContext(ctx).Model(new(model.User)).
+		Where("user_id = ? AND disabled = ?", userID, false).
+		Count(&count).Error; err != nil {
+		logger.Errorw("UserDB_IsDisabled failed to find an user", "userID", userID, "err", err)
+		return false, database.WrapError(err)
+	}
+	return count == 0, nil
+}
+
+func (db *userDB) SetDisabled(ctx context.Context, userID uint, disabled bool) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_SetDisabled try to update an user", "userID", userID, "disabled", disabled)
+
+	result := db.db.WithContext(ctx).
+		Model(new(model.User)).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"disabled":   disabled,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		logger.Errorw("UserDB_SetDisabled failed to update an user", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Errorf("UserDB_SetDisabled failed to update an user. rows affected: %d", result.RowsAffected)
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (db *userDB) UpdateRole(ctx context.Context, userID uint, role string) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("UserDB_UpdateRole try to update an user", "userID", userID, "role", role)
+
+	result := db.db.WithContext(ctx).
+		Model(new(model.User)).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		logger.Errorw("UserDB_UpdateRole failed to update an user", "err", result.Error)
+		return database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		logger.Errorf("UserDB_UpdateRole failed to update an user. rows affected: %d", result.RowsAffected)
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -3,20 +3,19 @@ package database
 import (
 	"context"
 	"fmt"
-	"github.com/go-redis/cache/v8"
-	"github.com/go-redis/redis/v8"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"time"
 )
 
-func NewUserCacheDB(conf *config.Config, cli redis.UniversalClient, delegate UserDB) UserDB {
+func NewUserCacheDB(conf *config.Config, c cache.Cache, delegate UserDB) UserDB {
 	return &userCache{
 		conf:     conf,
 		prefix:   conf.CacheConfig.Prefix,
 		ttl:      conf.CacheConfig.TTL,
-		cli:      cli,
-		cache:    cache.New(&cache.Options{Redis: cli}),
+		cache:    c,
 		delegate: delegate,
 	}
 }
@@ -25,8 +24,7 @@ type userCache struct {
 	conf     *config.Config
 	prefix   string
 	ttl      time.Duration
-	cli      redis.UniversalClient
-	cache    *cache.Cache
+	cache    cache.Cache
 	delegate UserDB
 }
 
@@ -34,12 +32,7 @@ func (uc *userCache) Save(ctx context.Context, u *userModel.User) error {
 	if err := uc.delegate.Save(ctx, u); err != nil {
 		return err
 	}
-	_ = uc.cache.Set(&cache.Item{
-		Ctx:   ctx,
-		Key:   uc.getUserCacheKey(u.ID),
-		Value: u,
-		TTL:   uc.ttl,
-	})
+	_ = uc.cache.Set(ctx, uc.getUserCacheKey(u.ID), u, uc.ttl)
 	return nil
 }
 
@@ -47,26 +40,14 @@ func (uc *userCache) Update(ctx context.Context, u *userModel.User) error {
 	if err := uc.delegate.Update(ctx, u); err != nil {
 		return err
 	}
-	_ = uc.cache.Set(&cache.Item{
-		Ctx:   ctx,
-		Key:   uc.getUserCacheKey(u.ID),
-		Value: u,
-		TTL:   uc.ttl,
-		SetXX: true,
-	})
+	_ = uc.cache.SetXX(ctx, uc.getUserCacheKey(u.ID), u, uc.ttl)
 	return nil
 }
 
 func (uc *userCache) FindByID(ctx context.Context, userID uint) (*userModel.User, error) {
 	var find userModel.User
-	err := uc.cache.Once(&cache.Item{
-		Ctx:   ctx,
-		Key:   uc.getUserCacheKey(userID),
-		Value: &find,
-		TTL:   uc.ttl,
-		Do: func(item *cache.Item) (interface{}, error) {
-			return uc.delegate.FindByID(ctx, userID)
-		},
+	err := uc.cache.Once(ctx, uc.getUserCacheKey(userID), &find, uc.ttl, func() (interface{}, error) {
+		return uc.delegate.FindByID(ctx, userID)
 	})
 	if err != nil {
 		return nil, err
@@ -102,6 +83,66 @@ func (uc *userCache) FindFollowerIDs(ctx context.Context, userID uint) ([]uint,
 	return uc.delegate.FindFollowerIDs(ctx, userID)
 }
 
+func (uc *userCache) Block(ctx context.Context, userID, blockID uint) error {
+	return uc.delegate.Block(ctx, userID, blockID)
+}
+
+func (uc *userCache) UnBlock(ctx context.Context, userID, blockID uint) error {
+	return uc.delegate.UnBlock(ctx, userID, blockID)
+}
+
+func (uc *userCache) IsBlocked(ctx context.Context, userID, blockID uint) (bool, error) {
+	return uc.delegate.IsBlocked(ctx, userID, blockID)
+}
+
+func (uc *userCache) Mute(ctx context.Context, userID, muteID uint) error {
+	return uc.delegate.Mute(ctx, userID, muteID)
+}
+
+func (uc *userCache) UnMute(ctx context.Context, userID, muteID uint) error {
+	return uc.delegate.UnMute(ctx, userID, muteID)
+}
+
+func (uc *userCache) FindMutedIDs(ctx context.Context, userID uint) ([]uint, error) {
+	return uc.delegate.FindMutedIDs(ctx, userID)
+}
+
+func (uc *userCache) FindByIDIncludeDisabled(ctx context.Context, userID uint) (*userModel.User, error) {
+	return uc.delegate.FindByIDIncludeDisabled(ctx, userID)
+}
+
+func (uc *userCache) FindUsers(ctx context.Context, query userModel.UserQuery, offset, limit int) (*userModel.Users, error) {
+	return uc.delegate.FindUsers(ctx, query, offset, limit)
+}
+
+// IsDisabled checks given user from cached users of FindByID because disabled users are not found by FindByID.
+func (uc *userCache) IsDisabled(ctx context.Context, userID uint) (bool, error) {
+	_, err := uc.FindByID(ctx, userID)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+func (uc *userCache) SetDisabled(ctx context.Context, userID uint, disabled bool) error {
+	if err := uc.delegate.SetDisabled(ctx, userID, disabled); err != nil {
+		return err
+	}
+	_ = uc.cache.Delete(ctx, uc.getUserCacheKey(userID))
+	return nil
+}
+
+func (uc *userCache) UpdateRole(ctx context.Context, userID uint, role string) error {
+	if err := uc.delegate.UpdateRole(ctx, userID, role); err != nil {
+		return err
+	}
+	_ = uc.cache.Delete(ctx, uc.getUserCacheKey(userID))
+	return nil
+}
+
 func (uc *userCache) getUserCacheKey(id uint) string {
 	return fmt.Sprintf("%susers.%d", uc.prefix, id)
 }
@@ -2,14 +2,17 @@ package database
 
 import (
 	"context"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/cache"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"go.uber.org/zap/zapcore"
+	"math"
 	"testing"
 )
 
@@ -17,6 +20,7 @@ type CacheSuite struct {
 	suite.Suite
 	cacheDB    UserDB
 	cacheClose cache.CloseFunc
+	cli        redis.UniversalClient
 	dbMock     *mocks.UserDB
 }
 
@@ -35,9 +39,10 @@ func (s *CacheSuite) SetupTest() {
 	// setup database mock
 	s.dbMock = &mocks.UserDB{}
 
-	cli, _, closeFn := cache.NewTestCache(s.T())
-	s.cacheDB = NewUserCacheDB(conf, cli, s.dbMock)
+	cli, c, closeFn := cache.NewTestCache(s.T())
+	s.cacheDB = NewUserCacheDB(conf, c, s.dbMock)
 	s.cacheClose = closeFn
+	s.cli = cli
 }
 
 func (s *CacheSuite) TearDownTest() {
@@ -175,7 +180,53 @@ func (s *CacheSuite) TestFindFollowerIDsNoCache() {
 	s.dbMock.AssertCalled(s.T(), "FindFollowerIDs", mock.Anything, userID)
 }
 
+func (s *CacheSuite) TestIsDisabled() {
+	u := defaultUser
+	s.dbMock.On("Save", mock.Anything, mock.Anything).Return(nil)
+	s.dbMock.On("FindByID", mock.Anything, uint(math.MaxInt8)).Return(nil, database.ErrRecordNotFound)
+	s.NoError(s.cacheDB.Save(context.TODO(), u))
+
+	// cached user
+	disabled, err := s.cacheDB.IsDisabled(context.TODO(), u.ID)
+	s.NoError(err)
+	s.False(disabled)
+
+	// not found user
+	disabled, err = s.cacheDB.IsDisabled(context.TODO(), math.MaxInt8)
+	s.NoError(err)
+	s.True(disabled)
+	s.dbMock.AssertNotCalled(s.T(), "IsDisabled", mock.Anything, mock.Anything)
+}
+
+func (s *CacheSuite) TestSetDisabledEvictCache() {
+	u := defaultUser
+	s.dbMock.On("Save", mock.Anything, mock.Anything).Return(nil)
+	s.dbMock.On("SetDisabled", mock.Anything, u.ID, true).Return(nil)
+	s.dbMock.On("FindByID", mock.Anything, u.ID).Return(nil, database.ErrRecordNotFound)
+	s.NoError(s.cacheDB.Save(context.TODO(), u))
+
+	err := s.cacheDB.SetDisabled(context.TODO(), u.ID, true)
+
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+	disabled, err := s.cacheDB.IsDisabled(context.TODO(), u.ID)
+	s.NoError(err)
+	s.True(disabled)
+}
+
+func (s *CacheSuite) TestUpdateRoleEvictCache() {
+	u := defaultUser
+	s.dbMock.On("Save", mock.Anything, mock.Anything).Return(nil)
+	s.dbMock.On("UpdateRole", mock.Anything, u.ID, "moderator").Return(nil)
+	s.NoError(s.cacheDB.Save(context.TODO(), u))
+
+	err := s.cacheDB.UpdateRole(context.TODO(), u.ID, "moderator")
+
+	s.NoError(err)
+	s.Empty(s.getCacheKeys())
+	s.dbMock.AssertCalled(s.T(), "UpdateRole", mock.Anything, u.ID, "moderator")
+}
+
 func (s *CacheSuite) getCacheKeys() []string {
-	cli := s.cacheDB.(*userCache).cli
-	return cli.Keys(context.Background(), "*").Val()
+	return s.cli.Keys(context.Background(), "*").Val()
 }
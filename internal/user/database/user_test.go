@@ -31,6 +31,8 @@ var (
 type Suite struct {
 	suite.Suite
 	db         UserDB
+	tokenDB    TokenDB
+	identityDB IdentityDB
 	originDB   *gorm.DB
 	dbTeardown database.CloseFunc
 }
@@ -48,6 +50,8 @@ func (s *Suite) SetupSuite() {
 	})
 	s.originDB, s.dbTeardown = database.NewTestDatabase(s.T(), true)
 	s.db = NewUserDB(cfg, s.originDB)
+	s.tokenDB = NewTokenDB(cfg, s.originDB)
+	s.identityDB = NewIdentityDB(cfg, s.originDB)
 }
 
 func (s *Suite) TearDownSuite() {
@@ -56,7 +60,13 @@ func (s *Suite) TearDownSuite() {
 
 func (s *Suite) SetupTest() {
 	err := database.DeleteRecordAll(s.T(), s.originDB, []string{
+		model.TableNameRefreshToken, "token_id > 0",
+		model.TableNameUserToken, "token_id > 0",
+		model.TableNameUserIdentity, "identity_id > 0",
+		model.TableNameRevokedToken, "user_id > 0",
 		model.TableNameFollow, "user_id > 0 AND follow_id > 0",
+		model.TableNameBlock, "user_id > 0",
+		model.TableNameMute, "user_id > 0",
 		model.TableNameUser, "user_id > 0",
 	})
 	s.NoError(err)
@@ -212,6 +222,13 @@ func (s *Suite) TestFindByEmail() {
 				assert.WithinDuration(t, defaultUser.UpdatedAt, u.UpdatedAt, time.Minute)
 				assert.False(t, u.Disabled)
 			},
+		}, {
+			name:  "disabled",
+			email: disabledUser.Email,
+			assertFn: func(t *testing.T, u *model.User) {
+				assert.Equal(t, disabledUser.ID, u.ID)
+				assert.True(t, u.Disabled)
+			},
 		}, {
 			name:  "not found",
 			email: "notfound@gmail.com",
@@ -233,6 +250,12 @@ func (s *Suite) TestFindByEmail() {
 }
 
 func (s *Suite) TestUpdate() {
+	verifiedAt := time.Now()
+	verified := *defaultUser2
+	verified.EmailVerifiedAt = &verifiedAt
+	emailChanged := verified
+	emailChanged.Email = "changed@email.com"
+	emailChanged.EmailVerifiedAt = nil
 	cases := []struct {
 		name     string
 		update   *model.User
@@ -259,6 +282,22 @@ func (s *Suite) TestUpdate() {
 				assert.Equal(t, "updatedImage", find.Image)
 				assert.False(t, find.Disabled)
 			},
+		}, {
+			name:   "verify email",
+			update: &verified,
+			assertFn: func(t *testing.T, find *model.User, findErr error) {
+				assert.NoError(t, findErr)
+				assert.True(t, find.IsVerified())
+				assert.WithinDuration(t, verifiedAt, *find.EmailVerifiedAt, time.Second)
+			},
+		}, {
+			name:   "clear email verification",
+			update: &emailChanged,
+			assertFn: func(t *testing.T, find *model.User, findErr error) {
+				assert.NoError(t, findErr)
+				assert.Equal(t, defaultUser2.Name, find.Name)
+				assert.False(t, find.IsVerified())
+			},
 		}, {
 			name: "not found",
 			update: &model.User{
@@ -374,6 +413,173 @@ func (s *Suite) TestFindFollowerIDs() {
 	assert.Empty(s.T(), followers)
 }
 
+func (s *Suite) TestFindByIDIncludeDisabled() {
+	u, err := s.db.FindByIDIncludeDisabled(context.TODO(), disabledUser.ID)
+
+	s.NoError(err)
+	s.Equal(disabledUser.Email, u.Email)
+	s.True(u.Disabled)
+	s.Equal("user", u.Role)
+
+	_, err = s.db.FindByIDIncludeDisabled(context.TODO(), math.MaxInt8)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestFindUsers() {
+	s.NoError(s.db.UpdateRole(context.TODO(), defaultUser2.ID, "moderator"))
+	disabled, enabled := true, false
+
+	cases := []struct {
+		name     string
+		query    model.UserQuery
+		offset   int
+		limit    int
+		expected []*model.User
+		total    int64
+	}{
+		{
+			name:     "all users",
+			limit:    10,
+			expected: []*model.User{defaultUser, defaultUser2, defaultUser3, defaultUser4, defaultUser5, disabledUser},
+			total:    6,
+		}, {
+			name:     "paging",
+			offset:   1,
+			limit:    2,
+			expected: []*model.User{defaultUser2, defaultUser3},
+			total:    6,
+		}, {
+			name:     "keyword of email",
+			query:    model.UserQuery{Keyword: "DEFAULT2@"},
+			limit:    10,
+			expected: []*model.User{defaultUser2},
+			total:    1,
+		}, {
+			name:     "keyword of name",
+			query:    model.UserQuery{Keyword: defaultUser3.Name},
+			limit:    10,
+			expected: []*model.User{defaultUser3},
+			total:    1,
+		}, {
+			name:     "keyword with wildcard",
+			query:    model.UserQuery{Keyword: "%"},
+			limit:    10,
+			expected: []*model.User{},
+			total:    0,
+		}, {
+			name:     "role",
+			query:    model.UserQuery{Role: "moderator"},
+			limit:    10,
+			expected: []*model.User{defaultUser2},
+			total:    1,
+		}, {
+			name:     "disabled",
+			query:    model.UserQuery{Disabled: &disabled},
+			limit:    10,
+			expected: []*model.User{disabledUser},
+			total:    1,
+		}, {
+			name:     "enabled",
+			query:    model.UserQuery{Keyword: "default", Disabled: &enabled},
+			limit:    2,
+			expected: []*model.User{defaultUser, defaultUser2},
+			total:    5,
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			users, err := s.db.FindUsers(context.TODO(), tc.query, tc.offset, tc.limit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.total, users.UsersCount)
+			assert.Len(t, users.Users, len(tc.expected))
+			for i, u := range users.Users {
+				assert.Equal(t, tc.expected[i].ID, u.ID)
+			}
+		})
+	}
+}
+
+func (s *Suite) TestIsDisabled() {
+	cases := []struct {
+		name     string
+		id       uint
+		expected bool
+	}{
+		{
+			name:     "enabled",
+			id:       defaultUser.ID,
+			expected: false,
+		}, {
+			name:     "disabled",
+			id:       disabledUser.ID,
+			expected: true,
+		}, {
+			name:     "not exist",
+			id:       math.MaxInt8,
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			disabled, err := s.db.IsDisabled(context.TODO(), tc.id)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, disabled)
+		})
+	}
+}
+
+func (s *Suite) TestSetDisabled() {
+	// disable
+	s.NoError(s.db.SetDisabled(context.TODO(), defaultUser.ID, true))
+	find, err := s.db.FindByIDIncludeDisabled(context.TODO(), defaultUser.ID)
+	s.NoError(err)
+	s.True(find.Disabled)
+
+	// enable
+	s.NoError(s.db.SetDisabled(context.TODO(), defaultUser.ID, false))
+	find, err = s.db.FindByID(context.TODO(), defaultUser.ID)
+	s.NoError(err)
+	s.False(find.Disabled)
+
+	// not exist
+	err = s.db.SetDisabled(context.TODO(), math.MaxInt8, true)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestUpdateRole() {
+	err := s.db.UpdateRole(context.TODO(), defaultUser.ID, "admin")
+
+	s.NoError(err)
+	find, err := s.db.FindByID(context.TODO(), defaultUser.ID)
+	s.NoError(err)
+	s.Equal("admin", find.Role)
+
+	err = s.db.UpdateRole(context.TODO(), math.MaxInt8, "admin")
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestFindRole() {
+	s.NoError(s.db.UpdateRole(context.TODO(), defaultUser.ID, "moderator"))
+
+	role, err := FindRole(context.TODO(), s.db, defaultUser.ID)
+	s.NoError(err)
+	s.Equal("moderator", role)
+
+	// disabled user
+	role, err = FindRole(context.TODO(), s.db, disabledUser.ID)
+	s.NoError(err)
+	s.Empty(role)
+
+	// not found
+	role, err = FindRole(context.TODO(), s.db, math.MaxInt8)
+	s.NoError(err)
+	s.Empty(role)
+}
+
 func newTestUser(email string, disabled bool) *model.User {
 	idx := atomic.AddInt32(&idx, 1)
 	return &model.User{
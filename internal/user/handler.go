@@ -1,36 +1,62 @@
 package user
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
+	articleDB "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/mail"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/oidc"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
 	userDB "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"time"
 )
 
 type Handler struct {
-	cfg         *config.Config
-	userDB      userDB.UserDB
-	jwtSecret   []byte
-	jwtDuration time.Duration
+	cfg             *config.Config
+	userDB          userDB.UserDB
+	tokenDB         userDB.TokenDB
+	identityDB      userDB.IdentityDB
+	articleDB       articleDB.ArticleDB
+	jwtSecret       []byte
+	jwtDuration     time.Duration
+	refreshDuration time.Duration
+	eventBus        *event.Bus
+	mailer          mail.Mailer
+	oidcProviders   map[string]*oidc.Provider
 }
 
 // NewHandler returns a new Handle from given serverenv.ServerEnv and config.Config.
 func NewHandler(env *serverenv.ServerEnv, conf *config.Config) (*Handler, error) {
 	return &Handler{
-		cfg:         conf,
-		userDB:      env.GetUserDB(),
-		jwtSecret:   []byte(conf.JWTConfig.Secret),
-		jwtDuration: conf.JWTConfig.SessionTimeout,
+		cfg:             conf,
+		userDB:          env.GetUserDB(),
+		tokenDB:         env.GetTokenDB(),
+		identityDB:      env.GetIdentityDB(),
+		articleDB:       env.GetArticleDB(),
+		jwtSecret:       []byte(conf.JWTConfig.Secret),
+		jwtDuration:     conf.JWTConfig.AccessTokenTimeout,
+		refreshDuration: conf.JWTConfig.SessionTimeout,
+		eventBus:        env.GetEventBus(),
+		mailer:          env.GetMailer(),
+		oidcProviders:   oidc.NewProviders(conf),
 	}, nil
 }
 
-// Route configures route given "/api" echo.Group to "/api/users/**, /api/profile/**" paths.
+// Route configures route given "/api" echo.Group to "/api/users/**, /api/profile/**, /api/auth/**, /api/admin/**" paths.
 func (h *Handler) Route(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
 	// anonymous
 	anonymousUserGroup := e.Group("/users")
 	anonymousUserGroup.POST("/login", h.handleSignIn)
 	anonymousUserGroup.POST("", h.handleSignUp)
+	anonymousUserGroup.POST("/refresh", h.handleRefreshToken)
+	anonymousUserGroup.POST("/logout", h.handleLogout, authMiddleware)
+	anonymousUserGroup.POST("/verify-email", h.handleVerifyEmail)
+	anonymousUserGroup.POST("/verify-email/resend", h.handleResendVerification)
+	anonymousUserGroup.POST("/password/forgot", h.handleForgotPassword)
+	anonymousUserGroup.POST("/password/reset", h.handleResetPassword)
 
 	// auth required
 	userGroup := e.Group("/user")
@@ -38,9 +64,33 @@ func (h *Handler) Route(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
 	userGroup.GET("", h.handleCurrentUser)
 	userGroup.PUT("", h.handleUpdateUser)
 
+	// social login
+	authGroup := e.Group("/auth")
+	authGroup.GET("/:provider/login", h.handleOIDCLogin)
+	authGroup.GET("/:provider/callback", h.handleOIDCCallback)
+
 	profileGroup := e.Group("/profiles")
 	profileGroup.Use(authMiddleware)
 	profileGroup.GET("/:username", h.handleGetProfile)
 	profileGroup.POST("/:username/follow", h.handleFollow)
 	profileGroup.DELETE("/:username/follow", h.handleUnfollow)
+	profileGroup.POST("/:username/block", h.handleBlock)
+	profileGroup.DELETE("/:username/block", h.handleUnblock)
+	profileGroup.POST("/:username/mute", h.handleMute)
+	profileGroup.DELETE("/:username/mute", h.handleUnmute)
+
+	// admin
+	adminGroup := e.Group("/admin/users")
+	adminGroup.Use(authMiddleware, authutils.NewPermissionMiddleware(h.findRole, authutils.PermissionManageUsers))
+	adminGroup.GET("", h.handleAdminGetUsers)
+	adminGroup.GET("/:id", h.handleAdminGetUser)
+	adminGroup.POST("/:id/disable", h.handleAdminDisableUser)
+	adminGroup.POST("/:id/enable", h.handleAdminEnableUser)
+	adminGroup.POST("/:id/password/reset", h.handleAdminResetPassword)
+	adminGroup.PUT("/:id/role", h.handleAdminUpdateRole)
+}
+
+// findRole returns the current role of given user for authutils.NewPermissionMiddleware.
+func (h *Handler) findRole(ctx context.Context, userID uint) (string, error) {
+	return userDB.FindRole(ctx, h.userDB, userID)
 }
@@ -2,15 +2,21 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jinzhu/copier"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/tidwall/gjson"
+	articleMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/article/database/mocks"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/mail"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/oidc"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/oidc/oidctest"
 	userMocks "github.com/zacscoding/echo-gorm-realworld-app/internal/user/database/mocks"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
@@ -33,6 +39,12 @@ type TestSuite struct {
 	e *echo.Echo
 	h *Handler
 	u *userMocks.UserDB
+	t *userMocks.TokenDB
+	i *userMocks.IdentityDB
+	a *articleMocks.ArticleDB
+	m *testMailer
+
+	oidcServer *oidctest.Server
 }
 
 func TestRunSuite(t *testing.T) {
@@ -51,24 +63,43 @@ func (s *TestSuite) SetupSuite() {
 	e.Validator = httputils.NewValidator()
 	apiGroup := e.Group("/api")
 
+	s.oidcServer = oidctest.NewServer("client-id", "client-secret")
 	u := &userMocks.UserDB{}
 	h := Handler{
-		cfg:         cfg,
-		userDB:      u,
-		jwtSecret:   []byte(cfg.JWTConfig.Secret),
-		jwtDuration: time.Hour,
+		cfg:             cfg,
+		userDB:          u,
+		jwtSecret:       []byte(cfg.JWTConfig.Secret),
+		jwtDuration:     time.Hour,
+		refreshDuration: 24 * time.Hour,
+		oidcProviders: map[string]*oidc.Provider{
+			"stub": oidc.NewProvider("stub", config.OIDCProviderConfig{
+				Issuer:       s.oidcServer.URL,
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURL:  "http://localhost:8080/api/auth/stub/callback",
+			}, time.Second),
+		},
 	}
-	h.Route(apiGroup, authutils.NewJWTMiddleware(map[string]struct{}{
+	h.Route(apiGroup, authutils.NewJWTMiddlewareWithCheckers(map[string]struct{}{
 		"/api/profiles/:username": {},
-	}, cfg.JWTConfig.Secret))
+	}, cfg.JWTConfig.Secret, func(ctx context.Context, tokenID string) (bool, error) {
+		return s.t.IsAccessTokenRevoked(ctx, tokenID)
+	}, func(ctx context.Context, userID uint) (bool, error) {
+		return s.u.IsDisabled(ctx, userID)
+	}))
 
 	s.e = e
 	s.h = &h
 	s.u = u
 }
 
+func (s *TestSuite) TearDownSuite() {
+	s.oidcServer.Close()
+}
+
 func (s *TestSuite) SetupTest() {
 	s.resetMocks()
+	s.h.cfg.UserConfig.Verification.Required = true
 	defaultUsers = []*userModel.User{}
 	for i := 1; i <= 5; i++ {
 		defaultUsers = append(defaultUsers, newUser(uint(i), fmt.Sprintf("user-%d", i), false))
@@ -77,8 +108,26 @@ func (s *TestSuite) SetupTest() {
 
 func (s *TestSuite) resetMocks() {
 	u := &userMocks.UserDB{}
+	u.On("IsDisabled", mock.Anything, mock.Anything).Return(false, nil).Maybe()
 	s.h.userDB = u
 	s.u = u
+
+	t := &userMocks.TokenDB{}
+	t.On("IsAccessTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	s.h.tokenDB = t
+	s.t = t
+
+	i := &userMocks.IdentityDB{}
+	s.h.identityDB = i
+	s.i = i
+
+	a := &articleMocks.ArticleDB{}
+	s.h.articleDB = a
+	s.a = a
+
+	m := &testMailer{}
+	s.h.mailer = m
+	s.m = m
 }
 
 func assertUserResponse(t *testing.T, res string, expected *userModel.User, isSignUp bool) {
@@ -115,15 +164,31 @@ func assertErrorResponse(t *testing.T, actual *httptest.ResponseRecorder, code i
 
 func newUser(id uint, username string, disable bool) *userModel.User {
 	p, _ := hashutils.EncodePassword(username)
+	verifiedAt := time.Now()
 	return &userModel.User{
-		ID:       id,
-		Email:    fmt.Sprintf("%s@gmail.com", username),
-		Name:     username,
-		Password: p,
-		Bio:      username + " bio",
-		Image:    username + " image",
-		Disabled: disable,
+		ID:              id,
+		Email:           fmt.Sprintf("%s@gmail.com", username),
+		Name:            username,
+		Password:        p,
+		Bio:             username + " bio",
+		Image:           username + " image",
+		Disabled:        disable,
+		EmailVerifiedAt: &verifiedAt,
+	}
+}
+
+// testMailer is a mail.Mailer which keeps sent messages.
+type testMailer struct {
+	messages []*mail.Message
+	err      error
+}
+
+func (tm *testMailer) Send(_ context.Context, m *mail.Message) error {
+	if tm.err != nil {
+		return tm.err
 	}
+	tm.messages = append(tm.messages, m)
+	return nil
 }
 
 func copyUser(u *userModel.User) *userModel.User {
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	TableNameUserIdentity = "user_identities"
+)
+
+// UserIdentity represents database model for external identities linked to users.
+// Subject is an identifier of the user issued by Provider, so (Provider, Subject) is unique.
+type UserIdentity struct {
+	ID        uint      `gorm:"column:identity_id"`
+	UserID    uint      `gorm:"column:user_id"`
+	Provider  string    `gorm:"column:provider"`
+	Subject   string    `gorm:"column:subject"`
+	Email     string    `gorm:"column:email"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (i UserIdentity) TableName() string {
+	return TableNameUserIdentity
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	TableNameRefreshToken = "refresh_tokens"
+	TableNameRevokedToken = "revoked_tokens"
+	TableNameUserToken    = "user_tokens"
+)
+
+// Purposes of user tokens.
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+)
+
+// RefreshToken represents database model for refresh tokens.
+// Only a hash of the token is stored, the raw token is returned to users once.
+type RefreshToken struct {
+	ID        uint       `gorm:"column:token_id"`
+	TokenHash string     `gorm:"column:token_hash"`
+	UserID    uint       `gorm:"column:user_id"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (t RefreshToken) TableName() string {
+	return TableNameRefreshToken
+}
+
+// IsActive returns true if this token is not revoked and not expired, otherwise false.
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RevokedToken represents database model for revoked access tokens.
+// TokenID is an id(jti) of JWT and rows can be deleted after ExpiresAt.
+type RevokedToken struct {
+	TokenID   string    `gorm:"column:token_id;primaryKey"`
+	UserID    uint      `gorm:"column:user_id"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (t RevokedToken) TableName() string {
+	return TableNameRevokedToken
+}
+
+// UserToken represents database model for one-time tokens sent to users by email.
+// Only a hash of the token is stored same as RefreshToken and Email is an address the token was sent to.
+type UserToken struct {
+	ID        uint       `gorm:"column:token_id"`
+	UserID    uint       `gorm:"column:user_id"`
+	Email     string     `gorm:"column:email"`
+	Purpose   string     `gorm:"column:purpose"`
+	TokenHash string     `gorm:"column:token_hash"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (t UserToken) TableName() string {
+	return TableNameUserToken
+}
+
+// IsActive returns true if this token is not used and not expired, otherwise false.
+func (t *UserToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -7,19 +7,23 @@ import (
 const (
 	TableNameUser   = "users"
 	TableNameFollow = "follows"
+	TableNameBlock  = "blocks"
+	TableNameMute   = "mutes"
 )
 
 // User represents database model for users.
 type User struct {
-	ID        uint      `gorm:"column:user_id" json:"-"`
-	Email     string    `gorm:"column:email" json:"email"`
-	Name      string    `gorm:"column:name" json:"username"`
-	Password  string    `gorm:"column:password" json:"-"`
-	Bio       string    `gorm:"column:bio" json:"bio"`
-	Image     string    `gorm:"column:image" json:"image"`
-	CreatedAt time.Time `gorm:"column:created_at" json:"-"`
-	UpdatedAt time.Time `gorm:"column:updated_at" json:"-"`
-	Disabled  bool      `gorm:"column:disabled" json:"-"`
+	ID              uint       `gorm:"column:user_id" json:"-"`
+	Email           string     `gorm:"column:email" json:"email"`
+	Name            string     `gorm:"column:name" json:"username"`
+	Password        string     `gorm:"column:password" json:"-"`
+	Bio             string     `gorm:"column:bio" json:"bio"`
+	Image           string     `gorm:"column:image" json:"image"`
+	CreatedAt       time.Time  `gorm:"column:created_at" json:"-"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at" json:"-"`
+	Disabled        bool       `gorm:"column:disabled" json:"-"`
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"-"`
+	Role            string     `gorm:"column:role;default:user" json:"-"`
 
 	// Following is used for profile not database field.
 	Following bool `gorm:"-"`
@@ -29,6 +33,11 @@ func (u User) TableName() string {
 	return TableNameUser
 }
 
+// IsVerified returns true if the email of this user is verified, otherwise false.
+func (u *User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // ToProfile converts current user to Profile.
 func (u *User) ToProfile() *Profile {
 	return &Profile{
@@ -39,6 +48,20 @@ func (u *User) ToProfile() *Profile {
 	}
 }
 
+// Users represents a list of users with the total count.
+type Users struct {
+	Users      []*User
+	UsersCount int64
+}
+
+// UserQuery represents a query to search users.
+// Keyword is matched to a part of email or username and empty fields are not filtered.
+type UserQuery struct {
+	Keyword  string
+	Role     string
+	Disabled *bool
+}
+
 // Follow represents a database model for following relation between users.
 type Follow struct {
 	User      User `gorm:"foreignkey:UserID"`
@@ -52,6 +75,30 @@ func (f Follow) TableName() string {
 	return TableNameFollow
 }
 
+// Block represents a database model for blocking relation between users.
+// UserID blocks BlockID.
+type Block struct {
+	UserID    uint
+	BlockID   uint
+	CreatedAt time.Time
+}
+
+func (b Block) TableName() string {
+	return TableNameBlock
+}
+
+// Mute represents a database model for muting relation between users.
+// UserID mutes MuteID.
+type Mute struct {
+	UserID    uint
+	MuteID    uint
+	CreatedAt time.Time
+}
+
+func (m Mute) TableName() string {
+	return TableNameMute
+}
+
 // Profile represents user profile resource not database model.
 type Profile struct {
 	Username  string `json:"username"`
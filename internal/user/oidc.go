@@ -0,0 +1,301 @@
+package user
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/oidc"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// oidcStateCookie is a cookie binding a login state to the browser started the login.
+	oidcStateCookie = "oidc_state"
+	// maxUsernameAttempts is the number of attempts to find an unused username for a new user.
+	maxUsernameAttempts = 3
+)
+
+// oidcStateClaims represents claims of a signed login state passed through providers.
+type oidcStateClaims struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	jwt.StandardClaims
+}
+
+// handleOIDCLogin handles "GET /api/auth/:provider/login" to redirect users to the provider.
+func (h *Handler) handleOIDCLogin(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+	)
+
+	// Find a provider
+	provider, ok := h.oidcProviders[c.Param("provider")]
+	if !ok {
+		return httputils.NewNotFoundError(fmt.Sprintf("provider(%s) not found", c.Param("provider")))
+	}
+
+	// Make a state and an authorization url
+	nonce, err := authutils.MakeRefreshToken()
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	state, err := h.makeOIDCState(provider.Name(), nonce)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		logger.Errorw("UserHandler_handleOIDCLogin failed to make an authorization url", "provider", provider.Name(), "err", err)
+		return httputils.NewError(http.StatusBadGateway, "failed to connect to provider")
+	}
+
+	c.SetCookie(h.newOIDCStateCookie(c, state, int(h.cfg.OIDCConfig.StateTTL.Seconds())))
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// handleOIDCCallback handles "GET /api/auth/:provider/callback" to sign in an user authenticated by the provider.
+// The identity of the provider is linked to an user having the same verified email, or a new user is registered.
+func (h *Handler) handleOIDCCallback(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+	)
+
+	// Find a provider
+	provider, ok := h.oidcProviders[c.Param("provider")]
+	if !ok {
+		return httputils.NewNotFoundError(fmt.Sprintf("provider(%s) not found", c.Param("provider")))
+	}
+
+	// Check a state
+	state := c.QueryParam("state")
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err != nil || state == "" || cookie.Value != state {
+		return newInvalidOIDCStateError()
+	}
+	c.SetCookie(h.newOIDCStateCookie(c, "", -1))
+	stateClaims, err := h.parseOIDCState(state)
+	if err != nil || stateClaims.Provider != provider.Name() {
+		return newInvalidOIDCStateError()
+	}
+	if reason := c.QueryParam("error"); reason != "" {
+		return httputils.NewError(http.StatusUnauthorized, fmt.Sprintf("authentication failed: %s", reason))
+	}
+	code := c.QueryParam("code")
+	if code == "" {
+		return httputils.NewBindError("code", "required")
+	}
+
+	// Exchange a code for an ID token
+	claims, err := provider.Exchange(ctx, code, stateClaims.Nonce)
+	if err != nil {
+		logger.Errorw("UserHandler_handleOIDCCallback failed to exchange a code", "provider", provider.Name(), "err", err)
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			return httputils.NewError(http.StatusUnauthorized, "invalid id token")
+		}
+		return httputils.NewError(http.StatusBadGateway, "failed to connect to provider")
+	}
+
+	// Find or register an user of the identity
+	user, err := h.findOrCreateOIDCUser(ctx, provider.Name(), claims)
+	if err != nil {
+		return err
+	}
+	if err := h.checkSignInAllowed(user); err != nil {
+		return err
+	}
+	return h.responseUserWithRefreshToken(c, user)
+}
+
+// findOrCreateOIDCUser returns an user linked to the identity of given claims.
+// If not linked, links the identity to an user having the same email if the email is verified by the provider,
+// otherwise registers a new user.
+func (h *Handler) findOrCreateOIDCUser(ctx context.Context, provider string, claims *oidc.Claims) (*userModel.User, error) {
+	logger := logging.FromContext(ctx)
+
+	// Find a linked user
+	identity, err := h.identityDB.FindIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := h.userDB.FindByID(ctx, identity.UserID)
+		if err != nil {
+			if err == database.ErrRecordNotFound {
+				return nil, httputils.NewError(http.StatusForbidden, "user is disabled")
+			}
+			return nil, httputils.NewInternalServerError(err)
+		}
+		return user, nil
+	}
+	if err != database.ErrRecordNotFound {
+		return nil, httputils.NewInternalServerError(err)
+	}
+	if claims.Email == "" {
+		return nil, httputils.NewStatusUnprocessableEntity("email is required from provider")
+	}
+	identity = &userModel.UserIdentity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	// Link to an user having the same email
+	user, err := h.userDB.FindByEmail(ctx, claims.Email)
+	if err != nil && err != database.ErrRecordNotFound {
+		return nil, httputils.NewInternalServerError(err)
+	}
+	if user != nil {
+		if !claims.EmailVerified {
+			return nil, httputils.NewStatusUnprocessableEntity(fmt.Sprintf("email(%s) is already registered", claims.Email))
+		}
+		if !user.IsVerified() {
+			// The unverified account may be registered by someone else who doesn't own the email,
+			// so replace the password and revoke all sessions before taking it over.
+			password, err := makeRandomPassword()
+			if err != nil {
+				return nil, httputils.NewInternalServerError(err)
+			}
+			now := time.Now()
+			user.Password = password
+			user.EmailVerifiedAt = &now
+			if err := h.userDB.Update(ctx, user); err != nil {
+				return nil, httputils.NewInternalServerError(err)
+			}
+			if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, user.ID); err != nil {
+				return nil, httputils.NewInternalServerError(err)
+			}
+		}
+		identity.UserID = user.ID
+		if err := h.identityDB.SaveIdentity(ctx, identity); err != nil {
+			return nil, httputils.NewInternalServerError(err)
+		}
+		return user, nil
+	}
+
+	// Register a new user with a random password
+	password, err := makeRandomPassword()
+	if err != nil {
+		return nil, httputils.NewInternalServerError(err)
+	}
+	user = &userModel.User{
+		Email:    claims.Email,
+		Password: password,
+		Role:     authutils.RoleUser,
+	}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	username := oidcUsername(claims)
+	for i := 0; ; i++ {
+		user.ID, identity.ID = 0, 0
+		user.Name = username
+		if i > 0 {
+			// the username may be taken, so append a random suffix.
+			suffix, err := randomHex(2)
+			if err != nil {
+				return nil, httputils.NewInternalServerError(err)
+			}
+			user.Name = username + "-" + suffix
+		}
+		err = h.identityDB.SaveUserWithIdentity(ctx, user, identity)
+		if err == nil {
+			break
+		}
+		if err != database.ErrKeyConflict {
+			return nil, httputils.NewInternalServerError(err)
+		}
+		if i+1 == maxUsernameAttempts {
+			return nil, httputils.NewStatusUnprocessableEntity(fmt.Sprintf("duplicate username: %s", username))
+		}
+	}
+	if !user.IsVerified() {
+		if err := h.sendVerificationMail(ctx, user); err != nil {
+			logger.Errorw("UserHandler_findOrCreateOIDCUser failed to send a verification email", "userID", user.ID, "err", err)
+		}
+	}
+	return user, nil
+}
+
+// makeOIDCState returns a signed state of a login with given provider and nonce.
+func (h *Handler) makeOIDCState(provider, nonce string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &oidcStateClaims{
+		Provider: provider,
+		Nonce:    nonce,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(h.cfg.OIDCConfig.StateTTL).Unix(),
+		},
+	})
+	return token.SignedString(h.oidcStateKey())
+}
+
+// parseOIDCState verifies given signed state and returns the claims.
+func (h *Handler) parseOIDCState(state string) (*oidcStateClaims, error) {
+	claims := new(oidcStateClaims)
+	_, err := jwt.ParseWithClaims(state, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return h.oidcStateKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// oidcStateKey returns a key signing login states derived from the jwt secret,
+// so states can't be used as access tokens.
+func (h *Handler) oidcStateKey() []byte {
+	mac := hmac.New(sha256.New, h.jwtSecret)
+	mac.Write([]byte("oidc-state"))
+	return mac.Sum(nil)
+}
+
+// newOIDCStateCookie returns a state cookie scoped to "/api/auth/:provider" paths.
+func (h *Handler) newOIDCStateCookie(c echo.Context, state string, maxAge int) *http.Cookie {
+	path := c.Request().URL.Path
+	return &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     path[:strings.LastIndex(path, "/")],
+		MaxAge:   maxAge,
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// oidcUsername returns a username of a new user from given claims.
+func oidcUsername(claims *oidc.Claims) string {
+	for _, name := range []string{claims.PreferredUsername, claims.Name, strings.Split(claims.Email, "@")[0]} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return "user"
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func newInvalidOIDCStateError() error {
+	return httputils.NewError(http.StatusUnauthorized, "invalid state")
+}
@@ -0,0 +1,288 @@
+package user
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tidwall/gjson"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+func (s *TestSuite) TestHandleOIDCLogin() {
+	// when
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/stub/login", nil)
+	rec := httptest.NewRecorder()
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.Equal(http.StatusFound, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	s.NoError(err)
+	s.True(strings.HasPrefix(location.String(), s.oidcServer.URL+"/authorize"))
+	s.Equal("client-id", location.Query().Get("client_id"))
+	s.NotEmpty(location.Query().Get("nonce"))
+
+	cookies := rec.Result().Cookies()
+	s.Require().Len(cookies, 1)
+	s.Equal(oidcStateCookie, cookies[0].Name)
+	s.Equal(location.Query().Get("state"), cookies[0].Value)
+	s.Equal("/api/auth/stub", cookies[0].Path)
+	s.True(cookies[0].HttpOnly)
+}
+
+func (s *TestSuite) TestHandleOIDCLogin_UnknownProvider() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/unknown/login", nil)
+	rec := httptest.NewRecorder()
+
+	s.e.ServeHTTP(rec, req)
+
+	assertErrorResponse(s.T(), rec, http.StatusNotFound, "provider(unknown) not found")
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_NewUser() {
+	s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+	s.u.On("FindByEmail", mock.Anything, "new@gmail.com").Return(nil, database.ErrRecordNotFound)
+	s.i.On("SaveUserWithIdentity", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*userModel.User).ID = 100
+	}).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.oidcSignIn(map[string]interface{}{
+		"sub":                "subject1",
+		"email":              "new@gmail.com",
+		"email_verified":     true,
+		"preferred_username": "newbie",
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("new@gmail.com", gjson.Get(rec.Body.String(), "user.email").String())
+	s.Equal("newbie", gjson.Get(rec.Body.String(), "user.username").String())
+	s.NotEmpty(gjson.Get(rec.Body.String(), "user.token").String())
+	s.assertRefreshTokenSaved(rec.Body.String(), 100)
+	s.i.AssertCalled(s.T(), "SaveUserWithIdentity", mock.Anything, mock.MatchedBy(func(u *userModel.User) bool {
+		return u.Email == "new@gmail.com" && u.Name == "newbie" && u.IsVerified() && u.Password != ""
+	}), mock.MatchedBy(func(i *userModel.UserIdentity) bool {
+		return i.Provider == "stub" && i.Subject == "subject1" && i.Email == "new@gmail.com"
+	}))
+	s.Empty(s.m.messages)
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_DuplicateUsername() {
+	s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+	s.u.On("FindByEmail", mock.Anything, "new@gmail.com").Return(nil, database.ErrRecordNotFound)
+	s.i.On("SaveUserWithIdentity", mock.Anything, mock.MatchedBy(func(u *userModel.User) bool {
+		return u.Name == "new"
+	}), mock.Anything).Return(database.ErrKeyConflict).Once()
+	s.i.On("SaveUserWithIdentity", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*userModel.User).ID = 100
+	}).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.oidcSignIn(map[string]interface{}{
+		"sub":            "subject1",
+		"email":          "new@gmail.com",
+		"email_verified": true,
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Regexp("^new-[0-9a-f]{4}$", gjson.Get(rec.Body.String(), "user.username").String())
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_LinkedUser() {
+	s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(&userModel.UserIdentity{
+		UserID:   defaultUsers[0].ID,
+		Provider: "stub",
+		Subject:  "subject1",
+	}, nil)
+	s.u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(copyUser(defaultUsers[0]), nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.oidcSignIn(map[string]interface{}{"sub": "subject1"})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], false)
+	s.assertRefreshTokenSaved(rec.Body.String(), defaultUsers[0].ID)
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_LinkByEmail() {
+	s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+	s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+	s.i.On("SaveIdentity", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.oidcSignIn(map[string]interface{}{
+		"sub":            "subject1",
+		"email":          defaultUsers[0].Email,
+		"email_verified": true,
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], false)
+	s.i.AssertCalled(s.T(), "SaveIdentity", mock.Anything, mock.MatchedBy(func(i *userModel.UserIdentity) bool {
+		return i.UserID == defaultUsers[0].ID && i.Provider == "stub" && i.Subject == "subject1"
+	}))
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_LinkByEmailUnverified() {
+	unverified := copyUser(defaultUsers[0])
+	unverified.EmailVerifiedAt = nil
+	s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+	s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(unverified, nil)
+	s.u.On("Update", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("RevokeRefreshTokensByUser", mock.Anything, defaultUsers[0].ID).Return(nil)
+	s.i.On("SaveIdentity", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := s.oidcSignIn(map[string]interface{}{
+		"sub":            "subject1",
+		"email":          defaultUsers[0].Email,
+		"email_verified": true,
+	})
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(u *userModel.User) bool {
+		return u.IsVerified() && u.Password != defaultUsers[0].Password
+	}))
+	s.t.AssertCalled(s.T(), "RevokeRefreshTokensByUser", mock.Anything, defaultUsers[0].ID)
+	s.i.AssertCalled(s.T(), "SaveIdentity", mock.Anything, mock.Anything)
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_Fail() {
+	cases := []struct {
+		name      string
+		claims    map[string]interface{}
+		setupMock func()
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name:   "unverified email of existing user",
+			claims: map[string]interface{}{"sub": "subject1", "email": defaultUsers[0].Email},
+			setupMock: func() {
+				s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+				s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "is already registered",
+		}, {
+			name:   "no email",
+			claims: map[string]interface{}{"sub": "subject1"},
+			setupMock: func() {
+				s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusUnprocessableEntity,
+			msg:  "email is required",
+		}, {
+			name:   "disabled user",
+			claims: map[string]interface{}{"sub": "subject1"},
+			setupMock: func() {
+				s.i.On("FindIdentity", mock.Anything, "stub", "subject1").Return(&userModel.UserIdentity{UserID: defaultUsers[0].ID}, nil)
+				s.u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusForbidden,
+			msg:  "user is disabled",
+		},
+	}
+
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			s.resetMocks()
+			tc.setupMock()
+
+			rec := s.oidcSignIn(tc.claims)
+
+			assertErrorResponse(s.T(), rec, tc.code, tc.msg)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_InvalidState() {
+	cases := []struct {
+		name   string
+		state  string
+		cookie string
+	}{
+		{name: "no cookie", state: "state"},
+		{name: "mismatch cookie", state: "state", cookie: "other"},
+		{name: "not signed state", state: "state", cookie: "state"},
+	}
+
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			req, _ := http.NewRequest(http.MethodGet, "/api/auth/stub/callback?code=code&state="+tc.state, nil)
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: tc.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			s.e.ServeHTTP(rec, req)
+
+			assertErrorResponse(s.T(), rec, http.StatusUnauthorized, "invalid state")
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleOIDCCallback_ProviderError() {
+	// start a login to get a valid state
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/stub/login", nil)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	cookie := rec.Result().Cookies()[0]
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/auth/stub/callback?error=access_denied&state="+cookie.Value, nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+
+	s.e.ServeHTTP(rec, req)
+
+	assertErrorResponse(s.T(), rec, http.StatusUnauthorized, "authentication failed: access_denied")
+}
+
+// oidcSignIn signs in as given claims of the stub provider and returns a response of the callback.
+func (s *TestSuite) oidcSignIn(claims map[string]interface{}) *httptest.ResponseRecorder {
+	s.oidcServer.SetUser(claims)
+
+	// start a login
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/stub/login", nil)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	s.Require().Equal(http.StatusFound, rec.Code)
+	cookies := rec.Result().Cookies()
+
+	// authenticate at the provider
+	cli := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := cli.Get(rec.Header().Get("Location"))
+	s.Require().NoError(err)
+	res.Body.Close()
+	s.Require().Equal(http.StatusFound, res.StatusCode)
+	callback, err := url.Parse(res.Header.Get("Location"))
+	s.Require().NoError(err)
+
+	// back to the callback
+	req, _ = http.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec = httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	assert.NotContains(s.T(), rec.Body.String(), "invalid state")
+	return rec
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
@@ -36,6 +37,7 @@ func (h *Handler) handleGetProfile(c echo.Context) error {
 }
 
 // handleFollow handles "POST /api/user/profile/:username/follow" to update the following relation.
+// users blocked by given user can not follow the user.
 func (h *Handler) handleFollow(c echo.Context) error {
 	var (
 		ctx      = c.Request().Context()
@@ -46,12 +48,24 @@ func (h *Handler) handleFollow(c echo.Context) error {
 		return err
 	}
 	currentUserID := authutils.CurrentUser(c)
+	blocked, err := h.userDB.IsBlocked(ctx, user.ID, currentUserID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	if blocked {
+		return httputils.NewError(http.StatusForbidden, fmt.Sprintf("blocked by %s", user.Name))
+	}
 	if err := h.userDB.Follow(ctx, currentUserID, user.ID); err != nil {
 		if err == database.ErrKeyConflict {
 			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("user already following %s", user.Name))
 		}
 		return httputils.NewInternalServerError(err)
 	}
+	h.eventBus.Publish(ctx, &event.Event{
+		Type:    event.TypeUserFollowed,
+		ActorID: currentUserID,
+		UserID:  user.ID,
+	})
 	user.Following = true
 	return c.JSON(http.StatusOK, types.ToUserProfile(user))
 }
@@ -77,6 +91,105 @@ func (h *Handler) handleUnfollow(c echo.Context) error {
 	return c.JSON(http.StatusOK, types.ToUserProfile(user))
 }
 
+// handleBlock handles "POST /api/profiles/:username/block" to block an user.
+// The following relations between current user and the user are deleted.
+func (h *Handler) handleBlock(c echo.Context) error {
+	var (
+		ctx      = c.Request().Context()
+		username = c.Param("username")
+	)
+	user, err := h.getUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	currentUserID := authutils.CurrentUser(c)
+	if user.ID == currentUserID {
+		return httputils.NewStatusUnprocessableEntity("can not block yourself")
+	}
+	if err := h.userDB.Block(ctx, currentUserID, user.ID); err != nil {
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("user already blocking %s", user.Name))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	user.Following = false
+	return c.JSON(http.StatusOK, types.ToUserProfile(user))
+}
+
+// handleUnblock handles "DELETE /api/profiles/:username/block" to delete the blocking relation.
+func (h *Handler) handleUnblock(c echo.Context) error {
+	var (
+		ctx      = c.Request().Context()
+		username = c.Param("username")
+	)
+	user, err := h.getUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	currentUserID := authutils.CurrentUser(c)
+	if err := h.userDB.UnBlock(ctx, currentUserID, user.ID); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("user already unblocking user(%s)", username))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToUserProfile(user))
+}
+
+// handleMute handles "POST /api/profiles/:username/mute" to mute an user.
+// Articles and comments of muted users are excluded from article list and comments of current user.
+func (h *Handler) handleMute(c echo.Context) error {
+	var (
+		ctx      = c.Request().Context()
+		username = c.Param("username")
+	)
+	user, err := h.getUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	currentUserID := authutils.CurrentUser(c)
+	if user.ID == currentUserID {
+		return httputils.NewStatusUnprocessableEntity("can not mute yourself")
+	}
+	if err := h.userDB.Mute(ctx, currentUserID, user.ID); err != nil {
+		if err == database.ErrKeyConflict {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("user already muting %s", user.Name))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return h.profileResponse(c, user)
+}
+
+// handleUnmute handles "DELETE /api/profiles/:username/mute" to delete the muting relation.
+func (h *Handler) handleUnmute(c echo.Context) error {
+	var (
+		ctx      = c.Request().Context()
+		username = c.Param("username")
+	)
+	user, err := h.getUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	currentUserID := authutils.CurrentUser(c)
+	if err := h.userDB.UnMute(ctx, currentUserID, user.ID); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewStatusUnprocessableEntity(fmt.Sprintf("user already unmuting user(%s)", username))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return h.profileResponse(c, user)
+}
+
+// profileResponse writes a profile of given user with the following relation of current user.
+func (h *Handler) profileResponse(c echo.Context, user *userModel.User) error {
+	isFollow, err := h.userDB.IsFollow(c.Request().Context(), authutils.CurrentUser(c), user.ID)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	user.Following = isFollow
+	return c.JSON(http.StatusOK, types.ToUserProfile(user))
+}
+
 func (h *Handler) getUserByUsername(ctx context.Context, username string) (*userModel.User, error) {
 	user, err := h.userDB.FindByName(ctx, username)
 	if err != nil {
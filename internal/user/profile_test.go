@@ -98,6 +98,7 @@ func (s *TestSuite) TestHandleFollow() {
 			currentUser: defaultUsers[0],
 			setupMock: func(m *userMocks.UserDB) {
 				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("IsBlocked", mock.Anything, defaultUsers[1].ID, defaultUsers[0].ID).Return(false, nil)
 				m.On("Follow", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(nil)
 			},
 			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
@@ -125,6 +126,7 @@ func (s *TestSuite) TestHandleFollow() {
 			currentUser: defaultUsers[0],
 			setupMock: func(m *userMocks.UserDB) {
 				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("IsBlocked", mock.Anything, defaultUsers[1].ID, defaultUsers[0].ID).Return(false, nil)
 				m.On("Follow", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(database.ErrKeyConflict)
 			},
 			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
@@ -133,6 +135,19 @@ func (s *TestSuite) TestHandleFollow() {
 
 				assertErrorResponse(t, rec, http.StatusUnprocessableEntity, "user already following")
 			},
+		}, {
+			name:        "fail blocked",
+			username:    defaultUsers[1].Name,
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("IsBlocked", mock.Anything, defaultUsers[1].ID, defaultUsers[0].ID).Return(true, nil)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				s.u.AssertNotCalled(t, "Follow", mock.Anything, mock.Anything, mock.Anything)
+
+				assertErrorResponse(t, rec, http.StatusForbidden, "blocked by")
+			},
 		}, {
 			name:     "fail no auth",
 			username: defaultUsers[1].Name,
@@ -241,3 +256,125 @@ func (s *TestSuite) TestHandleUnFollow() {
 		})
 	}
 }
+
+func (s *TestSuite) TestHandleBlockAndMute() {
+	cases := []struct {
+		name        string
+		method      string
+		path        string
+		currentUser *userModel.User
+		setupMock   func(m *userMocks.UserDB)
+		assertFunc  func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB)
+	}{
+		{
+			name:        "block",
+			method:      http.MethodPost,
+			path:        fmt.Sprintf("/api/profiles/%s/block", defaultUsers[1].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("Block", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(nil)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				m.AssertCalled(t, "Block", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID)
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assertProfileResponse(t, rec.Body.String(), defaultUsers[1], false)
+			},
+		}, {
+			name:        "block yourself",
+			method:      http.MethodPost,
+			path:        fmt.Sprintf("/api/profiles/%s/block", defaultUsers[0].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[0].Name).Return(copyUser(defaultUsers[0]), nil)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				assertErrorResponse(t, rec, http.StatusUnprocessableEntity, "can not block yourself")
+			},
+		}, {
+			name:        "already blocking",
+			method:      http.MethodPost,
+			path:        fmt.Sprintf("/api/profiles/%s/block", defaultUsers[1].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("Block", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(database.ErrKeyConflict)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				assertErrorResponse(t, rec, http.StatusUnprocessableEntity, "user already blocking")
+			},
+		}, {
+			name:        "unblock not blocking",
+			method:      http.MethodDelete,
+			path:        fmt.Sprintf("/api/profiles/%s/block", defaultUsers[1].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("UnBlock", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(database.ErrRecordNotFound)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				assertErrorResponse(t, rec, http.StatusUnprocessableEntity, "user already unblocking")
+			},
+		}, {
+			name:        "mute",
+			method:      http.MethodPost,
+			path:        fmt.Sprintf("/api/profiles/%s/mute", defaultUsers[1].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("Mute", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(nil)
+				m.On("IsFollow", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(true, nil)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				m.AssertCalled(t, "Mute", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID)
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assertProfileResponse(t, rec.Body.String(), defaultUsers[1], true)
+			},
+		}, {
+			name:        "unmute",
+			method:      http.MethodDelete,
+			path:        fmt.Sprintf("/api/profiles/%s/mute", defaultUsers[1].Name),
+			currentUser: defaultUsers[0],
+			setupMock: func(m *userMocks.UserDB) {
+				m.On("FindByName", mock.Anything, defaultUsers[1].Name).Return(copyUser(defaultUsers[1]), nil)
+				m.On("UnMute", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(nil)
+				m.On("IsFollow", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID).Return(false, nil)
+			},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				m.AssertCalled(t, "UnMute", mock.Anything, defaultUsers[0].ID, defaultUsers[1].ID)
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assertProfileResponse(t, rec.Body.String(), defaultUsers[1], false)
+			},
+		}, {
+			name:   "fail no auth",
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/profiles/%s/mute", defaultUsers[1].Name),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, m *userMocks.UserDB) {
+				assertErrorResponse(t, rec, http.StatusUnauthorized, "auth required")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			if tc.setupMock != nil {
+				tc.setupMock(s.u)
+			}
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.currentUser != nil {
+				token, _ := s.h.makeJWTToken(tc.currentUser)
+				authutils.SetAuthToken(req, token)
+			}
+			rec := httptest.NewRecorder()
+
+			s.e.ServeHTTP(rec, req)
+
+			tc.assertFunc(t, rec, s.u)
+		})
+	}
+}
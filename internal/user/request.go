@@ -3,6 +3,7 @@ package user
 import (
 	"github.com/labstack/echo/v4"
 	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
 )
@@ -27,6 +28,7 @@ func (r *SignUpRequest) Bind(ctx echo.Context, u *userModel.User) error {
 	u.Name = r.User.Username
 	u.Email = r.User.Email
 	u.Password = password
+	u.Role = authutils.RoleUser
 	return nil
 }
 
@@ -42,6 +44,74 @@ func (r *SignInRequest) Bind(ctx echo.Context) error {
 	return httputils.BindAndValidate(ctx, r)
 }
 
+// RefreshTokenRequest represents request body data of refreshing an access token.
+type RefreshTokenRequest struct {
+	User struct {
+		RefreshToken string `json:"refreshToken" validate:"required"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *RefreshTokenRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
+// LogoutRequest represents request body data of logout.
+// RefreshToken is optional and will be revoked if provided.
+type LogoutRequest struct {
+	User struct {
+		RefreshToken string `json:"refreshToken" validate:"omitempty"`
+	} `json:"user"`
+}
+
+func (r *LogoutRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
+// VerifyEmailRequest represents request body data of verifying an email.
+type VerifyEmailRequest struct {
+	User struct {
+		Token string `json:"token" validate:"required"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *VerifyEmailRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
+// ResendVerificationRequest represents request body data of resending a verification email.
+type ResendVerificationRequest struct {
+	User struct {
+		Email string `json:"email" validate:"required,email"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *ResendVerificationRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
+// ForgotPasswordRequest represents request body data of requesting a password reset email.
+type ForgotPasswordRequest struct {
+	User struct {
+		Email string `json:"email" validate:"required,email"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *ForgotPasswordRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
+// ResetPasswordRequest represents request body data of resetting a password.
+type ResetPasswordRequest struct {
+	User struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *ResetPasswordRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
+
 // UpdateUserRequest represents request body data of updating an user.
 type UpdateUserRequest struct {
 	User struct {
@@ -53,6 +123,7 @@ type UpdateUserRequest struct {
 	} `json:"user" validate:"required"`
 }
 
+// Bind binds the request to given u. The email is regarded as unverified if changed.
 func (r *UpdateUserRequest) Bind(ctx echo.Context, u *userModel.User) error {
 	if err := httputils.BindAndValidate(ctx, r); err != nil {
 		return err
@@ -60,8 +131,9 @@ func (r *UpdateUserRequest) Bind(ctx echo.Context, u *userModel.User) error {
 	if r.User.Username != "" {
 		u.Name = r.User.Username
 	}
-	if r.User.Email != "" {
+	if r.User.Email != "" && r.User.Email != u.Email {
 		u.Email = r.User.Email
+		u.EmailVerifiedAt = nil
 	}
 	if r.User.Password != "" {
 		password, err := hashutils.EncodePassword(r.User.Password)
@@ -78,3 +150,46 @@ func (r *UpdateUserRequest) Bind(ctx echo.Context, u *userModel.User) error {
 	}
 	return nil
 }
+
+// UserListQuery represents query parameters of listing users by admins.
+// Disabled is one of "true" and "false" and all users are listed if empty.
+type UserListQuery struct {
+	Keyword  string `query:"keyword"`
+	Role     string `query:"role" validate:"omitempty,oneof=user moderator admin"`
+	Disabled string `query:"disabled" validate:"omitempty,oneof=true false"`
+	Limit    int    `query:"limit"`
+	Offset   int    `query:"offset"`
+}
+
+func (r *UserListQuery) Bind(ctx echo.Context, q *userModel.UserQuery) error {
+	if err := httputils.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.Limit < 0 {
+		return httputils.NewStatusUnprocessableEntity("limit must greater than or equals to 0")
+	}
+	if r.Offset < 0 {
+		return httputils.NewStatusUnprocessableEntity("offset must greater than or equals to 0")
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	q.Keyword = r.Keyword
+	q.Role = r.Role
+	if r.Disabled != "" {
+		disabled := r.Disabled == "true"
+		q.Disabled = &disabled
+	}
+	return nil
+}
+
+// UpdateRoleRequest represents request body data of changing a role of an user by admins.
+type UpdateRoleRequest struct {
+	User struct {
+		Role string `json:"role" validate:"required,oneof=user moderator admin"`
+	} `json:"user" validate:"required"`
+}
+
+func (r *UpdateRoleRequest) Bind(ctx echo.Context) error {
+	return httputils.BindAndValidate(ctx, r)
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
 	"net/http"
+	"time"
 )
 
 // handleSignUp handles "POST /api/users" to register a new user.
+// If verification is required, no tokens are issued and 202 Accepted is returned
+// until the email is verified, because the user is not allowed to sign in yet.
 func (h *Handler) handleSignUp(c echo.Context) error {
 	var (
 		ctx    = c.Request().Context()
@@ -35,7 +38,15 @@ func (h *Handler) handleSignUp(c echo.Context) error {
 		}
 		return httputils.NewInternalServerError(err)
 	}
-	return h.responseUser(c, &user)
+
+	// Send a verification email. The user can request it again if failed.
+	if err := h.sendVerificationMail(ctx, &user); err != nil {
+		logger.Errorw("UserHandler_handleSignUp failed to send a verification email", "userID", user.ID, "err", err)
+	}
+	if h.cfg.UserConfig.Verification.Required {
+		return c.JSON(http.StatusAccepted, types.ToStatusResponse(types.StatusVerificationRequired, nil))
+	}
+	return h.responseUserWithRefreshToken(c, &user)
 }
 
 // handleSignIn handles "POST /api/users/login" to sign in a user.
@@ -66,7 +77,105 @@ func (h *Handler) handleSignIn(c echo.Context) error {
 		logger.Errorw("UserHandler_handleSignIn failed to sign in with wrong password", "err", err)
 		return httputils.NewStatusUnprocessableEntity("password mismatch")
 	}
-	return h.responseUser(c, user)
+
+	// Check status of the user
+	if err := h.checkSignInAllowed(user); err != nil {
+		return err
+	}
+	return h.responseUserWithRefreshToken(c, user)
+}
+
+// handleRefreshToken handles "POST /api/users/refresh" to issue new tokens from a refresh token.
+// The given refresh token is revoked and a new refresh token is returned(rotation).
+func (h *Handler) handleRefreshToken(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &RefreshTokenRequest{}
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleRefreshToken failed to bind refresh request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Find a refresh token
+	tokenHash := hashutils.HashToken(req.User.RefreshToken)
+	token, err := h.tokenDB.FindRefreshToken(ctx, tokenHash)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return newInvalidRefreshTokenError()
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	if token.RevokedAt != nil {
+		// A revoked refresh token is used again, so revoke all sessions of the user
+		// because the token may be leaked.
+		logger.Errorw("UserHandler_handleRefreshToken revoked refresh token reused", "userID", token.UserID)
+		if err := h.tokenDB.RevokeRefreshTokensByUser(ctx, token.UserID); err != nil {
+			return httputils.NewInternalServerError(err)
+		}
+		return newInvalidRefreshTokenError()
+	}
+	if !token.IsActive(time.Now()) {
+		return newInvalidRefreshTokenError()
+	}
+
+	// Revoke given refresh token
+	if err := h.tokenDB.RevokeRefreshToken(ctx, token.UserID, tokenHash); err != nil {
+		if err == database.ErrRecordNotFound {
+			return newInvalidRefreshTokenError()
+		}
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Find an user of the token
+	user, err := h.userDB.FindByID(ctx, token.UserID)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return newInvalidRefreshTokenError()
+		}
+		return httputils.NewInternalServerError(err)
+	}
+
+	// Check status of the user
+	if err := h.checkSignInAllowed(user); err != nil {
+		return err
+	}
+	return h.responseUserWithRefreshToken(c, user)
+}
+
+// handleLogout handles "POST /api/users/logout" to revoke current access token and given refresh token.
+func (h *Handler) handleLogout(c echo.Context) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+		req    = &LogoutRequest{}
+		claims = authutils.CurrentClaims(c)
+	)
+
+	// Bind request
+	if err := req.Bind(c); err != nil {
+		logger.Errorw("UserHandler_handleLogout failed to bind logout request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Revoke a refresh token if provided
+	if req.User.RefreshToken != "" {
+		err := h.tokenDB.RevokeRefreshToken(ctx, claims.UserID, hashutils.HashToken(req.User.RefreshToken))
+		if err != nil && err != database.ErrRecordNotFound {
+			return httputils.NewInternalServerError(err)
+		}
+	}
+
+	// Revoke current access token
+	if claims.Id != "" {
+		if err := h.tokenDB.RevokeAccessToken(ctx, claims.UserID, claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+			return httputils.NewInternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusRevoked, nil))
 }
 
 // handleCurrentUser handles "GET /api/user" to get current user.
@@ -84,6 +193,7 @@ func (h *Handler) handleCurrentUser(c echo.Context) error {
 }
 
 // handleSignIn handles "PUT /api/user" to update current user.
+// A verification email is sent to a new email if the email is changed.
 func (h *Handler) handleUpdateUser(c echo.Context) error {
 	var (
 		ctx    = c.Request().Context()
@@ -98,6 +208,7 @@ func (h *Handler) handleUpdateUser(c echo.Context) error {
 	}
 
 	// Bind request
+	email := user.Email
 	if err := req.Bind(c, user); err != nil {
 		logger.Errorw("UserHandler_handleUpdateUser failed to bind request", "err", err)
 		return httputils.WrapBindError(err)
@@ -107,6 +218,13 @@ func (h *Handler) handleUpdateUser(c echo.Context) error {
 	if err := h.userDB.Update(ctx, user); err != nil {
 		return httputils.NewInternalServerError(err)
 	}
+
+	// Send a verification email to a new email. The user can request it again if failed.
+	if user.Email != email {
+		if err := h.sendVerificationMail(ctx, user); err != nil {
+			logger.Errorw("UserHandler_handleUpdateUser failed to send a verification email", "userID", user.ID, "err", err)
+		}
+	}
 	return h.responseUser(c, user)
 }
 
@@ -121,6 +239,48 @@ func (h *Handler) responseUser(c echo.Context, user *userModel.User) error {
 	return c.JSON(http.StatusOK, types.ToUserResponse(user, token))
 }
 
+func (h *Handler) responseUserWithRefreshToken(c echo.Context, user *userModel.User) error {
+	var (
+		ctx    = c.Request().Context()
+		logger = logging.FromContext(ctx)
+	)
+	// Make JWT token
+	token, err := h.makeJWTToken(user)
+	if err != nil {
+		logger.Errorw("UserHandler_responseUserWithRefreshToken failed to generate JWT token", "err", err)
+		return httputils.NewInternalServerError(err)
+	}
+	// Make and save a refresh token
+	refreshToken, err := authutils.MakeRefreshToken()
+	if err != nil {
+		logger.Errorw("UserHandler_responseUserWithRefreshToken failed to generate refresh token", "err", err)
+		return httputils.NewInternalServerError(err)
+	}
+	if err := h.tokenDB.SaveRefreshToken(ctx, &userModel.RefreshToken{
+		TokenHash: hashutils.HashToken(refreshToken),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(h.refreshDuration),
+	}); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToUserResponseWithRefreshToken(user, token, refreshToken))
+}
+
+// checkSignInAllowed returns an error if given user is disabled or not verified while verification is required.
+func (h *Handler) checkSignInAllowed(user *userModel.User) error {
+	if user.Disabled {
+		return httputils.NewError(http.StatusForbidden, "user is disabled")
+	}
+	if h.cfg.UserConfig.Verification.Required && !user.IsVerified() {
+		return httputils.NewError(http.StatusForbidden, "email is not verified")
+	}
+	return nil
+}
+
 func (h *Handler) makeJWTToken(u *userModel.User) (string, error) {
-	return authutils.MakeJWTToken(u.ID, h.jwtSecret, h.jwtDuration)
+	return authutils.MakeJWTToken(u.ID, u.Role, h.jwtSecret, h.jwtDuration)
+}
+
+func newInvalidRefreshTokenError() error {
+	return httputils.NewError(http.StatusUnauthorized, "invalid refresh token")
 }
@@ -13,11 +13,18 @@ import (
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/hashutils"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func (s *TestSuite) TestHandleSignUp() {
-	s.u.On("Save", mock.Anything, mock.Anything).Return(nil)
+	s.h.cfg.UserConfig.Verification.Required = false
+	s.u.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*userModel.User).ID = defaultUsers[0].ID
+	}).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
 
 	// when
 	uri := "/api/users"
@@ -43,6 +50,62 @@ func (s *TestSuite) TestHandleSignUp() {
 	}))
 	s.Equal(http.StatusOK, rec.Code)
 	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], true)
+	s.assertRefreshTokenSaved(rec.Body.String(), defaultUsers[0].ID)
+	s.assertUserTokenSent(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "/verify-email?token=")
+}
+
+func (s *TestSuite) TestHandleSignUp_MailError() {
+	s.h.cfg.UserConfig.Verification.Required = false
+	s.u.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*userModel.User).ID = defaultUsers[0].ID
+	}).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+	s.m.err = errors.New("force error")
+
+	// when
+	req, _ := http.NewRequest(http.MethodPost, "/api/users", toJsonReader(map[string]interface{}{
+		"user": map[string]interface{}{
+			"username": defaultUsers[0].Name,
+			"email":    defaultUsers[0].Email,
+			"password": defaultUsers[0].Name,
+		},
+	}))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], true)
+}
+
+func (s *TestSuite) TestHandleSignUp_VerificationRequired() {
+	s.u.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*userModel.User).ID = defaultUsers[0].ID
+	}).Return(nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	req, _ := http.NewRequest(http.MethodPost, "/api/users", toJsonReader(map[string]interface{}{
+		"user": map[string]interface{}{
+			"username": defaultUsers[0].Name,
+			"email":    defaultUsers[0].Email,
+			"password": defaultUsers[0].Name,
+		},
+	}))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.Equal(http.StatusAccepted, rec.Code)
+	s.Equal("verification_required", gjson.Get(rec.Body.String(), "status").String())
+	s.False(gjson.Get(rec.Body.String(), "user.token").Exists())
+	s.t.AssertNotCalled(s.T(), "SaveRefreshToken", mock.Anything, mock.Anything)
+	s.assertUserTokenSent(defaultUsers[0], userModel.TokenPurposeVerifyEmail, "/verify-email?token=")
 }
 
 func (s *TestSuite) TestHandleSignUp_BindError() {
@@ -154,6 +217,7 @@ func (s *TestSuite) TestHandleSignUp_Fail() {
 
 func (s *TestSuite) TestHandleSignIn() {
 	s.u.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(copyUser(defaultUsers[0]), nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
 
 	// when
 	uri := "/api/users/login"
@@ -172,6 +236,7 @@ func (s *TestSuite) TestHandleSignIn() {
 	s.u.AssertCalled(s.T(), "FindByEmail", mock.Anything, defaultUsers[0].Email)
 	s.Equal(http.StatusOK, rec.Code)
 	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], false)
+	s.assertRefreshTokenSaved(rec.Body.String(), defaultUsers[0].ID)
 }
 
 func (s *TestSuite) TestHandleSignIn_BindingError() {
@@ -250,6 +315,28 @@ func (s *TestSuite) TestHandleSignIn_Fail() {
 			},
 			code: http.StatusUnprocessableEntity,
 			msg:  "password mismatch",
+		}, {
+			name:     "disabled user",
+			email:    defaultUsers[0].Email,
+			password: defaultUsers[0].Name,
+			setupMock: func(m *userMocks.UserDB) {
+				u := copyUser(defaultUsers[0])
+				u.Disabled = true
+				m.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(u, nil)
+			},
+			code: http.StatusForbidden,
+			msg:  "user is disabled",
+		}, {
+			name:     "unverified email",
+			email:    defaultUsers[0].Email,
+			password: defaultUsers[0].Name,
+			setupMock: func(m *userMocks.UserDB) {
+				u := copyUser(defaultUsers[0])
+				u.EmailVerifiedAt = nil
+				m.On("FindByEmail", mock.Anything, defaultUsers[0].Email).Return(u, nil)
+			},
+			code: http.StatusForbidden,
+			msg:  "email is not verified",
 		}, {
 			name:     "any error",
 			email:    defaultUsers[0].Email,
@@ -303,6 +390,204 @@ func (s *TestSuite) TestHandleCurrentUser() {
 	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], false)
 }
 
+func (s *TestSuite) TestHandleRefreshToken() {
+	refreshToken := "refresh-token"
+	tokenHash := hashutils.HashToken(refreshToken)
+	s.t.On("FindRefreshToken", mock.Anything, tokenHash).Return(&userModel.RefreshToken{
+		ID:        1,
+		TokenHash: tokenHash,
+		UserID:    defaultUsers[0].ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	s.t.On("RevokeRefreshToken", mock.Anything, defaultUsers[0].ID, tokenHash).Return(nil)
+	s.t.On("SaveRefreshToken", mock.Anything, mock.Anything).Return(nil)
+	s.u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(copyUser(defaultUsers[0]), nil)
+
+	// when
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/refresh", toJsonReader(map[string]interface{}{
+		"user": map[string]interface{}{
+			"refreshToken": refreshToken,
+		},
+	}))
+	req.Header.Set("Content-Type", "application/json")
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.t.AssertCalled(s.T(), "RevokeRefreshToken", mock.Anything, defaultUsers[0].ID, tokenHash)
+	s.Equal(http.StatusOK, rec.Code)
+	assertUserResponse(s.T(), rec.Body.String(), defaultUsers[0], false)
+	s.assertRefreshTokenSaved(rec.Body.String(), defaultUsers[0].ID)
+	s.NotEqual(refreshToken, gjson.Get(rec.Body.String(), "user.refreshToken").String())
+}
+
+func (s *TestSuite) TestHandleRefreshToken_Fail() {
+	refreshToken := "refresh-token"
+	tokenHash := hashutils.HashToken(refreshToken)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	cases := []struct {
+		name      string
+		setupMock func(m *userMocks.TokenDB)
+		// expected
+		code int
+		msg  string
+	}{
+		{
+			name: "token not found",
+			setupMock: func(m *userMocks.TokenDB) {
+				m.On("FindRefreshToken", mock.Anything, tokenHash).Return(nil, database.ErrRecordNotFound)
+			},
+			code: http.StatusUnauthorized,
+			msg:  "invalid refresh token",
+		}, {
+			name: "expired token",
+			setupMock: func(m *userMocks.TokenDB) {
+				m.On("FindRefreshToken", mock.Anything, tokenHash).Return(&userModel.RefreshToken{
+					TokenHash: tokenHash,
+					UserID:    defaultUsers[0].ID,
+					ExpiresAt: time.Now().Add(-time.Minute),
+				}, nil)
+			},
+			code: http.StatusUnauthorized,
+			msg:  "invalid refresh token",
+		}, {
+			name: "reused revoked token",
+			setupMock: func(m *userMocks.TokenDB) {
+				m.On("FindRefreshToken", mock.Anything, tokenHash).Return(&userModel.RefreshToken{
+					TokenHash: tokenHash,
+					UserID:    defaultUsers[0].ID,
+					ExpiresAt: time.Now().Add(time.Hour),
+					RevokedAt: &revokedAt,
+				}, nil)
+				m.On("RevokeRefreshTokensByUser", mock.Anything, defaultUsers[0].ID).Return(nil)
+			},
+			code: http.StatusUnauthorized,
+			msg:  "invalid refresh token",
+		}, {
+			name: "any error",
+			setupMock: func(m *userMocks.TokenDB) {
+				m.On("FindRefreshToken", mock.Anything, tokenHash).Return(nil, errors.New("force error"))
+			},
+			code: http.StatusInternalServerError,
+			msg:  "force error",
+		},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			tc.setupMock(s.t)
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/users/refresh", toJsonReader(map[string]interface{}{
+				"user": map[string]interface{}{
+					"refreshToken": refreshToken,
+				},
+			}))
+			req.Header.Set("Content-Type", "application/json")
+
+			s.e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.code, rec.Code)
+			assert.Contains(t, gjson.Get(rec.Body.String(), "errors.body").String(), tc.msg)
+			s.t.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleRefreshToken_NotAllowed() {
+	refreshToken := "refresh-token"
+	tokenHash := hashutils.HashToken(refreshToken)
+	disabled := copyUser(defaultUsers[0])
+	disabled.Disabled = true
+	unverified := copyUser(defaultUsers[0])
+	unverified.EmailVerifiedAt = nil
+
+	cases := []struct {
+		name string
+		user *userModel.User
+		// expected
+		msg string
+	}{
+		{name: "disabled user", user: disabled, msg: "user is disabled"},
+		{name: "unverified email", user: unverified, msg: "email is not verified"},
+	}
+
+	for _, tc := range cases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.resetMocks()
+			s.t.On("FindRefreshToken", mock.Anything, tokenHash).Return(&userModel.RefreshToken{
+				TokenHash: tokenHash,
+				UserID:    tc.user.ID,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}, nil)
+			s.t.On("RevokeRefreshToken", mock.Anything, tc.user.ID, tokenHash).Return(nil)
+			s.u.On("FindByID", mock.Anything, tc.user.ID).Return(tc.user, nil)
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/users/refresh", toJsonReader(map[string]interface{}{
+				"user": map[string]interface{}{
+					"refreshToken": refreshToken,
+				},
+			}))
+			req.Header.Set("Content-Type", "application/json")
+
+			s.e.ServeHTTP(rec, req)
+
+			assertErrorResponse(t, rec, http.StatusForbidden, tc.msg)
+			s.t.AssertNotCalled(t, "SaveRefreshToken", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *TestSuite) TestHandleLogout() {
+	refreshToken := "refresh-token"
+	s.t.On("RevokeRefreshToken", mock.Anything, defaultUsers[0].ID, hashutils.HashToken(refreshToken)).Return(nil)
+	s.t.On("RevokeAccessToken", mock.Anything, defaultUsers[0].ID, mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/logout", toJsonReader(map[string]interface{}{
+		"user": map[string]interface{}{
+			"refreshToken": refreshToken,
+		},
+	}))
+	token, _ := s.h.makeJWTToken(defaultUsers[0])
+	req.Header.Set("Content-Type", "application/json")
+	authutils.SetAuthToken(req, token)
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("revoked", gjson.Get(rec.Body.String(), "status").String())
+	s.t.AssertCalled(s.T(), "RevokeRefreshToken", mock.Anything, defaultUsers[0].ID, hashutils.HashToken(refreshToken))
+	s.t.AssertCalled(s.T(), "RevokeAccessToken", mock.Anything, defaultUsers[0].ID, mock.MatchedBy(func(tokenID string) bool {
+		return tokenID != ""
+	}), mock.MatchedBy(func(expiresAt time.Time) bool {
+		return expiresAt.After(time.Now())
+	}))
+}
+
+func (s *TestSuite) TestHandleCurrentUser_RevokedToken() {
+	t := &userMocks.TokenDB{}
+	t.On("IsAccessTokenRevoked", mock.Anything, mock.Anything).Return(true, nil)
+	s.h.tokenDB = t
+	s.t = t
+
+	// when
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/user", nil)
+	token, _ := s.h.makeJWTToken(defaultUsers[0])
+	authutils.SetAuthToken(req, token)
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.u.AssertNotCalled(s.T(), "FindByID", mock.Anything, mock.Anything)
+	assertErrorResponse(s.T(), rec, http.StatusUnauthorized, "auth required")
+}
+
 func (s *TestSuite) TestHandleCurrentUser_Fail() {
 	cases := []struct {
 		name          string
@@ -373,6 +658,36 @@ func (s *TestSuite) TestHandleUpdateUser() {
 	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.Anything)
 	s.Equal(http.StatusOK, rec.Code)
 	assertUserResponse(s.T(), rec.Body.String(), updatedUser, false)
+	s.Empty(s.m.messages)
+}
+
+func (s *TestSuite) TestHandleUpdateUser_EmailChanged() {
+	updatedUser := copyUser(defaultUsers[0])
+	updatedUser.Email = "updated@gmail.com"
+	s.u.On("FindByID", mock.Anything, defaultUsers[0].ID).Return(copyUser(defaultUsers[0]), nil)
+	s.u.On("Update", mock.Anything, mock.Anything).Return(nil)
+	s.t.On("SaveUserToken", mock.Anything, mock.Anything).Return(nil)
+
+	// when
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/api/user", toJsonReader(map[string]interface{}{
+		"user": map[string]interface{}{
+			"email": updatedUser.Email,
+		},
+	}))
+	token, _ := s.h.makeJWTToken(updatedUser)
+	req.Header.Set("Content-Type", "application/json")
+	authutils.SetAuthToken(req, token)
+
+	s.e.ServeHTTP(rec, req)
+
+	// then
+	s.u.AssertCalled(s.T(), "Update", mock.Anything, mock.MatchedBy(func(u *userModel.User) bool {
+		return u.Email == updatedUser.Email && !u.IsVerified()
+	}))
+	s.Equal(http.StatusOK, rec.Code)
+	assertUserResponse(s.T(), rec.Body.String(), updatedUser, false)
+	s.assertUserTokenSent(updatedUser, userModel.TokenPurposeVerifyEmail, "/verify-email?token=")
 }
 
 func (s *TestSuite) TestHandleUpdateUser_BindError() {
@@ -484,3 +799,31 @@ func (s *TestSuite) TestHandleUpdateUser_Fail() {
 		})
 	}
 }
+
+func (s *TestSuite) assertRefreshTokenSaved(res string, userID uint) {
+	refreshToken := gjson.Get(res, "user.refreshToken").String()
+	s.NotEmpty(refreshToken)
+	s.t.AssertCalled(s.T(), "SaveRefreshToken", mock.Anything, mock.MatchedBy(func(t *userModel.RefreshToken) bool {
+		return t.UserID == userID &&
+			t.TokenHash == hashutils.HashToken(refreshToken) &&
+			t.ExpiresAt.After(time.Now())
+	}))
+}
+
+func (s *TestSuite) assertUserTokenSent(u *userModel.User, purpose, link string) {
+	var tokenHash string
+	s.t.AssertCalled(s.T(), "SaveUserToken", mock.Anything, mock.MatchedBy(func(t *userModel.UserToken) bool {
+		tokenHash = t.TokenHash
+		return t.UserID == u.ID &&
+			t.Email == u.Email &&
+			t.Purpose == purpose &&
+			t.ExpiresAt.After(time.Now())
+	}))
+	s.Require().Len(s.m.messages, 1)
+	msg := s.m.messages[0]
+	s.Equal(u.Email, msg.To)
+	idx := strings.Index(msg.Body, link)
+	s.Require().True(idx >= 0, msg.Body)
+	token := strings.Fields(msg.Body[idx+len(link):])[0]
+	s.Equal(hashutils.HashToken(token), tokenHash)
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errBlockedAddress is returned when a webhook url is resolved to an address which is not allowed.
+var errBlockedAddress = errors.New("webhook address is not allowed")
+
+// blockedNetworks are networks of loopback, private, link-local, unspecified and other special purpose
+// addresses, so webhooks cannot be used to send requests to internal services.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// isAllowedIP returns true if given ip is not in blockedNetworks.
+func isAllowedIP(ip net.IP) bool {
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkHost returns errBlockedAddress if any address of given host is not allowed.
+// The host is resolved with given resolver if it is not an ip address.
+func checkHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !isAllowedIP(ip) {
+			return errBlockedAddress
+		}
+		return nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !isAllowedIP(addr.IP) {
+			return errBlockedAddress
+		}
+	}
+	return nil
+}
+
+// newClient returns a http.Client sending webhook requests with given timeout.
+// Connections to addresses which are not allowed are refused when dialing, so a host cannot be
+// rebound to an internal address after registration. Redirects are not followed and
+// proxies from the environment are not used for the same reason.
+// Every address is allowed if allowPrivate is true.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isAllowedIP(ip) {
+				return errBlockedAddress
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsAllowedIP(t *testing.T) {
+	cases := []struct {
+		ip      string
+		allowed bool
+	}{
+		{ip: "8.8.8.8", allowed: true},
+		{ip: "2001:4860:4860::8888", allowed: true},
+		{ip: "127.0.0.1", allowed: false},
+		{ip: "10.1.2.3", allowed: false},
+		{ip: "172.16.0.1", allowed: false},
+		{ip: "192.168.0.1", allowed: false},
+		{ip: "169.254.169.254", allowed: false},
+		{ip: "0.0.0.0", allowed: false},
+		{ip: "::1", allowed: false},
+		{ip: "::", allowed: false},
+		{ip: "fd00::1", allowed: false},
+		{ip: "fe80::1", allowed: false},
+		{ip: "::ffff:127.0.0.1", allowed: false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.allowed, isAllowedIP(net.ParseIP(tc.ip)), tc.ip)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	assert.NoError(t, checkHost(context.TODO(), net.DefaultResolver, "8.8.8.8"))
+	assert.Equal(t, errBlockedAddress, checkHost(context.TODO(), net.DefaultResolver, "127.0.0.1"))
+	assert.Equal(t, errBlockedAddress, checkHost(context.TODO(), net.DefaultResolver, "localhost"))
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// blocked when dialing a loopback address
+	_, err := newClient(time.Second, false).Get(srv.URL)
+	assert.True(t, errors.Is(err, errBlockedAddress))
+	assert.Equal(t, "webhook address is not allowed", deliveryError(err))
+
+	// redirects are not followed
+	res, err := newClient(time.Second, true).Get(srv.URL + "/redirect")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:generate mockery --name WebhookDB --filename webhook_mock.go
+type WebhookDB interface {
+	// Save saves a given webhook w.
+	Save(ctx context.Context, w *model.Webhook) error
+
+	// FindWebhooks returns webhooks of given user ordered by created time.
+	FindWebhooks(ctx context.Context, user *userModel.User) ([]*model.Webhook, error)
+
+	// FindWebhookByID returns a webhook of given user matched by given id.
+	// database.ErrRecordNotFound will be returned if not exists.
+	FindWebhookByID(ctx context.Context, user *userModel.User, webhookID uint) (*model.Webhook, error)
+
+	// DeleteWebhook deletes a webhook of given user matched by given id with all deliveries.
+	// database.ErrRecordNotFound will be returned if not exists.
+	DeleteWebhook(ctx context.Context, user *userModel.User, webhookID uint) error
+
+	// SaveDeliveries saves given deliveries.
+	SaveDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+
+	// FindDeliveries returns deliveries of given webhook ordered by created time in descending order.
+	FindDeliveries(ctx context.Context, webhookID uint, offset, limit int) (*model.WebhookDeliveries, error)
+
+	// FindDueDeliveries returns pending deliveries which next attempt time is before now with the webhook.
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+
+	// ClaimDelivery increases attempts of given pending delivery and postpones the next attempt to until.
+	// false will be returned if the delivery is already claimed by others.
+	ClaimDelivery(ctx context.Context, d *model.WebhookDelivery, until time.Time) (bool, error)
+
+	// UpdateDelivery updates a result of the last attempt of given delivery.
+	UpdateDelivery(ctx context.Context, d *model.WebhookDelivery) error
+}
+
+// NewWebhookDB creates a new WebhookDB with given gorm.DB
+func NewWebhookDB(_ *config.Config, db *gorm.DB) WebhookDB {
+	return &webhookDB{
+		db: db,
+	}
+}
+
+type webhookDB struct {
+	db *gorm.DB
+}
+
+func (wdb *webhookDB) Save(ctx context.Context, w *model.Webhook) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("WebhookDB_Save try to save a webhook", "userID", w.UserID, "url", w.URL, "events", w.Events)
+
+	if err := wdb.db.WithContext(ctx).Create(w).Error; err != nil {
+		logger.Errorw("WebhookDB_Save failed to save a webhook", "userID", w.UserID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (wdb *webhookDB) FindWebhooks(ctx context.Context, user *userModel.User) ([]*model.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("WebhookDB_FindWebhooks no user")
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("WebhookDB_FindWebhooks try to find webhooks", "userID", user.ID)
+
+	webhooks := make([]*model.Webhook, 0)
+	if err := wdb.db.WithContext(ctx).
+		Where("user_id = ?", user.ID).
+		Order("created_at ASC, webhook_id ASC").
+		Find(&webhooks).Error; err != nil {
+		logger.Errorw("WebhookDB_FindWebhooks failed to find webhooks", "userID", user.ID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return webhooks, nil
+}
+
+func (wdb *webhookDB) FindWebhookByID(ctx context.Context, user *userModel.User, webhookID uint) (*model.Webhook, error) {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("WebhookDB_FindWebhookByID no user")
+		return nil, database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("WebhookDB_FindWebhookByID try to find a webhook", "userID", user.ID, "webhookID", webhookID)
+
+	var w model.Webhook
+	if err := wdb.db.WithContext(ctx).First(&w, "webhook_id = ? AND user_id = ?", webhookID, user.ID).Error; err != nil {
+		logger.Errorw("WebhookDB_FindWebhookByID failed to find a webhook", "userID", user.ID, "webhookID", webhookID, "err", err)
+		return nil, database.WrapError(err)
+	}
+	return &w, nil
+}
+
+func (wdb *webhookDB) DeleteWebhook(ctx context.Context, user *userModel.User, webhookID uint) error {
+	logger := logging.FromContext(ctx)
+	if user == nil {
+		logger.Error("WebhookDB_DeleteWebhook no user")
+		return database.WrapError(gorm.ErrRecordNotFound)
+	}
+	logger.Debugw("WebhookDB_DeleteWebhook try to delete a webhook", "userID", user.ID, "webhookID", webhookID)
+
+	err := database.RunInTx(ctx, wdb.db, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(txDb *gorm.DB) error {
+		var w model.Webhook
+		if err := txDb.First(&w, "webhook_id = ? AND user_id = ?", webhookID, user.ID).Error; err != nil {
+			return err
+		}
+		if err := txDb.Where("webhook_id = ?", w.ID).Delete(new(model.WebhookDelivery)).Error; err != nil {
+			return err
+		}
+		return txDb.Delete(&w).Error
+	})
+	if err != nil {
+		logger.Errorw("WebhookDB_DeleteWebhook failed to delete a webhook", "userID", user.ID, "webhookID", webhookID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (wdb *webhookDB) SaveDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	logger := logging.FromContext(ctx)
+	if len(deliveries) == 0 {
+		return nil
+	}
+	logger.Debugw("WebhookDB_SaveDeliveries try to save deliveries", "deliveries", len(deliveries))
+
+	if err := wdb.db.WithContext(ctx).Omit("Webhook").Create(&deliveries).Error; err != nil {
+		logger.Errorw("WebhookDB_SaveDeliveries failed to save deliveries", "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
+
+func (wdb *webhookDB) FindDeliveries(ctx context.Context, webhookID uint, offset, limit int) (*model.WebhookDeliveries, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("WebhookDB_FindDeliveries try to find deliveries", "webhookID", webhookID, "offset", offset, "limit", limit)
+
+	db := wdb.db.WithContext(ctx)
+	var total int64
+	if err := db.Model(new(model.WebhookDelivery)).Where("webhook_id = ?", webhookID).Count(&total).Error; err != nil {
+		logger.Errorw("WebhookDB_FindDeliveries failed to fetch total count", "webhookID", webhookID, "err", err)
+		return nil, database.WrapError(err)
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	if limit > 0 && total > int64(offset) {
+		if err := db.Where("webhook_id = ?", webhookID).
+			Order("created_at DESC, delivery_id DESC").
+			Offset(offset).
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			logger.Errorw("WebhookDB_FindDeliveries failed to find deliveries", "webhookID", webhookID, "err", err)
+			return nil, database.WrapError(err)
+		}
+	}
+	return &model.WebhookDeliveries{
+		Deliveries:      deliveries,
+		DeliveriesCount: total,
+	}, nil
+}
+
+func (wdb *webhookDB) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("WebhookDB_FindDueDeliveries try to find due deliveries", "now", now, "limit", limit)
+
+	var deliveries []*model.WebhookDelivery
+	if err := wdb.db.WithContext(ctx).
+		Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", model.DeliveryStatusPending, now).
+		Order("next_attempt_at ASC, delivery_id ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		logger.Errorw("WebhookDB_FindDueDeliveries failed to find due deliveries", "err", err)
+		return nil, database.WrapError(err)
+	}
+	return deliveries, nil
+}
+
+func (wdb *webhookDB) ClaimDelivery(ctx context.Context, d *model.WebhookDelivery, until time.Time) (bool, error) {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("WebhookDB_ClaimDelivery try to claim a delivery", "deliveryID", d.ID, "attempts", d.Attempts)
+
+	// attempts is used as a version to prevent sending a delivery concurrently.
+	result := wdb.db.WithContext(ctx).Model(new(model.WebhookDelivery)).
+		Where("delivery_id = ? AND status = ? AND attempts = ?", d.ID, model.DeliveryStatusPending, d.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        d.Attempts + 1,
+			"next_attempt_at": until,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		logger.Errorw("WebhookDB_ClaimDelivery failed to claim a delivery", "deliveryID", d.ID, "err", result.Error)
+		return false, database.WrapError(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	d.Attempts++
+	d.NextAttemptAt = until
+	return true, nil
+}
+
+func (wdb *webhookDB) UpdateDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	logger := logging.FromContext(ctx)
+	logger.Debugw("WebhookDB_UpdateDelivery try to update a delivery", "deliveryID", d.ID, "status", d.Status)
+
+	if err := wdb.db.WithContext(ctx).Model(d).
+		Select("Status", "NextAttemptAt", "StatusCode", "Error", "DeliveredAt", "UpdatedAt").
+		Updates(d).Error; err != nil {
+		logger.Errorw("WebhookDB_UpdateDelivery failed to update a delivery", "deliveryID", d.ID, "err", err)
+		return database.WrapError(err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type Suite struct {
+	suite.Suite
+	db         WebhookDB
+	originDB   *gorm.DB
+	dbTeardown database.CloseFunc
+	u1, u2     *userModel.User
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	cfg, _ := config.Load("")
+	logging.SetConfig(&logging.Config{
+		Encoding:    "console",
+		Level:       zapcore.FatalLevel,
+		Development: false,
+	})
+	s.originDB, s.dbTeardown = database.NewTestDatabase(s.T(), true)
+	s.db = NewWebhookDB(cfg, s.originDB)
+}
+
+func (s *Suite) TearDownSuite() {
+	s.dbTeardown()
+}
+
+func (s *Suite) SetupTest() {
+	err := database.DeleteRecordAll(s.T(), s.originDB, []string{
+		model.TableNameWebhookDelivery, "delivery_id > 0",
+		model.TableNameWebhook, "webhook_id > 0",
+		userModel.TableNameUser, "user_id > 0",
+	})
+	s.NoError(err)
+
+	s.u1 = &userModel.User{Email: "user1@email.com", Name: "user1", Password: "user1password"}
+	s.u2 = &userModel.User{Email: "user2@email.com", Name: "user2", Password: "user2password"}
+	s.NoError(s.originDB.Create([]*userModel.User{s.u1, s.u2}).Error)
+}
+
+func (s *Suite) TestSave() {
+	w := newWebhook(s.u1, model.EventArticleCreated, model.EventCommentCreated)
+
+	// when
+	err := s.db.Save(context.TODO(), w)
+
+	// then
+	s.NoError(err)
+	s.NotZero(w.ID)
+	find, err := s.db.FindWebhookByID(context.TODO(), s.u1, w.ID)
+	s.NoError(err)
+	s.Equal(w.URL, find.URL)
+	s.Equal(w.Secret, find.Secret)
+	s.Equal([]string{model.EventArticleCreated, model.EventCommentCreated}, find.EventList())
+}
+
+func (s *Suite) TestFindWebhooks() {
+	webhooks := []*model.Webhook{
+		newWebhook(s.u1, model.EventArticleCreated),
+		newWebhook(s.u2, model.EventArticleCreated),
+		newWebhook(s.u1, model.EventCommentCreated),
+	}
+	for _, w := range webhooks {
+		s.NoError(s.db.Save(context.TODO(), w))
+	}
+
+	// when
+	find, err := s.db.FindWebhooks(context.TODO(), s.u1)
+
+	// then
+	s.NoError(err)
+	s.Len(find, 2)
+	s.Equal(webhooks[0].ID, find[0].ID)
+	s.Equal(webhooks[2].ID, find[1].ID)
+}
+
+func (s *Suite) TestFindWebhookByID_OtherUser() {
+	w := newWebhook(s.u1, model.EventArticleCreated)
+	s.NoError(s.db.Save(context.TODO(), w))
+
+	// when
+	find, err := s.db.FindWebhookByID(context.TODO(), s.u2, w.ID)
+
+	// then
+	s.Nil(find)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func (s *Suite) TestDeleteWebhook() {
+	w := newWebhook(s.u1, model.EventArticleCreated)
+	s.NoError(s.db.Save(context.TODO(), w))
+	s.NoError(s.db.SaveDeliveries(context.TODO(), []*model.WebhookDelivery{newDelivery(w, time.Now())}))
+
+	// when delete other's webhook
+	err := s.db.DeleteWebhook(context.TODO(), s.u2, w.ID)
+	// then
+	s.Equal(database.ErrRecordNotFound, err)
+
+	// when
+	err = s.db.DeleteWebhook(context.TODO(), s.u1, w.ID)
+	// then
+	s.NoError(err)
+	_, err = s.db.FindWebhookByID(context.TODO(), s.u1, w.ID)
+	s.Equal(database.ErrRecordNotFound, err)
+	deliveries, err := s.db.FindDeliveries(context.TODO(), w.ID, 0, 10)
+	s.NoError(err)
+	s.EqualValues(0, deliveries.DeliveriesCount)
+}
+
+func (s *Suite) TestFindDeliveries() {
+	now := time.Now()
+	w := newWebhook(s.u1, model.EventArticleCreated)
+	s.NoError(s.db.Save(context.TODO(), w))
+	deliveries := []*model.WebhookDelivery{
+		newDelivery(w, now.Add(-2*time.Minute)),
+		newDelivery(w, now.Add(-time.Minute)),
+		newDelivery(w, now),
+	}
+	for i, d := range deliveries {
+		d.CreatedAt = now.Add(time.Duration(i) * time.Second)
+	}
+	s.NoError(s.db.SaveDeliveries(context.TODO(), deliveries))
+
+	// when
+	find, err := s.db.FindDeliveries(context.TODO(), w.ID, 1, 10)
+
+	// then
+	s.NoError(err)
+	s.EqualValues(3, find.DeliveriesCount)
+	s.Len(find.Deliveries, 2)
+	s.Equal(deliveries[1].ID, find.Deliveries[0].ID)
+	s.Equal(deliveries[0].ID, find.Deliveries[1].ID)
+}
+
+func (s *Suite) TestFindDueDeliveries() {
+	now := time.Now()
+	w := newWebhook(s.u1, model.EventArticleCreated)
+	s.NoError(s.db.Save(context.TODO(), w))
+	deliveries := []*model.WebhookDelivery{
+		newDelivery(w, now.Add(-time.Minute)),
+		newDelivery(w, now.Add(time.Minute)),
+		newDelivery(w, now.Add(-2*time.Minute)),
+		newDelivery(w, now.Add(-3*time.Minute)),
+	}
+	deliveries[3].Status = model.DeliveryStatusSucceeded
+	s.NoError(s.db.SaveDeliveries(context.TODO(), deliveries))
+
+	// when
+	find, err := s.db.FindDueDeliveries(context.TODO(), now, 10)
+
+	// then
+	s.NoError(err)
+	s.Len(find, 2)
+	s.Equal(deliveries[2].ID, find[0].ID)
+	s.Equal(deliveries[0].ID, find[1].ID)
+	s.Equal(w.Secret, find[0].Webhook.Secret)
+}
+
+func (s *Suite) TestClaimAndUpdateDelivery() {
+	now := time.Now()
+	w := newWebhook(s.u1, model.EventArticleCreated)
+	s.NoError(s.db.Save(context.TODO(), w))
+	d := newDelivery(w, now)
+	s.NoError(s.db.SaveDeliveries(context.TODO(), []*model.WebhookDelivery{d}))
+	stale := *d
+
+	// when
+	claimed, err := s.db.ClaimDelivery(context.TODO(), d, now.Add(time.Minute))
+	// then
+	s.NoError(err)
+	s.True(claimed)
+	s.Equal(1, d.Attempts)
+
+	// when claim with stale attempts
+	claimed, err = s.db.ClaimDelivery(context.TODO(), &stale, now.Add(time.Minute))
+	// then
+	s.NoError(err)
+	s.False(claimed)
+
+	// when
+	d.Succeed(now, 200)
+	err = s.db.UpdateDelivery(context.TODO(), d)
+	// then
+	s.NoError(err)
+	find, err := s.db.FindDeliveries(context.TODO(), w.ID, 0, 1)
+	s.NoError(err)
+	s.Equal(model.DeliveryStatusSucceeded, find.Deliveries[0].Status)
+	s.Equal(1, find.Deliveries[0].Attempts)
+	s.Equal(200, find.Deliveries[0].StatusCode)
+	s.NotNil(find.Deliveries[0].DeliveredAt)
+}
+
+func (s *Suite) TestNoUser() {
+	_, err := s.db.FindWebhooks(context.TODO(), nil)
+	s.Equal(database.ErrRecordNotFound, err)
+	_, err = s.db.FindWebhookByID(context.TODO(), nil, 1)
+	s.Equal(database.ErrRecordNotFound, err)
+	err = s.db.DeleteWebhook(context.TODO(), nil, 1)
+	s.Equal(database.ErrRecordNotFound, err)
+}
+
+func newWebhook(u *userModel.User, events ...string) *model.Webhook {
+	w := &model.Webhook{
+		UserID: u.ID,
+		URL:    "http://localhost/hooks",
+		Secret: "secret",
+	}
+	w.SetEvents(events)
+	return w
+}
+
+func newDelivery(w *model.Webhook, next time.Time) *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		WebhookID:     w.ID,
+		Event:         model.EventArticleCreated,
+		Payload:       `{"event":"article.created"}`,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: next,
+	}
+}
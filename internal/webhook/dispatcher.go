@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	webhookDB "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"golang.org/x/sync/errgroup"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Headers of webhook requests.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxDrainBytes is the max number of bytes read from a response body of a webhook.
+const maxDrainBytes = 64 << 10
+
+// claimMargin is added to the request timeout when claiming a delivery,
+// so the delivery is not sent again by others while sending.
+const claimMargin = 10 * time.Second
+
+// Dispatcher sends pending webhook deliveries and retries failed deliveries with backoff.
+type Dispatcher struct {
+	conf      config.WebhookConfig
+	webhookDB webhookDB.WebhookDB
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewDispatcher returns a new Dispatcher from given serverenv.ServerEnv and config.Config.
+func NewDispatcher(env *serverenv.ServerEnv, conf *config.Config) *Dispatcher {
+	return &Dispatcher{
+		conf:      conf.WebhookConfig,
+		webhookDB: env.GetWebhookDB(),
+		client:    newClient(conf.WebhookConfig.Timeout, conf.WebhookConfig.AllowPrivate),
+		now:       time.Now,
+	}
+}
+
+// Start sends due deliveries every interval until given ctx is done.
+// Nothing will be sent if the interval is not positive.
+func (d *Dispatcher) Start(ctx context.Context) {
+	logger := logging.DefaultLogger()
+	if d.conf.Interval <= 0 {
+		logger.Info("WebhookDispatcher is disabled")
+		return
+	}
+	logger.Infow("Starting WebhookDispatcher", "interval", d.conf.Interval)
+
+	ticker := time.NewTicker(d.conf.Interval)
+	defer ticker.Stop()
+	for {
+		if sent, err := d.Dispatch(ctx); err != nil {
+			logger.Errorw("WebhookDispatcher failed to dispatch deliveries", "sent", sent, "err", err)
+		} else if sent != 0 {
+			logger.Infow("WebhookDispatcher dispatched deliveries", "sent", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping WebhookDispatcher")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch sends all pending deliveries which next attempt time is before now in batches
+// with up to conf.Workers concurrent requests and returns the number of sent deliveries regardless of results.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int64, error) {
+	var (
+		now   = d.now()
+		batch = d.conf.Batch
+		total int64
+	)
+	if batch <= 0 {
+		batch = 100
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		deliveries, err := d.webhookDB.FindDueDeliveries(ctx, now, batch)
+		if err != nil {
+			return total, err
+		}
+		sent, err := d.deliverAll(ctx, deliveries)
+		total += sent
+		if err != nil {
+			return total, err
+		}
+		if len(deliveries) < batch {
+			return total, nil
+		}
+	}
+}
+
+// deliverAll sends given deliveries concurrently with up to conf.Workers goroutines
+// and returns the number of sent deliveries. The first error is returned after all deliveries are done.
+func (d *Dispatcher) deliverAll(ctx context.Context, deliveries []*model.WebhookDelivery) (int64, error) {
+	var (
+		g       errgroup.Group
+		workers = d.conf.Workers
+		total   int64
+	)
+	if workers <= 0 {
+		workers = 1
+	}
+	g.SetLimit(workers)
+	for _, delivery := range deliveries {
+		delivery := delivery
+		g.Go(func() error {
+			sent, err := d.deliver(ctx, delivery)
+			if sent {
+				atomic.AddInt64(&total, 1)
+			}
+			return err
+		})
+	}
+	err := g.Wait()
+	return total, err
+}
+
+// deliver claims given delivery and sends it to the webhook, then records the result.
+// false will be returned if the delivery is claimed by others.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	logger := logging.FromContext(ctx)
+	claimed, err := d.webhookDB.ClaimDelivery(ctx, delivery, d.now().Add(d.conf.Timeout+claimMargin))
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	statusCode, err := d.send(ctx, delivery)
+	now := d.now()
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.Succeed(now, statusCode)
+	} else {
+		msg := fmt.Sprintf("unexpected status code: %d", statusCode)
+		if err != nil {
+			msg = deliveryError(err)
+		}
+		logger.Warnw("WebhookDispatcher failed to send a delivery", "deliveryID", delivery.ID,
+			"attempts", delivery.Attempts, "statusCode", statusCode, "err", err)
+		delivery.Fail(now, statusCode, msg, d.conf.MaxAttempts, d.conf.Backoff)
+	}
+	delivery.UpdatedAt = now
+	return true, d.webhookDB.UpdateDelivery(ctx, delivery)
+}
+
+// deliveryError returns a generic message of given error of sending a delivery.
+// The message is shown to the owner of the webhook, so details of the network are not exposed.
+func deliveryError(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errBlockedAddress):
+		return errBlockedAddress.Error()
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "request timed out"
+	default:
+		return "failed to send a request"
+	}
+}
+
+// send posts the payload of given delivery signed with the webhook secret and returns the response status code.
+func (d *Dispatcher) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	w := delivery.Webhook
+	if w == nil {
+		return 0, fmt.Errorf("webhook %d not found", delivery.WebhookID)
+	}
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderSignature, "sha256="+w.Sign(payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// drain the body to reuse the connection, but not too much to be blocked by a large body.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxDrainBytes))
+	return res.StatusCode, nil
+}
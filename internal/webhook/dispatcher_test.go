@@ -0,0 +1,213 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// receivedRequest is a request received by a test webhook receiver.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, statusCode int) (*httptest.Server, chan *receivedRequest) {
+	received := make(chan *receivedRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received <- &receivedRequest{header: r.Header, body: body}
+		w.WriteHeader(statusCode)
+	}))
+	return srv, received
+}
+
+func newTestDispatcher(dbMock *mocks.WebhookDB, now time.Time) *Dispatcher {
+	return &Dispatcher{
+		conf: config.WebhookConfig{
+			Batch:       2,
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			Backoff:     time.Minute,
+		},
+		webhookDB: dbMock,
+		client:    &http.Client{Timeout: time.Second},
+		now: func() time.Time {
+			return now
+		},
+	}
+}
+
+func TestDispatcherDispatch(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusOK)
+	defer srv.Close()
+	var (
+		now      = time.Now()
+		dbMock   = &mocks.WebhookDB{}
+		d        = newTestDispatcher(dbMock, now)
+		w        = &model.Webhook{ID: 1, URL: srv.URL, Secret: "secret"}
+		delivery = &model.WebhookDelivery{
+			ID:        10,
+			WebhookID: w.ID,
+			Webhook:   w,
+			Event:     model.EventArticleCreated,
+			Payload:   `{"event":"article.created"}`,
+			Status:    model.DeliveryStatusPending,
+		}
+	)
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return([]*model.WebhookDelivery{delivery}, nil)
+	dbMock.On("ClaimDelivery", mock.Anything, delivery, mock.Anything).Run(func(args mock.Arguments) {
+		delivery.Attempts++
+	}).Return(true, nil)
+	dbMock.On("UpdateDelivery", mock.Anything, delivery).Return(nil)
+
+	sent, err := d.Dispatch(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, sent)
+	req := <-received
+	assert.Equal(t, delivery.Payload, string(req.body))
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, model.EventArticleCreated, req.header.Get(HeaderEvent))
+	assert.Equal(t, "10", req.header.Get(HeaderDelivery))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(req.body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.header.Get(HeaderSignature))
+
+	assert.Equal(t, model.DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, now, *delivery.DeliveredAt)
+}
+
+func TestDispatcherDispatchRetry(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusInternalServerError)
+	defer srv.Close()
+	var (
+		now      = time.Now()
+		dbMock   = &mocks.WebhookDB{}
+		d        = newTestDispatcher(dbMock, now)
+		w        = &model.Webhook{ID: 1, URL: srv.URL, Secret: "secret"}
+		delivery = &model.WebhookDelivery{
+			ID:        10,
+			WebhookID: w.ID,
+			Webhook:   w,
+			Event:     model.EventArticleCreated,
+			Payload:   `{}`,
+			Status:    model.DeliveryStatusPending,
+			Attempts:  1,
+		}
+	)
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return([]*model.WebhookDelivery{delivery}, nil)
+	dbMock.On("ClaimDelivery", mock.Anything, delivery, mock.Anything).Run(func(args mock.Arguments) {
+		delivery.Attempts++
+	}).Return(true, nil)
+	dbMock.On("UpdateDelivery", mock.Anything, delivery).Return(nil)
+
+	// when failed before max attempts
+	sent, err := d.Dispatch(context.TODO())
+
+	// then
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, sent)
+	<-received
+	assert.Equal(t, model.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, http.StatusInternalServerError, delivery.StatusCode)
+	assert.NotEmpty(t, delivery.Error)
+	assert.Equal(t, now.Add(2*time.Minute), delivery.NextAttemptAt)
+
+	// when failed at max attempts
+	sent, err = d.Dispatch(context.TODO())
+
+	// then
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, sent)
+	<-received
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, model.DeliveryStatusFailed, delivery.Status)
+	assert.Nil(t, delivery.DeliveredAt)
+}
+
+func TestDispatcherDispatchNotClaimed(t *testing.T) {
+	var (
+		now        = time.Now()
+		dbMock     = &mocks.WebhookDB{}
+		d          = newTestDispatcher(dbMock, now)
+		deliveries = []*model.WebhookDelivery{
+			{ID: 1, Webhook: &model.Webhook{URL: "http://localhost"}},
+			{ID: 2, Webhook: &model.Webhook{URL: "http://localhost"}},
+		}
+	)
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return(deliveries, nil).Once()
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return([]*model.WebhookDelivery{}, nil).Once()
+	dbMock.On("ClaimDelivery", mock.Anything, mock.Anything, now.Add(time.Second+claimMargin)).Return(false, nil)
+
+	sent, err := d.Dispatch(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, sent)
+	dbMock.AssertNumberOfCalls(t, "FindDueDeliveries", 2)
+	dbMock.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+}
+
+func TestDispatcherDispatchConcurrently(t *testing.T) {
+	var (
+		arrived = make(chan struct{}, 2)
+		release = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	var (
+		now        = time.Now()
+		dbMock     = &mocks.WebhookDB{}
+		d          = newTestDispatcher(dbMock, now)
+		w          = &model.Webhook{ID: 1, URL: srv.URL, Secret: "secret"}
+		deliveries = []*model.WebhookDelivery{
+			{ID: 1, WebhookID: w.ID, Webhook: w, Payload: `{}`, Status: model.DeliveryStatusPending},
+			{ID: 2, WebhookID: w.ID, Webhook: w, Payload: `{}`, Status: model.DeliveryStatusPending},
+		}
+	)
+	d.conf.Workers = 2
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return(deliveries, nil).Once()
+	dbMock.On("FindDueDeliveries", mock.Anything, now, 2).Return([]*model.WebhookDelivery{}, nil).Once()
+	dbMock.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	dbMock.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	// release requests after both requests are arrived
+	concurrent := make(chan bool, 1)
+	go func() {
+		defer close(release)
+		for i := 0; i < 2; i++ {
+			select {
+			case <-arrived:
+			case <-time.After(time.Second):
+				concurrent <- false
+				return
+			}
+		}
+		concurrent <- true
+	}()
+	sent, err := d.Dispatch(context.TODO())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, sent)
+	assert.True(t, <-concurrent)
+	for _, delivery := range deliveries {
+		assert.Equal(t, model.DeliveryStatusSucceeded, delivery.Status)
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	webhookDB "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+)
+
+// webhookEvents maps event types to webhook events which can be subscribed.
+var webhookEvents = map[string]string{
+	event.TypeArticleCreated: model.EventArticleCreated,
+	event.TypeArticleUpdated: model.EventArticleUpdated,
+	event.TypeCommentCreated: model.EventCommentCreated,
+}
+
+// Enqueuer saves pending deliveries of published events to webhooks of the target user.
+// Deliveries are sent by Dispatcher, so a slow webhook doesn't block publishers.
+type Enqueuer struct {
+	webhookDB webhookDB.WebhookDB
+}
+
+// NewEnqueuer returns a new Enqueuer from given serverenv.ServerEnv.
+func NewEnqueuer(env *serverenv.ServerEnv) *Enqueuer {
+	return &Enqueuer{
+		webhookDB: env.GetWebhookDB(),
+	}
+}
+
+// Handle saves deliveries of given event e to webhooks of the target user subscribing the event.
+// Events which can't be subscribed are ignored.
+func (q *Enqueuer) Handle(ctx context.Context, e *event.Event) error {
+	name, ok := webhookEvents[e.Type]
+	if !ok || e.UserID == 0 {
+		return nil
+	}
+	webhooks, err := q.webhookDB.FindWebhooks(ctx, &userModel.User{ID: e.UserID})
+	if err != nil {
+		return err
+	}
+
+	var subscribers []*model.Webhook
+	for _, w := range webhooks {
+		if w.HasEvent(name) {
+			subscribers = append(subscribers, w)
+		}
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&types.WebhookPayload{
+		Event:     name,
+		CreatedAt: types.JSONTime(e.CreatedAt),
+		Data:      e.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	deliveries := make([]*model.WebhookDelivery, len(subscribers))
+	for i, w := range subscribers {
+		deliveries[i] = &model.WebhookDelivery{
+			WebhookID:     w.ID,
+			Event:         name,
+			Payload:       string(payload),
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: e.CreatedAt,
+		}
+	}
+	return q.webhookDB.SaveDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/event"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database/mocks"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"testing"
+	"time"
+)
+
+func TestEnqueuerHandle(t *testing.T) {
+	var (
+		now      = time.Now()
+		dbMock   = &mocks.WebhookDB{}
+		q        = &Enqueuer{webhookDB: dbMock}
+		webhooks = []*model.Webhook{
+			{ID: 1, UserID: 2, Events: "article.created,comment.created"},
+			{ID: 2, UserID: 2, Events: "article.updated"},
+			{ID: 3, UserID: 2, Events: "comment.created"},
+		}
+		saved []*model.WebhookDelivery
+	)
+	dbMock.On("FindWebhooks", mock.Anything, &userModel.User{ID: 2}).Return(webhooks, nil)
+	dbMock.On("SaveDeliveries", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).([]*model.WebhookDelivery)
+	}).Return(nil)
+
+	err := q.Handle(context.TODO(), &event.Event{
+		Type:      event.TypeCommentCreated,
+		ActorID:   1,
+		UserID:    2,
+		Payload:   map[string]string{"body": "comment"},
+		CreatedAt: now,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2)
+	assert.EqualValues(t, 1, saved[0].WebhookID)
+	assert.EqualValues(t, 3, saved[1].WebhookID)
+	for _, d := range saved {
+		assert.Equal(t, model.EventCommentCreated, d.Event)
+		assert.Equal(t, model.DeliveryStatusPending, d.Status)
+		assert.Equal(t, now, d.NextAttemptAt)
+		var payload struct {
+			Event string            `json:"event"`
+			Data  map[string]string `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(d.Payload), &payload))
+		assert.Equal(t, model.EventCommentCreated, payload.Event)
+		assert.Equal(t, "comment", payload.Data["body"])
+	}
+}
+
+func TestEnqueuerHandleIgnored(t *testing.T) {
+	var (
+		dbMock = &mocks.WebhookDB{}
+		q      = &Enqueuer{webhookDB: dbMock}
+	)
+	dbMock.On("FindWebhooks", mock.Anything, &userModel.User{ID: 2}).Return([]*model.Webhook{
+		{ID: 1, UserID: 2, Events: "article.created"},
+	}, nil)
+
+	// unknown event
+	assert.NoError(t, q.Handle(context.TODO(), &event.Event{Type: event.TypeUserFollowed, ActorID: 1, UserID: 2}))
+	// no subscribers
+	assert.NoError(t, q.Handle(context.TODO(), &event.Event{Type: event.TypeArticleUpdated, ActorID: 2, UserID: 2}))
+
+	dbMock.AssertNumberOfCalls(t, "FindWebhooks", 1)
+	dbMock.AssertNotCalled(t, "SaveDeliveries", mock.Anything, mock.Anything)
+}
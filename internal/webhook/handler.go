@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/serverenv"
+	webhookDB "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/database"
+)
+
+type Handler struct {
+	cfg       *config.Config
+	webhookDB webhookDB.WebhookDB
+}
+
+// NewHandler returns a new Handle from given serverenv.ServerEnv and config.Config.
+func NewHandler(env *serverenv.ServerEnv, conf *config.Config) (*Handler, error) {
+	return &Handler{
+		cfg:       conf,
+		webhookDB: env.GetWebhookDB(),
+	}, nil
+}
+
+// Route configures route given "/api" echo.Group to "/api/user/webhooks/**" paths.
+func (h *Handler) Route(e *echo.Group, authMiddleware echo.MiddlewareFunc) {
+	webhookGroup := e.Group("/user/webhooks")
+	webhookGroup.Use(authMiddleware)
+	webhookGroup.POST("", h.handleCreateWebhook)
+	webhookGroup.GET("", h.handleGetWebhooks)
+	webhookGroup.DELETE("/:id", h.handleDeleteWebhook)
+	webhookGroup.GET("/:id/deliveries", h.handleGetDeliveries)
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	TableNameWebhook         = "webhooks"
+	TableNameWebhookDelivery = "webhook_deliveries"
+)
+
+// Webhook events which can be subscribed.
+const (
+	EventArticleCreated = "article.created"
+	EventArticleUpdated = "article.updated"
+	EventCommentCreated = "comment.created"
+)
+
+// Delivery statuses. Pending deliveries are sent until succeeded or failed after max attempts.
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+// secretSize is the number of random bytes of a webhook secret.
+const secretSize = 32
+
+// Webhook represents database model for webhooks.
+// Events is a comma separated list of subscribed events and Secret is a key of payload signatures.
+type Webhook struct {
+	ID        uint      `gorm:"column:webhook_id"`
+	UserID    uint      `gorm:"column:user_id"`
+	URL       string    `gorm:"column:url"`
+	Secret    string    `gorm:"column:secret"`
+	Events    string    `gorm:"column:events"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (w *Webhook) TableName() string {
+	return TableNameWebhook
+}
+
+// NewSecret returns a new random secret of webhooks.
+func NewSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SetEvents sets given events to subscribe.
+func (w *Webhook) SetEvents(events []string) {
+	w.Events = strings.Join(events, ",")
+}
+
+// EventList returns subscribed events.
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return []string{}
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// HasEvent returns true if this webhook subscribes given event, otherwise false.
+func (w *Webhook) HasEvent(event string) bool {
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns a hex encoded HMAC-SHA256 signature of given payload with the secret.
+func (w *Webhook) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliveries represents delivery list of a webhook with total size.
+type WebhookDeliveries struct {
+	Deliveries      []*WebhookDelivery
+	DeliveriesCount int64
+}
+
+// WebhookDelivery represents database model for deliveries of an event to a webhook.
+// Attempts is increased when a delivery is claimed to send and NextAttemptAt is the time to be sent.
+// StatusCode and Error are results of the last attempt.
+type WebhookDelivery struct {
+	ID            uint       `gorm:"column:delivery_id"`
+	WebhookID     uint       `gorm:"column:webhook_id"`
+	Webhook       *Webhook   `gorm:"foreignKey:WebhookID"`
+	Event         string     `gorm:"column:event"`
+	Payload       string     `gorm:"column:payload"`
+	Status        string     `gorm:"column:status"`
+	Attempts      int        `gorm:"column:attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	StatusCode    int        `gorm:"column:status_code"`
+	Error         string     `gorm:"column:error"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return TableNameWebhookDelivery
+}
+
+// Succeed marks this delivery as succeeded at now with given response status code.
+func (d *WebhookDelivery) Succeed(now time.Time, statusCode int) {
+	d.Status = DeliveryStatusSucceeded
+	d.StatusCode = statusCode
+	d.Error = ""
+	d.DeliveredAt = &now
+}
+
+// Fail records a failed attempt with given status code and error.
+// The delivery is retried after backoff doubled for each attempt, or failed if attempts reach maxAttempts.
+func (d *WebhookDelivery) Fail(now time.Time, statusCode int, err string, maxAttempts int, backoff time.Duration) {
+	d.StatusCode = statusCode
+	d.Error = err
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+	d.Status = DeliveryStatusPending
+	if d.Attempts > 1 {
+		backoff <<= uint(d.Attempts - 1)
+	}
+	d.NextAttemptAt = now.Add(backoff)
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWebhookEvents(t *testing.T) {
+	var w Webhook
+	assert.Equal(t, []string{}, w.EventList())
+	assert.False(t, w.HasEvent(EventArticleCreated))
+
+	w.SetEvents([]string{EventArticleCreated, EventCommentCreated})
+
+	assert.Equal(t, "article.created,comment.created", w.Events)
+	assert.Equal(t, []string{EventArticleCreated, EventCommentCreated}, w.EventList())
+	assert.True(t, w.HasEvent(EventArticleCreated))
+	assert.False(t, w.HasEvent(EventArticleUpdated))
+	assert.True(t, w.HasEvent(EventCommentCreated))
+}
+
+func TestWebhookSign(t *testing.T) {
+	secret, err := NewSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, secretSize*2)
+	w := Webhook{Secret: secret}
+	payload := []byte(`{"event":"article.created"}`)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), w.Sign(payload))
+}
+
+func TestWebhookDeliveryFail(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name          string
+		attempts      int
+		status        string
+		nextAttemptAt time.Time
+	}{
+		{
+			name:          "first attempt",
+			attempts:      1,
+			status:        DeliveryStatusPending,
+			nextAttemptAt: now.Add(time.Minute),
+		}, {
+			name:          "third attempt",
+			attempts:      3,
+			status:        DeliveryStatusPending,
+			nextAttemptAt: now.Add(4 * time.Minute),
+		}, {
+			name:     "max attempts",
+			attempts: 5,
+			status:   DeliveryStatusFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := WebhookDelivery{Status: DeliveryStatusPending, Attempts: tc.attempts}
+
+			d.Fail(now, 500, "server error", 5, time.Minute)
+
+			assert.Equal(t, tc.status, d.Status)
+			assert.Equal(t, 500, d.StatusCode)
+			assert.Equal(t, "server error", d.Error)
+			assert.Equal(t, tc.nextAttemptAt, d.NextAttemptAt)
+			assert.Nil(t, d.DeliveredAt)
+		})
+	}
+}
+
+func TestWebhookDeliverySucceed(t *testing.T) {
+	now := time.Now()
+	d := WebhookDelivery{Status: DeliveryStatusPending, Attempts: 2, Error: "timeout"}
+
+	d.Succeed(now, 204)
+
+	assert.Equal(t, DeliveryStatusSucceeded, d.Status)
+	assert.Equal(t, 204, d.StatusCode)
+	assert.Empty(t, d.Error)
+	assert.Equal(t, now, *d.DeliveredAt)
+}
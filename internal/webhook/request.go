@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/config"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net"
+	"net/url"
+)
+
+// CreateWebhookRequest represents request body data of registering a webhook.
+// URL must be a http or https url and Events are webhook events to subscribe.
+type CreateWebhookRequest struct {
+	Webhook struct {
+		URL    string   `json:"url" validate:"required,url"`
+		Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=article.created article.updated comment.created"`
+	} `json:"webhook" validate:"required"`
+}
+
+// Bind binds the request to given w.
+// The url is rejected if its host is resolved to an address which is not allowed unless conf.AllowPrivate is true.
+func (r *CreateWebhookRequest) Bind(ctx echo.Context, conf config.WebhookConfig, w *model.Webhook) error {
+	if err := httputils.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	u, err := url.Parse(r.Webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return httputils.NewStatusUnprocessableEntity("url must be a http or https url")
+	}
+	if !conf.AllowPrivate {
+		if err := checkHost(ctx.Request().Context(), net.DefaultResolver, u.Hostname()); err != nil {
+			return httputils.NewStatusUnprocessableEntity("url must be a public address")
+		}
+	}
+	w.URL = r.Webhook.URL
+	w.SetEvents(r.Webhook.Events)
+	return nil
+}
+
+// DeliveryQuery represents query parameters of getting deliveries of a webhook.
+// Deliveries are ordered by created time, so only offset based pagination is supported.
+type DeliveryQuery struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+func (r *DeliveryQuery) Bind(ctx echo.Context) error {
+	if err := httputils.BindAndValidate(ctx, r); err != nil {
+		return err
+	}
+	if r.Limit < 0 {
+		return httputils.NewStatusUnprocessableEntity("limit must greater than or equals to 0")
+	}
+	if r.Offset < 0 {
+		return httputils.NewStatusUnprocessableEntity("offset must greater than or equals to 0")
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/database"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/api/types"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/authutils"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+	"strconv"
+)
+
+// handleCreateWebhook handles "POST /api/user/webhooks" to register a webhook of current user.
+// The secret of signatures is returned only in this response.
+func (h *Handler) handleCreateWebhook(c echo.Context) error {
+	var (
+		ctx     = c.Request().Context()
+		logger  = logging.FromContext(ctx)
+		req     = &CreateWebhookRequest{}
+		webhook = model.Webhook{UserID: authutils.CurrentUser(c)}
+	)
+
+	// Bind request
+	if err := req.Bind(c, h.cfg.WebhookConfig, &webhook); err != nil {
+		logger.Errorw("WebhookHandler_handleCreateWebhook failed to bind request", "err", err)
+		return httputils.WrapBindError(err)
+	}
+	secret, err := model.NewSecret()
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	webhook.Secret = secret
+
+	// Save a webhook
+	if err := h.webhookDB.Save(ctx, &webhook); err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusCreated, types.ToWebhookResponse(&webhook, true))
+}
+
+// handleGetWebhooks handles "GET /api/user/webhooks" to get webhooks of current user.
+func (h *Handler) handleGetWebhooks(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+	)
+
+	// Query webhooks
+	webhooks, err := h.webhookDB.FindWebhooks(ctx, currentUser)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToWebhooksResponse(webhooks))
+}
+
+// handleDeleteWebhook handles "DELETE /api/user/webhooks/:id" to delete a webhook of current user with deliveries.
+func (h *Handler) handleDeleteWebhook(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	id, err := bindWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	// Delete a webhook
+	if err := h.webhookDB.DeleteWebhook(ctx, currentUser, id); err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("webhook(%d) not found", id))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToStatusResponse(types.StatusDeleted, nil))
+}
+
+// handleGetDeliveries handles "GET /api/user/webhooks/:id/deliveries?limit=&offset=" to get delivery logs of a webhook.
+func (h *Handler) handleGetDeliveries(c echo.Context) error {
+	var (
+		ctx         = c.Request().Context()
+		logger      = logging.FromContext(ctx)
+		query       = DeliveryQuery{}
+		currentUser = h.currentUser(c)
+	)
+
+	// Bind request
+	id, err := bindWebhookID(c)
+	if err != nil {
+		return err
+	}
+	if err := query.Bind(c); err != nil {
+		logger.Errorw("WebhookHandler_handleGetDeliveries failed to bind query", "err", err)
+		return httputils.WrapBindError(err)
+	}
+
+	// Query a webhook and deliveries
+	webhook, err := h.webhookDB.FindWebhookByID(ctx, currentUser, id)
+	if err != nil {
+		if err == database.ErrRecordNotFound {
+			return httputils.NewNotFoundError(fmt.Sprintf("webhook(%d) not found", id))
+		}
+		return httputils.NewInternalServerError(err)
+	}
+	deliveries, err := h.webhookDB.FindDeliveries(ctx, webhook.ID, query.Offset, query.Limit)
+	if err != nil {
+		return httputils.NewInternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, types.ToWebhookDeliveriesResponse(deliveries))
+}
+
+// bindWebhookID returns a webhook id parsed from the path parameter.
+func bindWebhookID(c echo.Context) (uint, error) {
+	value := c.Param("id")
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		logging.FromContext(c.Request().Context()).Errorw("WebhookHandler invalid webhook id", "id", value, "err", err)
+		return 0, httputils.NewBindError("id", "uint")
+	}
+	return uint(id), nil
+}
+
+func (h *Handler) currentUser(c echo.Context) *userModel.User {
+	uid := authutils.CurrentUser(c)
+	if uid == 0 {
+		return nil
+	}
+	return &userModel.User{
+		ID: uid,
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS revoked_tokens CASCADE;
+DROP TABLE IF EXISTS refresh_tokens CASCADE;
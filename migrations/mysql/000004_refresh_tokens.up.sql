@@ -0,0 +1,27 @@
+-- -----------------------------------------------------
+-- refresh_tokens
+-- -----------------------------------------------------
+CREATE TABLE refresh_tokens
+(
+    token_id   INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    user_id    INT UNSIGNED NOT NULL,
+    UNIQUE KEY unique_refresh_tokens_token_hash (token_hash),
+    CONSTRAINT refresh_tokens_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+) CHARACTER SET utf8mb4;
+
+-- -----------------------------------------------------
+-- revoked_tokens
+-- -----------------------------------------------------
+CREATE TABLE revoked_tokens
+(
+    token_id   VARCHAR(64) PRIMARY KEY,
+    user_id    INT UNSIGNED NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NULL
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
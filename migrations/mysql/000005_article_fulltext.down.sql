@@ -0,0 +1 @@
+DROP INDEX ft_articles_title_description_body ON articles;
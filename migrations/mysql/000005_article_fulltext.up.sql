@@ -0,0 +1,4 @@
+-- -----------------------------------------------------
+-- articles full-text search
+-- -----------------------------------------------------
+CREATE FULLTEXT INDEX ft_articles_title_description_body ON articles (title, description, body);
@@ -0,0 +1,6 @@
+DROP INDEX idx_comments_deleted_at_article_id ON comments;
+DROP INDEX idx_articles_slug ON articles;
+ALTER TABLE articles
+    DROP INDEX unique_articles_active_slug,
+    DROP COLUMN active_slug,
+    ADD UNIQUE KEY unique_articles_slug (slug);
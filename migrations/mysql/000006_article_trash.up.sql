@@ -0,0 +1,10 @@
+-- -----------------------------------------------------
+-- articles slug uniqueness ignoring soft-deleted rows
+-- -----------------------------------------------------
+-- active_slug is NULL if deleted, and NULLs are not compared in unique indices.
+ALTER TABLE articles
+    ADD COLUMN active_slug VARCHAR(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL, slug, NULL)) VIRTUAL,
+    DROP INDEX unique_articles_slug,
+    ADD UNIQUE KEY unique_articles_active_slug (active_slug);
+CREATE INDEX idx_articles_slug ON articles (slug);
+CREATE INDEX idx_comments_deleted_at_article_id ON comments (deleted_at, article_id);
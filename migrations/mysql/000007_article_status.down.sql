@@ -0,0 +1,4 @@
+DROP INDEX idx_articles_status_publish_at ON articles;
+ALTER TABLE articles
+    DROP COLUMN status,
+    DROP COLUMN publish_at;
@@ -0,0 +1,9 @@
+-- -----------------------------------------------------
+-- articles status and publish time
+-- -----------------------------------------------------
+-- status is one of "draft", "published" and "scheduled".
+ALTER TABLE articles
+    ADD COLUMN status     VARCHAR(16) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at DATETIME NULL;
+UPDATE articles SET publish_at = created_at;
+CREATE INDEX idx_articles_status_publish_at ON articles (status, publish_at);
@@ -0,0 +1,12 @@
+-- -----------------------------------------------------
+-- slug_history
+-- -----------------------------------------------------
+CREATE TABLE slug_history
+(
+    slug       VARCHAR(255) PRIMARY KEY,
+    created_at DATETIME NULL,
+    article_id INT UNSIGNED NOT NULL,
+    CONSTRAINT slug_history_article_id_fk
+        FOREIGN KEY (article_id) REFERENCES articles (article_id)
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_slug_history_article_id ON slug_history (article_id);
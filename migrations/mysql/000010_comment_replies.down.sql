@@ -0,0 +1,3 @@
+ALTER TABLE comments
+    DROP FOREIGN KEY comments_parent_id_fk,
+    DROP COLUMN parent_id;
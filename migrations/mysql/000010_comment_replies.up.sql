@@ -0,0 +1,7 @@
+-- -----------------------------------------------------
+-- comments parent for replies
+-- -----------------------------------------------------
+ALTER TABLE comments
+    ADD COLUMN parent_id INT UNSIGNED NULL,
+    ADD CONSTRAINT comments_parent_id_fk
+        FOREIGN KEY (parent_id) REFERENCES comments (comment_id);
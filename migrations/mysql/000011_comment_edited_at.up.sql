@@ -0,0 +1,4 @@
+-- -----------------------------------------------------
+-- comments edited time
+-- -----------------------------------------------------
+ALTER TABLE comments ADD COLUMN edited_at DATETIME NULL;
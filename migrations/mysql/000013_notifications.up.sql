@@ -0,0 +1,20 @@
+-- -----------------------------------------------------
+-- notifications
+-- -----------------------------------------------------
+-- article_id and comment_id have no foreign keys to keep notifications after the article is purged.
+CREATE TABLE notifications
+(
+    notification_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id         INT UNSIGNED NOT NULL,
+    actor_id        INT UNSIGNED NOT NULL,
+    type            VARCHAR(16)  NOT NULL,
+    article_id      INT UNSIGNED NULL,
+    comment_id      INT UNSIGNED NULL,
+    read_at         DATETIME NULL,
+    created_at      DATETIME NULL,
+    CONSTRAINT notifications_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT notifications_actor_id_fk
+        FOREIGN KEY (actor_id) REFERENCES users (user_id)
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_notifications_user ON notifications (user_id, read_at);
@@ -0,0 +1,41 @@
+-- -----------------------------------------------------
+-- webhooks
+-- -----------------------------------------------------
+-- events is a comma separated list of subscribed events.
+CREATE TABLE webhooks
+(
+    webhook_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id    INT UNSIGNED  NOT NULL,
+    url        VARCHAR(2048) NOT NULL,
+    secret     VARCHAR(128)  NOT NULL,
+    events     VARCHAR(255)  NOT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    CONSTRAINT webhooks_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_webhooks_user ON webhooks (user_id);
+
+-- -----------------------------------------------------
+-- webhook_deliveries
+-- -----------------------------------------------------
+-- pending deliveries are sent after next_attempt_at.
+CREATE TABLE webhook_deliveries
+(
+    delivery_id     INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    webhook_id      INT UNSIGNED NOT NULL,
+    event           VARCHAR(64)  NOT NULL,
+    payload         TEXT         NOT NULL,
+    status          VARCHAR(16)  NOT NULL,
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME     NOT NULL,
+    status_code     INT          NOT NULL DEFAULT 0,
+    error           TEXT NULL,
+    delivered_at    DATETIME NULL,
+    created_at      DATETIME NULL,
+    updated_at      DATETIME NULL,
+    CONSTRAINT webhook_deliveries_webhook_id_fk
+        FOREIGN KEY (webhook_id) REFERENCES webhooks (webhook_id)
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (status, next_attempt_at);
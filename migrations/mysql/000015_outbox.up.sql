@@ -0,0 +1,24 @@
+-- -----------------------------------------------------
+-- outbox
+-- -----------------------------------------------------
+-- domain events written in the same transaction as the domain change.
+-- pending events are relayed to sinks after next_attempt_at.
+CREATE TABLE outbox
+(
+    outbox_id       INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    idempotency_key VARCHAR(64)  NOT NULL,
+    aggregate_type  VARCHAR(32)  NOT NULL,
+    aggregate_id    INT UNSIGNED NOT NULL,
+    event_type      VARCHAR(64)  NOT NULL,
+    payload         TEXT         NOT NULL,
+    status          VARCHAR(16)  NOT NULL,
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME     NOT NULL,
+    last_error      TEXT NULL,
+    published_at    DATETIME NULL,
+    created_at      DATETIME NULL,
+    updated_at      DATETIME NULL
+) CHARACTER SET utf8mb4;
+CREATE UNIQUE INDEX idx_outbox_idempotency_key ON outbox (idempotency_key);
+CREATE INDEX idx_outbox_pending ON outbox (status, next_attempt_at);
+CREATE INDEX idx_outbox_published ON outbox (status, published_at);
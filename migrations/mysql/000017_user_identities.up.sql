@@ -0,0 +1,18 @@
+-- -----------------------------------------------------
+-- user_identities
+-- -----------------------------------------------------
+-- external identities of OIDC providers linked to users.
+CREATE TABLE user_identities
+(
+    identity_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id     INT UNSIGNED NOT NULL,
+    provider    VARCHAR(64)  NOT NULL,
+    subject     VARCHAR(255) NOT NULL,
+    email       VARCHAR(255) NULL,
+    created_at  DATETIME NULL,
+    updated_at  DATETIME NULL,
+    UNIQUE KEY unique_user_identities_provider_subject (provider, subject),
+    CONSTRAINT user_identities_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+) CHARACTER SET utf8mb4;
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,7 @@
+-- -----------------------------------------------------
+-- articles deleted user
+-- -----------------------------------------------------
+-- deleted_by is an id of a user who deleted the article. articles deleted by other users than the author
+-- (e.g. moderators) cannot be restored by the author.
+ALTER TABLE articles
+    ADD COLUMN deleted_by INT UNSIGNED NULL;
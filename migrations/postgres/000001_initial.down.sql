@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS article_favorites CASCADE;
+DROP TABLE IF EXISTS article_tags CASCADE;
+DROP TABLE IF EXISTS comments CASCADE;
+DROP TABLE IF EXISTS articles CASCADE;
+DROP TABLE IF EXISTS tags CASCADE;
+DROP TABLE IF EXISTS follows CASCADE;
+DROP TABLE IF EXISTS users CASCADE;
\ No newline at end of file
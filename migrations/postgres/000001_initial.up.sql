@@ -0,0 +1,109 @@
+-- -----------------------------------------------------
+-- users
+-- -----------------------------------------------------
+CREATE TABLE users
+(
+    user_id    SERIAL PRIMARY KEY,
+    email      VARCHAR(255) NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    bio        TEXT,
+    image      VARCHAR(255) NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    disabled   BOOLEAN DEFAULT FALSE,
+    CONSTRAINT unique_users_email UNIQUE (email),
+    CONSTRAINT unique_users_name UNIQUE (name)
+);
+
+-- -----------------------------------------------------
+-- follow
+-- -----------------------------------------------------
+CREATE TABLE follows
+(
+    created_at TIMESTAMP NULL,
+    user_id    INTEGER,
+    follow_id  INTEGER,
+    CONSTRAINT user_id_fk FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT follow_id_fk FOREIGN KEY (follow_id) REFERENCES users (user_id),
+    PRIMARY KEY (user_id, follow_id)
+);
+
+-- -----------------------------------------------------
+-- articles
+-- -----------------------------------------------------
+CREATE TABLE articles
+(
+    article_id  SERIAL PRIMARY KEY,
+    created_at  TIMESTAMP NULL,
+    updated_at  TIMESTAMP NULL,
+    deleted_at  TIMESTAMP NULL,
+    slug        VARCHAR(255),
+    title       VARCHAR(255),
+    description TEXT,
+    body        TEXT,
+    author_id   INTEGER,
+    CONSTRAINT unique_articles_slug UNIQUE (slug),
+    CONSTRAINT articles_author_id_fk
+        FOREIGN KEY (author_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_articles_deleted_at ON articles (deleted_at);
+
+-- -----------------------------------------------------
+-- article_favorites
+-- -----------------------------------------------------
+CREATE TABLE article_favorites
+(
+    user_id    INTEGER,
+    article_id INTEGER,
+    CONSTRAINT article_favorites_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT article_favorites_article_id_fk
+        FOREIGN KEY (article_id) REFERENCES articles (article_id),
+    PRIMARY KEY (user_id, article_id)
+);
+
+-- -----------------------------------------------------
+-- tags
+-- -----------------------------------------------------
+CREATE TABLE tags
+(
+    tag_id     SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NULL,
+    name       VARCHAR(255),
+    CONSTRAINT unique_tags_name UNIQUE (name)
+);
+
+-- -----------------------------------------------------
+-- tags
+-- -----------------------------------------------------
+CREATE TABLE article_tags
+(
+    article_id INTEGER,
+    tag_id     INTEGER,
+    CONSTRAINT article_tags_article_id_fk
+        FOREIGN KEY (article_id) REFERENCES articles (article_id),
+    CONSTRAINT article_tags_tag_id_fk
+        FOREIGN KEY (tag_id) REFERENCES tags (tag_id),
+    PRIMARY KEY (article_id, tag_id)
+);
+
+-- -----------------------------------------------------
+-- comments
+-- -----------------------------------------------------
+CREATE TABLE comments
+(
+    comment_id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    body       TEXT,
+    article_id INTEGER,
+    author_id  INTEGER,
+    CONSTRAINT comments_article_id_fk
+        FOREIGN KEY (article_id) REFERENCES articles (article_id),
+    CONSTRAINT comments_author_id_fk
+        FOREIGN KEY (author_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
@@ -0,0 +1 @@
+DELETE FROM users WHERE user_id IN (1);
\ No newline at end of file
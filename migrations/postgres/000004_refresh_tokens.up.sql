@@ -0,0 +1,27 @@
+-- -----------------------------------------------------
+-- refresh_tokens
+-- -----------------------------------------------------
+CREATE TABLE refresh_tokens
+(
+    token_id   SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    user_id    INTEGER NOT NULL,
+    CONSTRAINT unique_refresh_tokens_token_hash UNIQUE (token_hash),
+    CONSTRAINT refresh_tokens_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+
+-- -----------------------------------------------------
+-- revoked_tokens
+-- -----------------------------------------------------
+CREATE TABLE revoked_tokens
+(
+    token_id   VARCHAR(64) PRIMARY KEY,
+    user_id    INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NULL
+);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ft_articles_title_description_body;
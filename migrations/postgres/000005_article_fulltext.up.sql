@@ -0,0 +1,5 @@
+-- -----------------------------------------------------
+-- articles full-text search
+-- -----------------------------------------------------
+CREATE INDEX ft_articles_title_description_body ON articles
+    USING GIN (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, '') || ' ' || coalesce(body, '')));
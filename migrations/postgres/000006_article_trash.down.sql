@@ -0,0 +1,4 @@
+DROP INDEX idx_comments_deleted_at_article_id;
+DROP INDEX idx_articles_slug;
+DROP INDEX unique_articles_slug;
+ALTER TABLE articles ADD CONSTRAINT unique_articles_slug UNIQUE (slug);
@@ -0,0 +1,7 @@
+-- -----------------------------------------------------
+-- articles slug uniqueness ignoring soft-deleted rows
+-- -----------------------------------------------------
+ALTER TABLE articles DROP CONSTRAINT unique_articles_slug;
+CREATE UNIQUE INDEX unique_articles_slug ON articles (slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_articles_slug ON articles (slug);
+CREATE INDEX idx_comments_deleted_at_article_id ON comments (deleted_at, article_id);
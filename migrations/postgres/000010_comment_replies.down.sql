@@ -0,0 +1,4 @@
+DROP INDEX idx_comments_parent_id;
+ALTER TABLE comments
+    DROP CONSTRAINT comments_parent_id_fk,
+    DROP COLUMN parent_id;
@@ -0,0 +1,8 @@
+-- -----------------------------------------------------
+-- comments parent for replies
+-- -----------------------------------------------------
+ALTER TABLE comments
+    ADD COLUMN parent_id INTEGER NULL,
+    ADD CONSTRAINT comments_parent_id_fk
+        FOREIGN KEY (parent_id) REFERENCES comments (comment_id);
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
@@ -0,0 +1,20 @@
+-- -----------------------------------------------------
+-- notifications
+-- -----------------------------------------------------
+-- article_id and comment_id have no foreign keys to keep notifications after the article is purged.
+CREATE TABLE notifications
+(
+    notification_id SERIAL PRIMARY KEY,
+    user_id         INTEGER      NOT NULL,
+    actor_id        INTEGER      NOT NULL,
+    type            VARCHAR(16)  NOT NULL,
+    article_id      INTEGER      NULL,
+    comment_id      INTEGER      NULL,
+    read_at         TIMESTAMP NULL,
+    created_at      TIMESTAMP NULL,
+    CONSTRAINT notifications_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT notifications_actor_id_fk
+        FOREIGN KEY (actor_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_notifications_user ON notifications (user_id, read_at);
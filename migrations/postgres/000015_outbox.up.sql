@@ -0,0 +1,24 @@
+-- -----------------------------------------------------
+-- outbox
+-- -----------------------------------------------------
+-- domain events written in the same transaction as the domain change.
+-- pending events are relayed to sinks after next_attempt_at.
+CREATE TABLE outbox
+(
+    outbox_id       SERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(64)  NOT NULL,
+    aggregate_type  VARCHAR(32)  NOT NULL,
+    aggregate_id    INTEGER      NOT NULL,
+    event_type      VARCHAR(64)  NOT NULL,
+    payload         TEXT         NOT NULL,
+    status          VARCHAR(16)  NOT NULL,
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP    NOT NULL,
+    last_error      TEXT NULL,
+    published_at    TIMESTAMP NULL,
+    created_at      TIMESTAMP NULL,
+    updated_at      TIMESTAMP NULL
+);
+CREATE UNIQUE INDEX idx_outbox_idempotency_key ON outbox (idempotency_key);
+CREATE INDEX idx_outbox_pending ON outbox (status, next_attempt_at);
+CREATE INDEX idx_outbox_published ON outbox (status, published_at);
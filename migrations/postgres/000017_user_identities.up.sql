@@ -0,0 +1,18 @@
+-- -----------------------------------------------------
+-- user_identities
+-- -----------------------------------------------------
+-- external identities of OIDC providers linked to users.
+CREATE TABLE user_identities
+(
+    identity_id SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    provider    VARCHAR(64)  NOT NULL,
+    subject     VARCHAR(255) NOT NULL,
+    email       VARCHAR(255) NULL,
+    created_at  TIMESTAMP NULL,
+    updated_at  TIMESTAMP NULL,
+    CONSTRAINT unique_user_identities_provider_subject UNIQUE (provider, subject),
+    CONSTRAINT user_identities_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
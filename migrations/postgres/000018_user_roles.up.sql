@@ -0,0 +1,5 @@
+-- -----------------------------------------------------
+-- users role
+-- -----------------------------------------------------
+-- one of "user", "moderator" and "admin".
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
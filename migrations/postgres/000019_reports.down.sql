@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN hidden_at;
+DROP TABLE IF EXISTS reports;
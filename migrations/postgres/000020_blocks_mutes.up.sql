@@ -0,0 +1,27 @@
+-- -----------------------------------------------------
+-- blocks
+-- -----------------------------------------------------
+-- user_id blocks block_id. blocked users can not follow the blocker, comment on or favorite the blocker's articles.
+CREATE TABLE blocks
+(
+    created_at TIMESTAMP NULL,
+    user_id    INTEGER,
+    block_id   INTEGER,
+    CONSTRAINT blocks_user_id_fk FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT blocks_block_id_fk FOREIGN KEY (block_id) REFERENCES users (user_id),
+    PRIMARY KEY (user_id, block_id)
+);
+
+-- -----------------------------------------------------
+-- mutes
+-- -----------------------------------------------------
+-- user_id mutes mute_id. articles and comments of muted users are excluded for the muting user.
+CREATE TABLE mutes
+(
+    created_at TIMESTAMP NULL,
+    user_id    INTEGER,
+    mute_id    INTEGER,
+    CONSTRAINT mutes_user_id_fk FOREIGN KEY (user_id) REFERENCES users (user_id),
+    CONSTRAINT mutes_mute_id_fk FOREIGN KEY (mute_id) REFERENCES users (user_id),
+    PRIMARY KEY (user_id, mute_id)
+);
@@ -0,0 +1,4 @@
+-- -----------------------------------------------------
+-- articles full-text search
+-- -----------------------------------------------------
+-- SQLite has no full-text index on regular tables, so articles are searched with LIKE.
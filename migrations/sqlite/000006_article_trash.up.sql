@@ -0,0 +1,27 @@
+-- -----------------------------------------------------
+-- articles slug uniqueness ignoring soft-deleted rows
+-- -----------------------------------------------------
+-- SQLite can not drop a table constraint, so the articles table is rebuilt.
+CREATE TABLE articles_new
+(
+    article_id  INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME NULL,
+    updated_at  DATETIME NULL,
+    deleted_at  DATETIME NULL,
+    slug        VARCHAR(255),
+    title       VARCHAR(255),
+    description TEXT,
+    body        TEXT,
+    author_id   INTEGER,
+    CONSTRAINT articles_author_id_fk
+        FOREIGN KEY (author_id) REFERENCES users (user_id)
+);
+INSERT INTO articles_new (article_id, created_at, updated_at, deleted_at, slug, title, description, body, author_id)
+SELECT article_id, created_at, updated_at, deleted_at, slug, title, description, body, author_id
+FROM articles;
+DROP TABLE articles;
+ALTER TABLE articles_new RENAME TO articles;
+CREATE INDEX idx_articles_deleted_at ON articles (deleted_at);
+CREATE UNIQUE INDEX unique_articles_slug ON articles (slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_articles_slug ON articles (slug);
+CREATE INDEX idx_comments_deleted_at_article_id ON comments (deleted_at, article_id);
@@ -0,0 +1,3 @@
+DROP INDEX idx_articles_status_publish_at;
+ALTER TABLE articles DROP COLUMN publish_at;
+ALTER TABLE articles DROP COLUMN status;
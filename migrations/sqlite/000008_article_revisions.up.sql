@@ -0,0 +1,24 @@
+-- -----------------------------------------------------
+-- article_revisions
+-- -----------------------------------------------------
+CREATE TABLE article_revisions
+(
+    revision_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at  DATETIME NULL,
+    article_id  INTEGER NOT NULL,
+    number      INTEGER NOT NULL,
+    title       VARCHAR(255),
+    description TEXT,
+    body        TEXT,
+    editor_id   INTEGER,
+    CONSTRAINT unique_article_revisions_article_id_number UNIQUE (article_id, number),
+    CONSTRAINT article_revisions_article_id_fk
+        FOREIGN KEY (article_id) REFERENCES articles (article_id),
+    CONSTRAINT article_revisions_editor_id_fk
+        FOREIGN KEY (editor_id) REFERENCES users (user_id)
+);
+
+-- current contents of existing articles are the first revisions.
+INSERT INTO article_revisions (created_at, article_id, number, title, description, body, editor_id)
+SELECT updated_at, article_id, 1, title, description, body, author_id
+FROM articles;
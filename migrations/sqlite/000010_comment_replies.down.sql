@@ -0,0 +1,2 @@
+DROP INDEX idx_comments_parent_id;
+ALTER TABLE comments DROP COLUMN parent_id;
@@ -0,0 +1,6 @@
+-- -----------------------------------------------------
+-- comments parent for replies
+-- -----------------------------------------------------
+-- sqlite can't drop a column used in a foreign key, so parent_id is not constrained.
+ALTER TABLE comments ADD COLUMN parent_id INTEGER NULL;
+CREATE INDEX idx_comments_parent_id ON comments (parent_id);
@@ -0,0 +1,16 @@
+-- -----------------------------------------------------
+-- reactions
+-- -----------------------------------------------------
+-- target_type is one of "article" and "comment".
+CREATE TABLE reactions
+(
+    user_id     INTEGER     NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id   INTEGER     NOT NULL,
+    kind        VARCHAR(32) NOT NULL,
+    created_at  DATETIME NULL,
+    PRIMARY KEY (user_id, target_type, target_id, kind),
+    CONSTRAINT reactions_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_reactions_target ON reactions (target_type, target_id);
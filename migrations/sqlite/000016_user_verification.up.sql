@@ -0,0 +1,25 @@
+-- -----------------------------------------------------
+-- users email verified time
+-- -----------------------------------------------------
+-- existing users are regarded as verified.
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME NULL;
+UPDATE users SET email_verified_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+
+-- -----------------------------------------------------
+-- user_tokens
+-- -----------------------------------------------------
+-- one-time tokens sent by email such as email verification and password reset.
+CREATE TABLE user_tokens
+(
+    token_id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL,
+    purpose    VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at    DATETIME NULL,
+    created_at DATETIME NULL,
+    CONSTRAINT unique_user_tokens_token_hash UNIQUE (token_hash),
+    CONSTRAINT user_tokens_user_id_fk
+        FOREIGN KEY (user_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);
@@ -0,0 +1,34 @@
+-- -----------------------------------------------------
+-- reports
+-- -----------------------------------------------------
+-- target_type is one of "article" and "comment" and status is one of "pending", "resolved" and "dismissed".
+-- target_id and article_id have no foreign keys to keep reports after the article is purged.
+CREATE TABLE reports
+(
+    report_id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    reporter_id INTEGER      NOT NULL,
+    target_type VARCHAR(16)  NOT NULL,
+    target_id   INTEGER      NOT NULL,
+    article_id  INTEGER      NOT NULL,
+    reason      VARCHAR(32)  NOT NULL,
+    detail      TEXT         NULL,
+    status      VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    resolver_id INTEGER      NULL,
+    resolved_at DATETIME NULL,
+    created_at  DATETIME NULL,
+    updated_at  DATETIME NULL,
+    CONSTRAINT unique_reports_reporter_target UNIQUE (reporter_id, target_type, target_id),
+    CONSTRAINT reports_reporter_id_fk
+        FOREIGN KEY (reporter_id) REFERENCES users (user_id),
+    CONSTRAINT reports_resolver_id_fk
+        FOREIGN KEY (resolver_id) REFERENCES users (user_id)
+);
+CREATE INDEX idx_reports_target ON reports (target_type, target_id, status);
+CREATE INDEX idx_reports_status ON reports (status, created_at);
+
+-- -----------------------------------------------------
+-- comments hidden time
+-- -----------------------------------------------------
+-- comments are hidden by moderators or when reported too many times.
+-- articles are hidden by changing the status to "hidden" instead.
+ALTER TABLE comments ADD COLUMN hidden_at DATETIME NULL;
@@ -0,0 +1,2 @@
+DROP INDEX idx_articles_published_at;
+ALTER TABLE articles DROP COLUMN published_at;
@@ -0,0 +1,7 @@
+-- -----------------------------------------------------
+-- articles published time
+-- -----------------------------------------------------
+-- published_at is a time when the article is published and used for ordering listings.
+ALTER TABLE articles ADD COLUMN published_at DATETIME NULL;
+UPDATE articles SET published_at = COALESCE(publish_at, created_at) WHERE status IN ('published', 'hidden');
+CREATE INDEX idx_articles_published_at ON articles (published_at, article_id);
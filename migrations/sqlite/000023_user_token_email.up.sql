@@ -0,0 +1,6 @@
+-- -----------------------------------------------------
+-- user_tokens email
+-- -----------------------------------------------------
+-- email is an address the token was sent to. tokens are invalid after the email of the user is changed.
+ALTER TABLE user_tokens ADD COLUMN email VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE user_tokens SET email = (SELECT u.email FROM users u WHERE u.user_id = user_tokens.user_id);
@@ -0,0 +1,68 @@
+package types
+
+import (
+	articleModel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+	userModel "github.com/zacscoding/echo-gorm-realworld-app/internal/user/model"
+)
+
+// AdminUserResponse represents a single user response of admin api.
+type AdminUserResponse struct {
+	User *AdminUser `json:"user"`
+}
+
+// ToAdminUserResponse converts given u and counts to AdminUserResponse.
+func ToAdminUserResponse(u *userModel.User, counts *articleModel.ContentCounts) *AdminUserResponse {
+	res := &AdminUserResponse{
+		User: toAdminUser(u),
+	}
+	res.User.Contents = counts
+	return res
+}
+
+// AdminUsersResponse represents multiple users response of admin api.
+type AdminUsersResponse struct {
+	Users      []*AdminUser `json:"users"`
+	UsersCount int64        `json:"usersCount"`
+}
+
+// ToAdminUsersResponse converts given users to AdminUsersResponse.
+func ToAdminUsersResponse(users *userModel.Users) *AdminUsersResponse {
+	res := new(AdminUsersResponse)
+	res.Users = make([]*AdminUser, len(users.Users))
+	for i, u := range users.Users {
+		res.Users[i] = toAdminUser(u)
+	}
+	res.UsersCount = users.UsersCount
+	return res
+}
+
+// AdminUser represents an user with account details which are only visible to admins.
+// Contents is only provided when a single user is requested.
+type AdminUser struct {
+	ID            uint                        `json:"id"`
+	Email         string                      `json:"email"`
+	Username      string                      `json:"username"`
+	Bio           string                      `json:"bio"`
+	Image         string                      `json:"image"`
+	Role          string                      `json:"role"`
+	Disabled      bool                        `json:"disabled"`
+	EmailVerified bool                        `json:"emailVerified"`
+	Contents      *articleModel.ContentCounts `json:"contents,omitempty"`
+	CreatedAt     JSONTime                    `json:"createdAt"`
+	UpdatedAt     JSONTime                    `json:"updatedAt"`
+}
+
+func toAdminUser(u *userModel.User) *AdminUser {
+	return &AdminUser{
+		ID:            u.ID,
+		Email:         u.Email,
+		Username:      u.Name,
+		Bio:           u.Bio,
+		Image:         u.Image,
+		Role:          u.Role,
+		Disabled:      u.Disabled,
+		EmailVerified: u.IsVerified(),
+		CreatedAt:     JSONTime(u.CreatedAt),
+		UpdatedAt:     JSONTime(u.UpdatedAt),
+	}
+}
@@ -18,9 +18,11 @@ func ToArticleResponse(a *articlemodel.Article) *ArticleResponse {
 }
 
 // ArticlesResponse represents multiple articles response.
+// NextCursor will be omitted if no more articles.
 type ArticlesResponse struct {
 	Articles      []*Article `json:"articles"`
 	ArticlesCount int64      `json:"articlesCount"`
+	NextCursor    string     `json:"nextCursor,omitempty"`
 }
 
 // ToArticlesResponse converts given as to ArticlesResponse.
@@ -31,6 +33,9 @@ func ToArticlesResponse(as *articlemodel.Articles) *ArticlesResponse {
 		res.Articles[i] = toArticle(a)
 	}
 	res.ArticlesCount = as.ArticlesCount
+	if as.NextCursor != nil {
+		res.NextCursor = as.NextCursor.Encode()
+	}
 	return res
 }
 
@@ -45,16 +50,24 @@ func ToTagsResponse(tags []*articlemodel.Tag) *TagsResponse {
 }
 
 type Article struct {
-	Slug           string   `json:"slug"`
-	Title          string   `json:"title"`
-	Description    string   `json:"description"`
-	Body           string   `json:"body"`
-	Tags           []string `json:"tagList"`
-	CreatedAt      JSONTime `json:"createdAt"`
-	UpdatedAt      JSONTime `json:"updatedAt"`
-	Favorited      bool     `json:"favorited"`
-	FavoritesCount int      `json:"favoritesCount"`
-	Author         Author   `json:"author"`
+	Slug           string         `json:"slug"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	Body           string         `json:"body"`
+	Tags           []string       `json:"tagList"`
+	CreatedAt      JSONTime       `json:"createdAt"`
+	UpdatedAt      JSONTime       `json:"updatedAt"`
+	Favorited      bool           `json:"favorited"`
+	FavoritesCount int            `json:"favoritesCount"`
+	CommentsCount  int            `json:"commentsCount"`
+	Reactions      map[string]int `json:"reactions"`
+	UserReactions  []string       `json:"userReactions"`
+	Author         Author         `json:"author"`
+	Snippet        string         `json:"snippet,omitempty"`
+	DeletedAt      *JSONTime      `json:"deletedAt,omitempty"`
+	Status         string         `json:"status"`
+	PublishAt      *JSONTime      `json:"publishAt,omitempty"`
+	PublishedAt    *JSONTime      `json:"publishedAt,omitempty"`
 }
 
 type Author struct {
@@ -73,8 +86,21 @@ func toAuthor(u *userModel.User) Author {
 	}
 }
 
+// ArticleSummary represents an article referred by other resources.
+type ArticleSummary struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+func toArticleSummary(a *articlemodel.Article) *ArticleSummary {
+	return &ArticleSummary{
+		Slug:  a.Slug,
+		Title: a.Title,
+	}
+}
+
 func toArticle(a *articlemodel.Article) *Article {
-	return &Article{
+	res := &Article{
 		Slug:           a.Slug,
 		Title:          a.Title,
 		Description:    a.Description,
@@ -84,8 +110,29 @@ func toArticle(a *articlemodel.Article) *Article {
 		UpdatedAt:      JSONTime(a.UpdatedAt),
 		Favorited:      a.Favorited,
 		FavoritesCount: a.FavoritesCount,
+		CommentsCount:  a.CommentsCount,
+		Reactions:      toReactions(a.Reactions),
+		UserReactions:  toUserReactions(a.UserReactions),
 		Author:         toAuthor(&a.Author),
+		Snippet:        a.Snippet,
+		Status:         a.Status,
 	}
+	if res.Status == "" {
+		res.Status = articlemodel.ArticleStatusPublished
+	}
+	if a.DeletedAt.Valid {
+		deletedAt := JSONTime(a.DeletedAt.Time)
+		res.DeletedAt = &deletedAt
+	}
+	if a.PublishAt != nil {
+		publishAt := JSONTime(*a.PublishAt)
+		res.PublishAt = &publishAt
+	}
+	if a.PublishedAt != nil {
+		publishedAt := JSONTime(*a.PublishedAt)
+		res.PublishedAt = &publishedAt
+	}
+	return res
 }
 
 func toTags(tags []*articlemodel.Tag) []string {
@@ -16,35 +16,77 @@ func ToCommentResponse(c *articlemodel.Comment) *CommentResponse {
 	}
 }
 
+// ArticleCommentResponse represents a single comment response with the commented article.
+type ArticleCommentResponse struct {
+	Article *ArticleSummary `json:"article"`
+	Comment *Comment        `json:"comment"`
+}
+
+// ToArticleCommentResponse converts given a and c to ArticleCommentResponse.
+func ToArticleCommentResponse(a *articlemodel.Article, c *articlemodel.Comment) *ArticleCommentResponse {
+	return &ArticleCommentResponse{
+		Article: toArticleSummary(a),
+		Comment: toComment(c),
+	}
+}
+
 // CommentsResponse represents multiple comments response.
+// NextCursor will be omitted if no more root comments.
 type CommentsResponse struct {
-	Comments []*Comment `json:"comments"`
+	Comments      []*Comment `json:"comments"`
+	CommentsCount int64      `json:"commentsCount"`
+	NextCursor    string     `json:"nextCursor,omitempty"`
 }
 
 // ToCommentsResponse converts given comments to CommentsResponse.
-func ToCommentsResponse(comments []*articlemodel.Comment) *CommentsResponse {
+func ToCommentsResponse(comments *articlemodel.Comments) *CommentsResponse {
 	res := new(CommentsResponse)
-	res.Comments = make([]*Comment, len(comments))
-	for i, c := range comments {
+	res.Comments = make([]*Comment, len(comments.Comments))
+	for i, c := range comments.Comments {
 		res.Comments[i] = toComment(c)
 	}
+	res.CommentsCount = comments.CommentsCount
+	if comments.NextCursor != nil {
+		res.NextCursor = comments.NextCursor.Encode()
+	}
 	return res
 }
 
 type Comment struct {
-	ID        uint     `json:"id"`
-	CreatedAt JSONTime `json:"createdAt"`
-	UpdatedAt JSONTime `json:"updatedAt"`
-	Body      string   `json:"body"`
-	Author    Author   `json:"author"`
+	ID            uint           `json:"id"`
+	CreatedAt     JSONTime       `json:"createdAt"`
+	UpdatedAt     JSONTime       `json:"updatedAt"`
+	Body          string         `json:"body"`
+	Author        *Author        `json:"author"`
+	ParentID      *uint          `json:"parentId"`
+	Deleted       bool           `json:"deleted"`
+	Hidden        bool           `json:"hidden"`
+	Edited        bool           `json:"edited"`
+	Reactions     map[string]int `json:"reactions"`
+	UserReactions []string       `json:"userReactions"`
+	Replies       []*Comment     `json:"replies"`
 }
 
 func toComment(c *articlemodel.Comment) *Comment {
-	return &Comment{
-		ID:        c.ID,
-		CreatedAt: JSONTime(c.CreatedAt),
-		UpdatedAt: JSONTime(c.UpdatedAt),
-		Body:      c.Body,
-		Author:    toAuthor(&c.Author),
+	comment := &Comment{
+		ID:            c.ID,
+		CreatedAt:     JSONTime(c.CreatedAt),
+		UpdatedAt:     JSONTime(c.UpdatedAt),
+		Body:          c.Body,
+		ParentID:      c.ParentID,
+		Deleted:       c.IsDeleted(),
+		Hidden:        c.IsHidden(),
+		Edited:        c.IsEdited(),
+		Reactions:     toReactions(c.Reactions),
+		UserReactions: toUserReactions(c.UserReactions),
+		Replies:       make([]*Comment, len(c.Replies)),
+	}
+	if !comment.Deleted && !comment.Hidden {
+		author := toAuthor(&c.Author)
+		comment.Author = &author
+	}
+	for i, reply := range c.Replies {
+		comment.Replies[i] = toComment(reply)
 	}
+	return comment
 }
@@ -0,0 +1,56 @@
+package types
+
+import (
+	notificationModel "github.com/zacscoding/echo-gorm-realworld-app/internal/notification/model"
+)
+
+// NotificationsResponse represents multiple notifications response.
+type NotificationsResponse struct {
+	Notifications      []*Notification `json:"notifications"`
+	NotificationsCount int64           `json:"notificationsCount"`
+	UnreadCount        int64           `json:"unreadCount"`
+}
+
+// ToNotificationsResponse converts given notifications to NotificationsResponse.
+func ToNotificationsResponse(notifications *notificationModel.Notifications) *NotificationsResponse {
+	res := new(NotificationsResponse)
+	res.Notifications = make([]*Notification, len(notifications.Notifications))
+	for i, n := range notifications.Notifications {
+		res.Notifications[i] = toNotification(n)
+	}
+	res.NotificationsCount = notifications.NotificationsCount
+	res.UnreadCount = notifications.UnreadCount
+	return res
+}
+
+// UnreadNotificationsResponse represents the number of unread notifications response.
+type UnreadNotificationsResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+// Notification represents a notification of the current user.
+// Article is omitted if the notification is not related to articles or the article is deleted.
+type Notification struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	Actor     Author          `json:"actor"`
+	Article   *ArticleSummary `json:"article,omitempty"`
+	CommentID *uint           `json:"commentId,omitempty"`
+	Read      bool            `json:"read"`
+	CreatedAt JSONTime        `json:"createdAt"`
+}
+
+func toNotification(n *notificationModel.Notification) *Notification {
+	res := &Notification{
+		ID:        n.ID,
+		Type:      n.Type,
+		Actor:     toAuthor(&n.Actor),
+		CommentID: n.CommentID,
+		Read:      n.IsRead(),
+		CreatedAt: JSONTime(n.CreatedAt),
+	}
+	if n.Article != nil {
+		res.Article = toArticleSummary(n.Article)
+	}
+	return res
+}
@@ -0,0 +1,17 @@
+package types
+
+// toReactions returns the number of reactions by kind which is not nil.
+func toReactions(reactions map[string]int) map[string]int {
+	if reactions == nil {
+		return make(map[string]int)
+	}
+	return reactions
+}
+
+// toUserReactions returns reaction kinds of a user which is not nil.
+func toUserReactions(kinds []string) []string {
+	if kinds == nil {
+		return make([]string, 0)
+	}
+	return kinds
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+)
+
+// ReportResponse represents a single report response.
+type ReportResponse struct {
+	Report *Report `json:"report"`
+}
+
+// ToReportResponse converts given r to ReportResponse.
+func ToReportResponse(r *articlemodel.Report) *ReportResponse {
+	return &ReportResponse{
+		Report: toReport(r),
+	}
+}
+
+// ReportsResponse represents multiple reports response.
+type ReportsResponse struct {
+	Reports      []*Report `json:"reports"`
+	ReportsCount int64     `json:"reportsCount"`
+}
+
+// ToReportsResponse converts given reports to ReportsResponse.
+func ToReportsResponse(reports *articlemodel.Reports) *ReportsResponse {
+	res := new(ReportsResponse)
+	res.Reports = make([]*Report, len(reports.Reports))
+	for i, r := range reports.Reports {
+		res.Reports[i] = toReport(r)
+	}
+	res.ReportsCount = reports.ReportsCount
+	return res
+}
+
+// Report represents a report to an article or a comment.
+// ArticleSlug is the slug of the reported article or the article of the reported comment.
+type Report struct {
+	ID          uint      `json:"id"`
+	TargetType  string    `json:"targetType"`
+	TargetID    uint      `json:"targetId"`
+	ArticleSlug string    `json:"articleSlug"`
+	Reason      string    `json:"reason"`
+	Detail      string    `json:"detail"`
+	Status      string    `json:"status"`
+	Reporter    *Author   `json:"reporter"`
+	ResolverID  *uint     `json:"resolverId,omitempty"`
+	ResolvedAt  *JSONTime `json:"resolvedAt,omitempty"`
+	CreatedAt   JSONTime  `json:"createdAt"`
+}
+
+func toReport(r *articlemodel.Report) *Report {
+	reporter := toAuthor(&r.Reporter)
+	report := &Report{
+		ID:          r.ID,
+		TargetType:  r.TargetType,
+		TargetID:    r.TargetID,
+		ArticleSlug: r.ArticleSlug,
+		Reason:      r.Reason,
+		Detail:      r.Detail,
+		Status:      r.Status,
+		Reporter:    &reporter,
+		ResolverID:  r.ResolverID,
+		CreatedAt:   JSONTime(r.CreatedAt),
+	}
+	if r.ResolvedAt != nil {
+		resolvedAt := JSONTime(*r.ResolvedAt)
+		report.ResolvedAt = &resolvedAt
+	}
+	return report
+}
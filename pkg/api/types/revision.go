@@ -0,0 +1,70 @@
+package types
+
+import (
+	articlemodel "github.com/zacscoding/echo-gorm-realworld-app/internal/article/model"
+)
+
+// RevisionResponse represents a single article revision response.
+type RevisionResponse struct {
+	Revision *Revision `json:"revision"`
+}
+
+// ToRevisionResponse converts given r to RevisionResponse.
+func ToRevisionResponse(r *articlemodel.ArticleRevision) *RevisionResponse {
+	return &RevisionResponse{
+		Revision: toRevision(r),
+	}
+}
+
+// RevisionsResponse represents multiple article revisions response.
+type RevisionsResponse struct {
+	Revisions      []*Revision `json:"revisions"`
+	RevisionsCount int         `json:"revisionsCount"`
+}
+
+// ToRevisionsResponse converts given revisions to RevisionsResponse.
+func ToRevisionsResponse(revisions []*articlemodel.ArticleRevision) *RevisionsResponse {
+	res := new(RevisionsResponse)
+	res.Revisions = make([]*Revision, len(revisions))
+	for i, r := range revisions {
+		res.Revisions[i] = toRevision(r)
+	}
+	res.RevisionsCount = len(revisions)
+	return res
+}
+
+// RevisionDiffResponse represents a unified diff between two article revisions.
+type RevisionDiffResponse struct {
+	Diff struct {
+		From uint   `json:"from"`
+		To   uint   `json:"to"`
+		Diff string `json:"diff"`
+	} `json:"diff"`
+}
+
+// ToRevisionDiffResponse converts given revisions and diff to RevisionDiffResponse.
+func ToRevisionDiffResponse(from, to *articlemodel.ArticleRevision, diff string) *RevisionDiffResponse {
+	res := new(RevisionDiffResponse)
+	res.Diff.From = from.Number
+	res.Diff.To = to.Number
+	res.Diff.Diff = diff
+	return res
+}
+
+type Revision struct {
+	Number      uint     `json:"number"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Body        string   `json:"body"`
+	CreatedAt   JSONTime `json:"createdAt"`
+}
+
+func toRevision(r *articlemodel.ArticleRevision) *Revision {
+	return &Revision{
+		Number:      r.Number,
+		Title:       r.Title,
+		Description: r.Description,
+		Body:        r.Body,
+		CreatedAt:   JSONTime(r.CreatedAt),
+	}
+}
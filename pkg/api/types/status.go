@@ -3,7 +3,17 @@ package types
 type Status string
 
 var (
-	StatusDeleted = Status("deleted")
+	StatusDeleted   = Status("deleted")
+	StatusRevoked   = Status("revoked")
+	StatusVerified  = Status("verified")
+	StatusSent      = Status("sent")
+	StatusUpdated   = Status("updated")
+	StatusDisabled  = Status("disabled")
+	StatusEnabled   = Status("enabled")
+	StatusResolved  = Status("resolved")
+	StatusDismissed = Status("dismissed")
+
+	StatusVerificationRequired = Status("verification_required")
 )
 
 // StatusResponse represents a status response.
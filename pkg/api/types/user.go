@@ -5,13 +5,15 @@ import (
 )
 
 // UserResponse represents User resource.
+// RefreshToken is only provided when a new session is started or refreshed.
 type UserResponse struct {
 	User struct {
-		Email    string `json:"email"`
-		Token    string `json:"token"`
-		Username string `json:"username"`
-		Bio      string `json:"bio"`
-		Image    string `json:"image"`
+		Email        string `json:"email"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken,omitempty"`
+		Username     string `json:"username"`
+		Bio          string `json:"bio"`
+		Image        string `json:"image"`
 	} `json:"user"`
 }
 
@@ -26,6 +28,13 @@ func ToUserResponse(u *userModel.User, token string) *UserResponse {
 	return user
 }
 
+// ToUserResponseWithRefreshToken converts given model.User to UserResponse with JWT Token and refresh token.
+func ToUserResponseWithRefreshToken(u *userModel.User, token, refreshToken string) *UserResponse {
+	user := ToUserResponse(u, token)
+	user.User.RefreshToken = refreshToken
+	return user
+}
+
 // UserProfile represents UserProfile resource.
 type UserProfile struct {
 	Profile struct {
@@ -0,0 +1,118 @@
+package types
+
+import (
+	webhookModel "github.com/zacscoding/echo-gorm-realworld-app/internal/webhook/model"
+)
+
+// WebhookResponse represents a single webhook response.
+type WebhookResponse struct {
+	Webhook *Webhook `json:"webhook"`
+}
+
+// ToWebhookResponse converts given w to WebhookResponse.
+// The secret is included only if withSecret is true, i.e. when the webhook is created.
+func ToWebhookResponse(w *webhookModel.Webhook, withSecret bool) *WebhookResponse {
+	res := &WebhookResponse{
+		Webhook: toWebhook(w),
+	}
+	if withSecret {
+		res.Webhook.Secret = w.Secret
+	}
+	return res
+}
+
+// WebhooksResponse represents multiple webhooks response.
+type WebhooksResponse struct {
+	Webhooks      []*Webhook `json:"webhooks"`
+	WebhooksCount int        `json:"webhooksCount"`
+}
+
+// ToWebhooksResponse converts given webhooks to WebhooksResponse without secrets.
+func ToWebhooksResponse(webhooks []*webhookModel.Webhook) *WebhooksResponse {
+	res := new(WebhooksResponse)
+	res.Webhooks = make([]*Webhook, len(webhooks))
+	for i, w := range webhooks {
+		res.Webhooks[i] = toWebhook(w)
+	}
+	res.WebhooksCount = len(webhooks)
+	return res
+}
+
+type Webhook struct {
+	ID        uint     `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt JSONTime `json:"createdAt"`
+	UpdatedAt JSONTime `json:"updatedAt"`
+}
+
+func toWebhook(w *webhookModel.Webhook) *Webhook {
+	return &Webhook{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.EventList(),
+		CreatedAt: JSONTime(w.CreatedAt),
+		UpdatedAt: JSONTime(w.UpdatedAt),
+	}
+}
+
+// WebhookDeliveriesResponse represents multiple deliveries of a webhook response.
+type WebhookDeliveriesResponse struct {
+	Deliveries      []*WebhookDelivery `json:"deliveries"`
+	DeliveriesCount int64              `json:"deliveriesCount"`
+}
+
+// ToWebhookDeliveriesResponse converts given deliveries to WebhookDeliveriesResponse.
+func ToWebhookDeliveriesResponse(deliveries *webhookModel.WebhookDeliveries) *WebhookDeliveriesResponse {
+	res := new(WebhookDeliveriesResponse)
+	res.Deliveries = make([]*WebhookDelivery, len(deliveries.Deliveries))
+	for i, d := range deliveries.Deliveries {
+		res.Deliveries[i] = toWebhookDelivery(d)
+	}
+	res.DeliveriesCount = deliveries.DeliveriesCount
+	return res
+}
+
+// WebhookDelivery represents a delivery of an event to a webhook.
+// StatusCode and Error are results of the last attempt and NextAttemptAt is omitted if not pending.
+type WebhookDelivery struct {
+	ID            uint      `json:"id"`
+	Event         string    `json:"event"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	StatusCode    int       `json:"statusCode,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	NextAttemptAt *JSONTime `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *JSONTime `json:"deliveredAt,omitempty"`
+	CreatedAt     JSONTime  `json:"createdAt"`
+}
+
+func toWebhookDelivery(d *webhookModel.WebhookDelivery) *WebhookDelivery {
+	res := &WebhookDelivery{
+		ID:         d.ID,
+		Event:      d.Event,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		CreatedAt:  JSONTime(d.CreatedAt),
+	}
+	if d.Status == webhookModel.DeliveryStatusPending {
+		next := JSONTime(d.NextAttemptAt)
+		res.NextAttemptAt = &next
+	}
+	if d.DeliveredAt != nil {
+		delivered := JSONTime(*d.DeliveredAt)
+		res.DeliveredAt = &delivered
+	}
+	return res
+}
+
+// WebhookPayload represents a request body sent to webhooks.
+// Data is a resource of the event such as an article or a comment.
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt JSONTime    `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
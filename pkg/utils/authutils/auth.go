@@ -1,26 +1,37 @@
 package authutils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strings"
 	"time"
 )
 
 const (
 	AuthScheme = "Token"
+
+	refreshTokenSize = 32
 )
 
 type JWTClaims struct {
 	UserID uint
+	Role   string `json:"role,omitempty"`
 	jwt.StandardClaims
 }
 
-func MakeJWTToken(userID uint, secret []byte, expires time.Duration) (string, error) {
+// MakeJWTToken returns a signed JWT access token with a unique token id(jti) and given user's role.
+func MakeJWTToken(userID uint, role string, secret []byte, expires time.Duration) (string, error) {
 	c := &JWTClaims{
 		UserID: userID,
+		Role:   role,
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
 			ExpiresAt: time.Now().Add(expires).Unix(),
 		},
 	}
@@ -29,13 +40,60 @@ func MakeJWTToken(userID uint, secret []byte, expires time.Duration) (string, er
 	return token.SignedString(secret)
 }
 
+// MakeRefreshToken returns a new opaque refresh token generated from crypto/rand.
+func MakeRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // CurrentUser returns current user id which stored at echo.Context if exist, otherwise returns 0.
 func CurrentUser(ctx echo.Context) uint {
+	claims := CurrentClaims(ctx)
+	if claims == nil {
+		return 0
+	}
+	return claims.UserID
+}
+
+// CurrentRole returns the role of current user which stored at echo.Context if exist, otherwise returns empty string.
+func CurrentRole(ctx echo.Context) string {
+	claims := CurrentClaims(ctx)
+	if claims == nil {
+		return ""
+	}
+	return claims.Role
+}
+
+// CurrentClaims returns JWTClaims of current user which stored at echo.Context if exist, otherwise returns nil.
+func CurrentClaims(ctx echo.Context) *JWTClaims {
 	token, ok := ctx.Get("user").(*jwt.Token)
 	if !ok {
-		return 0
+		return nil
+	}
+	return token.Claims.(*JWTClaims)
+}
+
+// ParseAuthHeader returns verified JWTClaims of a token in given Authorization header value having AuthScheme.
+// Revocation of the token is not checked.
+func ParseAuthHeader(header, secret string) (*JWTClaims, error) {
+	prefix := AuthScheme + " "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, errors.New("missing or malformed jwt")
+	}
+	claims := &JWTClaims{}
+	_, err := jwt.ParseWithClaims(header[len(prefix):], claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected jwt signing method: %v", t.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return token.Claims.(*JWTClaims).UserID
+	return claims, nil
 }
 
 func SetAuthToken(r *http.Request, token string) {
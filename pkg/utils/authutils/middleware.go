@@ -1,16 +1,36 @@
 package authutils
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
 	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
 )
 
+// RevocationChecker returns a true if given token id(jti) is revoked, otherwise false.
+type RevocationChecker func(ctx context.Context, tokenID string) (bool, error)
+
+// DisabledChecker returns a true if given user is disabled, otherwise false.
+type DisabledChecker func(ctx context.Context, userID uint) (bool, error)
+
 // NewJWTMiddleware returns JWT auth middleware with given optional paths and secret.
 // requests in optionalAuthPaths will skip middleware if header's Authorization is empty.
 func NewJWTMiddleware(optionalAuthPaths map[string]struct{}, secret string) echo.MiddlewareFunc {
-	return middleware.JWTWithConfig(
+	return NewJWTMiddlewareWithRevocation(optionalAuthPaths, secret, nil)
+}
+
+// NewJWTMiddlewareWithRevocation returns JWT auth middleware same as NewJWTMiddleware and
+// rejects tokens if given isRevoked returns true. Tokens without id(jti) are not checked.
+func NewJWTMiddlewareWithRevocation(optionalAuthPaths map[string]struct{}, secret string, isRevoked RevocationChecker) echo.MiddlewareFunc {
+	return NewJWTMiddlewareWithCheckers(optionalAuthPaths, secret, isRevoked, nil)
+}
+
+// NewJWTMiddlewareWithCheckers returns JWT auth middleware same as NewJWTMiddlewareWithRevocation and
+// also rejects tokens of users if given isDisabled returns true. nil checkers are skipped.
+func NewJWTMiddlewareWithCheckers(optionalAuthPaths map[string]struct{}, secret string, isRevoked RevocationChecker,
+	isDisabled DisabledChecker) echo.MiddlewareFunc {
+	jwtMiddleware := middleware.JWTWithConfig(
 		middleware.JWTConfig{
 			Skipper: func(ctx echo.Context) bool {
 				if _, ok := optionalAuthPaths[ctx.Path()]; !ok {
@@ -28,4 +48,40 @@ func NewJWTMiddleware(optionalAuthPaths map[string]struct{}, secret string) echo
 			},
 		},
 	)
+	if isRevoked == nil && isDisabled == nil {
+		return jwtMiddleware
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(func(c echo.Context) error {
+			claims := CurrentClaims(c)
+			if claims == nil {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+			if isRevoked != nil && claims.Id != "" {
+				revoked, err := isRevoked(ctx, claims.Id)
+				if err != nil {
+					logging.FromContext(ctx).Errorw("failed to check token revocation", "tokenID", claims.Id, "err", err)
+					return httputils.NewInternalServerError(err)
+				}
+				if revoked {
+					logging.FromContext(ctx).Errorw("auth failed", "err", "revoked token", "tokenID", claims.Id)
+					return httputils.NewUnauthorized()
+				}
+			}
+			if isDisabled != nil {
+				disabled, err := isDisabled(ctx, claims.UserID)
+				if err != nil {
+					logging.FromContext(ctx).Errorw("failed to check disabled user", "userID", claims.UserID, "err", err)
+					return httputils.NewInternalServerError(err)
+				}
+				if disabled {
+					logging.FromContext(ctx).Errorw("auth failed", "err", "disabled user", "userID", claims.UserID)
+					return httputils.NewUnauthorized()
+				}
+			}
+			return next(c)
+		})
+	}
 }
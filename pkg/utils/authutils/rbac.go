@@ -0,0 +1,93 @@
+package authutils
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/logging"
+	"github.com/zacscoding/echo-gorm-realworld-app/pkg/utils/httputils"
+	"net/http"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permission represents an action which is allowed to specific roles.
+type Permission string
+
+const (
+	// PermissionDeleteAnyArticle allows to delete articles written by other users.
+	PermissionDeleteAnyArticle Permission = "article:delete_any"
+	// PermissionDeleteAnyComment allows to delete comments written by other users.
+	PermissionDeleteAnyComment Permission = "comment:delete_any"
+	// PermissionModerateContent allows to review reports and hide or restore reported contents.
+	PermissionModerateContent Permission = "content:moderate"
+	// PermissionManageUsers allows to manage users such as changing roles.
+	PermissionManageUsers Permission = "user:manage"
+)
+
+var rolePermissions = map[string]map[Permission]struct{}{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionDeleteAnyArticle: {},
+		PermissionDeleteAnyComment: {},
+		PermissionModerateContent:  {},
+	},
+	RoleAdmin: {
+		PermissionDeleteAnyArticle: {},
+		PermissionDeleteAnyComment: {},
+		PermissionModerateContent:  {},
+		PermissionManageUsers:      {},
+	},
+}
+
+// IsValidRole returns true if given role is one of RoleUser, RoleModerator and RoleAdmin, otherwise false.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission returns true if given role is granted given permission, otherwise false.
+// unknown roles have no permissions.
+func HasPermission(role string, p Permission) bool {
+	_, ok := rolePermissions[role][p]
+	return ok
+}
+
+// RoleFinder returns the current role of given user.
+type RoleFinder func(ctx context.Context, userID uint) (string, error)
+
+// NewPermissionMiddleware returns a middleware which rejects requests with 403 Forbidden
+// if the role of current user doesn't have all of given permissions.
+// The role is found by given findRole, so changed roles are applied to issued access tokens immediately.
+// The role in the access token is used if findRole is nil.
+// The middleware must be used after JWT auth middleware and returns 401 Unauthorized if no current user.
+func NewPermissionMiddleware(findRole RoleFinder, permissions ...Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := CurrentClaims(c)
+			if claims == nil {
+				return httputils.NewUnauthorized()
+			}
+			ctx := c.Request().Context()
+			role := claims.Role
+			if findRole != nil {
+				var err error
+				if role, err = findRole(ctx, claims.UserID); err != nil {
+					logging.FromContext(ctx).Errorw("failed to find a role", "userID", claims.UserID, "err", err)
+					return httputils.NewInternalServerError(err)
+				}
+			}
+			for _, p := range permissions {
+				if !HasPermission(role, p) {
+					logging.FromContext(ctx).Errorw("permission denied",
+						"userID", claims.UserID, "role", role, "permission", p)
+					return httputils.NewError(http.StatusForbidden, "permission denied")
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package hashutils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,3 +19,10 @@ func EncodePassword(pass string) (string, error) {
 func MatchesPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+// HashToken returns a hex encoded SHA-256 hash of given token.
+// This is used for storing high entropy tokens such as refresh tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
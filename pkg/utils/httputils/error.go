@@ -38,6 +38,11 @@ func NewUnauthorized() error {
 	return NewError(http.StatusUnauthorized, "auth required")
 }
 
+// NewTooManyRequests returns echo.HTTPError with 429 Too Many Requests.
+func NewTooManyRequests() error {
+	return NewError(http.StatusTooManyRequests, "too many requests")
+}
+
 // NewStatusUnprocessableEntity returns echo.HTTPError with 422 Unprocessable Entity and given message.
 func NewStatusUnprocessableEntity(msg string) error {
 	return NewError(http.StatusUnprocessableEntity, msg)